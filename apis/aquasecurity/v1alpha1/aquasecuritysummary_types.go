@@ -0,0 +1,127 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AquaSecuritySummarySpec The summary is maintained by the operator, the spec has no settings
+type AquaSecuritySummarySpec struct {
+}
+
+// AquaSeverityCounts Number of findings per severity
+type AquaSeverityCounts struct {
+	Critical int64 `json:"critical"`
+	High     int64 `json:"high"`
+	Medium   int64 `json:"medium"`
+	Low      int64 `json:"low"`
+	Unknown  int64 `json:"unknown,omitempty"`
+}
+
+// AquaNamespaceSecuritySummary The findings of the reports of a namespace
+type AquaNamespaceSecuritySummary struct {
+	Namespace string `json:"namespace"`
+	// Vulnerabilities Vulnerabilities of the vulnerability reports
+	Vulnerabilities AquaSeverityCounts `json:"vulnerabilities"`
+	// FailingChecks Failing checks of the config audit reports
+	FailingChecks AquaSeverityCounts `json:"failingChecks"`
+	// VulnerabilityReports Number of vulnerability reports
+	VulnerabilityReports int64 `json:"vulnerabilityReports"`
+	// ConfigAuditReports Number of config audit reports
+	ConfigAuditReports int64 `json:"configAuditReports"`
+}
+
+// AquaVulnerableImage An image of the vulnerability reports
+type AquaVulnerableImage struct {
+	// Image Registry, repository and tag of the image
+	Image           string             `json:"image"`
+	Vulnerabilities AquaSeverityCounts `json:"vulnerabilities"`
+	// Workloads Number of vulnerability reports of the image, a report per container of a workload
+	Workloads int64 `json:"workloads"`
+}
+
+// AquaFailingCheck A config audit check failing on at least one resource
+type AquaFailingCheck struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	// Resources Number of config audit reports the check fails in
+	Resources int64 `json:"resources"`
+}
+
+// AquaSecuritySummaryTrendPoint The cluster totals at a time
+type AquaSecuritySummaryTrendPoint struct {
+	Time            metav1.Time        `json:"time"`
+	Vulnerabilities AquaSeverityCounts `json:"vulnerabilities"`
+	FailingChecks   AquaSeverityCounts `json:"failingChecks"`
+}
+
+// AquaSecuritySummaryStatus The aggregation of the vulnerability and config audit reports of the cluster
+type AquaSecuritySummaryStatus struct {
+	// Vulnerabilities Vulnerabilities of all the vulnerability reports
+	Vulnerabilities AquaSeverityCounts `json:"vulnerabilities,omitempty"`
+	// FailingChecks Failing checks of all the config audit reports
+	FailingChecks AquaSeverityCounts `json:"failingChecks,omitempty"`
+	// VulnerabilityReports Number of vulnerability reports
+	VulnerabilityReports int64 `json:"vulnerabilityReports,omitempty"`
+	// ConfigAuditReports Number of config audit reports
+	ConfigAuditReports int64 `json:"configAuditReports,omitempty"`
+	// Namespaces The findings per namespace, by name
+	Namespaces []AquaNamespaceSecuritySummary `json:"namespaces,omitempty"`
+	// TopVulnerableImages The images with the most critical, then high, medium and low vulnerabilities
+	TopVulnerableImages []AquaVulnerableImage `json:"topVulnerableImages,omitempty"`
+	// TopFailingChecks The checks failing the most, by severity then number of resources
+	TopFailingChecks []AquaFailingCheck `json:"topFailingChecks,omitempty"`
+	// Trend The totals sampled over time, oldest first
+	Trend []AquaSecuritySummaryTrendPoint `json:"trend,omitempty"`
+	// LastUpdateTime Time the reports were last aggregated
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Message Why the last aggregation failed, empty when it succeeded
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Critical",type="integer",JSONPath=".status.vulnerabilities.critical",description="Critical Vulnerabilities"
+//+kubebuilder:printcolumn:name="High",type="integer",JSONPath=".status.vulnerabilities.high",description="High Vulnerabilities"
+//+kubebuilder:printcolumn:name="High Failing Checks",type="integer",JSONPath=".status.failingChecks.high",description="High Severity Failing Config Audit Checks"
+//+kubebuilder:printcolumn:name="Updated",type="date",JSONPath=".status.lastUpdateTime",description="Last Aggregation"
+
+// AquaSecuritySummary is the Schema for the aquasecuritysummaries API, a cluster wide summary of the starboard and
+// trivy-operator reports kept up to date by the operator
+type AquaSecuritySummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AquaSecuritySummarySpec   `json:"spec,omitempty"`
+	Status AquaSecuritySummaryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AquaSecuritySummaryList contains a list of AquaSecuritySummary
+type AquaSecuritySummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AquaSecuritySummary `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AquaSecuritySummary{}, &AquaSecuritySummaryList{})
+}
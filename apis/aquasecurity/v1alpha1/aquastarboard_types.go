@@ -39,16 +39,30 @@ type AquaStarboardSpec struct {
 	Envs                             []corev1.EnvVar              `json:"env,omitempty"`
 	KubeEnforcerVersion              string                       `json:"kube_enforcer_version,omitempty"`
 	LogDevMode                       bool                         `json:"logDevMode,omitempty"`
-	ConcurrentScanJobsLimit          string                       `json:"concurrentScanJobsLimit,omitempty"`
-	ScanJobRetryAfter                string                       `json:"scanJobRetryAfter,omitempty"`
+	ConcurrentScanJobsLimit          string                       `json:"concurrentScanJobsLimit,omitempty"` // Deprecated: use settings.concurrentScanJobsLimit
+	ScanJobRetryAfter                string                       `json:"scanJobRetryAfter,omitempty"`       // Deprecated: use settings.scanJobRetryAfter
 	MetricsBindAddress               string                       `json:"metricsBindAddress,omitempty"`
 	HealthProbeBindAddress           string                       `json:"healthProbeBindAddress,omitempty"`
-	CisKubernetesBenchmarkEnabled    string                       `json:"cisKubernetesBenchmarkEnabled,omitempty"`
-	VulnerabilityScannerEnabled      string                       `json:"vulnerabilityScannerEnabled,omitempty"`
-	BatchDeleteLimit                 string                       `json:"batchDeleteLimit,omitempty"`
-	BatchDeleteDelay                 string                       `json:"batchDeleteDelay,omitempty"`
-	OperatorClusterComplianceEnabled string                       `json:"operator_cluster_compliance_enabled"`
+	CisKubernetesBenchmarkEnabled    string                       `json:"cisKubernetesBenchmarkEnabled,omitempty"` // Deprecated: use settings.cisKubernetesBenchmarkEnabled
+	VulnerabilityScannerEnabled      string                       `json:"vulnerabilityScannerEnabled,omitempty"`   // Deprecated: use settings.vulnerabilityScannerEnabled
+	BatchDeleteLimit                 string                       `json:"batchDeleteLimit,omitempty"`              // Deprecated: use settings.batchDeleteLimit
+	BatchDeleteDelay                 string                       `json:"batchDeleteDelay,omitempty"`              // Deprecated: use settings.batchDeleteDelay
+	OperatorClusterComplianceEnabled string                       `json:"operator_cluster_compliance_enabled"`     // Deprecated: use settings.clusterComplianceEnabled
 	ConfigMapChecksum                string                       `json:"config_map_checksum,omitempty"`
+	// DriftPolicy How manual changes to the starboard deployment and configmaps are handled
+	DriftPolicy *v1alpha1.AquaDriftPolicy `json:"driftPolicy,omitempty"`
+	// Paused Stops reconciling the CR until unset
+	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindow The windows the disruptive changes wait for, they are applied at once when unset
+	MaintenanceWindow *v1alpha1.AquaMaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	// Scanner The operator deployed for the reports, starboard or trivy-operator. Switching it removes the
+	// objects of the previous one
+	Scanner v1alpha1.AquaStarboardScanner `json:"scanner,omitempty"`
+	// Settings Typed settings of the scanner and its scan jobs, they take precedence over the string fields above,
+	// which are deprecated
+	Settings *v1alpha1.AquaStarboardSettings `json:"settings,omitempty"`
+	// Policies Rego policies of the conftest config audit scanner of starboard, changing them rolls starboard
+	Policies *v1alpha1.AquaStarboardPolicies `json:"policies,omitempty"`
 }
 
 // AquaStarboardStatus defines the observed state of AquaStarboard
@@ -57,6 +71,12 @@ type AquaStarboardStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	Nodes []string                     `json:"nodes"`
 	State v1alpha1.AquaDeploymentState `json:"state"`
+	// Drifts The manual changes detected on the owned objects, see the drift policy
+	Drifts []v1alpha1.AquaDrift `json:"drifts,omitempty"`
+	// Maintenance The disruptive changes waiting for the maintenance window
+	Maintenance *v1alpha1.AquaMaintenanceStatus `json:"maintenance,omitempty"`
+	// Scanner The operator deployed for the reports
+	Scanner v1alpha1.AquaStarboardScanner `json:"scanner,omitempty"`
 }
 
 //+kubebuilder:object:root=true
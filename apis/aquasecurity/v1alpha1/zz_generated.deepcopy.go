@@ -27,6 +27,188 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaFailingCheck) DeepCopyInto(out *AquaFailingCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaFailingCheck.
+func (in *AquaFailingCheck) DeepCopy() *AquaFailingCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaFailingCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaNamespaceSecuritySummary) DeepCopyInto(out *AquaNamespaceSecuritySummary) {
+	*out = *in
+	out.Vulnerabilities = in.Vulnerabilities
+	out.FailingChecks = in.FailingChecks
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaNamespaceSecuritySummary.
+func (in *AquaNamespaceSecuritySummary) DeepCopy() *AquaNamespaceSecuritySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaNamespaceSecuritySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSecuritySummary) DeepCopyInto(out *AquaSecuritySummary) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSecuritySummary.
+func (in *AquaSecuritySummary) DeepCopy() *AquaSecuritySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSecuritySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaSecuritySummary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSecuritySummaryList) DeepCopyInto(out *AquaSecuritySummaryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AquaSecuritySummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSecuritySummaryList.
+func (in *AquaSecuritySummaryList) DeepCopy() *AquaSecuritySummaryList {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSecuritySummaryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaSecuritySummaryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSecuritySummarySpec) DeepCopyInto(out *AquaSecuritySummarySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSecuritySummarySpec.
+func (in *AquaSecuritySummarySpec) DeepCopy() *AquaSecuritySummarySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSecuritySummarySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSecuritySummaryStatus) DeepCopyInto(out *AquaSecuritySummaryStatus) {
+	*out = *in
+	out.Vulnerabilities = in.Vulnerabilities
+	out.FailingChecks = in.FailingChecks
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]AquaNamespaceSecuritySummary, len(*in))
+		copy(*out, *in)
+	}
+	if in.TopVulnerableImages != nil {
+		in, out := &in.TopVulnerableImages, &out.TopVulnerableImages
+		*out = make([]AquaVulnerableImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.TopFailingChecks != nil {
+		in, out := &in.TopFailingChecks, &out.TopFailingChecks
+		*out = make([]AquaFailingCheck, len(*in))
+		copy(*out, *in)
+	}
+	if in.Trend != nil {
+		in, out := &in.Trend, &out.Trend
+		*out = make([]AquaSecuritySummaryTrendPoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSecuritySummaryStatus.
+func (in *AquaSecuritySummaryStatus) DeepCopy() *AquaSecuritySummaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSecuritySummaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSecuritySummaryTrendPoint) DeepCopyInto(out *AquaSecuritySummaryTrendPoint) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	out.Vulnerabilities = in.Vulnerabilities
+	out.FailingChecks = in.FailingChecks
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSecuritySummaryTrendPoint.
+func (in *AquaSecuritySummaryTrendPoint) DeepCopy() *AquaSecuritySummaryTrendPoint {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSecuritySummaryTrendPoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSeverityCounts) DeepCopyInto(out *AquaSeverityCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSeverityCounts.
+func (in *AquaSeverityCounts) DeepCopy() *AquaSeverityCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSeverityCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AquaStarboard) DeepCopyInto(out *AquaStarboard) {
 	*out = *in
@@ -103,7 +285,7 @@ func (in *AquaStarboardSpec) DeepCopyInto(out *AquaStarboardSpec) {
 	if in.RegistryData != nil {
 		in, out := &in.RegistryData, &out.RegistryData
 		*out = new(operatorv1alpha1.AquaDockerRegistry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ImageData != nil {
 		in, out := &in.ImageData, &out.ImageData
@@ -117,6 +299,26 @@ func (in *AquaStarboardSpec) DeepCopyInto(out *AquaStarboardSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DriftPolicy != nil {
+		in, out := &in.DriftPolicy, &out.DriftPolicy
+		*out = new(operatorv1alpha1.AquaDriftPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(operatorv1alpha1.AquaMaintenanceWindow)
+		**out = **in
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(operatorv1alpha1.AquaStarboardSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = new(operatorv1alpha1.AquaStarboardPolicies)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaStarboardSpec.
@@ -137,6 +339,18 @@ func (in *AquaStarboardStatus) DeepCopyInto(out *AquaStarboardStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]operatorv1alpha1.AquaDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(operatorv1alpha1.AquaMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaStarboardStatus.
@@ -148,3 +362,19 @@ func (in *AquaStarboardStatus) DeepCopy() *AquaStarboardStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaVulnerableImage) DeepCopyInto(out *AquaVulnerableImage) {
+	*out = *in
+	out.Vulnerabilities = in.Vulnerabilities
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaVulnerableImage.
+func (in *AquaVulnerableImage) DeepCopy() *AquaVulnerableImage {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaVulnerableImage)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -40,7 +40,9 @@ type AquaCspSpec struct {
 	GatewayService *AquaService `json:"gateway,required"`
 	ServerService  *AquaService `json:"server,required"`
 
-	LicenseToken           string                   `json:"licenseToken,omitempty"`
+	// Deprecated: moved into the common.license secret by the operator
+	LicenseToken string `json:"licenseToken,omitempty"`
+	// Deprecated: moved into the common.adminPassword secret by the operator
 	AdminPassword          string                   `json:"adminPassword,omitempty"`
 	Enforcer               *AquaEnforcerDetailes    `json:"enforcer,omitempty"`
 	Route                  bool                     `json:"route,omitempty"`
@@ -51,6 +53,18 @@ type AquaCspSpec struct {
 	DeployKubeEnforcer     *AquaKubeEnforcerDetails `json:"kubeEnforcer,omitempty"`
 	EnforcerUpdateApproved *bool                    `json:"updateEnforcer,omitempty"`
 	Mtls                   bool                     `json:"mtls,omitempty"`
+	// Upgrade Controls the ordered upgrade of the components when infra.version changes
+	Upgrade *AquaCspUpgradeStrategy `json:"upgrade,omitempty"`
+	// Paused Stops reconciling the CR until unset
+	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindow The windows the disruptive changes wait for, they are applied at once when unset
+	MaintenanceWindow *AquaMaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// AquaCspUpgradeStrategy controls the ordered upgrade of the platform components
+type AquaCspUpgradeStrategy struct {
+	// Paused Keeps the running upgrade at its current phase until unset
+	Paused bool `json:"paused,omitempty"`
 }
 
 // AquaCspStatus defines the observed state of AquaCsp
@@ -59,12 +73,60 @@ type AquaCspStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	Phase string              `json:"phase"`
 	State AquaDeploymentState `json:"state"`
+	// Version The Aqua version all the components were upgraded to
+	Version           string   `json:"version,omitempty"`
+	AvailableUpgrades []string `json:"availableUpgrades,omitempty"`
+	// Upgrade The upgrade in progress, components are upgraded one phase at a time
+	Upgrade *AquaCspUpgradeStatus `json:"upgrade,omitempty"`
+}
+
+// AquaCspUpgradeStatus reports the progress of an ordered platform upgrade
+type AquaCspUpgradeStatus struct {
+	FromVersion string           `json:"fromVersion"`
+	ToVersion   string           `json:"toVersion"`
+	Phase       AquaUpgradePhase `json:"phase"`
+	Paused      bool             `json:"paused,omitempty"`
+	Message     string           `json:"message,omitempty"`
+}
+
+type AquaUpgradePhase string
+
+const (
+	// AquaUpgradePhasePending The upgrade didn't start yet, it stays pending while it is paused
+	AquaUpgradePhasePending AquaUpgradePhase = "Pending"
+
+	// AquaUpgradePhaseDatabase The database is backed up and upgraded
+	AquaUpgradePhaseDatabase AquaUpgradePhase = "Database"
+
+	// AquaUpgradePhaseServer The server is upgraded and its API reports the new version
+	AquaUpgradePhaseServer AquaUpgradePhase = "Server"
+
+	// AquaUpgradePhaseGateway The gateway is upgraded
+	AquaUpgradePhaseGateway AquaUpgradePhase = "Gateway"
+
+	// AquaUpgradePhaseKubeEnforcer The KubeEnforcer is upgraded
+	AquaUpgradePhaseKubeEnforcer AquaUpgradePhase = "KubeEnforcer"
+
+	// AquaUpgradePhaseEnforcer The enforcers are upgraded, the last phase
+	AquaUpgradePhaseEnforcer AquaUpgradePhase = "Enforcer"
+)
+
+// AquaUpgradePhases The upgrade phases in the order they run
+var AquaUpgradePhases = []AquaUpgradePhase{
+	AquaUpgradePhasePending,
+	AquaUpgradePhaseDatabase,
+	AquaUpgradePhaseServer,
+	AquaUpgradePhaseGateway,
+	AquaUpgradePhaseKubeEnforcer,
+	AquaUpgradePhaseEnforcer,
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath="..metadata.creationTimestamp",description="Aqua Csp Age"
 //+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="Aqua Csp status"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Aqua Csp version"
+//+kubebuilder:printcolumn:name="Upgrade",type="string",JSONPath=".status.upgrade.phase",description="Aqua Csp upgrade phase"
 
 // AquaCsp is the Schema for the aquacsps API
 type AquaCsp struct {
@@ -34,16 +34,79 @@ type AquaDatabaseSpec struct {
 	AuditDB        *AuditDBInformation `json:"auditDB,omitempty"`
 	DiskSize       int                 `json:"diskSize,required"`
 	RunAsNonRoot   bool                `json:"runAsNonRoot,omitempty"`
+	// Paused Stops reconciling the CR until unset
+	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindow The windows the disruptive changes wait for, they are applied at once when unset
+	MaintenanceWindow *AquaMaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // AquaDatabaseStatus defines the observed state of AquaDatabase
 type AquaDatabaseStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	Nodes []string            `json:"nodes"`
-	State AquaDeploymentState `json:"state"`
+	Nodes             []string                    `json:"nodes"`
+	State             AquaDeploymentState         `json:"state"`
+	Storage           []AquaDatabaseStorageStatus `json:"storage,omitempty"`
+	Images            []AquaImageStatus           `json:"images,omitempty"`
+	Version           string                      `json:"version,omitempty"`
+	AvailableUpgrades []string                    `json:"availableUpgrades,omitempty"`
+	// LastBackup is the job that dumped the database before its last upgrade
+	LastBackup string `json:"lastBackup,omitempty"`
+	// Maintenance The disruptive changes waiting for the maintenance window
+	Maintenance *AquaMaintenanceStatus `json:"maintenance,omitempty"`
 }
 
+// AquaDatabaseStorageStatus reports the persistent volume claim backing a database deployment
+type AquaDatabaseStorageStatus struct {
+	// Deployment is the database deployment that mounts the claim
+	Deployment string `json:"deployment"`
+	// PvcName is the claim currently mounted by the deployment
+	PvcName        string                    `json:"pvcName"`
+	StorageClass   string                    `json:"storageClass,omitempty"`
+	RequestedSize  string                    `json:"requestedSize,omitempty"`
+	Capacity       string                    `json:"capacity,omitempty"`
+	ResizeState    AquaStorageResizeState    `json:"resizeState,omitempty"`
+	MigrationState AquaStorageMigrationState `json:"migrationState,omitempty"`
+	// MigrationTarget is the claim being populated while a storage class migration is in progress
+	MigrationTarget string `json:"migrationTarget,omitempty"`
+	Message         string `json:"message,omitempty"`
+}
+
+type AquaStorageResizeState string
+
+const (
+	// AquaStorageResizePending The claim request was increased and is waiting for the volume plugin
+	AquaStorageResizePending AquaStorageResizeState = "Pending"
+
+	// AquaStorageResizeInProgress The volume is being expanded by the storage provider
+	AquaStorageResizeInProgress AquaStorageResizeState = "Resizing"
+
+	// AquaStorageFileSystemResizePending The volume was expanded and waits for the node to grow the file system
+	AquaStorageFileSystemResizePending AquaStorageResizeState = "FileSystemResizePending"
+
+	// AquaStorageResizeCompleted The claim capacity matches the requested size
+	AquaStorageResizeCompleted AquaStorageResizeState = "Completed"
+
+	// AquaStorageResizeNotSupported The storage class of the claim doesn't allow volume expansion
+	AquaStorageResizeNotSupported AquaStorageResizeState = "ExpansionNotSupported"
+)
+
+type AquaStorageMigrationState string
+
+const (
+	// AquaStorageMigrationScalingDown Waiting for the database deployment to stop before copying
+	AquaStorageMigrationScalingDown AquaStorageMigrationState = "ScalingDown"
+
+	// AquaStorageMigrationCopying The migration job copies the data to the new claim
+	AquaStorageMigrationCopying AquaStorageMigrationState = "Copying"
+
+	// AquaStorageMigrationCompleted The database deployment was switched to the new claim
+	AquaStorageMigrationCompleted AquaStorageMigrationState = "Completed"
+
+	// AquaStorageMigrationFailed The migration job failed, the database keeps using the old claim
+	AquaStorageMigrationFailed AquaStorageMigrationState = "Failed"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.deploy.replicas",description="Replicas Number"
@@ -32,24 +32,171 @@ type AquaEnforcerSpec struct {
 	Infrastructure *AquaInfrastructure `json:"infra"`
 	Common         *AquaCommon         `json:"common"`
 
-	EnforcerService        *AquaService            `json:"deploy,required"`
-	Gateway                *AquaGatewayInformation `json:"gateway,required"`
-	Token                  string                  `json:"token,required"`
-	Secret                 *AquaSecret             `json:"secret,omitempty"`
-	Envs                   []corev1.EnvVar         `json:"env,omitempty"`
-	RunAsNonRoot           bool                    `json:"runAsNonRoot,omitempty"`
-	EnforcerUpdateApproved *bool                   `json:"updateEnforcer,omitempty"`
-	Mtls                   bool                    `json:"mtls,omitempty"`
-	ConfigMapChecksum      string                  `json:"config_map_checksum,omitempty"`
-	AquaExpressMode        bool                    `json:"aqua_express_mode,omitempty"`
-	RhcosVersion           string                  `json:"rhcosVersion,omitempty"`
+	EnforcerService *AquaService            `json:"deploy,required"`
+	Gateway         *AquaGatewayInformation `json:"gateway,required"`
+	// Deprecated: moved into the secret by the operator
+	Token                  string          `json:"token,omitempty"`
+	Secret                 *AquaSecret     `json:"secret,omitempty"`
+	Envs                   []corev1.EnvVar `json:"env,omitempty"`
+	RunAsNonRoot           bool            `json:"runAsNonRoot,omitempty"`
+	EnforcerUpdateApproved *bool           `json:"updateEnforcer,omitempty"`
+	Mtls                   bool            `json:"mtls,omitempty"`
+	ConfigMapChecksum      string          `json:"config_map_checksum,omitempty"`
+	AquaExpressMode        bool            `json:"aqua_express_mode,omitempty"`
+	RhcosVersion           string          `json:"rhcosVersion,omitempty"` // detected on OpenShift when empty
+	// Paused Stops reconciling the CR until unset
+	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindow The windows the disruptive changes wait for, they are applied at once when unset
+	MaintenanceWindow *AquaMaintenanceWindow `json:"maintenanceWindow,omitempty"`
+	// NodeGroups Sets of nodes running their own enforcer DaemonSet and ConfigMap, in place of the single <name>-agent
+	// DaemonSet. The node selections of the groups can't overlap
+	NodeGroups []AquaEnforcerNodeGroup `json:"nodeGroups,omitempty"`
+	// ContainerRuntime Mounts the socket of the container runtime the nodes run, read from the nodes. Unset keeps the
+	// default mounts
+	ContainerRuntime *AquaEnforcerContainerRuntime `json:"containerRuntime,omitempty"`
+	// SecurityProfile Privileges of the enforcers, in place of runAsNonRoot. On OpenShift the operator generates the
+	// SecurityContextConstraints of the profile
+	SecurityProfile *AquaEnforcerSecurityProfile `json:"securityProfile,omitempty"`
+	// EnforcerGroup Creates or looks up the enforcer group through the Aqua API and writes its token to the token
+	// secret, in place of token
+	EnforcerGroup *AquaEnforcerGroupToken `json:"enforcerGroup,omitempty"`
+}
+
+// AquaEnforcerGroupToken The enforcer group of the enforcers in the Aqua console, reached through an AquaServer or a login
+type AquaEnforcerGroupToken struct {
+	// Name ID of the enforcer group, the name of the CR when unset. An existing group keeps its settings
+	Name string `json:"name,omitempty"`
+	// Server Name of the AquaServer of the namespace, its API is called with the administrator password
+	Server string `json:"server,omitempty"`
+	// Login Address and credentials of the Aqua API, when the server isn't deployed by the operator
+	Login *AquaLogin `json:"login,omitempty"`
+	// RotateToken Changing the value gives the group a new token, e.g. the date of the rotation
+	RotateToken string `json:"rotateToken,omitempty"`
+	// Adopt Lets the operator give a new token to a group it didn't create, the enforcers of other clusters in the
+	// group disconnect until they get the new token
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+// AquaEnforcerSecurityProfile The privileges of the enforcer container
+type AquaEnforcerSecurityProfile struct {
+	// Type privileged runs the enforcers privileged, restricted with the capabilities Aqua requires only, custom with
+	// the capabilities listed
+	// +kubebuilder:validation:Enum=privileged;restricted;custom
+	Type string `json:"type"`
+	// Capabilities Capabilities of the custom profile
+	Capabilities []corev1.Capability `json:"capabilities,omitempty"`
+	// SeccompProfile Seccomp profile of the restricted and custom profiles, RuntimeDefault when unset
+	SeccompProfile *corev1.SeccompProfile `json:"seccompProfile,omitempty"`
+	// AppArmorProfile AppArmor profile of the restricted and custom profiles, unconfined when unset
+	// +kubebuilder:validation:Pattern=`^(unconfined|runtime/default|localhost/.+)$`
+	AppArmorProfile string `json:"appArmorProfile,omitempty"`
+}
+
+// AquaEnforcerContainerRuntime The container runtime detection, the nodes of a group running different runtimes get a
+// DaemonSet per runtime
+type AquaEnforcerContainerRuntime struct {
+	// Runtimes Settings of a runtime, in place of its defaults
+	Runtimes []AquaEnforcerRuntimeSettings `json:"runtimes,omitempty"`
+}
+
+// AquaEnforcerRuntimeSettings The socket and env of the enforcers of the nodes running a container runtime
+type AquaEnforcerRuntimeSettings struct {
+	// +kubebuilder:validation:Enum=containerd;cri-o;docker
+	Name string `json:"name"`
+	// SocketPath Path of the socket of the runtime on the nodes
+	SocketPath string `json:"socketPath,omitempty"`
+	// Env Environment variables of the enforcers of the runtime, added to env
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// AquaEnforcerNodeGroup A set of nodes whose enforcers have their own settings, the settings not set are the ones
+// of the spec
+type AquaEnforcerNodeGroup struct {
+	// Name Name of the group, the DaemonSet is <cr name>-<name>-agent and the ConfigMap aqua-csp-enforcer-<name>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name string `json:"name"`
+	// NodeSelector Labels of the nodes of the group, added to the nodeSelector of deploy
+	NodeSelector map[string]string `json:"nodeSelector"`
+	// Tolerations Tolerations of the enforcers of the group, in place of the ones of deploy
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Resources Resources of the enforcers of the group, in place of the ones of deploy
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// LogicalName AQUA_LOGICAL_NAME of the enforcers of the group
+	LogicalName string `json:"logicalName,omitempty"`
+	// Gateway Gateway the enforcers of the group connect to
+	Gateway *AquaGatewayInformation `json:"gateway,omitempty"`
+	// AquaExpressMode AQUA_EXPRESS_MODE of the enforcers of the group
+	AquaExpressMode *bool `json:"aqua_express_mode,omitempty"`
+	// RhcosVersion AQUA_OCP_FULL_VERSION of the enforcers of the group, detected on OpenShift when unset
+	RhcosVersion string `json:"rhcosVersion,omitempty"`
+	// ConfigData Entries added to the ConfigMap of the group, or replacing its entries, e.g. AQUA_ENFORCE_MODE
+	ConfigData map[string]string `json:"configData,omitempty"`
+}
+
+// AquaEnforcerNodeGroupStatus The rollout of the DaemonSet of a node group
+type AquaEnforcerNodeGroupStatus struct {
+	Name      string `json:"name"`
+	DaemonSet string `json:"daemonSet"`
+	// DesiredNumberScheduled Number of nodes of the group
+	DesiredNumberScheduled int32 `json:"desiredNumberScheduled"`
+	// NumberReady Number of nodes of the group running a ready enforcer
+	NumberReady int32 `json:"numberReady"`
 }
 
 // AquaEnforcerStatus defines the observed state of AquaEnforcer
 type AquaEnforcerStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	State AquaDeploymentState `json:"state"`
+	State             AquaDeploymentState `json:"state"`
+	Images            []AquaImageStatus   `json:"images,omitempty"`
+	Version           string              `json:"version,omitempty"`
+	AvailableUpgrades []string            `json:"availableUpgrades,omitempty"`
+	// Drifts The manual changes detected on the owned objects, see the drift policy
+	Drifts []AquaDrift `json:"drifts,omitempty"`
+	// Maintenance The disruptive changes waiting for the maintenance window
+	Maintenance *AquaMaintenanceStatus `json:"maintenance,omitempty"`
+	// NodeGroups The rollout of the node groups, the state aggregates them
+	NodeGroups []AquaEnforcerNodeGroupStatus `json:"nodeGroups,omitempty"`
+	// RhcosVersions The OpenShift versions detected for the nodes of the enforcers, on OpenShift when rhcosVersion isn't set
+	RhcosVersions []AquaEnforcerRhcosVersion `json:"rhcosVersions,omitempty"`
+	// ContainerRuntimes The container runtimes detected on the nodes of the enforcers, when containerRuntime is set
+	ContainerRuntimes []AquaEnforcerContainerRuntimeStatus `json:"containerRuntimes,omitempty"`
+	// EnforcerGroup The enforcer group the token was fetched from, when enforcerGroup is set
+	EnforcerGroup *AquaEnforcerGroupTokenStatus `json:"enforcerGroup,omitempty"`
+}
+
+// AquaEnforcerGroupTokenStatus The enforcer group of the token secret
+type AquaEnforcerGroupTokenStatus struct {
+	Name string `json:"name"`
+	// RotateToken The rotateToken of the spec the token was last rotated for
+	RotateToken string `json:"rotateToken,omitempty"`
+	// LastRotation Time the operator last gave the group a new token
+	LastRotation *metav1.Time `json:"lastRotation,omitempty"`
+	// Created The operator created the group, the token of another group is only rotated with adopt
+	Created bool `json:"created,omitempty"`
+}
+
+// AquaEnforcerContainerRuntimeStatus The nodes of a node group running a container runtime
+type AquaEnforcerContainerRuntimeStatus struct {
+	// NodeGroup Name of the node group, empty for the enforcers of a CR without groups
+	NodeGroup string `json:"nodeGroup,omitempty"`
+	// Runtime containerd, cri-o, docker or unknown
+	Runtime   string `json:"runtime"`
+	DaemonSet string `json:"daemonSet"`
+	Nodes     int32  `json:"nodes"`
+}
+
+// AquaEnforcerRhcosVersion The OpenShift version of the RHCOS nodes of a node group
+type AquaEnforcerRhcosVersion struct {
+	// NodeGroup Name of the node group, empty for the enforcers of a CR without groups
+	NodeGroup string `json:"nodeGroup,omitempty"`
+	// Version The version written to AQUA_OCP_FULL_VERSION
+	Version string `json:"version"`
+	// OSImages The osImage of the RHCOS nodes of the group, more than one while the group is upgraded
+	OSImages []string `json:"osImages,omitempty"`
+	// UpdatingNodes Number of nodes the machine config operator is still updating, the version changes once it is 0
+	UpdatingNodes int32 `json:"updatingNodes,omitempty"`
 }
 
 //+kubebuilder:object:root=true
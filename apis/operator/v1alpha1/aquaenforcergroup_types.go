@@ -0,0 +1,92 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AquaEnforcerGroupSpec defines the desired state of AquaEnforcerGroup
+type AquaEnforcerGroupSpec struct {
+	AquaConsoleSync `json:",inline"`
+
+	// Name ID of the enforcer group in the console, the name of the CR when unset
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Type Type of the enforcers of the group, agent by default
+	// +kubebuilder:validation:Enum=agent;kube_enforcer;host_enforcer;micro_enforcer;nano_enforcer
+	Type string `json:"type,omitempty"`
+	// LogicalName Prefix of the names of the enforcers of the group in the console
+	LogicalName string `json:"logicalName,omitempty"`
+	// Gateways IDs of the gateways the enforcers of the group connect to
+	Gateways []string `json:"gateways,omitempty"`
+	// Enforce Enforce mode, audit only when false
+	Enforce bool `json:"enforce,omitempty"`
+	// Orchestrator The orchestrator the enforcers of the group are deployed by
+	Orchestrator *AquaEnforcerGroupOrchestrator `json:"orchestrator,omitempty"`
+	// Settings Other fields of the enforcer group in the Aqua API, e.g. {"syscall_enabled": true}, the fields of the
+	// spec take precedence
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Settings *runtime.RawExtension `json:"settings,omitempty"`
+}
+
+// AquaEnforcerGroupOrchestrator The orchestrator of the enforcers of an enforcer group
+type AquaEnforcerGroupOrchestrator struct {
+	// Type kubernetes, openshift, tanzu, ...
+	Type           string `json:"type"`
+	Namespace      string `json:"namespace,omitempty"`
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Server",type="string",JSONPath=".spec.server",description="Aqua Server"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="Aqua Enforcer Group sync status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Aqua Enforcer Group Age"
+
+// AquaEnforcerGroup is the Schema for the aquaenforcergroups API
+type AquaEnforcerGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AquaEnforcerGroupSpec `json:"spec,omitempty"`
+	Status AquaConsoleStatus     `json:"status,omitempty"`
+}
+
+// GetConsoleSync returns the server the enforcer group is synced into
+func (in *AquaEnforcerGroup) GetConsoleSync() *AquaConsoleSync {
+	return &in.Spec.AquaConsoleSync
+}
+
+// GetConsoleStatus returns the sync status of the enforcer group
+func (in *AquaEnforcerGroup) GetConsoleStatus() *AquaConsoleStatus {
+	return &in.Status
+}
+
+//+kubebuilder:object:root=true
+
+// AquaEnforcerGroupList contains a list of AquaEnforcerGroup
+type AquaEnforcerGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AquaEnforcerGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AquaEnforcerGroup{}, &AquaEnforcerGroupList{})
+}
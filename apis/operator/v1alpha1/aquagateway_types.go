@@ -39,14 +39,27 @@ type AquaGatewaySpec struct {
 	RunAsNonRoot   bool                     `json:"runAsNonRoot,omitempty"`
 	Route          bool                     `json:"route,omitempty"`
 	Mtls           bool                     `json:"mtls,omitempty"`
+	// Paused Stops reconciling the CR until unset
+	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindow The windows the disruptive changes wait for, they are applied at once when unset
+	MaintenanceWindow *AquaMaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // AquaGatewayStatus defines the observed state of AquaGateway
 type AquaGatewayStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	Nodes []string            `json:"nodes"`
-	State AquaDeploymentState `json:"state"`
+	Nodes             []string            `json:"nodes"`
+	State             AquaDeploymentState `json:"state"`
+	Images            []AquaImageStatus   `json:"images,omitempty"`
+	Version           string              `json:"version,omitempty"`
+	AvailableUpgrades []string            `json:"availableUpgrades,omitempty"`
+	Rollout           *AquaRolloutStatus  `json:"rollout,omitempty"`
+	Conditions        []metav1.Condition  `json:"conditions,omitempty"`
+	// Drifts The manual changes detected on the owned objects, see the drift policy
+	Drifts []AquaDrift `json:"drifts,omitempty"`
+	// Maintenance The disruptive changes waiting for the maintenance window
+	Maintenance *AquaMaintenanceStatus `json:"maintenance,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -28,29 +28,48 @@ import (
 // AquaKubeEnforcerSpec defines the desired state of AquaKubeEnforcer
 type AquaKubeEnforcerSpec struct {
 	// Other fields
-	Infrastructure         *AquaInfrastructure    `json:"infra,omitempty"`
-	Config                 AquaKubeEnforcerConfig `json:"config"`
-	Token                  string                 `json:"token,omitempty"`
-	RegistryData           *AquaDockerRegistry    `json:"registry,omitempty"`
-	ImageData              *AquaImage             `json:"image,omitempty"`
-	EnforcerUpdateApproved *bool                  `json:"updateEnforcer,omitempty"`
-	AllowAnyVersion        bool                   `json:"allowAnyVersion,omitempty"`
-	KubeEnforcerService    *AquaService           `json:"deploy,omitempty"`
-	Envs                   []corev1.EnvVar        `json:"env,omitempty"`
-	Mtls                   bool                   `json:"mtls,omitempty"`
-	DeployStarboard        *AquaStarboardDetails  `json:"starboard,omitempty"`
-	ConfigMapChecksum      string                 `json:"config_map_checksum,omitempty"`
+	Infrastructure *AquaInfrastructure    `json:"infra,omitempty"`
+	Config         AquaKubeEnforcerConfig `json:"config"`
+	// Deprecated: moved into the tokenSecretRef secret by the operator
+	Token                  string                `json:"token,omitempty"`
+	TokenSecretRef         *AquaSecret           `json:"tokenSecretRef,omitempty"`
+	RegistryData           *AquaDockerRegistry   `json:"registry,omitempty"`
+	ImageData              *AquaImage            `json:"image,omitempty"`
+	EnforcerUpdateApproved *bool                 `json:"updateEnforcer,omitempty"`
+	AllowAnyVersion        bool                  `json:"allowAnyVersion,omitempty"`
+	KubeEnforcerService    *AquaService          `json:"deploy,omitempty"`
+	Envs                   []corev1.EnvVar       `json:"env,omitempty"`
+	Mtls                   bool                  `json:"mtls,omitempty"`
+	DeployStarboard        *AquaStarboardDetails `json:"starboard,omitempty"`
+	ConfigMapChecksum      string                `json:"config_map_checksum,omitempty"`
 
 	// Add the new fields here
 	ValidatingWebhookTimeout int `json:"validatingWebhookTimeout,omitempty"`
 	MutatingWebhookTimeout   int `json:"mutatingWebhookTimeout,omitempty"`
+	// ImageVerification Pins the images to their digests, and verifies their signatures when a key is set
+	ImageVerification *AquaImageVerification `json:"imageVerification,omitempty"`
+	// DriftPolicy How manual changes to the kube-enforcer deployment and configmap are handled
+	DriftPolicy *AquaDriftPolicy `json:"driftPolicy,omitempty"`
+	// Paused Stops reconciling the CR until unset
+	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindow The windows the disruptive changes wait for, they are applied at once when unset
+	MaintenanceWindow *AquaMaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // AquaKubeEnforcerStatus defines the observed state of AquaKubeEnforcer
 type AquaKubeEnforcerStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	State AquaDeploymentState `json:"state"`
+	State             AquaDeploymentState `json:"state"`
+	Images            []AquaImageStatus   `json:"images,omitempty"`
+	Version           string              `json:"version,omitempty"`
+	AvailableUpgrades []string            `json:"availableUpgrades,omitempty"`
+	// Drifts The manual changes detected on the owned objects, see the drift policy
+	Drifts []AquaDrift `json:"drifts,omitempty"`
+	// Maintenance The disruptive changes waiting for the maintenance window
+	Maintenance *AquaMaintenanceStatus `json:"maintenance,omitempty"`
+	// Conditions The Ready condition aggregates the readiness of the kube-enforcer objects
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,84 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AquaRegistryIntegrationSpec defines the desired state of AquaRegistryIntegration
+type AquaRegistryIntegrationSpec struct {
+	AquaConsoleSync `json:",inline"`
+
+	// Name Name of the registry in the console, the name of the CR when unset
+	Name string `json:"name,omitempty"`
+	// Type Type of the registry in the Aqua API, e.g. HUB, V2, ACR, ECR, GCR, GAR, JFROG, QUAY or HARBOR
+	Type string `json:"type"`
+	// URL Address of the registry, unset for Docker Hub
+	URL      string `json:"url,omitempty"`
+	Username string `json:"username,omitempty"`
+	// PasswordSecretRef Secret with the password or the token of the registry
+	PasswordSecretRef *AquaSecret `json:"passwordSecretRef,omitempty"`
+	// Prefixes Prefixes of the image names the registry is used for
+	Prefixes []string `json:"prefixes,omitempty"`
+	// AutoPull Pulls and scans the images of the registry
+	AutoPull bool `json:"autoPull,omitempty"`
+	// Settings Other fields of the registry in the Aqua API, e.g. {"auto_pull_max": 100}, the fields of the spec
+	// take precedence
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Settings *runtime.RawExtension `json:"settings,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Server",type="string",JSONPath=".spec.server",description="Aqua Server"
+//+kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type",description="Registry Type"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="Aqua Registry Integration sync status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Aqua Registry Integration Age"
+
+// AquaRegistryIntegration is the Schema for the aquaregistryintegrations API
+type AquaRegistryIntegration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AquaRegistryIntegrationSpec `json:"spec,omitempty"`
+	Status AquaConsoleStatus           `json:"status,omitempty"`
+}
+
+// GetConsoleSync returns the server the registry is synced into
+func (in *AquaRegistryIntegration) GetConsoleSync() *AquaConsoleSync {
+	return &in.Spec.AquaConsoleSync
+}
+
+// GetConsoleStatus returns the sync status of the registry
+func (in *AquaRegistryIntegration) GetConsoleStatus() *AquaConsoleStatus {
+	return &in.Status
+}
+
+//+kubebuilder:object:root=true
+
+// AquaRegistryIntegrationList contains a list of AquaRegistryIntegration
+type AquaRegistryIntegrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AquaRegistryIntegration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AquaRegistryIntegration{}, &AquaRegistryIntegrationList{})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AquaRuntimePolicySpec defines the desired state of AquaRuntimePolicy
+type AquaRuntimePolicySpec struct {
+	AquaConsoleSync `json:",inline"`
+
+	// Name Name of the runtime policy in the console, the name of the CR when unset
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Enabled The policy is applied, true by default
+	Enabled *bool `json:"enabled,omitempty"`
+	// Enforce Blocks what the controls deny, audit only when false
+	Enforce bool `json:"enforce,omitempty"`
+	// Scope The workloads the policy applies to, all of them when unset
+	Scope *AquaRuntimePolicyScope `json:"scope,omitempty"`
+	// Controls The controls of the policy in the Aqua API, e.g. {"drift_prevention": {"enabled": true}}, the fields
+	// of the spec take precedence
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Controls *runtime.RawExtension `json:"controls,omitempty"`
+}
+
+// AquaRuntimePolicyScope The workloads a runtime policy applies to
+type AquaRuntimePolicyScope struct {
+	// Expression Boolean expression of the variables, e.g. v1 && v2
+	Expression string                           `json:"expression"`
+	Variables  []AquaRuntimePolicyScopeVariable `json:"variables"`
+}
+
+// AquaRuntimePolicyScopeVariable A variable of the scope of a runtime policy
+type AquaRuntimePolicyScopeVariable struct {
+	// Attribute e.g. kubernetes.namespace, kubernetes.cluster or image.name
+	Attribute string `json:"attribute"`
+	Value     string `json:"value"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Server",type="string",JSONPath=".spec.server",description="Aqua Server"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="Aqua Runtime Policy sync status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Aqua Runtime Policy Age"
+
+// AquaRuntimePolicy is the Schema for the aquaruntimepolicies API
+type AquaRuntimePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AquaRuntimePolicySpec `json:"spec,omitempty"`
+	Status AquaConsoleStatus     `json:"status,omitempty"`
+}
+
+// GetConsoleSync returns the server the runtime policy is synced into
+func (in *AquaRuntimePolicy) GetConsoleSync() *AquaConsoleSync {
+	return &in.Spec.AquaConsoleSync
+}
+
+// GetConsoleStatus returns the sync status of the runtime policy
+func (in *AquaRuntimePolicy) GetConsoleStatus() *AquaConsoleStatus {
+	return &in.Status
+}
+
+//+kubebuilder:object:root=true
+
+// AquaRuntimePolicyList contains a list of AquaRuntimePolicy
+type AquaRuntimePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AquaRuntimePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AquaRuntimePolicy{}, &AquaRuntimePolicyList{})
+}
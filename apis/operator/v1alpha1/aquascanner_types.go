@@ -35,14 +35,25 @@ type AquaScannerSpec struct {
 	Login             *AquaLogin   `json:"login,required"`
 	RunAsNonRoot      bool         `json:"runAsNonRoot,omitempty"`
 	ConfigMapChecksum string       `json:"config_map_checksum,omitempty"`
+	// Paused Stops reconciling the CR until unset
+	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindow The windows the disruptive changes wait for, they are applied at once when unset
+	MaintenanceWindow *AquaMaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // AquaScannerStatus defines the observed state of AquaScanner
 type AquaScannerStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	Nodes []string            `json:"nodes"`
-	State AquaDeploymentState `json:"state"`
+	Nodes             []string            `json:"nodes"`
+	State             AquaDeploymentState `json:"state"`
+	Images            []AquaImageStatus   `json:"images,omitempty"`
+	Version           string              `json:"version,omitempty"`
+	AvailableUpgrades []string            `json:"availableUpgrades,omitempty"`
+	// Drifts The manual changes detected on the owned objects, see the drift policy
+	Drifts []AquaDrift `json:"drifts,omitempty"`
+	// Maintenance The disruptive changes waiting for the maintenance window
+	Maintenance *AquaMaintenanceStatus `json:"maintenance,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -32,26 +32,41 @@ type AquaServerSpec struct {
 	Infrastructure *AquaInfrastructure `json:"infra"`
 	Common         *AquaCommon         `json:"common"`
 
-	ServerService     *AquaService             `json:"deploy,required"`
-	ExternalDb        *AquaDatabaseInformation `json:"externalDb,omitempty"`
-	AuditDB           *AuditDBInformation      `json:"auditDB,omitempty"`
-	LicenseToken      string                   `json:"licenseToken,omitempty"`
-	AdminPassword     string                   `json:"adminPassword,omitempty"`
-	Enforcer          *AquaEnforcerDetailes    `json:"enforcer,omitempty"`
-	Envs              []corev1.EnvVar          `json:"env,omitempty"`
-	ConfigMapData     map[string]string        `json:"configMapData,omitempty"`
-	RunAsNonRoot      bool                     `json:"runAsNonRoot,omitempty"`
-	Route             bool                     `json:"route,omitempty"`
-	Mtls              bool                     `json:"mtls,omitempty"`
-	ConfigMapChecksum string                   `json:"config_map_checksum,omitempty"`
+	ServerService *AquaService             `json:"deploy,required"`
+	ExternalDb    *AquaDatabaseInformation `json:"externalDb,omitempty"`
+	AuditDB       *AuditDBInformation      `json:"auditDB,omitempty"`
+	// Deprecated: moved into the common.license secret by the operator
+	LicenseToken string `json:"licenseToken,omitempty"`
+	// Deprecated: moved into the common.adminPassword secret by the operator
+	AdminPassword     string                `json:"adminPassword,omitempty"`
+	Enforcer          *AquaEnforcerDetailes `json:"enforcer,omitempty"`
+	Envs              []corev1.EnvVar       `json:"env,omitempty"`
+	ConfigMapData     map[string]string     `json:"configMapData,omitempty"`
+	RunAsNonRoot      bool                  `json:"runAsNonRoot,omitempty"`
+	Route             bool                  `json:"route,omitempty"`
+	Mtls              bool                  `json:"mtls,omitempty"`
+	ConfigMapChecksum string                `json:"config_map_checksum,omitempty"`
+	// Paused Stops reconciling the CR until unset
+	Paused bool `json:"paused,omitempty"`
+	// MaintenanceWindow The windows the disruptive changes wait for, they are applied at once when unset
+	MaintenanceWindow *AquaMaintenanceWindow `json:"maintenanceWindow,omitempty"`
 }
 
 // AquaServerStatus defines the observed state of AquaServer
 type AquaServerStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	Nodes []string            `json:"nodes"`
-	State AquaDeploymentState `json:"state"`
+	Nodes             []string            `json:"nodes"`
+	State             AquaDeploymentState `json:"state"`
+	Images            []AquaImageStatus   `json:"images,omitempty"`
+	Version           string              `json:"version,omitempty"`
+	AvailableUpgrades []string            `json:"availableUpgrades,omitempty"`
+	Rollout           *AquaRolloutStatus  `json:"rollout,omitempty"`
+	Conditions        []metav1.Condition  `json:"conditions,omitempty"`
+	// Drifts The manual changes detected on the owned objects, see the drift policy
+	Drifts []AquaDrift `json:"drifts,omitempty"`
+	// Maintenance The disruptive changes waiting for the maintenance window
+	Maintenance *AquaMaintenanceStatus `json:"maintenance,omitempty"`
 }
 
 //+kubebuilder:object:root=true
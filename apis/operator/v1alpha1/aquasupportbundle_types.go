@@ -0,0 +1,83 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AquaSupportBundleSpec defines the desired state of AquaSupportBundle
+type AquaSupportBundleSpec struct {
+	// LogSince Age of the oldest pod log lines collected, 24h by default
+	LogSince string `json:"logSince,omitempty"`
+	// TailLines Maximum number of log lines collected per container, 2000 by default
+	TailLines int64 `json:"tailLines,omitempty"`
+	// SecretName Prefix of the secrets holding the bundle, <name>-support-bundle by default
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// AquaSupportBundleState The phase of a support bundle collection
+type AquaSupportBundleState string
+
+const (
+	// AquaSupportBundleStateCompleted The bundle is stored in the secrets of the status
+	AquaSupportBundleStateCompleted AquaSupportBundleState = "Completed"
+
+	// AquaSupportBundleStateFailed The bundle couldn't be collected, see the message
+	AquaSupportBundleStateFailed AquaSupportBundleState = "Failed"
+)
+
+// AquaSupportBundleStatus defines the observed state of AquaSupportBundle
+type AquaSupportBundleStatus struct {
+	State   AquaSupportBundleState `json:"state,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	// Secrets The secrets holding the parts of the gzipped tarball, in order
+	Secrets []string `json:"secrets,omitempty"`
+	// Size Size of the gzipped tarball in bytes
+	Size           int64        `json:"size,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Errors The parts of the bundle that couldn't be collected
+	Errors []string `json:"errors,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="Aqua Support Bundle status"
+//+kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".status.size",description="Bundle Size In Bytes"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Aqua Support Bundle Age"
+
+// AquaSupportBundle is the Schema for the aquasupportbundles API
+type AquaSupportBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AquaSupportBundleSpec   `json:"spec,omitempty"`
+	Status AquaSupportBundleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AquaSupportBundleList contains a list of AquaSupportBundle
+type AquaSupportBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AquaSupportBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AquaSupportBundle{}, &AquaSupportBundleList{})
+}
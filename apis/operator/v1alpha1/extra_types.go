@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type AquaInfrastructure struct {
@@ -23,20 +24,43 @@ type AquaCommon struct {
 	DbDiskSize         int         `json:"dbDiskSize,omitempty"`
 	SplitDB            bool        `json:"splitDB,omitempty"`
 	AllowAnyVersion    bool        `json:"allowAnyVersion,omitempty"`
+	// SecretStore External store the credentials are read from instead of the plaintext spec fields
+	SecretStore *AquaSecretStore `json:"secretStore,omitempty"`
+	// ImageVerification Pins the images to their digests, and verifies their signatures when a key is set
+	ImageVerification *AquaImageVerification `json:"imageVerification,omitempty"`
+	// DriftPolicy How manual changes to the workloads and configmaps the operator owns are handled
+	DriftPolicy *AquaDriftPolicy `json:"driftPolicy,omitempty"`
+}
+
+// AquaImageVerification resolves the image tags to digests through the registry before rollout
+type AquaImageVerification struct {
+	// CosignPublicKey Secret with a PEM public key, a new image without a matching cosign signature is not rolled out
+	CosignPublicKey *AquaSecret `json:"cosignPublicKey,omitempty"`
+}
+
+// AquaImageStatus the digest an image tag was pinned to
+type AquaImageStatus struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest"`
+	// VerifiedKey Checksum of the public key the signature was verified with
+	VerifiedKey string `json:"verifiedKey,omitempty"`
 }
 
 type AquaDockerRegistry struct {
 	URL      string `json:"url"`
 	Username string `json:"username"`
-	Password string `json:"password"`
-	Email    string `json:"email"`
+	// Deprecated: moved into the passwordSecretRef secret by the operator
+	Password          string      `json:"password,omitempty"`
+	PasswordSecretRef *AquaSecret `json:"passwordSecretRef,omitempty"`
+	Email             string      `json:"email"`
 }
 
 type AquaDatabaseInformation struct {
 	Host     string `json:"host"`
 	Port     int64  `json:"port"`
 	Username string `json:"username"`
-	Password string `json:"password"`
+	// Deprecated: moved by the operator into common.databaseSecret, or auditDB.secret for the audit database
+	Password string `json:"password,omitempty"`
 }
 
 type AquaSecret struct {
@@ -44,6 +68,48 @@ type AquaSecret struct {
 	Key  string `json:"key"`
 }
 
+// AquaSecretStore External store holding the aqua credentials, the operator copies the values
+// into the secrets used by the workloads and reads them again every refresh interval
+type AquaSecretStore struct {
+	Vault *AquaVaultSecretStore `json:"vault,omitempty"`
+	File  *AquaFileSecretStore  `json:"file,omitempty"`
+	// RefreshInterval How often the values are read from the store, defaults to 1h
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+	// Keys The store keys of the credentials, credentials without a key are not managed by the store
+	Keys AquaSecretStoreKeys `json:"keys,omitempty"`
+}
+
+// AquaVaultSecretStore Vault compatible KV HTTP API
+type AquaVaultSecretStore struct {
+	Address string `json:"address"`
+	// Path of the KV secret, for example secret/data/aqua for a KV version 2 engine
+	Path      string `json:"path"`
+	Namespace string `json:"namespace,omitempty"`
+	// TokenSecret Secret holding the token sent to the store
+	TokenSecret *AquaSecret `json:"tokenSecret"`
+	Insecure    bool        `json:"tlsNoVerify,omitempty"`
+}
+
+// AquaFileSecretStore Directory mounted into the operator pod, for example by the secrets store CSI driver,
+// with a file per key
+type AquaFileSecretStore struct {
+	// Path Directory of the files, relative to the secret store root of the operator, see SECRET_STORE_ROOT
+	Path string `json:"path"`
+}
+
+type AquaSecretStoreKeys struct {
+	AdminPassword string `json:"adminPassword,omitempty"`
+	LicenseToken  string `json:"licenseToken,omitempty"`
+	// DbPassword Password of the external database
+	DbPassword       string `json:"dbPassword,omitempty"`
+	AuditDbPassword  string `json:"auditDbPassword,omitempty"`
+	EnforcerToken    string `json:"enforcerToken,omitempty"`
+	ScannerUsername  string `json:"scannerUsername,omitempty"`
+	ScannerPassword  string `json:"scannerPassword,omitempty"`
+	RegistryUsername string `json:"registryUsername,omitempty"`
+	RegistryPassword string `json:"registryPassword,omitempty"`
+}
+
 type AquaImage struct {
 	Repository string `json:"repository"`
 	Registry   string `json:"registry"`
@@ -65,6 +131,91 @@ type AquaService struct {
 	Tolerations    []corev1.Toleration          `json:"tolerations,omitempty"`
 	VolumeMounts   []corev1.VolumeMount         `json:"volumeMounts,omitempty"`
 	Volumes        []corev1.Volume              `json:"volumes,omitempty"`
+	// Rollout Revision history and automatic rollback of the server and gateway deployments
+	Rollout *AquaRolloutStrategy `json:"rollout,omitempty"`
+}
+
+// AquaRolloutStrategy Keeps the rendered deployment revisions and rolls back a rollout that doesn't become ready
+type AquaRolloutStrategy struct {
+	// ProgressDeadlineSeconds Seconds a rollout has to become ready before it is rolled back, defaults to 600
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+	// RevisionHistoryLimit Number of rendered revisions kept, defaults to 10
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// DisableAutoRollback Keeps a rollout that missed its deadline instead of rolling back to the last healthy revision
+	DisableAutoRollback bool `json:"disableAutoRollback,omitempty"`
+}
+
+// AquaRolloutStatus The revisions of the rendered deployment
+type AquaRolloutStatus struct {
+	// Revision The revision the deployment runs
+	Revision int64 `json:"revision,omitempty"`
+	// LastHealthyRevision The last revision that became ready
+	LastHealthyRevision int64 `json:"lastHealthyRevision,omitempty"`
+	// RolledBackFrom Hash of the rendering rolled back from, it is applied again only once the spec changes
+	RolledBackFrom string `json:"rolledBackFrom,omitempty"`
+}
+
+// AquaDriftAction What is done with a manual change to an owned object
+type AquaDriftAction string
+
+const (
+	// AquaDriftActionEnforce Reverts the change, and records it
+	AquaDriftActionEnforce AquaDriftAction = "Enforce"
+
+	// AquaDriftActionReport Keeps the change, and records it
+	AquaDriftActionReport AquaDriftAction = "Report"
+
+	// AquaDriftActionIgnore Keeps the change silently
+	AquaDriftActionIgnore AquaDriftAction = "Ignore"
+)
+
+// AquaDriftPolicy Decides what is done with the manual changes to the owned objects, field by field
+type AquaDriftPolicy struct {
+	// Default Action for the changes no rule matches, Enforce by default
+	Default AquaDriftAction `json:"default,omitempty"`
+	// Rules The first rule matching a changed field decides its action
+	Rules []AquaDriftRule `json:"rules,omitempty"`
+}
+
+// AquaDriftRule Matches the changed fields by object and JSON pointer
+type AquaDriftRule struct {
+	// Kind Kind of the object, any kind when empty
+	Kind string `json:"kind,omitempty"`
+	// Name Name of the object, any name when empty
+	Name string `json:"name,omitempty"`
+	// Paths JSON pointers of the fields, a pointer matches the fields under it, all the fields when empty
+	Paths  []string        `json:"paths,omitempty"`
+	Action AquaDriftAction `json:"action"`
+}
+
+// AquaDrift A manual change detected on an owned object
+type AquaDrift struct {
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name"`
+	Action AquaDriftAction `json:"action"`
+	// Patch JSON patch from the rendering the operator applied to the live object
+	Patch      string      `json:"patch"`
+	DetectedAt metav1.Time `json:"detectedAt"`
+}
+
+// AquaMaintenanceWindow The recurring windows the disruptive changes are applied in. DaemonSet updates,
+// deployment image changes, database restarts and certificate rotations wait for the next window
+type AquaMaintenanceWindow struct {
+	// Schedule Cron expression of the window starts: minute hour day-of-month month day-of-week
+	Schedule string `json:"schedule"`
+	// Duration How long each window stays open, e.g. 2h
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone IANA name of the time zone of the schedule, UTC by default
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// AquaMaintenanceStatus The disruptive changes held back until the maintenance window opens
+type AquaMaintenanceStatus struct {
+	// Deferred The changes waiting for the window
+	Deferred []string `json:"deferred,omitempty"`
+	// DeferredUntil When the next window opens
+	DeferredUntil *metav1.Time `json:"deferredUntil,omitempty"`
+	Message       string       `json:"message,omitempty"`
 }
 
 type AquaGatewayInformation struct {
@@ -74,10 +225,14 @@ type AquaGatewayInformation struct {
 
 type AquaLogin struct {
 	Username string `json:"username"`
-	Password string `json:"password"`
-	Host     string `json:"host"`
-	Token    string `json:"token"`
-	Insecure bool   `json:"tlsNoVerify"`
+	// Deprecated: moved into the passwordSecretRef secret by the operator
+	Password          string      `json:"password,omitempty"`
+	PasswordSecretRef *AquaSecret `json:"passwordSecretRef,omitempty"`
+	Host              string      `json:"host"`
+	// Deprecated: moved into the tokenSecretRef secret by the operator
+	Token          string      `json:"token,omitempty"`
+	TokenSecretRef *AquaSecret `json:"tokenSecretRef,omitempty"`
+	Insecure       bool        `json:"tlsNoVerify"`
 }
 
 type AquaScannerCliScale struct {
@@ -120,6 +275,14 @@ const (
 	AquaEnforcerWaiting AquaDeploymentState = "Waiting For Enforcers to Start"
 )
 
+const (
+	// AquaConditionDegraded The workload doesn't run the rendering of the current spec
+	AquaConditionDegraded = "Degraded"
+
+	// AquaConditionReady The objects of the CR are created, up to date and ready
+	AquaConditionReady = "Ready"
+)
+
 type AquaKubeEnforcerConfig struct {
 	GatewayAddress  string `json:"gateway_address,omitempty"`
 	ClusterName     string `json:"cluster_name,omitempty"`
@@ -131,6 +294,85 @@ type AquaKubeEnforcerDetails struct {
 	Registry string `json:"registry,omitempty"`
 }
 
+// AquaStarboardScanner The operator producing the security reports the KubeEnforcer watches
+type AquaStarboardScanner string
+
+const (
+	// AquaStarboardScannerStarboard Deploys starboard-operator, the default. Starboard is end of life
+	AquaStarboardScannerStarboard AquaStarboardScanner = "starboard"
+
+	// AquaStarboardScannerTrivyOperator Deploys trivy-operator, the successor of starboard
+	AquaStarboardScannerTrivyOperator AquaStarboardScanner = "trivy-operator"
+)
+
+// AquaStarboardSettings Typed settings of starboard or trivy-operator and of its scan jobs. A setting set here takes
+// precedence over the string field of the same name in the spec
+type AquaStarboardSettings struct {
+	// ConcurrentScanJobsLimit Maximum number of scan jobs running at once
+	// +kubebuilder:validation:Minimum=1
+	ConcurrentScanJobsLimit *int32 `json:"concurrentScanJobsLimit,omitempty"`
+	// ScanJobRetryAfter Time to wait before retrying a failed scan job, e.g. 30s
+	ScanJobRetryAfter *metav1.Duration `json:"scanJobRetryAfter,omitempty"`
+	// CisKubernetesBenchmarkEnabled Runs the CIS Kubernetes benchmark on the nodes, starboard only
+	CisKubernetesBenchmarkEnabled *bool `json:"cisKubernetesBenchmarkEnabled,omitempty"`
+	// VulnerabilityScannerEnabled Scans the workload images for vulnerabilities
+	VulnerabilityScannerEnabled *bool `json:"vulnerabilityScannerEnabled,omitempty"`
+	// ClusterComplianceEnabled Generates the cluster compliance reports
+	ClusterComplianceEnabled *bool `json:"clusterComplianceEnabled,omitempty"`
+	// BatchDeleteLimit Maximum number of reports deleted at once
+	// +kubebuilder:validation:Minimum=1
+	BatchDeleteLimit *int32 `json:"batchDeleteLimit,omitempty"`
+	// BatchDeleteDelay Time to wait between batches of deleted reports, e.g. 10s
+	BatchDeleteDelay *metav1.Duration `json:"batchDeleteDelay,omitempty"`
+	// ConfigAuditScanner Scanner of the config audit reports, Conftest by default. trivy-operator always uses Trivy
+	// +kubebuilder:validation:Enum=Conftest;Polaris;Trivy
+	ConfigAuditScanner string `json:"configAuditScanner,omitempty"`
+	// TargetNamespaces Namespaces scanned, all namespaces when empty
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// ExcludeNamespaces Namespaces not scanned, kube-system when empty
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+	// ScanJobTolerations Tolerations of the scan jobs
+	ScanJobTolerations []corev1.Toleration `json:"scanJobTolerations,omitempty"`
+	// ScanJobResources Resources of the scanner container of the scan jobs
+	ScanJobResources *corev1.ResourceRequirements `json:"scanJobResources,omitempty"`
+}
+
+// AquaStarboardPolicies Rego policies of the conftest config audit scanner of starboard, assembled in the
+// starboard-policies-config ConfigMap
+type AquaStarboardPolicies struct {
+	// DefaultBundle Adds the policies shipped with the operator
+	DefaultBundle bool `json:"defaultBundle,omitempty"`
+	// Modules Inline Rego policies and libraries
+	Modules []AquaStarboardRegoModule `json:"modules,omitempty"`
+	// ConfigMaps ConfigMaps in the namespace of the CR whose .rego keys are added as policies or libraries, named
+	// after the key
+	ConfigMaps []AquaStarboardPolicyConfigMap `json:"configMaps,omitempty"`
+}
+
+// AquaStarboardRegoModule An inline Rego policy or library
+type AquaStarboardRegoModule struct {
+	// Name Name of the policy or library in the policies ConfigMap
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]+$`
+	Name string `json:"name"`
+	// Rego Source of the module
+	// +kubebuilder:validation:MinLength=1
+	Rego string `json:"rego"`
+	// Library Makes the module a library imported by the policies instead of a policy
+	Library bool `json:"library,omitempty"`
+	// Kinds Kinds of the objects the policy checks, Workload when empty. Not used for a library
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// AquaStarboardPolicyConfigMap A ConfigMap of Rego policies or libraries
+type AquaStarboardPolicyConfigMap struct {
+	// Name Name of the ConfigMap
+	Name string `json:"name"`
+	// Library Makes the modules of the ConfigMap libraries instead of policies
+	Library bool `json:"library,omitempty"`
+	// Kinds Kinds of the objects the policies check, Workload when empty
+	Kinds []string `json:"kinds,omitempty"`
+}
+
 type AquaStarboardConfig struct {
 	ImagePullSecret string `json:"imagePullSecret,omitempty"`
 }
@@ -144,18 +386,74 @@ type AquaStarboardDetails struct {
 	ImageData                     *AquaImage          `json:"image,omitempty"`
 	Envs                          []corev1.EnvVar     `json:"env,omitempty"`
 	LogDevMode                    bool                `json:"logDevMode,omitempty"`
-	ConcurrentScanJobsLimit       string              `json:"concurrentScanJobsLimit,omitempty"`
-	ScanJobRetryAfter             string              `json:"scanJobRetryAfter,omitempty"`
+	ConcurrentScanJobsLimit       string              `json:"concurrentScanJobsLimit,omitempty"` // Deprecated: use settings.concurrentScanJobsLimit
+	ScanJobRetryAfter             string              `json:"scanJobRetryAfter,omitempty"`       // Deprecated: use settings.scanJobRetryAfter
 	MetricsBindAddress            string              `json:"metricsBindAddress,omitempty"`
 	HealthProbeBindAddress        string              `json:"healthProbeBindAddress,omitempty"`
-	CisKubernetesBenchmarkEnabled string              `json:"cisKubernetesBenchmarkEnabled,omitempty"`
-	VulnerabilityScannerEnabled   string              `json:"vulnerabilityScannerEnabled,omitempty"`
-	BatchDeleteLimit              string              `json:"batchDeleteLimit,omitempty"`
-	BatchDeleteDelay              string              `json:"batchDeleteDelay,omitempty"`
+	CisKubernetesBenchmarkEnabled string              `json:"cisKubernetesBenchmarkEnabled,omitempty"` // Deprecated: use settings.cisKubernetesBenchmarkEnabled
+	VulnerabilityScannerEnabled   string              `json:"vulnerabilityScannerEnabled,omitempty"`   // Deprecated: use settings.vulnerabilityScannerEnabled
+	BatchDeleteLimit              string              `json:"batchDeleteLimit,omitempty"`              // Deprecated: use settings.batchDeleteLimit
+	BatchDeleteDelay              string              `json:"batchDeleteDelay,omitempty"`              // Deprecated: use settings.batchDeleteDelay
 	ImageTag                      string              `json:"tag,omitempty"`
+	// Scanner The operator deployed for the reports, starboard or trivy-operator
+	Scanner AquaStarboardScanner `json:"scanner,omitempty"`
+	// Settings Typed settings of the scanner, in place of the string fields
+	Settings *AquaStarboardSettings `json:"settings,omitempty"`
+	// Policies Rego policies of the starboard config audit scanner
+	Policies *AquaStarboardPolicies `json:"policies,omitempty"`
 }
 
 type AuditDBInformation struct {
 	AuditDBSecret *AquaSecret              `json:"secret,omitempty"`
 	Data          *AquaDatabaseInformation `json:"information,omitempty"`
 }
+
+// AquaConsoleSync The Aqua server an object of the console is synced into, and how its changes in the console are
+// handled
+type AquaConsoleSync struct {
+	// Server Name of the AquaServer of the namespace, its API is called with its administrator password
+	Server string `json:"server"`
+	// DriftPolicy How the changes made to the object in the console are handled, they are reverted by default. The
+	// rules match the kind of the CR and the name of the object in the console
+	DriftPolicy *AquaDriftPolicy `json:"driftPolicy,omitempty"`
+	// DeletionPolicy Delete removes the object from the console with the CR, Orphan keeps it. By default an object the
+	// operator created is deleted and an adopted one is kept
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// Adopt Syncs the CR into an object of the same name already in the console, the sync fails otherwise
+	Adopt bool `json:"adopt,omitempty"`
+	// SyncInterval How often the object is compared with the console, 10m by default
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty"`
+	// Paused Stops syncing the object until unset
+	Paused bool `json:"paused,omitempty"`
+}
+
+// AquaConsoleState The sync of an object of the console
+type AquaConsoleState string
+
+const (
+	// AquaConsoleStateSynced The object of the console matches the spec
+	AquaConsoleStateSynced AquaConsoleState = "Synced"
+
+	// AquaConsoleStateDrifted The object of the console has changes the drift policy keeps
+	AquaConsoleStateDrifted AquaConsoleState = "Drifted"
+
+	// AquaConsoleStateFailed The object couldn't be synced, see the message
+	AquaConsoleStateFailed AquaConsoleState = "Failed"
+)
+
+// AquaConsoleStatus The sync of an object of the console
+type AquaConsoleStatus struct {
+	State   AquaConsoleState `json:"state,omitempty"`
+	Message string           `json:"message,omitempty"`
+	// Name Name of the object in the console
+	Name string `json:"name,omitempty"`
+	// Created The operator created the object in the console, it wasn't adopted
+	Created bool `json:"created,omitempty"`
+	// Hash Hash of the object last written to the console, a new one is written when the spec or its secrets change
+	Hash               string       `json:"hash,omitempty"`
+	ObservedGeneration int64        `json:"observedGeneration,omitempty"`
+	LastSync           *metav1.Time `json:"lastSync,omitempty"`
+	// Drifts The changes made in the console, see the drift policy
+	Drifts []AquaDrift `json:"drifts,omitempty"`
+}
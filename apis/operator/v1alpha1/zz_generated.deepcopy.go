@@ -23,6 +23,7 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -44,6 +45,21 @@ func (in *AquaCommon) DeepCopyInto(out *AquaCommon) {
 		*out = new(AquaSecret)
 		**out = **in
 	}
+	if in.SecretStore != nil {
+		in, out := &in.SecretStore, &out.SecretStore
+		*out = new(AquaSecretStore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(AquaImageVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftPolicy != nil {
+		in, out := &in.DriftPolicy, &out.DriftPolicy
+		*out = new(AquaDriftPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaCommon.
@@ -56,13 +72,64 @@ func (in *AquaCommon) DeepCopy() *AquaCommon {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaConsoleStatus) DeepCopyInto(out *AquaConsoleStatus) {
+	*out = *in
+	if in.LastSync != nil {
+		in, out := &in.LastSync, &out.LastSync
+		*out = (*in).DeepCopy()
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]AquaDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaConsoleStatus.
+func (in *AquaConsoleStatus) DeepCopy() *AquaConsoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaConsoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaConsoleSync) DeepCopyInto(out *AquaConsoleSync) {
+	*out = *in
+	if in.DriftPolicy != nil {
+		in, out := &in.DriftPolicy, &out.DriftPolicy
+		*out = new(AquaDriftPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncInterval != nil {
+		in, out := &in.SyncInterval, &out.SyncInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaConsoleSync.
+func (in *AquaConsoleSync) DeepCopy() *AquaConsoleSync {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaConsoleSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AquaCsp) DeepCopyInto(out *AquaCsp) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaCsp.
@@ -131,7 +198,7 @@ func (in *AquaCspSpec) DeepCopyInto(out *AquaCspSpec) {
 	if in.RegistryData != nil {
 		in, out := &in.RegistryData, &out.RegistryData
 		*out = new(AquaDockerRegistry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ExternalDb != nil {
 		in, out := &in.ExternalDb, &out.ExternalDb
@@ -194,6 +261,16 @@ func (in *AquaCspSpec) DeepCopyInto(out *AquaCspSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(AquaCspUpgradeStrategy)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(AquaMaintenanceWindow)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaCspSpec.
@@ -209,6 +286,16 @@ func (in *AquaCspSpec) DeepCopy() *AquaCspSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AquaCspStatus) DeepCopyInto(out *AquaCspStatus) {
 	*out = *in
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(AquaCspUpgradeStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaCspStatus.
@@ -221,6 +308,36 @@ func (in *AquaCspStatus) DeepCopy() *AquaCspStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaCspUpgradeStatus) DeepCopyInto(out *AquaCspUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaCspUpgradeStatus.
+func (in *AquaCspUpgradeStatus) DeepCopy() *AquaCspUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaCspUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaCspUpgradeStrategy) DeepCopyInto(out *AquaCspUpgradeStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaCspUpgradeStrategy.
+func (in *AquaCspUpgradeStrategy) DeepCopy() *AquaCspUpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaCspUpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AquaDatabase) DeepCopyInto(out *AquaDatabase) {
 	*out = *in
@@ -318,6 +435,11 @@ func (in *AquaDatabaseSpec) DeepCopyInto(out *AquaDatabaseSpec) {
 		*out = new(AuditDBInformation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(AquaMaintenanceWindow)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaDatabaseSpec.
@@ -338,6 +460,26 @@ func (in *AquaDatabaseStatus) DeepCopyInto(out *AquaDatabaseStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = make([]AquaDatabaseStorageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]AquaImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(AquaMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaDatabaseStatus.
@@ -350,9 +492,29 @@ func (in *AquaDatabaseStatus) DeepCopy() *AquaDatabaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaDatabaseStorageStatus) DeepCopyInto(out *AquaDatabaseStorageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaDatabaseStorageStatus.
+func (in *AquaDatabaseStorageStatus) DeepCopy() *AquaDatabaseStorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaDatabaseStorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AquaDockerRegistry) DeepCopyInto(out *AquaDockerRegistry) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(AquaSecret)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaDockerRegistry.
@@ -366,73 +528,84 @@ func (in *AquaDockerRegistry) DeepCopy() *AquaDockerRegistry {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaEnforcer) DeepCopyInto(out *AquaEnforcer) {
+func (in *AquaDrift) DeepCopyInto(out *AquaDrift) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.DetectedAt.DeepCopyInto(&out.DetectedAt)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcer.
-func (in *AquaEnforcer) DeepCopy() *AquaEnforcer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaDrift.
+func (in *AquaDrift) DeepCopy() *AquaDrift {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaEnforcer)
+	out := new(AquaDrift)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AquaEnforcer) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaDriftPolicy) DeepCopyInto(out *AquaDriftPolicy) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]AquaDriftRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaDriftPolicy.
+func (in *AquaDriftPolicy) DeepCopy() *AquaDriftPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaDriftPolicy)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaEnforcerDetailes) DeepCopyInto(out *AquaEnforcerDetailes) {
+func (in *AquaDriftRule) DeepCopyInto(out *AquaDriftRule) {
 	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerDetailes.
-func (in *AquaEnforcerDetailes) DeepCopy() *AquaEnforcerDetailes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaDriftRule.
+func (in *AquaDriftRule) DeepCopy() *AquaDriftRule {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaEnforcerDetailes)
+	out := new(AquaDriftRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaEnforcerList) DeepCopyInto(out *AquaEnforcerList) {
+func (in *AquaEnforcer) DeepCopyInto(out *AquaEnforcer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]AquaEnforcer, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerList.
-func (in *AquaEnforcerList) DeepCopy() *AquaEnforcerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcer.
+func (in *AquaEnforcer) DeepCopy() *AquaEnforcer {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaEnforcerList)
+	out := new(AquaEnforcer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AquaEnforcerList) DeepCopyObject() runtime.Object {
+func (in *AquaEnforcer) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -440,74 +613,59 @@ func (in *AquaEnforcerList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaEnforcerSpec) DeepCopyInto(out *AquaEnforcerSpec) {
+func (in *AquaEnforcerContainerRuntime) DeepCopyInto(out *AquaEnforcerContainerRuntime) {
 	*out = *in
-	if in.Infrastructure != nil {
-		in, out := &in.Infrastructure, &out.Infrastructure
-		*out = new(AquaInfrastructure)
-		**out = **in
-	}
-	if in.Common != nil {
-		in, out := &in.Common, &out.Common
-		*out = new(AquaCommon)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.EnforcerService != nil {
-		in, out := &in.EnforcerService, &out.EnforcerService
-		*out = new(AquaService)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Gateway != nil {
-		in, out := &in.Gateway, &out.Gateway
-		*out = new(AquaGatewayInformation)
-		**out = **in
-	}
-	if in.Secret != nil {
-		in, out := &in.Secret, &out.Secret
-		*out = new(AquaSecret)
-		**out = **in
-	}
-	if in.Envs != nil {
-		in, out := &in.Envs, &out.Envs
-		*out = make([]v1.EnvVar, len(*in))
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make([]AquaEnforcerRuntimeSettings, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.EnforcerUpdateApproved != nil {
-		in, out := &in.EnforcerUpdateApproved, &out.EnforcerUpdateApproved
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerContainerRuntime.
+func (in *AquaEnforcerContainerRuntime) DeepCopy() *AquaEnforcerContainerRuntime {
+	if in == nil {
+		return nil
 	}
+	out := new(AquaEnforcerContainerRuntime)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerSpec.
-func (in *AquaEnforcerSpec) DeepCopy() *AquaEnforcerSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerContainerRuntimeStatus) DeepCopyInto(out *AquaEnforcerContainerRuntimeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerContainerRuntimeStatus.
+func (in *AquaEnforcerContainerRuntimeStatus) DeepCopy() *AquaEnforcerContainerRuntimeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaEnforcerSpec)
+	out := new(AquaEnforcerContainerRuntimeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaEnforcerStatus) DeepCopyInto(out *AquaEnforcerStatus) {
+func (in *AquaEnforcerDetailes) DeepCopyInto(out *AquaEnforcerDetailes) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerStatus.
-func (in *AquaEnforcerStatus) DeepCopy() *AquaEnforcerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerDetailes.
+func (in *AquaEnforcerDetailes) DeepCopy() *AquaEnforcerDetailes {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaEnforcerStatus)
+	out := new(AquaEnforcerDetailes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaGateway) DeepCopyInto(out *AquaGateway) {
+func (in *AquaEnforcerGroup) DeepCopyInto(out *AquaEnforcerGroup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -515,18 +673,18 @@ func (in *AquaGateway) DeepCopyInto(out *AquaGateway) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGateway.
-func (in *AquaGateway) DeepCopy() *AquaGateway {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerGroup.
+func (in *AquaEnforcerGroup) DeepCopy() *AquaEnforcerGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaGateway)
+	out := new(AquaEnforcerGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AquaGateway) DeepCopyObject() runtime.Object {
+func (in *AquaEnforcerGroup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -534,46 +692,31 @@ func (in *AquaGateway) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaGatewayInformation) DeepCopyInto(out *AquaGatewayInformation) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGatewayInformation.
-func (in *AquaGatewayInformation) DeepCopy() *AquaGatewayInformation {
-	if in == nil {
-		return nil
-	}
-	out := new(AquaGatewayInformation)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaGatewayList) DeepCopyInto(out *AquaGatewayList) {
+func (in *AquaEnforcerGroupList) DeepCopyInto(out *AquaEnforcerGroupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]AquaGateway, len(*in))
+		*out = make([]AquaEnforcerGroup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGatewayList.
-func (in *AquaGatewayList) DeepCopy() *AquaGatewayList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerGroupList.
+func (in *AquaEnforcerGroupList) DeepCopy() *AquaEnforcerGroupList {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaGatewayList)
+	out := new(AquaEnforcerGroupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AquaGatewayList) DeepCopyObject() runtime.Object {
+func (in *AquaEnforcerGroupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -581,25 +724,502 @@ func (in *AquaGatewayList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaGatewaySpec) DeepCopyInto(out *AquaGatewaySpec) {
+func (in *AquaEnforcerGroupOrchestrator) DeepCopyInto(out *AquaEnforcerGroupOrchestrator) {
 	*out = *in
-	if in.Infrastructure != nil {
-		in, out := &in.Infrastructure, &out.Infrastructure
-		*out = new(AquaInfrastructure)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerGroupOrchestrator.
+func (in *AquaEnforcerGroupOrchestrator) DeepCopy() *AquaEnforcerGroupOrchestrator {
+	if in == nil {
+		return nil
 	}
-	if in.Common != nil {
-		in, out := &in.Common, &out.Common
-		*out = new(AquaCommon)
-		(*in).DeepCopyInto(*out)
+	out := new(AquaEnforcerGroupOrchestrator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerGroupSpec) DeepCopyInto(out *AquaEnforcerGroupSpec) {
+	*out = *in
+	in.AquaConsoleSync.DeepCopyInto(&out.AquaConsoleSync)
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.GatewayService != nil {
-		in, out := &in.GatewayService, &out.GatewayService
-		*out = new(AquaService)
+	if in.Orchestrator != nil {
+		in, out := &in.Orchestrator, &out.Orchestrator
+		*out = new(AquaEnforcerGroupOrchestrator)
+		**out = **in
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ExternalDb != nil {
-		in, out := &in.ExternalDb, &out.ExternalDb
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerGroupSpec.
+func (in *AquaEnforcerGroupSpec) DeepCopy() *AquaEnforcerGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerGroupToken) DeepCopyInto(out *AquaEnforcerGroupToken) {
+	*out = *in
+	if in.Login != nil {
+		in, out := &in.Login, &out.Login
+		*out = new(AquaLogin)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerGroupToken.
+func (in *AquaEnforcerGroupToken) DeepCopy() *AquaEnforcerGroupToken {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerGroupToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerGroupTokenStatus) DeepCopyInto(out *AquaEnforcerGroupTokenStatus) {
+	*out = *in
+	if in.LastRotation != nil {
+		in, out := &in.LastRotation, &out.LastRotation
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerGroupTokenStatus.
+func (in *AquaEnforcerGroupTokenStatus) DeepCopy() *AquaEnforcerGroupTokenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerGroupTokenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerList) DeepCopyInto(out *AquaEnforcerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AquaEnforcer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerList.
+func (in *AquaEnforcerList) DeepCopy() *AquaEnforcerList {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaEnforcerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerNodeGroup) DeepCopyInto(out *AquaEnforcerNodeGroup) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(AquaGatewayInformation)
+		**out = **in
+	}
+	if in.AquaExpressMode != nil {
+		in, out := &in.AquaExpressMode, &out.AquaExpressMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConfigData != nil {
+		in, out := &in.ConfigData, &out.ConfigData
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerNodeGroup.
+func (in *AquaEnforcerNodeGroup) DeepCopy() *AquaEnforcerNodeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerNodeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerNodeGroupStatus) DeepCopyInto(out *AquaEnforcerNodeGroupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerNodeGroupStatus.
+func (in *AquaEnforcerNodeGroupStatus) DeepCopy() *AquaEnforcerNodeGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerNodeGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerRhcosVersion) DeepCopyInto(out *AquaEnforcerRhcosVersion) {
+	*out = *in
+	if in.OSImages != nil {
+		in, out := &in.OSImages, &out.OSImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerRhcosVersion.
+func (in *AquaEnforcerRhcosVersion) DeepCopy() *AquaEnforcerRhcosVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerRhcosVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerRuntimeSettings) DeepCopyInto(out *AquaEnforcerRuntimeSettings) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerRuntimeSettings.
+func (in *AquaEnforcerRuntimeSettings) DeepCopy() *AquaEnforcerRuntimeSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerRuntimeSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerSecurityProfile) DeepCopyInto(out *AquaEnforcerSecurityProfile) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]v1.Capability, len(*in))
+		copy(*out, *in)
+	}
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(v1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerSecurityProfile.
+func (in *AquaEnforcerSecurityProfile) DeepCopy() *AquaEnforcerSecurityProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerSecurityProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerSpec) DeepCopyInto(out *AquaEnforcerSpec) {
+	*out = *in
+	if in.Infrastructure != nil {
+		in, out := &in.Infrastructure, &out.Infrastructure
+		*out = new(AquaInfrastructure)
+		**out = **in
+	}
+	if in.Common != nil {
+		in, out := &in.Common, &out.Common
+		*out = new(AquaCommon)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnforcerService != nil {
+		in, out := &in.EnforcerService, &out.EnforcerService
+		*out = new(AquaService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(AquaGatewayInformation)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(AquaSecret)
+		**out = **in
+	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnforcerUpdateApproved != nil {
+		in, out := &in.EnforcerUpdateApproved, &out.EnforcerUpdateApproved
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(AquaMaintenanceWindow)
+		**out = **in
+	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]AquaEnforcerNodeGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContainerRuntime != nil {
+		in, out := &in.ContainerRuntime, &out.ContainerRuntime
+		*out = new(AquaEnforcerContainerRuntime)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfile != nil {
+		in, out := &in.SecurityProfile, &out.SecurityProfile
+		*out = new(AquaEnforcerSecurityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnforcerGroup != nil {
+		in, out := &in.EnforcerGroup, &out.EnforcerGroup
+		*out = new(AquaEnforcerGroupToken)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerSpec.
+func (in *AquaEnforcerSpec) DeepCopy() *AquaEnforcerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaEnforcerStatus) DeepCopyInto(out *AquaEnforcerStatus) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]AquaImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]AquaDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(AquaMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]AquaEnforcerNodeGroupStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.RhcosVersions != nil {
+		in, out := &in.RhcosVersions, &out.RhcosVersions
+		*out = make([]AquaEnforcerRhcosVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContainerRuntimes != nil {
+		in, out := &in.ContainerRuntimes, &out.ContainerRuntimes
+		*out = make([]AquaEnforcerContainerRuntimeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnforcerGroup != nil {
+		in, out := &in.EnforcerGroup, &out.EnforcerGroup
+		*out = new(AquaEnforcerGroupTokenStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaEnforcerStatus.
+func (in *AquaEnforcerStatus) DeepCopy() *AquaEnforcerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaEnforcerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaFileSecretStore) DeepCopyInto(out *AquaFileSecretStore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaFileSecretStore.
+func (in *AquaFileSecretStore) DeepCopy() *AquaFileSecretStore {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaFileSecretStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaGateway) DeepCopyInto(out *AquaGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGateway.
+func (in *AquaGateway) DeepCopy() *AquaGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaGatewayInformation) DeepCopyInto(out *AquaGatewayInformation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGatewayInformation.
+func (in *AquaGatewayInformation) DeepCopy() *AquaGatewayInformation {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaGatewayInformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaGatewayList) DeepCopyInto(out *AquaGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AquaGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGatewayList.
+func (in *AquaGatewayList) DeepCopy() *AquaGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaGatewaySpec) DeepCopyInto(out *AquaGatewaySpec) {
+	*out = *in
+	if in.Infrastructure != nil {
+		in, out := &in.Infrastructure, &out.Infrastructure
+		*out = new(AquaInfrastructure)
+		**out = **in
+	}
+	if in.Common != nil {
+		in, out := &in.Common, &out.Common
+		*out = new(AquaCommon)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GatewayService != nil {
+		in, out := &in.GatewayService, &out.GatewayService
+		*out = new(AquaService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDb != nil {
+		in, out := &in.ExternalDb, &out.ExternalDb
 		*out = new(AquaDatabaseInformation)
 		**out = **in
 	}
@@ -615,6 +1235,11 @@ func (in *AquaGatewaySpec) DeepCopyInto(out *AquaGatewaySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(AquaMaintenanceWindow)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGatewaySpec.
@@ -628,36 +1253,105 @@ func (in *AquaGatewaySpec) DeepCopy() *AquaGatewaySpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaGatewayStatus) DeepCopyInto(out *AquaGatewayStatus) {
+func (in *AquaGatewayStatus) DeepCopyInto(out *AquaGatewayStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]AquaImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(AquaRolloutStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]AquaDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(AquaMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGatewayStatus.
+func (in *AquaGatewayStatus) DeepCopy() *AquaGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaImage) DeepCopyInto(out *AquaImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaImage.
+func (in *AquaImage) DeepCopy() *AquaImage {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaImageStatus) DeepCopyInto(out *AquaImageStatus) {
 	*out = *in
-	if in.Nodes != nil {
-		in, out := &in.Nodes, &out.Nodes
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaGatewayStatus.
-func (in *AquaGatewayStatus) DeepCopy() *AquaGatewayStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaImageStatus.
+func (in *AquaImageStatus) DeepCopy() *AquaImageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaGatewayStatus)
+	out := new(AquaImageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AquaImage) DeepCopyInto(out *AquaImage) {
+func (in *AquaImageVerification) DeepCopyInto(out *AquaImageVerification) {
 	*out = *in
+	if in.CosignPublicKey != nil {
+		in, out := &in.CosignPublicKey, &out.CosignPublicKey
+		*out = new(AquaSecret)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaImage.
-func (in *AquaImage) DeepCopy() *AquaImage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaImageVerification.
+func (in *AquaImageVerification) DeepCopy() *AquaImageVerification {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaImage)
+	out := new(AquaImageVerification)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -683,7 +1377,7 @@ func (in *AquaKubeEnforcer) DeepCopyInto(out *AquaKubeEnforcer) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaKubeEnforcer.
@@ -775,10 +1469,15 @@ func (in *AquaKubeEnforcerSpec) DeepCopyInto(out *AquaKubeEnforcerSpec) {
 		**out = **in
 	}
 	out.Config = in.Config
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(AquaSecret)
+		**out = **in
+	}
 	if in.RegistryData != nil {
 		in, out := &in.RegistryData, &out.RegistryData
 		*out = new(AquaDockerRegistry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ImageData != nil {
 		in, out := &in.ImageData, &out.ImageData
@@ -807,6 +1506,21 @@ func (in *AquaKubeEnforcerSpec) DeepCopyInto(out *AquaKubeEnforcerSpec) {
 		*out = new(AquaStarboardDetails)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(AquaImageVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftPolicy != nil {
+		in, out := &in.DriftPolicy, &out.DriftPolicy
+		*out = new(AquaDriftPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(AquaMaintenanceWindow)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaKubeEnforcerSpec.
@@ -822,6 +1536,35 @@ func (in *AquaKubeEnforcerSpec) DeepCopy() *AquaKubeEnforcerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AquaKubeEnforcerStatus) DeepCopyInto(out *AquaKubeEnforcerStatus) {
 	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]AquaImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]AquaDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(AquaMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaKubeEnforcerStatus.
@@ -837,14 +1580,319 @@ func (in *AquaKubeEnforcerStatus) DeepCopy() *AquaKubeEnforcerStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AquaLogin) DeepCopyInto(out *AquaLogin) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(AquaSecret)
+		**out = **in
+	}
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(AquaSecret)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaLogin.
+func (in *AquaLogin) DeepCopy() *AquaLogin {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaLogin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaMaintenanceStatus) DeepCopyInto(out *AquaMaintenanceStatus) {
+	*out = *in
+	if in.Deferred != nil {
+		in, out := &in.Deferred, &out.Deferred
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeferredUntil != nil {
+		in, out := &in.DeferredUntil, &out.DeferredUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaMaintenanceStatus.
+func (in *AquaMaintenanceStatus) DeepCopy() *AquaMaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaMaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaMaintenanceWindow) DeepCopyInto(out *AquaMaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaMaintenanceWindow.
+func (in *AquaMaintenanceWindow) DeepCopy() *AquaMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRegistryIntegration) DeepCopyInto(out *AquaRegistryIntegration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRegistryIntegration.
+func (in *AquaRegistryIntegration) DeepCopy() *AquaRegistryIntegration {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRegistryIntegration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaRegistryIntegration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRegistryIntegrationList) DeepCopyInto(out *AquaRegistryIntegrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AquaRegistryIntegration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRegistryIntegrationList.
+func (in *AquaRegistryIntegrationList) DeepCopy() *AquaRegistryIntegrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRegistryIntegrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaRegistryIntegrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRegistryIntegrationSpec) DeepCopyInto(out *AquaRegistryIntegrationSpec) {
+	*out = *in
+	in.AquaConsoleSync.DeepCopyInto(&out.AquaConsoleSync)
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(AquaSecret)
+		**out = **in
+	}
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRegistryIntegrationSpec.
+func (in *AquaRegistryIntegrationSpec) DeepCopy() *AquaRegistryIntegrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRegistryIntegrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRolloutStatus) DeepCopyInto(out *AquaRolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRolloutStatus.
+func (in *AquaRolloutStatus) DeepCopy() *AquaRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRolloutStrategy) DeepCopyInto(out *AquaRolloutStrategy) {
+	*out = *in
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRolloutStrategy.
+func (in *AquaRolloutStrategy) DeepCopy() *AquaRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRuntimePolicy) DeepCopyInto(out *AquaRuntimePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRuntimePolicy.
+func (in *AquaRuntimePolicy) DeepCopy() *AquaRuntimePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRuntimePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaRuntimePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRuntimePolicyList) DeepCopyInto(out *AquaRuntimePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AquaRuntimePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRuntimePolicyList.
+func (in *AquaRuntimePolicyList) DeepCopy() *AquaRuntimePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRuntimePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaRuntimePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRuntimePolicyScope) DeepCopyInto(out *AquaRuntimePolicyScope) {
+	*out = *in
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]AquaRuntimePolicyScopeVariable, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRuntimePolicyScope.
+func (in *AquaRuntimePolicyScope) DeepCopy() *AquaRuntimePolicyScope {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRuntimePolicyScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRuntimePolicyScopeVariable) DeepCopyInto(out *AquaRuntimePolicyScopeVariable) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRuntimePolicyScopeVariable.
+func (in *AquaRuntimePolicyScopeVariable) DeepCopy() *AquaRuntimePolicyScopeVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaRuntimePolicyScopeVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaRuntimePolicySpec) DeepCopyInto(out *AquaRuntimePolicySpec) {
+	*out = *in
+	in.AquaConsoleSync.DeepCopyInto(&out.AquaConsoleSync)
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = new(AquaRuntimePolicyScope)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Controls != nil {
+		in, out := &in.Controls, &out.Controls
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaLogin.
-func (in *AquaLogin) DeepCopy() *AquaLogin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaRuntimePolicySpec.
+func (in *AquaRuntimePolicySpec) DeepCopy() *AquaRuntimePolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AquaLogin)
+	out := new(AquaRuntimePolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -944,6 +1992,11 @@ func (in *AquaScannerSpec) DeepCopyInto(out *AquaScannerSpec) {
 	if in.Login != nil {
 		in, out := &in.Login, &out.Login
 		*out = new(AquaLogin)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(AquaMaintenanceWindow)
 		**out = **in
 	}
 }
@@ -966,6 +2019,28 @@ func (in *AquaScannerStatus) DeepCopyInto(out *AquaScannerStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]AquaImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]AquaDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(AquaMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaScannerStatus.
@@ -993,6 +2068,52 @@ func (in *AquaSecret) DeepCopy() *AquaSecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSecretStore) DeepCopyInto(out *AquaSecretStore) {
+	*out = *in
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(AquaVaultSecretStore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(AquaFileSecretStore)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	out.Keys = in.Keys
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSecretStore.
+func (in *AquaSecretStore) DeepCopy() *AquaSecretStore {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSecretStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSecretStoreKeys) DeepCopyInto(out *AquaSecretStoreKeys) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSecretStoreKeys.
+func (in *AquaSecretStoreKeys) DeepCopy() *AquaSecretStoreKeys {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSecretStoreKeys)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AquaServer) DeepCopyInto(out *AquaServer) {
 	*out = *in
@@ -1099,6 +2220,11 @@ func (in *AquaServerSpec) DeepCopyInto(out *AquaServerSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(AquaMaintenanceWindow)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaServerSpec.
@@ -1119,6 +2245,40 @@ func (in *AquaServerStatus) DeepCopyInto(out *AquaServerStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]AquaImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AvailableUpgrades != nil {
+		in, out := &in.AvailableUpgrades, &out.AvailableUpgrades
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(AquaRolloutStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]AquaDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(AquaMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaServerStatus.
@@ -1187,6 +2347,11 @@ func (in *AquaService) DeepCopyInto(out *AquaService) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(AquaRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaService.
@@ -1231,7 +2396,7 @@ func (in *AquaStarboardDetails) DeepCopyInto(out *AquaStarboardDetails) {
 	if in.RegistryData != nil {
 		in, out := &in.RegistryData, &out.RegistryData
 		*out = new(AquaDockerRegistry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ImageData != nil {
 		in, out := &in.ImageData, &out.ImageData
@@ -1245,6 +2410,16 @@ func (in *AquaStarboardDetails) DeepCopyInto(out *AquaStarboardDetails) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(AquaStarboardSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = new(AquaStarboardPolicies)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaStarboardDetails.
@@ -1257,6 +2432,270 @@ func (in *AquaStarboardDetails) DeepCopy() *AquaStarboardDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaStarboardPolicies) DeepCopyInto(out *AquaStarboardPolicies) {
+	*out = *in
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]AquaStarboardRegoModule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]AquaStarboardPolicyConfigMap, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaStarboardPolicies.
+func (in *AquaStarboardPolicies) DeepCopy() *AquaStarboardPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaStarboardPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaStarboardPolicyConfigMap) DeepCopyInto(out *AquaStarboardPolicyConfigMap) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaStarboardPolicyConfigMap.
+func (in *AquaStarboardPolicyConfigMap) DeepCopy() *AquaStarboardPolicyConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaStarboardPolicyConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaStarboardRegoModule) DeepCopyInto(out *AquaStarboardRegoModule) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaStarboardRegoModule.
+func (in *AquaStarboardRegoModule) DeepCopy() *AquaStarboardRegoModule {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaStarboardRegoModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaStarboardSettings) DeepCopyInto(out *AquaStarboardSettings) {
+	*out = *in
+	if in.ConcurrentScanJobsLimit != nil {
+		in, out := &in.ConcurrentScanJobsLimit, &out.ConcurrentScanJobsLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScanJobRetryAfter != nil {
+		in, out := &in.ScanJobRetryAfter, &out.ScanJobRetryAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CisKubernetesBenchmarkEnabled != nil {
+		in, out := &in.CisKubernetesBenchmarkEnabled, &out.CisKubernetesBenchmarkEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VulnerabilityScannerEnabled != nil {
+		in, out := &in.VulnerabilityScannerEnabled, &out.VulnerabilityScannerEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ClusterComplianceEnabled != nil {
+		in, out := &in.ClusterComplianceEnabled, &out.ClusterComplianceEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BatchDeleteLimit != nil {
+		in, out := &in.BatchDeleteLimit, &out.BatchDeleteLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BatchDeleteDelay != nil {
+		in, out := &in.BatchDeleteDelay, &out.BatchDeleteDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeNamespaces != nil {
+		in, out := &in.ExcludeNamespaces, &out.ExcludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScanJobTolerations != nil {
+		in, out := &in.ScanJobTolerations, &out.ScanJobTolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScanJobResources != nil {
+		in, out := &in.ScanJobResources, &out.ScanJobResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaStarboardSettings.
+func (in *AquaStarboardSettings) DeepCopy() *AquaStarboardSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaStarboardSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSupportBundle) DeepCopyInto(out *AquaSupportBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSupportBundle.
+func (in *AquaSupportBundle) DeepCopy() *AquaSupportBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSupportBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaSupportBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSupportBundleList) DeepCopyInto(out *AquaSupportBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AquaSupportBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSupportBundleList.
+func (in *AquaSupportBundleList) DeepCopy() *AquaSupportBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSupportBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AquaSupportBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSupportBundleSpec) DeepCopyInto(out *AquaSupportBundleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSupportBundleSpec.
+func (in *AquaSupportBundleSpec) DeepCopy() *AquaSupportBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSupportBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaSupportBundleStatus) DeepCopyInto(out *AquaSupportBundleStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaSupportBundleStatus.
+func (in *AquaSupportBundleStatus) DeepCopy() *AquaSupportBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaSupportBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AquaVaultSecretStore) DeepCopyInto(out *AquaVaultSecretStore) {
+	*out = *in
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(AquaSecret)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AquaVaultSecretStore.
+func (in *AquaVaultSecretStore) DeepCopy() *AquaVaultSecretStore {
+	if in == nil {
+		return nil
+	}
+	out := new(AquaVaultSecretStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuditDBInformation) DeepCopyInto(out *AuditDBInformation) {
 	*out = *in
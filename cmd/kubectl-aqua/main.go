@@ -0,0 +1,156 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-aqua is a kubectl plugin for the day-2 operations of the Aqua operator deployments
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aquasecurity/aqua-operator/controllers/render"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const usage = `kubectl aqua - day-2 operations for the Aqua operator
+
+Usage:
+  kubectl aqua <command> [NAME] [flags]
+
+NAME is the AquaCsp, or the standalone Aqua CR, to work on. It can be omitted
+when the namespace holds a single one.
+
+Commands:
+  status                    Tree of the CRs, their workloads and conditions
+  approve-enforcer-update   Approve the pending enforcer and kube-enforcer update
+  rotate-certs              Rotate the kube-enforcer webhook certificates
+  backup                    Dump the internal database to its backup claim
+  restore                   Load a dump of the backup claim into the internal database
+  support-bundle            Collect the CRs, workloads, events and logs into a tarball
+  versions                  Running, desired and available Aqua versions
+
+Run "kubectl aqua <command> -h" for the flags of a command.
+`
+
+// plugin The clients and flags shared by the commands
+type plugin struct {
+	Client    client.Client
+	Clientset kubernetes.Interface
+	Namespace string
+	Out       io.Writer
+}
+
+type command struct {
+	run  func(p *plugin, flags *flag.FlagSet, args []string) error
+	init func(flags *flag.FlagSet)
+}
+
+var commands = map[string]command{
+	"status":                  {run: runStatus},
+	"approve-enforcer-update": {run: runApproveEnforcerUpdate, init: initApproveEnforcerUpdate},
+	"rotate-certs":            {run: runRotateCerts, init: initRotateCerts},
+	"backup":                  {run: runBackup, init: initBackup},
+	"restore":                 {run: runRestore, init: initRestore},
+	"support-bundle":          {run: runSupportBundle, init: initSupportBundle},
+	"versions":                {run: runVersions},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err := run(os.Args[1], cmd, os.Args[2:]); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(name string, cmd command, args []string) error {
+	flags := flag.NewFlagSet("kubectl aqua "+name, flag.ContinueOnError)
+	namespace := flags.String("n", "", "Namespace of the Aqua CRs, the namespace of the current context by default.")
+	kubeconfig := flags.String("kubeconfig", "", "Path of the kubeconfig file.")
+	verbose := flags.Bool("v", false, "Print the operator code logs to stderr.")
+	if cmd.init != nil {
+		cmd.init(flags)
+	}
+
+	// allow NAME before the flags, like kubectl does
+	positional := []string{}
+	for len(args) > 0 {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		args = flags.Args()
+		if len(args) > 0 {
+			positional = append(positional, args[0])
+			args = args[1:]
+		}
+	}
+
+	logs := io.Discard
+	if *verbose {
+		logs = os.Stderr
+	}
+	logf.SetLogger(zap.New(zap.WriteTo(logs), zap.Level(zapcore.InfoLevel)))
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = *kubeconfig
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+
+	restConfig, err := config.ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	p := &plugin{Namespace: *namespace, Out: os.Stdout}
+	if len(p.Namespace) == 0 {
+		p.Namespace, _, err = config.Namespace()
+		if err != nil {
+			return err
+		}
+	}
+
+	p.Client, err = client.New(restConfig, client.Options{Scheme: render.NewScheme()})
+	if err != nil {
+		return err
+	}
+
+	p.Clientset, err = kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	return cmd.run(p, flags, positional)
+}
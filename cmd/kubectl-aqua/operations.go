@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/controllers/common"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquadatabase"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquakubeenforcer"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/supportbundle"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	waitFlag       bool
+	timeoutFlag    time.Duration
+	fileFlag       string
+	auditFlag      bool
+	yesFlag        bool
+	outputFlag     string
+	logSinceFlag   time.Duration
+	tailFlag       int64
+	operatorNsFlag string
+	forceFlag      bool
+	pollInterval   = 5 * time.Second
+	enforcerStates = map[string]bool{
+		string(operatorv1alpha1.AquaEnforcerUpdatePendingApproval): true,
+		string(operatorv1alpha1.AquaEnforcerUpdateInProgress):      true,
+	}
+)
+
+func initWait(flags *flag.FlagSet, timeout time.Duration) {
+	flags.BoolVar(&waitFlag, "wait", true, "Wait for the operation to finish.")
+	flags.DurationVar(&timeoutFlag, "timeout", timeout, "How long to wait for the operation.")
+}
+
+// getTree returns the roots and the CRs they own, directly or through another CR
+func (p *plugin) getTree(name string) ([]*unstructured.Unstructured, error) {
+	roots, err := p.getRoots(name)
+	if err != nil {
+		return nil, err
+	}
+
+	crs, err := p.listAquaObjects(supportbundle.AquaKinds...)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := map[types.UID]bool{}
+	for _, root := range roots {
+		owned[root.GetUID()] = true
+	}
+	// AquaCsp > AquaKubeEnforcer > AquaStarboard is the deepest ownership
+	for i := 0; i < 2; i++ {
+		for _, cr := range crs {
+			if owner := controllerOf(cr); owner != nil && owned[*owner] {
+				owned[cr.GetUID()] = true
+			}
+		}
+	}
+
+	result := []*unstructured.Unstructured{}
+	for _, cr := range crs {
+		if owned[cr.GetUID()] {
+			result = append(result, cr)
+		}
+	}
+
+	return result, nil
+}
+
+func filterKind(crs []*unstructured.Unstructured, kinds ...string) []*unstructured.Unstructured {
+	result := []*unstructured.Unstructured{}
+	for _, cr := range crs {
+		for _, kind := range kinds {
+			if cr.GetKind() == kind {
+				result = append(result, cr)
+			}
+		}
+	}
+
+	return result
+}
+
+/*	----------------------------------------------------------------------------------------------------------------
+							Enforcer Update Approval
+	----------------------------------------------------------------------------------------------------------------
+*/
+
+func initApproveEnforcerUpdate(flags *flag.FlagSet) {
+	initWait(flags, 30*time.Minute)
+}
+
+// runApproveEnforcerUpdate sets spec.updateEnforcer on the CRs holding the approval, waits for the enforcers to
+// roll out and sets it back, so the next update needs an approval again
+func runApproveEnforcerUpdate(p *plugin, _ *flag.FlagSet, args []string) error {
+	roots, err := p.getRoots(nameArg(args))
+	if err != nil {
+		return err
+	}
+
+	targets := filterKind(roots, "AquaCsp", "AquaEnforcer", "AquaKubeEnforcer")
+	if len(targets) == 0 {
+		return fmt.Errorf("no AquaCsp, AquaEnforcer or AquaKubeEnforcer to approve in namespace %s", p.Namespace)
+	}
+
+	for _, target := range targets {
+		err = p.setUpdateEnforcer(target, true)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(p.Out, "%s/%s: enforcer update approved\n", target.GetKind(), target.GetName())
+	}
+
+	if !waitFlag {
+		fmt.Fprintln(p.Out, "spec.updateEnforcer stays true, later updates are rolled out without approval")
+		return nil
+	}
+
+	err = wait.PollImmediate(pollInterval, timeoutFlag, func() (bool, error) {
+		crs, err := p.getTree(nameArg(args))
+		if err != nil {
+			return false, err
+		}
+		for _, cr := range filterKind(crs, "AquaEnforcer", "AquaKubeEnforcer") {
+			state, _, _ := unstructured.NestedString(cr.Object, "status", "state")
+			if enforcerStates[state] {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for the enforcers to update: %v", err)
+	}
+
+	for _, target := range targets {
+		err = p.setUpdateEnforcer(target, false)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(p.Out, "Enforcers updated, the next update needs an approval again")
+
+	return nil
+}
+
+func (p *plugin) setUpdateEnforcer(cr *unstructured.Unstructured, approved bool) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"updateEnforcer":%t}}`, approved))
+	return p.Client.Patch(context.TODO(), cr.DeepCopy(), client.RawPatch(types.MergePatchType, patch))
+}
+
+/*	----------------------------------------------------------------------------------------------------------------
+							Certificates Rotation
+	----------------------------------------------------------------------------------------------------------------
+*/
+
+func initRotateCerts(flags *flag.FlagSet) {
+	flags.BoolVar(&forceFlag, "force", false, "Rotate outside the maintenance window of the AquaKubeEnforcer.")
+}
+
+func runRotateCerts(p *plugin, _ *flag.FlagSet, args []string) error {
+	crs, err := p.getTree(nameArg(args))
+	if err != nil {
+		return err
+	}
+
+	kubeEnforcers := filterKind(crs, "AquaKubeEnforcer")
+	if len(kubeEnforcers) == 0 {
+		return fmt.Errorf("no AquaKubeEnforcer in namespace %s", p.Namespace)
+	}
+
+	for _, obj := range kubeEnforcers {
+		cr := &operatorv1alpha1.AquaKubeEnforcer{}
+		err = p.Client.Get(context.TODO(), client.ObjectKeyFromObject(obj), cr)
+		if err != nil {
+			return err
+		}
+
+		open, next, err := common.MaintenanceWindowOpen(cr.Spec.MaintenanceWindow, time.Now())
+		if err != nil {
+			return fmt.Errorf("AquaKubeEnforcer/%s: %v", cr.Name, err)
+		}
+		if !open && !forceFlag {
+			return fmt.Errorf("AquaKubeEnforcer/%s: rotation deferred until %s, the maintenance window is closed, --force rotates now",
+				cr.Name, next.Format(time.RFC3339))
+		}
+
+		err = aquakubeenforcer.RotateCertificates(p.Client, cr)
+		if err != nil {
+			return fmt.Errorf("AquaKubeEnforcer/%s: %v", cr.Name, err)
+		}
+		fmt.Fprintf(p.Out, "AquaKubeEnforcer/%s: webhook certificates rotated, the kube-enforcer is restarting\n", cr.Name)
+	}
+
+	return nil
+}
+
+/*	----------------------------------------------------------------------------------------------------------------
+							Database Backup And Restore
+	----------------------------------------------------------------------------------------------------------------
+*/
+
+func initBackup(flags *flag.FlagSet) {
+	initWait(flags, 30*time.Minute)
+}
+
+func initRestore(flags *flag.FlagSet) {
+	initWait(flags, 60*time.Minute)
+	flags.StringVar(&fileFlag, "file", "", "Dump to restore, as printed by the backup command.")
+	flags.BoolVar(&auditFlag, "audit", false, "Restore the audit database of a split database.")
+	flags.BoolVar(&yesFlag, "yes", false, "Confirm the database content is replaced.")
+}
+
+func (p *plugin) getDatabase(name string) (*operatorv1alpha1.AquaDatabase, error) {
+	crs, err := p.getTree(name)
+	if err != nil {
+		return nil, err
+	}
+
+	databases := filterKind(crs, "AquaDatabase")
+	if len(databases) != 1 {
+		return nil, fmt.Errorf("found %d AquaDatabase CRs in namespace %s, name the one to use", len(databases), p.Namespace)
+	}
+
+	cr := &operatorv1alpha1.AquaDatabase{}
+	err = p.Client.Get(context.TODO(), client.ObjectKeyFromObject(databases[0]), cr)
+	return cr, err
+}
+
+func runBackup(p *plugin, _ *flag.FlagSet, args []string) error {
+	cr, err := p.getDatabase(nameArg(args))
+	if err != nil {
+		return err
+	}
+
+	reconciler := &aquadatabase.AquaDatabaseReconciler{Client: p.Client, Scheme: p.Client.Scheme()}
+	suffix := time.Now().Format("20060102150405")
+
+	for _, instance := range aquadatabase.GetDatabaseInstances(cr) {
+		job, err := reconciler.RunBackup(cr, instance, fmt.Sprintf(consts.DbBackupJobName, instance.DeployName, suffix))
+		if err != nil {
+			return fmt.Errorf("backing up %s: %v", instance.DeployName, err)
+		}
+		fmt.Fprintf(p.Out, "%s: backup job %s started\n", instance.DeployName, job.Name)
+
+		if !waitFlag {
+			continue
+		}
+
+		output, err := p.waitForJob(job)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(p.Out, "%s: dump %s written to claim %s\n", instance.DeployName, output, fmt.Sprintf(consts.DbBackupPvcName, instance.DeployName))
+	}
+
+	return nil
+}
+
+func runRestore(p *plugin, _ *flag.FlagSet, args []string) error {
+	if len(fileFlag) == 0 {
+		return fmt.Errorf("--file is required")
+	}
+	if !yesFlag {
+		return fmt.Errorf("restoring replaces the content of the database, stop the server and gateway first and pass --yes")
+	}
+
+	cr, err := p.getDatabase(nameArg(args))
+	if err != nil {
+		return err
+	}
+
+	instances := aquadatabase.GetDatabaseInstances(cr)
+	instance := instances[0]
+	if auditFlag {
+		if len(instances) < 2 {
+			return fmt.Errorf("AquaDatabase/%s has no audit database", cr.Name)
+		}
+		instance = instances[1]
+	}
+
+	reconciler := &aquadatabase.AquaDatabaseReconciler{Client: p.Client, Scheme: p.Client.Scheme()}
+	jobName := fmt.Sprintf("%s-restore-%s", instance.DeployName, time.Now().Format("20060102150405"))
+	job, err := reconciler.RunRestore(cr, instance, jobName, fileFlag)
+	if err != nil {
+		return fmt.Errorf("restoring %s: %v", instance.DeployName, err)
+	}
+	fmt.Fprintf(p.Out, "%s: restore job %s started\n", instance.DeployName, job.Name)
+
+	if waitFlag {
+		if _, err = p.waitForJob(job); err != nil {
+			return err
+		}
+		fmt.Fprintf(p.Out, "%s: %s restored\n", instance.DeployName, fileFlag)
+	}
+
+	return nil
+}
+
+// waitForJob waits for the job to finish and returns the last line its pod printed
+func (p *plugin) waitForJob(job *batchv1.Job) (string, error) {
+	err := wait.PollImmediate(pollInterval, timeoutFlag, func() (bool, error) {
+		err := p.Client.Get(context.TODO(), client.ObjectKeyFromObject(job), job)
+		if err != nil {
+			return false, err
+		}
+		finished, _ := k8s.IsJobFinished(job)
+		return finished, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("waiting for job %s: %v", job.Name, err)
+	}
+
+	if _, failed := k8s.IsJobFinished(job); failed {
+		return "", fmt.Errorf("job %s failed, see kubectl logs -n %s job/%s", job.Name, job.Namespace, job.Name)
+	}
+
+	pods := &corev1.PodList{}
+	err = p.Client.List(context.TODO(), pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name})
+	if err != nil || len(pods.Items) == 0 {
+		return "", err
+	}
+
+	stream, err := p.Clientset.CoreV1().Pods(job.Namespace).GetLogs(pods.Items[0].Name, &corev1.PodLogOptions{}).Stream(context.TODO())
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		return "", err
+	}
+	lines := bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+
+	return string(lines[len(lines)-1]), nil
+}
+
+/*	----------------------------------------------------------------------------------------------------------------
+							Support Bundle
+	----------------------------------------------------------------------------------------------------------------
+*/
+
+func initSupportBundle(flags *flag.FlagSet) {
+	flags.StringVar(&outputFlag, "o", "", "File the bundle is written to, aqua-support-bundle-<namespace>-<time>.tar.gz by default.")
+	flags.DurationVar(&logSinceFlag, "since", 24*time.Hour, "Age of the oldest pod log lines collected.")
+	flags.Int64Var(&tailFlag, "tail", consts.SupportBundleTailLines, "Maximum number of log lines collected per container, 0 for all.")
+	flags.StringVar(&operatorNsFlag, "operator-namespace", "", "Namespace of the operator, the namespace of the Aqua CRs by default.")
+}
+
+func runSupportBundle(p *plugin, _ *flag.FlagSet, _ []string) error {
+	output := outputFlag
+	if len(output) == 0 {
+		output = fmt.Sprintf("aqua-support-bundle-%s-%s.tar.gz", p.Namespace, time.Now().Format("20060102150405"))
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	collector := supportbundle.NewCollector(p.Client, p.Clientset, supportbundle.Options{
+		Namespace:         p.Namespace,
+		LogSince:          logSinceFlag,
+		TailLines:         tailFlag,
+		OperatorNamespace: valueOr(operatorNsFlag, p.Namespace),
+	})
+	if err = collector.Write(file); err != nil {
+		return err
+	}
+
+	for _, part := range collector.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", part)
+	}
+	fmt.Fprintf(p.Out, "Support bundle written to %s\n", output)
+	return nil
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/pkg/utils/supportbundle"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/versions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// workloadKinds The workloads shown under the CR that owns them
+var workloadKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+}
+
+// listAquaObjects returns the Aqua CRs and the workloads of the namespace
+func (p *plugin) listAquaObjects(kinds ...schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+	result := []*unstructured.Unstructured{}
+	for _, gvk := range kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := p.Client.List(context.TODO(), list, client.InNamespace(p.Namespace)); err != nil {
+			return nil, fmt.Errorf("listing %s: %v", gvk.Kind, err)
+		}
+		for i := range list.Items {
+			result = append(result, &list.Items[i])
+		}
+	}
+
+	return result, nil
+}
+
+// getRoots returns the Aqua CRs no other CR owns, only the one named name when set
+func (p *plugin) getRoots(name string) ([]*unstructured.Unstructured, error) {
+	crs, err := p.listAquaObjects(supportbundle.AquaKinds...)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := []*unstructured.Unstructured{}
+	for _, cr := range crs {
+		if owner := controllerOf(cr); owner != nil {
+			continue
+		}
+		if len(name) > 0 && cr.GetName() != name {
+			continue
+		}
+		roots = append(roots, cr)
+	}
+
+	if len(roots) == 0 {
+		if len(name) > 0 {
+			return nil, fmt.Errorf("no Aqua CR named %s in namespace %s", name, p.Namespace)
+		}
+		return nil, fmt.Errorf("no Aqua CRs in namespace %s", p.Namespace)
+	}
+
+	return roots, nil
+}
+
+func controllerOf(obj *unstructured.Unstructured) *types.UID {
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.Controller != nil && *owner.Controller {
+			uid := owner.UID
+			return &uid
+		}
+	}
+
+	return nil
+}
+
+func runStatus(p *plugin, _ *flag.FlagSet, args []string) error {
+	roots, err := p.getRoots(nameArg(args))
+	if err != nil {
+		return err
+	}
+
+	objects, err := p.listAquaObjects(append(append([]schema.GroupVersionKind{}, supportbundle.AquaKinds...), workloadKinds...)...)
+	if err != nil {
+		return err
+	}
+
+	children := map[types.UID][]*unstructured.Unstructured{}
+	for _, obj := range objects {
+		if owner := controllerOf(obj); owner != nil {
+			children[*owner] = append(children[*owner], obj)
+		}
+	}
+	for _, list := range children {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].GetKind() != list[j].GetKind() {
+				return list[i].GetKind() < list[j].GetKind()
+			}
+			return list[i].GetName() < list[j].GetName()
+		})
+	}
+
+	for _, root := range roots {
+		p.printNode(root, children, "", "")
+	}
+
+	return nil
+}
+
+// printNode prints the object, its conditions and the objects it controls as a tree
+func (p *plugin) printNode(obj *unstructured.Unstructured, children map[types.UID][]*unstructured.Unstructured, prefix, childPrefix string) {
+	fmt.Fprintf(p.Out, "%s%s/%s  %s\n", prefix, obj.GetKind(), obj.GetName(), describe(obj))
+
+	lines := []string{}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, _ := item.(map[string]interface{})
+		line := fmt.Sprintf("%v=%v", condition["type"], condition["status"])
+		if reason, ok := condition["reason"]; ok && reason != "" {
+			line = fmt.Sprintf("%s (%v)", line, reason)
+		}
+		if message, ok := condition["message"]; ok && message != "" {
+			line = fmt.Sprintf("%s: %v", line, message)
+		}
+		lines = append(lines, line)
+	}
+	if message, found, _ := unstructured.NestedString(obj.Object, "status", "upgrade", "message"); found && len(message) > 0 {
+		lines = append(lines, "Upgrade: "+message)
+	}
+	if message, found, _ := unstructured.NestedString(obj.Object, "status", "maintenance", "message"); found && len(message) > 0 {
+		deferred, _, _ := unstructured.NestedStringSlice(obj.Object, "status", "maintenance", "deferred")
+		lines = append(lines, fmt.Sprintf("Maintenance: %s (%s)", message, strings.Join(deferred, ", ")))
+	}
+	if paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused"); paused {
+		lines = append(lines, "Paused: spec.paused is true")
+	}
+
+	nodes := children[obj.GetUID()]
+	for i, line := range lines {
+		branch := "├── "
+		if i == len(lines)-1 && len(nodes) == 0 {
+			branch = "└── "
+		}
+		fmt.Fprintf(p.Out, "%s%s%s\n", childPrefix, branch, line)
+	}
+
+	for i, child := range nodes {
+		if i == len(nodes)-1 {
+			p.printNode(child, children, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			p.printNode(child, children, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// describe returns the state of a CR, or the readiness of a workload
+func describe(obj *unstructured.Unstructured) string {
+	status := func(fields ...string) int64 {
+		value, _, _ := unstructured.NestedInt64(obj.Object, append([]string{"status"}, fields...)...)
+		return value
+	}
+
+	switch obj.GetKind() {
+	case "Deployment":
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		return fmt.Sprintf("%d/%d ready, %d updated", status("readyReplicas"), replicas, status("updatedReplicas"))
+	case "DaemonSet":
+		return fmt.Sprintf("%d/%d ready, %d updated", status("numberReady"), status("desiredNumberScheduled"), status("updatedNumberScheduled"))
+	case "Job":
+		switch {
+		case status("succeeded") > 0:
+			return "Succeeded"
+		case status("failed") > 0 && status("active") == 0:
+			return "Failed"
+		}
+		return "Running"
+	}
+
+	parts := []string{}
+	if state, _, _ := unstructured.NestedString(obj.Object, "status", "state"); len(state) > 0 {
+		parts = append(parts, state)
+	} else {
+		parts = append(parts, "Unknown")
+	}
+	if version, _, _ := unstructured.NestedString(obj.Object, "status", "version"); len(version) > 0 {
+		parts = append(parts, "version "+version)
+	}
+	if paused, _, _ := unstructured.NestedBool(obj.Object, "status", "upgrade", "paused"); paused {
+		parts = append(parts, "upgrade paused")
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func runVersions(p *plugin, _ *flag.FlagSet, args []string) error {
+	crs, err := p.getTree(nameArg(args))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(p.Out, "%-34s %-12s %-12s %-24s %s\n", "CR", "RUNNING", "DESIRED", "STATE", "AVAILABLE UPGRADES")
+	for _, cr := range crs {
+		running, _, _ := unstructured.NestedString(cr.Object, "status", "version")
+		desired, _, _ := unstructured.NestedString(cr.Object, "spec", "infra", "version")
+		state, _, _ := unstructured.NestedString(cr.Object, "status", "state")
+		available, _, _ := unstructured.NestedStringSlice(cr.Object, "status", "availableUpgrades")
+		if len(available) == 0 && len(running) > 0 {
+			available = versions.AvailableUpgrades(running)
+		}
+
+		fmt.Fprintf(p.Out, "%-34s %-12s %-12s %-24s %s\n",
+			fmt.Sprintf("%s/%s", cr.GetKind(), cr.GetName()),
+			valueOr(running, "-"),
+			valueOr(desired, "-"),
+			valueOr(state, "-"),
+			valueOr(strings.Join(available, ", "), "-"))
+	}
+
+	fmt.Fprintf(p.Out, "\nSupported by this operator: %s (latest %s)\n", strings.Join(versions.Supported(), ", "), versions.Latest().Version)
+	return nil
+}
+
+func nameArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+
+	return ""
+}
+
+func valueOr(value, fallback string) string {
+	if len(value) == 0 {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aquasecuritysummary
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/securitysummary"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	aquasecurityv1alpha1 "github.com/aquasecurity/aqua-operator/apis/aquasecurity/v1alpha1"
+)
+
+var log = logf.Log.WithName("controller_aquasecuritysummary")
+
+var (
+	vulnerabilityReports = schema.GroupVersionKind{Group: "aquasecurity.github.io", Version: "v1alpha1", Kind: "VulnerabilityReportList"}
+	configAuditReports   = schema.GroupVersionKind{Group: "aquasecurity.github.io", Version: "v1alpha1", Kind: "ConfigAuditReportList"}
+)
+
+// AquaSecuritySummaryReconciler reconciles a AquaSecuritySummary object
+type AquaSecuritySummaryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Reader Lists the reports from the API server, so thousands of reports aren't cached
+	Reader client.Reader
+}
+
+//+kubebuilder:rbac:groups=aquasecurity.github.io,resources=aquasecuritysummaries,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=aquasecurity.github.io,resources=aquasecuritysummaries/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=aquasecurity.github.io,resources=vulnerabilityreports;configauditreports,verbs=get;list
+
+// Reconcile aggregates the vulnerability and config audit reports of the cluster into the status, and again every
+// refresh interval. A report kind that isn't installed counts as no reports
+func (r *AquaSecuritySummaryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.WithValues("Request.Name", req.Name)
+	reqLogger.Info("Reconciling AquaSecuritySummary")
+
+	// Fetch the AquaSecuritySummary instance
+	instance := &aquasecurityv1alpha1.AquaSecuritySummary{}
+	err := r.Client.Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	aggregator := securitysummary.NewAggregator()
+	err = r.listReports(ctx, vulnerabilityReports, aggregator.AddVulnerabilityReport)
+	if err == nil {
+		err = r.listReports(ctx, configAuditReports, aggregator.AddConfigAuditReport)
+	}
+	if err != nil {
+		reqLogger.Error(err, "Failed to aggregate the reports")
+		instance.Status.Message = err.Error()
+		if updateErr := r.Client.Status().Update(context.TODO(), instance); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
+	now := metav1.Now()
+	status := aggregator.Status(consts.SecuritySummaryTopImages, consts.SecuritySummaryTopChecks)
+	status.LastUpdateTime = &now
+	status.Trend = instance.Status.Trend
+	if len(status.Trend) == 0 || now.Sub(status.Trend[len(status.Trend)-1].Time.Time) >= consts.SecuritySummaryTrendInterval {
+		status.Trend = append(status.Trend, aquasecurityv1alpha1.AquaSecuritySummaryTrendPoint{
+			Time:            now,
+			Vulnerabilities: status.Vulnerabilities,
+			FailingChecks:   status.FailingChecks,
+		})
+	}
+	if len(status.Trend) > consts.SecuritySummaryTrendPoints {
+		status.Trend = status.Trend[len(status.Trend)-consts.SecuritySummaryTrendPoints:]
+	}
+
+	instance.Status = status
+	err = r.Client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("Aggregated the reports", "VulnerabilityReports", status.VulnerabilityReports, "ConfigAuditReports", status.ConfigAuditReports)
+	return reconcile.Result{RequeueAfter: consts.SecuritySummaryRefreshInterval}, nil
+}
+
+// listReports passes every report of the kind to add, reading them a page at a time
+func (r *AquaSecuritySummaryReconciler) listReports(ctx context.Context, gvk schema.GroupVersionKind, add func(*unstructured.Unstructured)) error {
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		err := r.Reader.List(ctx, list, client.Limit(consts.SecuritySummaryListLimit), client.Continue(continueToken))
+		if meta.IsNoMatchError(err) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("listing %s: %v", gvk.Kind, err)
+		}
+
+		for i := range list.Items {
+			add(&list.Items[i])
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AquaSecuritySummaryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("AquaSecuritySummary-controller").
+		WithOptions(controller.Options{Reconciler: r}).
+		// the status updates of the controller don't trigger another aggregation
+		For(&aquasecurityv1alpha1.AquaSecuritySummary{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
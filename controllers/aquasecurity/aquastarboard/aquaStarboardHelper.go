@@ -3,10 +3,13 @@ package aquastarboard
 import (
 	"fmt"
 	aquasecurityv1alpha1 "github.com/aquasecurity/aqua-operator/apis/aquasecurity/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
 	"github.com/aquasecurity/aqua-operator/pkg/consts"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/rbac"
 	"os"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -197,7 +200,11 @@ func (enf *AquaStarboardHelper) CreateStarboardClusterRole(name string, namespac
 		},
 	}
 
-	crole := rbac.CreateClusterRole(name, namespace, "starboard-operator", fmt.Sprintf("%s-rbac", "aqua-sb"), "Deploy Aqua Starboard Cluster Role", rules)
+	if enf.Scanner() == v1alpha1.AquaStarboardScannerTrivyOperator {
+		rules = trivyOperatorRules(rules)
+	}
+
+	crole := rbac.CreateClusterRole(name, namespace, enf.OperatorName(), fmt.Sprintf("%s-rbac", "aqua-sb"), "Deploy Aqua Starboard Cluster Role", rules)
 
 	return crole
 }
@@ -295,7 +302,9 @@ func (enf *AquaStarboardHelper) CreateStarboardSecret(cr, namespace, name, app s
 	return starboardSecret
 }
 
-func (enf *AquaStarboardHelper) CreateStarboardConftestConfigMap(cr, namespace, name, app, version string) *corev1.ConfigMap {
+// CreateStarboardConftestConfigMap The policies of the conftest config audit scanner, policies holds the keys
+// assembled by PoliciesData
+func (enf *AquaStarboardHelper) CreateStarboardConftestConfigMap(cr, namespace, name, app, version string, policies map[string]string) *corev1.ConfigMap {
 	labels := map[string]string{
 		"app":                        app,
 		"deployedby":                 "aqua-operator",
@@ -318,12 +327,23 @@ func (enf *AquaStarboardHelper) CreateStarboardConftestConfigMap(cr, namespace,
 			Labels:      labels,
 			Annotations: annotations,
 		},
+		Data: policies,
 	}
 
 	return configMap
 }
 
-func (enf *AquaStarboardHelper) CreateStarboardConfigMap(cr, namespace, name, app string) *corev1.ConfigMap {
+func (enf *AquaStarboardHelper) CreateStarboardConfigMap(cr, namespace, name, app string) (*corev1.ConfigMap, error) {
+	settings, err := enf.Settings()
+	if err != nil {
+		return nil, err
+	}
+	data, err := scanJobConfigData(settings)
+	if err != nil {
+		return nil, err
+	}
+	data["configAuditReports.scanner"] = settings.ConfigAuditScanner
+
 	labels := map[string]string{
 		"app":                app,
 		"deployedby":         "aqua-operator",
@@ -343,17 +363,53 @@ func (enf *AquaStarboardHelper) CreateStarboardConfigMap(cr, namespace, name, ap
 			Labels:      labels,
 			Annotations: annotations,
 		},
-		Data: map[string]string{
-			"configAuditReports.scanner": "Conftest",
+		Data: data,
+	}
+
+	return configMap, nil
+}
+
+// CreateStarboardTrivyConfigMap The configuration of the trivy vulnerability scan jobs of starboard
+func (enf *AquaStarboardHelper) CreateStarboardTrivyConfigMap(cr, namespace string) (*corev1.ConfigMap, error) {
+	settings, err := enf.Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "starboard-trivy-config",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":                "starboard",
+				"deployedby":         "aqua-operator",
+				"aquasecoperator_cr": cr,
+			},
+			Annotations: map[string]string{
+				"description": "Deploy Aqua starboard trivy ConfigMap",
+			},
 		},
+		Data: trivyConfigData(settings),
 	}
 
-	return configMap
+	return configMap, nil
 }
 
-func (enf *AquaStarboardHelper) CreateStarboardDeployment(cr *aquasecurityv1alpha1.AquaStarboard, name, app, registry, tag, pullPolicy, repository string) *appsv1.Deployment {
+func (enf *AquaStarboardHelper) CreateStarboardDeployment(cr *aquasecurityv1alpha1.AquaStarboard, name, app, registry, tag, pullPolicy, repository string) (*appsv1.Deployment, error) {
+	settings, err := enf.Settings()
+	if err != nil {
+		return nil, err
+	}
 
-	image := os.Getenv("RELATED_IMAGE_STARBOARD")
+	relatedImage := "RELATED_IMAGE_STARBOARD"
+	if enf.Scanner() == v1alpha1.AquaStarboardScannerTrivyOperator {
+		relatedImage = "RELATED_IMAGE_TRIVY_OPERATOR"
+	}
+	image := os.Getenv(relatedImage)
 	if image == "" {
 		image = fmt.Sprintf("%s/%s:%s", registry, repository, tag)
 	}
@@ -373,9 +429,12 @@ func (enf *AquaStarboardHelper) CreateStarboardDeployment(cr *aquasecurityv1alph
 	readOnlyRootFilesystem := true
 	allowPrivilegeEscalation := false
 
-	envVars := enf.getStarboardEnvVars(cr)
+	envVars := enf.getStarboardEnvVars(cr, settings)
+	if enf.Scanner() == v1alpha1.AquaStarboardScannerTrivyOperator {
+		envVars = enf.getTrivyOperatorEnvVars(envVars)
+	}
 	selectors := map[string]string{
-		"app": "starboard-operator",
+		"app": enf.OperatorName(),
 	}
 
 	ports := []corev1.ContainerPort{
@@ -505,10 +564,11 @@ func (enf *AquaStarboardHelper) CreateStarboardDeployment(cr *aquasecurityv1alph
 		deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, enf.Parameters.Starboard.Spec.Envs...)
 	}
 
-	return deployment
+	return deployment, nil
 }
 
-func (ebf *AquaStarboardHelper) getStarboardEnvVars(cr *aquasecurityv1alpha1.AquaStarboard) []corev1.EnvVar {
+// getStarboardEnvVars maps the resolved settings of the CR to the environment of the scanner
+func (ebf *AquaStarboardHelper) getStarboardEnvVars(cr *aquasecurityv1alpha1.AquaStarboard, settings *v1alpha1.AquaStarboardSettings) []corev1.EnvVar {
 
 	result := []corev1.EnvVar{
 		{
@@ -517,10 +577,11 @@ func (ebf *AquaStarboardHelper) getStarboardEnvVars(cr *aquasecurityv1alpha1.Aqu
 		},
 		{
 			Name:  "OPERATOR_TARGET_NAMESPACES",
-			Value: "",
+			Value: strings.Join(settings.TargetNamespaces, ","),
 		},
+		{
 			Name:  "OPERATOR_EXCLUDE_NAMESPACES",
-			Value: consts.OperatorExcludeNamespaces,
+			Value: strings.Join(settings.ExcludeNamespaces, ","),
 		},
 		{
 			Name:  "OPERATOR_METRICS_BIND_ADDRESS",
@@ -543,96 +604,34 @@ func (ebf *AquaStarboardHelper) getStarboardEnvVars(cr *aquasecurityv1alpha1.Aqu
 	}
 	result = append(result, operatorLogDevMode)
 
-	operatorConcurrentScanJobsLimit := corev1.EnvVar{
-		Name:  "OPERATOR_CONCURRENT_SCAN_JOBS_LIMIT",
-		Value: consts.OperatorConcurrentScanJobsLimit,
-	}
-
-	if cr.Spec.ConcurrentScanJobsLimit != "" {
-		operatorConcurrentScanJobsLimit = corev1.EnvVar{
+	return append(result,
+		corev1.EnvVar{
 			Name:  "OPERATOR_CONCURRENT_SCAN_JOBS_LIMIT",
-			Value: cr.Spec.ConcurrentScanJobsLimit,
-		}
-	}
-
-	result = append(result, operatorConcurrentScanJobsLimit)
-
-	operatorScanJobRetryAfter := corev1.EnvVar{
-		Name:  "OPERATOR_SCAN_JOB_RETRY_AFTER",
-		Value: consts.OperatorScanJobRetryAfter,
-	}
-
-	if cr.Spec.ScanJobRetryAfter != "" {
-		operatorScanJobRetryAfter = corev1.EnvVar{
+			Value: strconv.Itoa(int(*settings.ConcurrentScanJobsLimit)),
+		},
+		corev1.EnvVar{
 			Name:  "OPERATOR_SCAN_JOB_RETRY_AFTER",
-			Value: cr.Spec.ScanJobRetryAfter}
-	}
-
-	result = append(result, operatorScanJobRetryAfter)
-
-	operatorCisKubernetesBenchmarkEnabled := corev1.EnvVar{
-		Name:  "OPERATOR_CIS_KUBERNETES_BENCHMARK_ENABLED",
-		Value: consts.OperatorCisKubernetesBenchmarkEnabled,
-	}
-
-	if cr.Spec.CisKubernetesBenchmarkEnabled != "" {
-		operatorCisKubernetesBenchmarkEnabled = corev1.EnvVar{
+			Value: settings.ScanJobRetryAfter.Duration.String(),
+		},
+		corev1.EnvVar{
 			Name:  "OPERATOR_CIS_KUBERNETES_BENCHMARK_ENABLED",
-			Value: cr.Spec.CisKubernetesBenchmarkEnabled}
-	}
-
-	result = append(result, operatorCisKubernetesBenchmarkEnabled)
-
-	operatorVulnerabilityScannerEnabled := corev1.EnvVar{
-		Name:  "OPERATOR_VULNERABILITY_SCANNER_ENABLED",
-		Value: consts.OperatorVulnerabilityScannerEnabled,
-	}
-
-	if cr.Spec.VulnerabilityScannerEnabled != "" {
-		operatorVulnerabilityScannerEnabled = corev1.EnvVar{
+			Value: strconv.FormatBool(*settings.CisKubernetesBenchmarkEnabled),
+		},
+		corev1.EnvVar{
 			Name:  "OPERATOR_VULNERABILITY_SCANNER_ENABLED",
-			Value: cr.Spec.VulnerabilityScannerEnabled}
-	}
-
-	result = append(result, operatorVulnerabilityScannerEnabled)
-
-	operatorBatchDeleteLimit := corev1.EnvVar{
-		Name:  "OPERATOR_BATCH_DELETE_LIMIT",
-		Value: consts.OperatorBatchDeleteLimit,
-	}
-
-	if cr.Spec.BatchDeleteLimit != "" {
-		operatorBatchDeleteLimit = corev1.EnvVar{
+			Value: strconv.FormatBool(*settings.VulnerabilityScannerEnabled),
+		},
+		corev1.EnvVar{
 			Name:  "OPERATOR_BATCH_DELETE_LIMIT",
-			Value: cr.Spec.BatchDeleteLimit}
-	}
-
-	result = append(result, operatorBatchDeleteLimit)
-
-	operatorBatchDeleteDelay := corev1.EnvVar{
-		Name:  "OPERATOR_BATCH_DELETE_DELAY",
-		Value: consts.OperatorBatchDeleteDelay,
-	}
-
-	if cr.Spec.BatchDeleteDelay != "" {
-		operatorBatchDeleteDelay = corev1.EnvVar{
-			Name:  "OPERATOR_BATCH_DELETE_DELAY",
-			Value: cr.Spec.BatchDeleteDelay}
-	}
-
-	result = append(result, operatorBatchDeleteDelay)
-
-	operatorClusterComplianceEnabled := corev1.EnvVar{
-		Name:  "OPERATOR_CLUSTER_COMPLIANCE_ENABLED",
-		Value: consts.OperatorClusterComplianceEnabled,
-	}
-
-	if cr.Spec.OperatorClusterComplianceEnabled != "" {
-		operatorClusterComplianceEnabled = corev1.EnvVar{
+			Value: strconv.Itoa(int(*settings.BatchDeleteLimit)),
+		},
+		corev1.EnvVar{
 			Name:  "OPERATOR_BATCH_DELETE_DELAY",
-			Value: cr.Spec.BatchDeleteDelay}
-	}
-
-	result = append(result, operatorClusterComplianceEnabled)
-	return result
+			Value: settings.BatchDeleteDelay.Duration.String(),
+		},
+		corev1.EnvVar{
+			Name:  "OPERATOR_CLUSTER_COMPLIANCE_ENABLED",
+			Value: strconv.FormatBool(*settings.ClusterComplianceEnabled),
+		},
+	)
 }
@@ -26,18 +26,19 @@ import (
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/rbac"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
-	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -59,6 +60,7 @@ type AquaStarboardReconciler struct {
 //+kubebuilder:rbac:groups=aquasecurity.aquasec.com,resources=aquastarboards,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=aquasecurity.aquasec.com,resources=aquastarboards/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=aquasecurity.aquasec.com,resources=aquastarboards/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aquasecurity.github.io,resources=aquasecuritysummaries,verbs=get;create
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
@@ -92,6 +94,24 @@ func (r *AquaStarboardReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
+
+	if common2.ReconcilePaused(instance, instance.Spec.Paused) {
+		return reconcile.Result{}, nil
+	}
+
+	scrubbed, err := r.scrubPlaintextSecrets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if scrubbed {
+		reqLogger.Info("Moved plaintext credentials from the AquaStarboard spec into secrets")
+		err = r.Client.Update(context.Background(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	instance = r.updateStarboardObject(instance)
 	r.Client.Update(context.Background(), instance)
 
@@ -101,6 +121,17 @@ func (r *AquaStarboardReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		_ = r.Client.Status().Update(context.Background(), instance)
 	}
 
+	// the settings are checked before any object is changed, a typo in a string setting stops the reconcile
+	if _, err = newAquaStarboardHelper(instance).Settings(); err != nil {
+		reqLogger.Error(err, "Invalid AquaStarboard settings")
+		return reconcile.Result{}, err
+	}
+
+	err = r.migrateScanner(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	_, err = r.addStarboardClusterRole(instance)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -132,17 +163,24 @@ func (r *AquaStarboardReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return reconcile.Result{}, err
 	}
 
-	_, err = r.addStarboardSecret(instance)
+	if newAquaStarboardHelper(instance).Scanner() == v1alpha1.AquaStarboardScannerStarboard {
+		_, err = r.addStarboardSecret(instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	_, err = r.addStarboardDeployment(instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	_, err = r.addStarboardDeployment(instance)
+	err = r.addSecuritySummary()
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return common2.MaintenanceResult(instance.Status.Maintenance), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -156,6 +194,7 @@ func (r *AquaStarboardReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&rbacv1.ClusterRole{}).
 		Owns(&rbacv1.ClusterRoleBinding{}).
 		Owns(&corev1.ConfigMap{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.policyConfigMapRequests)).
 		For(&aquasecurityv1alpha1.AquaStarboard{}).
 		Complete(r)
 }
@@ -169,33 +208,38 @@ func (r *AquaStarboardReconciler) addStarboardDeployment(cr *aquasecurityv1alpha
 	reqLogger := log.WithValues("Starboard deployment phase", "Create Deployment")
 	reqLogger.Info("Start creating deployment")
 	reqLogger.Info("Aqua Starboard", "cr.Spec.Infrastructure.Version", cr.Spec.Infrastructure.Version)
-	pullPolicy, registry, repository, tag := extra.GetImageData("starboard-operator", cr.Spec.Infrastructure.Version, cr.Spec.StarboardService.ImageData, true)
-
 	starboardHelper := newAquaStarboardHelper(cr)
-	deployment := starboardHelper.CreateStarboardDeployment(cr,
-		"starboard-operator",
-		"starboard-operator",
+	version, imageData := starboardHelper.ImageData()
+	pullPolicy, registry, repository, tag := extra.GetImageData(starboardHelper.OperatorName(), version, imageData, true)
+
+	deployment, err := starboardHelper.CreateStarboardDeployment(cr,
+		starboardHelper.OperatorName(),
+		starboardHelper.OperatorName(),
 		registry,
 		tag,
 		pullPolicy,
 		repository)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := common2.MapPodImages(&deployment.Spec.Template.Spec); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	// Set AquaStarboard instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, deployment, r.Scheme); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	apply := common2.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 	// Check if this object already exists
 	found := &appsv1.Deployment{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Aqua Starboard: Creating a New deployment", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
-		err = patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment)
-		if err != nil {
-			reqLogger.Error(err, "Unable to set default for k8s-objectmatcher", err)
-		}
-
-		err = r.Client.Create(context.TODO(), deployment)
+		_, err = apply.Apply("AquaStarboard deployment", deployment)
 		if err != nil {
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -206,18 +250,27 @@ func (r *AquaStarboardReconciler) addStarboardDeployment(cr *aquasecurityv1alpha
 	}
 
 	if found != nil {
+		drift := common2.NewAquaDriftHelper(cr.Spec.DriftPolicy, &cr.Status.Drifts, r.Client, cr)
+		if _, err = drift.Reconcile(found, deployment); err != nil {
+			return reconcile.Result{}, err
+		}
 
-		update, err := k8s.CheckForK8sObjectUpdate("AquaStarboard deployment", found, deployment)
+		maintenance := common2.NewAquaMaintenanceHelper(cr.Spec.MaintenanceWindow, &cr.Status.Maintenance, r.Client, cr)
+		wait, err := maintenance.Defer(fmt.Sprintf("Deployment %s image update", found.Name),
+			common2.ImagesChanged(found.Spec.Template.Spec, deployment.Spec.Template.Spec))
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
 
+		update, err := apply.Apply("AquaStarboard deployment", deployment)
+		if err != nil {
+			reqLogger.Error(err, "Aqua Starboard: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
+			return reconcile.Result{}, err
+		}
 		if update {
-			err = r.Client.Update(context.Background(), deployment)
-			if err != nil {
-				reqLogger.Error(err, "Aqua Starboard: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
-				return reconcile.Result{}, err
-			}
 			// Spec updated - return and requeue
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -258,6 +311,48 @@ func (r *AquaStarboardReconciler) addStarboardDeployment(cr *aquasecurityv1alpha
 	return reconcile.Result{}, nil
 }
 
+// migrateScanner removes the objects of the scanner deployed before, once the CR switches to the other scanner, and
+// records the scanner deployed in the status. The CRs created before the scanner was selectable run starboard
+func (r *AquaStarboardReconciler) migrateScanner(cr *aquasecurityv1alpha1.AquaStarboard) error {
+	reqLogger := log.WithValues("Starboard Migration Phase", "Migrate Scanner")
+
+	starboardHelper := newAquaStarboardHelper(cr)
+	scanner := starboardHelper.Scanner()
+	if cr.Status.Scanner == scanner {
+		return nil
+	}
+
+	previous := cr.Status.Scanner
+	if previous == "" {
+		previous = v1alpha1.AquaStarboardScannerStarboard
+	}
+	if previous != scanner {
+		reqLogger.Info("Aqua Starboard: Switching scanner", "From", previous, "To", scanner)
+		for _, obj := range starboardHelper.ScannerObjects(previous) {
+			err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			// the objects of another CR or installation are left as is
+			if !metav1.IsControlledBy(obj, cr) {
+				continue
+			}
+
+			reqLogger.Info("Aqua Starboard: Removing object of the previous scanner", "Kind", fmt.Sprintf("%T", obj), "Name", obj.GetName())
+			err = r.Client.Delete(context.TODO(), obj)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	cr.Status.Scanner = scanner
+	return r.Client.Status().Update(context.Background(), cr)
+}
+
 func (r *AquaStarboardReconciler) updateStarboardServerObject(serviceObject *v1alpha1.AquaService, StarboardImageData *v1alpha1.AquaImage) *v1alpha1.AquaService {
 
 	if serviceObject == nil {
@@ -278,6 +373,20 @@ func (r *AquaStarboardReconciler) updateStarboardServerObject(serviceObject *v1a
 	return serviceObject
 }
 
+// scrubPlaintextSecrets moves the plaintext registry password of the spec into an owned secret and sets the matching ref
+func (r *AquaStarboardReconciler) scrubPlaintextSecrets(cr *aquasecurityv1alpha1.AquaStarboard) (bool, error) {
+	if cr.Spec.RegistryData == nil {
+		return false, nil
+	}
+
+	return common2.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.RegistryData.Password,
+		&cr.Spec.RegistryData.PasswordSecretRef,
+		fmt.Sprintf(consts.RegistryPasswordSecretName, cr.Name),
+		consts.RegistryPasswordSecretKey,
+		"Secret for aqua registry password")
+}
+
 func (r *AquaStarboardReconciler) updateStarboardObject(cr *aquasecurityv1alpha1.AquaStarboard) *aquasecurityv1alpha1.AquaStarboard {
 
 	cr.Spec.Infrastructure = common2.UpdateAquaInfrastructureFull(cr.Spec.Infrastructure, cr.Name, cr.Namespace, "starboard")
@@ -296,42 +405,18 @@ func (r *AquaStarboardReconciler) addStarboardClusterRole(cr *aquasecurityv1alph
 		return reconcile.Result{}, err
 	}
 
-	// Check if this ClusterRole already exists
-	found := &rbacv1.ClusterRole{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: crole.Name}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua Starboard: Creating a New ClusterRole", "ClusterRole.Namespace", crole.Namespace, "ClusterRole.Name", crole.Name)
-		err = r.Client.Create(context.TODO(), crole)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if the ClusterRole Rules, matches the found Rules
-	equal, err := k8s.CompareByHash(crole.Rules, found.Rules)
-
+	apply := common2.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+	update, err := apply.Apply("AquaStarboard cluster role", crole)
 	if err != nil {
+		log.Error(err, "Failed to apply ClusterRole", "ClusterRole.Namespace", crole.Namespace, "ClusterRole.Name", crole.Name)
 		return reconcile.Result{}, err
 	}
-
-	if !equal {
-		found = crole
-		log.Info("Aqua Starboard: Updating ClusterRole", "ClusterRole.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
-		err := r.Client.Update(context.TODO(), found)
-		if err != nil {
-			log.Error(err, "Failed to update ClusterRole", "ClusterRole.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
-			return reconcile.Result{}, err
-		}
-
+	if update {
 		return reconcile.Result{Requeue: true}, nil
 	}
 
 	// ClusterRole already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua ClusterRole Exists", "ClusterRole.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
+	reqLogger.Info("Skip reconcile: Aqua ClusterRole Exists", "ClusterRole.Namespace", crole.Namespace, "ClusterRole.Name", crole.Name)
 	return reconcile.Result{Requeue: true}, nil
 }
 
@@ -379,10 +464,10 @@ func (r *AquaStarboardReconciler) addStarboardClusterRoleBinding(cr *aquasecurit
 	starboardHelper := newAquaStarboardHelper(cr)
 	crb := starboardHelper.CreateClusterRoleBinding(cr.Name,
 		cr.Namespace,
-		"starboard-operator",
+		starboardHelper.OperatorName(),
 		"ke-crb",
 		cr.Spec.Infrastructure.ServiceAccount,
-		"starboard-operator")
+		starboardHelper.OperatorName())
 
 	// Set AquaStarboard instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, crb, r.Scheme); err != nil {
@@ -416,18 +501,42 @@ func (r *AquaStarboardReconciler) addStarboardConfigMap(cr *aquasecurityv1alpha1
 
 	// Define a new ClusterRoleBinding object
 	starboardHelper := newAquaStarboardHelper(cr)
-	configMaps := []*corev1.ConfigMap{
-		starboardHelper.CreateStarboardConftestConfigMap(cr.Name,
-			cr.Namespace,
-			"starboard-policies-config",
-			"starboard-policies-configmap",
-			cr.Spec.KubeEnforcerVersion,
-		),
-		starboardHelper.CreateStarboardConfigMap(cr.Name,
+	var configMaps []*corev1.ConfigMap
+	if starboardHelper.Scanner() == v1alpha1.AquaStarboardScannerTrivyOperator {
+		trivyConfigMaps, err := starboardHelper.CreateTrivyOperatorConfigMaps(cr.Name, cr.Namespace)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		configMaps = trivyConfigMaps
+	} else {
+		starboardConfigMap, err := starboardHelper.CreateStarboardConfigMap(cr.Name,
 			cr.Namespace,
 			"starboard",
 			"starboard",
-		),
+		)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		trivyConfigMap, err := starboardHelper.CreateStarboardTrivyConfigMap(cr.Name, cr.Namespace)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		policies, err := r.starboardPolicies(cr)
+		if err != nil {
+			reqLogger.Error(err, "Invalid AquaStarboard policies")
+			return reconcile.Result{}, err
+		}
+		configMaps = []*corev1.ConfigMap{
+			starboardHelper.CreateStarboardConftestConfigMap(cr.Name,
+				cr.Namespace,
+				"starboard-policies-config",
+				"starboard-policies-configmap",
+				cr.Spec.KubeEnforcerVersion,
+				policies,
+			),
+			starboardConfigMap,
+			trivyConfigMap,
+		}
 	}
 
 	configMapsData := make(map[string]string)
@@ -451,12 +560,15 @@ func (r *AquaStarboardReconciler) addStarboardConfigMap(cr *aquasecurityv1alpha1
 		if err := controllerutil.SetControllerReference(cr, configMap, r.Scheme); err != nil {
 			return reconcile.Result{}, err
 		}
+
+		apply := common2.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 		// Check if ConfigMap already exists
 		foundConfigMap := &corev1.ConfigMap{}
 		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, foundConfigMap)
 		if err != nil && errors.IsNotFound(err) {
 			reqLogger.Info("Aqua Starboard: Creating a New ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
-			err = r.Client.Create(context.TODO(), configMap)
+			_, err = apply.Apply("AquaStarboard configmap", configMap)
 
 			if err != nil {
 				reqLogger.Error(err, fmt.Sprintf("Failed to create configmap name: %s", configMap.Name))
@@ -467,15 +579,20 @@ func (r *AquaStarboardReconciler) addStarboardConfigMap(cr *aquasecurityv1alpha1
 			return reconcile.Result{}, err
 		}
 
-		// Check if the ConfigMap Data, matches the found Data
-		if !equality.Semantic.DeepDerivative(configMap.Data, foundConfigMap.Data) {
-			foundConfigMap = configMap
-			log.Info("Aqua Starboard: Updating ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-			err := r.Client.Update(context.TODO(), foundConfigMap)
-			if err != nil {
-				log.Error(err, "Aqua Starboard: Failed to update ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-				return reconcile.Result{}, err
-			}
+		drift := common2.NewAquaDriftHelper(cr.Spec.DriftPolicy, &cr.Status.Drifts, r.Client, cr)
+		_, err = drift.Reconcile(foundConfigMap, configMap)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// Apply the ConfigMap Data, reverting the manual changes the drift policy enforces
+		update, err := apply.Apply("AquaStarboard configmap", configMap)
+		if err != nil {
+			log.Error(err, "Aqua Starboard: Failed to apply ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
+			return reconcile.Result{}, err
+		}
+		if update {
+			log.Info("Aqua Starboard: Applied ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
 			return reconcile.Result{Requeue: true}, nil
 		}
 
@@ -485,6 +602,72 @@ func (r *AquaStarboardReconciler) addStarboardConfigMap(cr *aquasecurityv1alpha1
 	return reconcile.Result{Requeue: requeue}, nil
 }
 
+// starboardPolicies assembles the policies of the CR with the ConfigMaps they reference
+func (r *AquaStarboardReconciler) starboardPolicies(cr *aquasecurityv1alpha1.AquaStarboard) (map[string]string, error) {
+	starboardHelper := newAquaStarboardHelper(cr)
+
+	configMaps := map[string]*corev1.ConfigMap{}
+	for _, name := range starboardHelper.PolicyConfigMaps() {
+		configMap := &corev1.ConfigMap{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, configMap)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		configMaps[name] = configMap
+	}
+
+	return starboardHelper.PoliciesData(configMaps)
+}
+
+// policyConfigMapRequests enqueues the AquaStarboards whose policies reference the ConfigMap, so a policy change rolls
+// starboard
+func (r *AquaStarboardReconciler) policyConfigMapRequests(obj client.Object) []reconcile.Request {
+	list := &aquasecurityv1alpha1.AquaStarboardList{}
+	if err := r.Client.List(context.TODO(), list, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Failed to list the AquaStarboards of a policies ConfigMap", "ConfigMap.Namespace", obj.GetNamespace(), "ConfigMap.Name", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, item := range list.Items {
+		if policiesReference(item.Spec.Policies, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace}})
+		}
+	}
+
+	return requests
+}
+
+// addSecuritySummary creates the AquaSecuritySummary of the cluster aggregating the reports of starboard. It is
+// cluster scoped, so it isn't owned by the AquaStarboard and outlives it
+func (r *AquaStarboardReconciler) addSecuritySummary() error {
+	found := &aquasecurityv1alpha1.AquaSecuritySummary{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: consts.SecuritySummaryName}, found)
+	if err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	summary := &aquasecurityv1alpha1.AquaSecuritySummary{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: consts.SecuritySummaryName,
+			Labels: map[string]string{
+				"deployedby": "aqua-operator",
+			},
+		},
+	}
+	log.Info("Aqua Starboard: Creating the AquaSecuritySummary", "AquaSecuritySummary.Name", summary.Name)
+	err = r.Client.Create(context.TODO(), summary)
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+
+	return err
+}
+
 func (r *AquaStarboardReconciler) addStarboardSecret(cr *aquasecurityv1alpha1.AquaStarboard) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Starboard Requirements Phase", "Create Token Secret")
 	reqLogger.Info("Start creating token secret")
@@ -531,13 +714,18 @@ func (r *AquaStarboardReconciler) CreateImagePullSecret(cr *aquasecurityv1alpha1
 	reqLogger := log.WithValues("Starboard Requirements Phase", "Create Image Pull Secret")
 	reqLogger.Info("Start creating aqua images pull secret")
 
+	registry, err := secrets.GetRegistryData(r.Client, cr.Namespace, *cr.Spec.RegistryData)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Define a new secret object
 	secret := secrets.CreatePullImageSecret(
 		cr.Name,
 		cr.Namespace,
 		"ke-image-pull-secret",
 		cr.Spec.Config.ImagePullSecret,
-		*cr.Spec.RegistryData)
+		registry)
 
 	// Set AquaStarboardKind instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
@@ -546,7 +734,7 @@ func (r *AquaStarboardReconciler) CreateImagePullSecret(cr *aquasecurityv1alpha1
 
 	// Check if this secret already exists
 	found := &corev1.Secret{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Creating a New Aqua Image Pull Secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
 		err = r.Client.Create(context.TODO(), secret)
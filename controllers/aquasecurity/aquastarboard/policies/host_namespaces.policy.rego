@@ -0,0 +1,30 @@
+package aqua.kubernetes.host_namespaces
+
+import data.lib.kubernetes
+
+__rego_metadata__ := {
+	"id": "AQUA-KSV-002",
+	"title": "Access to host namespaces",
+	"severity": "HIGH",
+	"type": "Kubernetes Security Check",
+	"description": "A pod sharing the PID, IPC or network namespace of the host can see and reach its processes and interfaces.",
+	"recommended_actions": "Don't set hostPID, hostIPC and hostNetwork.",
+}
+
+host_namespaces[namespace] {
+	pod := kubernetes.pods[_]
+	namespace := ["hostPID", "hostIPC", "hostNetwork"][_]
+	pod.spec[namespace] == true
+}
+
+deny[res] {
+	namespace := host_namespaces[_]
+	msg := sprintf("%s '%s' sets %s", [kubernetes.kind, kubernetes.name, namespace])
+	res := {
+		"msg": msg,
+		"id": __rego_metadata__.id,
+		"title": __rego_metadata__.title,
+		"severity": __rego_metadata__.severity,
+		"type": __rego_metadata__.type,
+	}
+}
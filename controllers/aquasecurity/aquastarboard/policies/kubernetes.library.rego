@@ -0,0 +1,34 @@
+package lib.kubernetes
+
+object = input
+
+name = object.metadata.name
+
+kind = object.kind
+
+# pods returns the pod spec of a pod, a workload or a cron job
+pods[pod] {
+	kind == "Pod"
+	pod := object
+}
+
+pods[pod] {
+	kind != "Pod"
+	kind != "CronJob"
+	pod := object.spec.template
+}
+
+pods[pod] {
+	kind == "CronJob"
+	pod := object.spec.jobTemplate.spec.template
+}
+
+containers[container] {
+	pod := pods[_]
+	container := pod.spec.containers[_]
+}
+
+containers[container] {
+	pod := pods[_]
+	container := pod.spec.initContainers[_]
+}
@@ -0,0 +1,25 @@
+package aqua.kubernetes.privileged_container
+
+import data.lib.kubernetes
+
+__rego_metadata__ := {
+	"id": "AQUA-KSV-001",
+	"title": "Privileged container",
+	"severity": "HIGH",
+	"type": "Kubernetes Security Check",
+	"description": "A privileged container has the capabilities of the host, it can escape its isolation.",
+	"recommended_actions": "Set securityContext.privileged to false.",
+}
+
+deny[res] {
+	container := kubernetes.containers[_]
+	container.securityContext.privileged == true
+	msg := sprintf("Container '%s' of %s '%s' is privileged", [container.name, kubernetes.kind, kubernetes.name])
+	res := {
+		"msg": msg,
+		"id": __rego_metadata__.id,
+		"title": __rego_metadata__.title,
+		"severity": __rego_metadata__.severity,
+		"type": __rego_metadata__.type,
+	}
+}
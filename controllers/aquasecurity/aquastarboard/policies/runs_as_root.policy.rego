@@ -0,0 +1,31 @@
+package aqua.kubernetes.runs_as_root
+
+import data.lib.kubernetes
+
+__rego_metadata__ := {
+	"id": "AQUA-KSV-003",
+	"title": "Container may run as root",
+	"severity": "MEDIUM",
+	"type": "Kubernetes Security Check",
+	"description": "A container that may run as root has more privileges on the node when it escapes its isolation.",
+	"recommended_actions": "Set securityContext.runAsNonRoot to true on the pod or the container.",
+}
+
+runs_as_root[container] {
+	pod := kubernetes.pods[_]
+	container := pod.spec.containers[_]
+	not container.securityContext.runAsNonRoot
+	not pod.spec.securityContext.runAsNonRoot
+}
+
+deny[res] {
+	container := runs_as_root[_]
+	msg := sprintf("Container '%s' of %s '%s' may run as root", [container.name, kubernetes.kind, kubernetes.name])
+	res := {
+		"msg": msg,
+		"id": __rego_metadata__.id,
+		"title": __rego_metadata__.title,
+		"severity": __rego_metadata__.severity,
+		"type": __rego_metadata__.type,
+	}
+}
@@ -0,0 +1,25 @@
+package aqua.kubernetes.writable_root_filesystem
+
+import data.lib.kubernetes
+
+__rego_metadata__ := {
+	"id": "AQUA-KSV-004",
+	"title": "Root file system is not read-only",
+	"severity": "LOW",
+	"type": "Kubernetes Security Check",
+	"description": "A writable root file system lets an attacker change the binaries of the container.",
+	"recommended_actions": "Set securityContext.readOnlyRootFilesystem to true.",
+}
+
+deny[res] {
+	container := kubernetes.containers[_]
+	not container.securityContext.readOnlyRootFilesystem == true
+	msg := sprintf("Container '%s' of %s '%s' has a writable root file system", [container.name, kubernetes.kind, kubernetes.name])
+	res := {
+		"msg": msg,
+		"id": __rego_metadata__.id,
+		"title": __rego_metadata__.title,
+		"severity": __rego_metadata__.severity,
+		"type": __rego_metadata__.type,
+	}
+}
@@ -0,0 +1,140 @@
+package aquastarboard
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/rego"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultPolicies The policy bundle shipped with the operator, <name>.policy.rego and <name>.library.rego files
+//
+//go:embed policies/*.rego
+var defaultPolicies embed.FS
+
+var policyName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// PolicyConfigMaps returns the names of the ConfigMaps the policies of the CR reference
+func (enf *AquaStarboardHelper) PolicyConfigMaps() []string {
+	policies := enf.Parameters.Starboard.Spec.Policies
+	if policies == nil {
+		return nil
+	}
+
+	names := []string{}
+	for _, configMap := range policies.ConfigMaps {
+		names = append(names, configMap.Name)
+	}
+
+	return names
+}
+
+// PoliciesData assembles the policies of the CR, the default bundle, the referenced ConfigMaps and the inline modules,
+// into the conftest.policy.<name>.rego, conftest.policy.<name>.kinds and conftest.library.<name>.rego keys starboard
+// reads. configMaps holds the referenced ConfigMaps by name. Each module is checked, a name used twice is an error
+func (enf *AquaStarboardHelper) PoliciesData(configMaps map[string]*corev1.ConfigMap) (map[string]string, error) {
+	data := map[string]string{}
+	policies := enf.Parameters.Starboard.Spec.Policies
+	if policies == nil {
+		return data, nil
+	}
+
+	add := func(source, name, module string, library bool, kinds []string) error {
+		if !policyName.MatchString(name) {
+			return fmt.Errorf("%s: invalid policy name %q", source, name)
+		}
+		if err := rego.Check(name+".rego", module); err != nil {
+			return fmt.Errorf("%s: policy %s: %v", source, name, err)
+		}
+
+		if library {
+			key := fmt.Sprintf("conftest.library.%s.rego", name)
+			if _, ok := data[key]; ok {
+				return fmt.Errorf("%s: library %s is defined twice", source, name)
+			}
+			data[key] = module
+			return nil
+		}
+
+		key := fmt.Sprintf("conftest.policy.%s.rego", name)
+		if _, ok := data[key]; ok {
+			return fmt.Errorf("%s: policy %s is defined twice", source, name)
+		}
+		data[key] = module
+		if len(kinds) == 0 {
+			kinds = []string{"Workload"}
+		}
+		data[fmt.Sprintf("conftest.policy.%s.kinds", name)] = strings.Join(kinds, ",")
+
+		return nil
+	}
+
+	if policies.DefaultBundle {
+		files, err := defaultPolicies.ReadDir("policies")
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			module, err := defaultPolicies.ReadFile(path.Join("policies", file.Name()))
+			if err != nil {
+				return nil, err
+			}
+			name := strings.TrimSuffix(file.Name(), ".rego")
+			library := strings.HasSuffix(name, ".library")
+			name = strings.TrimSuffix(strings.TrimSuffix(name, ".library"), ".policy")
+			if err := add("default bundle", name, string(module), library, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, ref := range policies.ConfigMaps {
+		configMap, ok := configMaps[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("policies ConfigMap %s not found", ref.Name)
+		}
+
+		// the keys are sorted so the error of a duplicate is the same on every reconcile
+		keys := []string{}
+		for key := range configMap.Data {
+			if strings.HasSuffix(key, ".rego") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			source := fmt.Sprintf("ConfigMap %s", ref.Name)
+			if err := add(source, strings.TrimSuffix(key, ".rego"), configMap.Data[key], ref.Library, ref.Kinds); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, module := range policies.Modules {
+		if err := add("modules", module.Name, module.Rego, module.Library, module.Kinds); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// policiesReference returns true when the policies of the CR reference the ConfigMap
+func policiesReference(policies *v1alpha1.AquaStarboardPolicies, name string) bool {
+	if policies == nil {
+		return false
+	}
+	for _, configMap := range policies.ConfigMaps {
+		if configMap.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,198 @@
+package aquastarboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Settings returns the settings of the scanner with every value set: the defaults, overridden by the deprecated
+// string fields of the spec, overridden by spec.settings. A string field that doesn't parse is an error, so a typo
+// isn't passed on to the scanner
+func (enf *AquaStarboardHelper) Settings() (*v1alpha1.AquaStarboardSettings, error) {
+	spec := enf.Parameters.Starboard.Spec
+
+	concurrentScanJobsLimit, err := parseInt32("concurrentScanJobsLimit", spec.ConcurrentScanJobsLimit, consts.OperatorConcurrentScanJobsLimit)
+	if err != nil {
+		return nil, err
+	}
+	scanJobRetryAfter, err := parseDuration("scanJobRetryAfter", spec.ScanJobRetryAfter, consts.OperatorScanJobRetryAfter)
+	if err != nil {
+		return nil, err
+	}
+	cisKubernetesBenchmarkEnabled, err := parseBool("cisKubernetesBenchmarkEnabled", spec.CisKubernetesBenchmarkEnabled, consts.OperatorCisKubernetesBenchmarkEnabled)
+	if err != nil {
+		return nil, err
+	}
+	vulnerabilityScannerEnabled, err := parseBool("vulnerabilityScannerEnabled", spec.VulnerabilityScannerEnabled, consts.OperatorVulnerabilityScannerEnabled)
+	if err != nil {
+		return nil, err
+	}
+	clusterComplianceEnabled, err := parseBool("operatorClusterComplianceEnabled", spec.OperatorClusterComplianceEnabled, consts.OperatorClusterComplianceEnabled)
+	if err != nil {
+		return nil, err
+	}
+	batchDeleteLimit, err := parseInt32("batchDeleteLimit", spec.BatchDeleteLimit, consts.OperatorBatchDeleteLimit)
+	if err != nil {
+		return nil, err
+	}
+	batchDeleteDelay, err := parseDuration("batchDeleteDelay", spec.BatchDeleteDelay, consts.OperatorBatchDeleteDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &v1alpha1.AquaStarboardSettings{
+		ConcurrentScanJobsLimit:       &concurrentScanJobsLimit,
+		ScanJobRetryAfter:             &scanJobRetryAfter,
+		CisKubernetesBenchmarkEnabled: &cisKubernetesBenchmarkEnabled,
+		VulnerabilityScannerEnabled:   &vulnerabilityScannerEnabled,
+		ClusterComplianceEnabled:      &clusterComplianceEnabled,
+		BatchDeleteLimit:              &batchDeleteLimit,
+		BatchDeleteDelay:              &batchDeleteDelay,
+		ConfigAuditScanner:            "Conftest",
+		ExcludeNamespaces:             strings.Split(consts.OperatorExcludeNamespaces, ","),
+		ScanJobResources:              defaultScanJobResources(),
+	}
+
+	typed := spec.Settings
+	if typed == nil {
+		return settings, nil
+	}
+
+	if typed.ConcurrentScanJobsLimit != nil {
+		settings.ConcurrentScanJobsLimit = typed.ConcurrentScanJobsLimit
+	}
+	if typed.ScanJobRetryAfter != nil {
+		settings.ScanJobRetryAfter = typed.ScanJobRetryAfter
+	}
+	if typed.CisKubernetesBenchmarkEnabled != nil {
+		settings.CisKubernetesBenchmarkEnabled = typed.CisKubernetesBenchmarkEnabled
+	}
+	if typed.VulnerabilityScannerEnabled != nil {
+		settings.VulnerabilityScannerEnabled = typed.VulnerabilityScannerEnabled
+	}
+	if typed.ClusterComplianceEnabled != nil {
+		settings.ClusterComplianceEnabled = typed.ClusterComplianceEnabled
+	}
+	if typed.BatchDeleteLimit != nil {
+		settings.BatchDeleteLimit = typed.BatchDeleteLimit
+	}
+	if typed.BatchDeleteDelay != nil {
+		settings.BatchDeleteDelay = typed.BatchDeleteDelay
+	}
+	if typed.ConfigAuditScanner != "" {
+		settings.ConfigAuditScanner = typed.ConfigAuditScanner
+	}
+	if len(typed.TargetNamespaces) > 0 {
+		settings.TargetNamespaces = typed.TargetNamespaces
+	}
+	if len(typed.ExcludeNamespaces) > 0 {
+		settings.ExcludeNamespaces = typed.ExcludeNamespaces
+	}
+	if len(typed.ScanJobTolerations) > 0 {
+		settings.ScanJobTolerations = typed.ScanJobTolerations
+	}
+	if typed.ScanJobResources != nil {
+		// only the quantities set replace the defaults
+		for name, quantity := range typed.ScanJobResources.Requests {
+			settings.ScanJobResources.Requests[name] = quantity
+		}
+		for name, quantity := range typed.ScanJobResources.Limits {
+			settings.ScanJobResources.Limits[name] = quantity
+		}
+	}
+
+	if *settings.ConcurrentScanJobsLimit < 1 {
+		return nil, fmt.Errorf("settings.concurrentScanJobsLimit must be at least 1, got %d", *settings.ConcurrentScanJobsLimit)
+	}
+	if *settings.BatchDeleteLimit < 1 {
+		return nil, fmt.Errorf("settings.batchDeleteLimit must be at least 1, got %d", *settings.BatchDeleteLimit)
+	}
+
+	return settings, nil
+}
+
+// defaultScanJobResources The resources of the trivy container of the scan jobs when none are set
+func defaultScanJobResources() *corev1.ResourceRequirements {
+	return &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("100M"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("500M"),
+		},
+	}
+}
+
+// scanJobConfigData The scan job keys of the starboard and trivy-operator ConfigMaps
+func scanJobConfigData(settings *v1alpha1.AquaStarboardSettings) (map[string]string, error) {
+	data := map[string]string{}
+	if len(settings.ScanJobTolerations) > 0 {
+		tolerations, err := json.Marshal(settings.ScanJobTolerations)
+		if err != nil {
+			return nil, err
+		}
+		data["scanJob.tolerations"] = string(tolerations)
+	}
+
+	return data, nil
+}
+
+// scanJobResourcesData The resource keys of a scanner plugin ConfigMap, e.g. trivy.resources.limits.cpu
+func scanJobResourcesData(plugin string, resources *corev1.ResourceRequirements) map[string]string {
+	data := map[string]string{}
+	for name, quantity := range resources.Requests {
+		data[fmt.Sprintf("%s.resources.requests.%s", plugin, name)] = quantity.String()
+	}
+	for name, quantity := range resources.Limits {
+		data[fmt.Sprintf("%s.resources.limits.%s", plugin, name)] = quantity.String()
+	}
+
+	return data
+}
+
+func parseInt32(field, value, defaultValue string) (int32, error) {
+	if value == "" {
+		value = defaultValue
+	}
+	i, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %q isn't a number", field, value)
+	}
+
+	return int32(i), nil
+}
+
+func parseBool(field, value, defaultValue string) (bool, error) {
+	if value == "" {
+		value = defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s: %q isn't true or false", field, value)
+	}
+
+	return b, nil
+}
+
+func parseDuration(field, value, defaultValue string) (metav1.Duration, error) {
+	if value == "" {
+		value = defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return metav1.Duration{}, fmt.Errorf("%s: %q isn't a duration, e.g. 30s", field, value)
+	}
+
+	return metav1.Duration{Duration: d}, nil
+}
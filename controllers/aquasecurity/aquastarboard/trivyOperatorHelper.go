@@ -0,0 +1,235 @@
+package aquastarboard
+
+import (
+	"fmt"
+
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Scanner returns the operator deployed for the reports, starboard unless trivy-operator is set
+func (enf *AquaStarboardHelper) Scanner() v1alpha1.AquaStarboardScanner {
+	if enf.Parameters.Starboard.Spec.Scanner == v1alpha1.AquaStarboardScannerTrivyOperator {
+		return v1alpha1.AquaStarboardScannerTrivyOperator
+	}
+
+	return v1alpha1.AquaStarboardScannerStarboard
+}
+
+// OperatorName returns the name of the deployment, cluster role and cluster role binding of the scanner
+func (enf *AquaStarboardHelper) OperatorName() string {
+	return operatorName(enf.Scanner())
+}
+
+func operatorName(scanner v1alpha1.AquaStarboardScanner) string {
+	if scanner == v1alpha1.AquaStarboardScannerTrivyOperator {
+		return consts.TrivyOperatorName
+	}
+
+	return consts.StarboardOperatorName
+}
+
+// ImageData returns the version and image of the scanner. The image set for starboard-operator, the repository
+// the AquaCsp and older CRs set, isn't used for trivy-operator, its registry is kept
+func (enf *AquaStarboardHelper) ImageData() (string, *v1alpha1.AquaImage) {
+	cr := enf.Parameters.Starboard
+	if enf.Scanner() != v1alpha1.AquaStarboardScannerTrivyOperator {
+		return cr.Spec.Infrastructure.Version, cr.Spec.StarboardService.ImageData
+	}
+
+	imageData := cr.Spec.StarboardService.ImageData.DeepCopy()
+	if imageData != nil && (imageData.Repository == "" || imageData.Repository == consts.StarboardOperatorName) {
+		imageData.Repository = consts.TrivyOperatorName
+		imageData.Tag = ""
+	}
+
+	return consts.TrivyOperatorVersion, imageData
+}
+
+// ScannerObjects returns the objects deployed for the scanner, the ones removed when the CR switches to the other
+// scanner
+func (enf *AquaStarboardHelper) ScannerObjects(scanner v1alpha1.AquaStarboardScanner) []client.Object {
+	namespace := enf.Parameters.Starboard.Namespace
+	name := operatorName(scanner)
+
+	objects := []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: name}},
+	}
+
+	configMaps := []string{"starboard", "starboard-policies-config"}
+	if scanner == v1alpha1.AquaStarboardScannerTrivyOperator {
+		configMaps = []string{"trivy-operator", "trivy-operator-trivy-config", "trivy-operator-policies-config"}
+	} else {
+		configMaps = append(configMaps, "starboard-trivy-config")
+		objects = append(objects, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "aqua-starboard-token", Namespace: namespace}})
+	}
+	for _, configMap := range configMaps {
+		objects = append(objects, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: configMap, Namespace: namespace}})
+	}
+
+	return objects
+}
+
+// trivyOperatorRules The rules of starboard, with the report kinds trivy-operator adds
+func trivyOperatorRules(starboardRules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{}
+	for _, rule := range starboardRules {
+		if len(rule.APIGroups) == 1 && rule.APIGroups[0] == "aquasecurity.github.io" {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return append(rules,
+		rbacv1.PolicyRule{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"namespaces",
+			},
+			Verbs: []string{
+				"get", "list", "watch",
+			},
+		},
+		rbacv1.PolicyRule{
+			APIGroups: []string{
+				"aquasecurity.github.io",
+			},
+			Resources: []string{
+				"vulnerabilityreports", "exposedsecretreports", "configauditreports", "clusterconfigauditreports",
+				"rbacassessmentreports", "clusterrbacassessmentreports", "infraassessmentreports",
+				"clustercompliancereports", "clustercompliancereports/status", "clustercompliancedetailreports",
+			},
+			Verbs: []string{
+				"get", "list", "watch", "create", "update", "patch", "delete",
+			},
+		},
+	)
+}
+
+// CreateTrivyOperatorConfigMaps The configuration of trivy-operator and of the trivy scan jobs, and the policies
+// of its config audit scanner
+func (enf *AquaStarboardHelper) CreateTrivyOperatorConfigMaps(cr, namespace string) ([]*corev1.ConfigMap, error) {
+	settings, err := enf.Settings()
+	if err != nil {
+		return nil, err
+	}
+	data, err := scanJobConfigData(settings)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range map[string]string{
+		"vulnerabilityReports.scanner":  "Trivy",
+		"configAuditReports.scanner":    "Trivy",
+		"report.recordFailedChecksOnly": "true",
+		"compliance.failEntriesLimit":   "10",
+		"scanJob.compressLogs":          "true",
+		"scanJob.podTemplateLabels":     fmt.Sprintf("deployedby=aqua-operator,aquasecoperator_cr=%s", cr),
+	} {
+		data[k] = v
+	}
+
+	labels := map[string]string{
+		"app":                        consts.TrivyOperatorName,
+		"deployedby":                 "aqua-operator",
+		"aquasecoperator_cr":         cr,
+		"app.kubernetes.io/name":     consts.TrivyOperatorName,
+		"app.kubernetes.io/instance": consts.TrivyOperatorName,
+		"app.kubernetes.io/version":  consts.TrivyOperatorVersion,
+	}
+
+	configMap := func(name, description string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+				Annotations: map[string]string{
+					"description": description,
+				},
+			},
+			Data: data,
+		}
+	}
+
+	return []*corev1.ConfigMap{
+		configMap("trivy-operator", "Deploy Aqua trivy-operator ConfigMap", data),
+		configMap("trivy-operator-trivy-config", "Deploy Aqua trivy-operator trivy ConfigMap", trivyConfigData(settings)),
+		configMap("trivy-operator-policies-config", "Deploy Aqua trivy-operator-policies-config ConfigMap", nil),
+	}, nil
+}
+
+// trivyConfigData The configuration of the trivy scan jobs, with the resources of the settings
+func trivyConfigData(settings *v1alpha1.AquaStarboardSettings) map[string]string {
+	data := map[string]string{
+		"trivy.repository":   "ghcr.io/aquasecurity/trivy",
+		"trivy.tag":          consts.TrivyVersion,
+		"trivy.mode":         "Standalone",
+		"trivy.dbRepository": "ghcr.io/aquasecurity/trivy-db",
+		"trivy.severity":     "UNKNOWN,LOW,MEDIUM,HIGH,CRITICAL",
+		"trivy.timeout":      "5m0s",
+		"trivy.slow":         "true",
+	}
+	for k, v := range scanJobResourcesData("trivy", settings.ScanJobResources) {
+		data[k] = v
+	}
+
+	return data
+}
+
+// getTrivyOperatorEnvVars maps the settings of the CR to the environment of trivy-operator, the benchmark of
+// starboard is replaced by its config audit and RBAC assessment scanners
+func (ebf *AquaStarboardHelper) getTrivyOperatorEnvVars(starboardEnvs []corev1.EnvVar) []corev1.EnvVar {
+	cr := ebf.Parameters.Starboard
+
+	result := []corev1.EnvVar{}
+	for _, env := range starboardEnvs {
+		if env.Name == "OPERATOR_CIS_KUBERNETES_BENCHMARK_ENABLED" {
+			continue
+		}
+		result = append(result, env)
+	}
+
+	return append(result,
+		corev1.EnvVar{
+			Name:  "OPERATOR_SERVICE_ACCOUNT",
+			Value: cr.Spec.Infrastructure.ServiceAccount,
+		},
+		corev1.EnvVar{
+			Name:  "OPERATOR_SCAN_JOB_TIMEOUT",
+			Value: "5m",
+		},
+		corev1.EnvVar{
+			Name:  "OPERATOR_CONFIG_AUDIT_SCANNER_ENABLED",
+			Value: "true",
+		},
+		corev1.EnvVar{
+			Name:  "OPERATOR_RBAC_ASSESSMENT_SCANNER_ENABLED",
+			Value: "true",
+		},
+		corev1.EnvVar{
+			Name:  "OPERATOR_EXPOSED_SECRET_SCANNER_ENABLED",
+			Value: "true",
+		},
+		corev1.EnvVar{
+			Name:  "OPERATOR_VULNERABILITY_SCANNER_SCAN_ONLY_CURRENT_REVISIONS",
+			Value: "true",
+		},
+		corev1.EnvVar{
+			Name:  "OPERATOR_CONFIG_AUDIT_SCANNER_SCAN_ONLY_CURRENT_REVISIONS",
+			Value: "true",
+		},
+	)
+}
@@ -0,0 +1,203 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type ApplyParameters struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	Cr     client.Object
+}
+
+// AquaApplyHelper server-side applies the objects owned by a CR with the field manager of the operator. Only the
+// fields the operator renders are owned by it, the fields set by other controllers, like the replicas of an HPA or
+// the sidecars of a service mesh, are left to them, and the ownership of a field is never forced from them
+type AquaApplyHelper struct {
+	Parameters ApplyParameters
+}
+
+func NewAquaApplyHelper(k8sclient client.Client,
+	scheme *runtime.Scheme,
+	cr client.Object) *AquaApplyHelper {
+	params := ApplyParameters{
+		Client: k8sclient,
+		Scheme: scheme,
+		Cr:     cr,
+	}
+
+	return &AquaApplyHelper{
+		Parameters: params,
+	}
+}
+
+// Apply applies the rendering of the object and returns true when the object was created or changed. The rendered
+// fields another manager set to a different value, like the replicas of an HPA, are left out and stay with that
+// manager, the drift policy of the CR reverts the manual changes before. Only the fields of the earlier updates
+// of the operator are taken back on a conflict. The rendering itself is left as is.
+func (ah *AquaApplyHelper) Apply(objectName string, obj client.Object) (bool, error) {
+	reqLogger := log.WithValues("Apply Phase", "Server-Side Apply")
+
+	desired, found, err := ah.prepare(objectName, obj)
+	if err != nil {
+		return false, err
+	}
+	previous := ""
+	if found != nil {
+		previous = found.GetResourceVersion()
+	}
+
+	applied, err := ah.apply(objectName, desired)
+	if err != nil {
+		reqLogger.Error(err, "Failed to apply", "Object", objectName, "Name", desired.GetName())
+		return false, err
+	}
+
+	changed := applied.GetResourceVersion() != previous
+	if changed {
+		reqLogger.Info(fmt.Sprintf("Applied %s", objectName), "Namespace", desired.GetNamespace(), "Name", desired.GetName(), "Created", len(previous) == 0)
+	}
+
+	return changed, nil
+}
+
+// Changed checks applying the rendering would create or change the object, without applying it
+func (ah *AquaApplyHelper) Changed(obj client.Object) (bool, error) {
+	desired, found, err := ah.prepare("", obj)
+	if err != nil {
+		return false, err
+	}
+	if found == nil {
+		return true, nil
+	}
+
+	applied, err := ah.apply("", desired, client.DryRunAll)
+	if err != nil {
+		return false, err
+	}
+
+	for _, o := range []client.Object{found, applied} {
+		o.GetObjectKind().SetGroupVersionKind(desired.GetObjectKind().GroupVersionKind())
+		o.SetResourceVersion("")
+		o.SetManagedFields(nil)
+	}
+	return !equality.Semantic.DeepEqual(found, applied), nil
+}
+
+// prepare returns a copy of the rendering owned by the CR and ready to apply, without the fields left to other
+// managers, and the live object or nil
+func (ah *AquaApplyHelper) prepare(objectName string, obj client.Object) (client.Object, client.Object, error) {
+	reqLogger := log.WithValues("Apply Phase", "Server-Side Apply")
+	desired := obj.DeepCopyObject().(client.Object)
+
+	err := controllerutil.SetControllerReference(ah.Parameters.Cr, desired, ah.Parameters.Scheme)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gvk, err := apiutil.GVKForObject(desired, ah.Parameters.Scheme)
+	if err != nil {
+		return nil, nil, err
+	}
+	desired.GetObjectKind().SetGroupVersionKind(gvk)
+
+	// the cluster scoped objects are rendered in the namespace of the CR, for their owner reference
+	mapping, err := ah.Parameters.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err == nil && mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		desired.SetNamespace("")
+	}
+	desired.SetResourceVersion("")
+	desired.SetManagedFields(nil)
+
+	found := desired.DeepCopyObject().(client.Object)
+	err = ah.Parameters.Client.Get(context.TODO(), client.ObjectKeyFromObject(desired), found)
+	if errors.IsNotFound(err) {
+		return desired, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	left, err := k8s.LeaveForeignFields(found, desired, consts.FieldManager, consts.LegacyFieldManager)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(left) > 0 && len(objectName) > 0 {
+		reqLogger.Info("Leaving the fields set by other managers", "Object", objectName, "Name", desired.GetName(), "Fields", left)
+	}
+
+	return desired, found, nil
+}
+
+// apply applies the rendering without forcing it, a conflict with the earlier updates of the operator is forced
+// and a conflict with another manager, one that changed a field since the rendering was prepared, is returned
+func (ah *AquaApplyHelper) apply(objectName string, desired client.Object, opts ...client.PatchOption) (client.Object, error) {
+	applied, err := ah.patch(desired, false, opts...)
+	if !errors.IsConflict(err) {
+		return applied, err
+	}
+
+	conflicts, others := fieldConflicts(err)
+	if others {
+		if len(objectName) > 0 {
+			recordEvent(ah.Parameters.Cr, corev1.EventTypeWarning, "FieldConflict",
+				fmt.Sprintf("Left %s of %s %s to their managers", strings.Join(conflicts, ", "), objectName, desired.GetName()))
+		}
+		return applied, err
+	}
+
+	return ah.patch(desired, true, opts...)
+}
+
+// patch applies a copy of the rendering and returns it as the API server responded, so the rendering is sent
+// again as is on a retry
+func (ah *AquaApplyHelper) patch(desired client.Object, force bool, opts ...client.PatchOption) (client.Object, error) {
+	opts = append(opts, client.FieldOwner(consts.FieldManager))
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	applied := desired.DeepCopyObject().(client.Object)
+	return applied, ah.Parameters.Client.Patch(context.TODO(), applied, client.Apply, opts...)
+}
+
+// fieldConflicts returns the conflicting fields with their managers, and whether a manager other than the operator
+// and its updates before server-side apply is involved
+func fieldConflicts(err error) ([]string, bool) {
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return []string{err.Error()}, true
+	}
+
+	conflicts := []string{}
+	others := false
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+
+		manager := cause.Message
+		if parts := strings.Split(cause.Message, `"`); len(parts) >= 3 {
+			manager = parts[1]
+		}
+		if manager != consts.FieldManager && manager != consts.LegacyFieldManager {
+			others = true
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s (%s)", cause.Field, manager))
+	}
+
+	return conflicts, others
+}
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/aquaapi"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/operatorconfig"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AquaServerApiURL returns the address of the API of the AquaServer name, the service of the server unless the
+// aquaServerApi of the operator configuration sets another one, and true when its certificate isn't verified
+func AquaServerApiURL(namespace, name string) (string, bool, error) {
+	config, err := operatorconfig.Get()
+	if err != nil {
+		return "", false, err
+	}
+
+	api := config.AquaServerApi
+	return api.Address(fmt.Sprintf(consts.ServerServiceName, name), namespace), api != nil && api.TLSNoVerify, nil
+}
+
+// NewAquaServerApiClient returns a client of the API of the AquaServer of the namespace, see AquaServerApiURL, logged
+// in as administrator with the password of common.adminPassword, or of the <name>-aqua-admin secret the operator creates
+func NewAquaServerApiClient(k8sclient client.Client, namespace, name string) (*aquaapi.Client, error) {
+	server := &operatorv1alpha1.AquaServer{}
+	err := k8sclient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, server)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &operatorv1alpha1.AquaSecret{
+		Name: fmt.Sprintf(consts.AdminPasswordSecretName, name),
+		Key:  consts.AdminPasswordSecretKey,
+	}
+	if server.Spec.Common != nil && server.Spec.Common.AdminPassword != nil {
+		ref = server.Spec.Common.AdminPassword
+	}
+	password, err := secrets.GetSecretValue(k8sclient, namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	url, insecure, err := AquaServerApiURL(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return aquaapi.NewClient(url, "administrator", password, insecure), nil
+}
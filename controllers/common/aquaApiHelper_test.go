@@ -0,0 +1,111 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/operatorconfig"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Aqua server API helper", func() {
+	var (
+		k8sclient client.Client
+		api       *httptest.Server
+		logins    []string
+	)
+
+	configure := func(data string) {
+		config := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: consts.OperatorConfigMapName, Namespace: "aqua-operator"},
+			Data:       map[string]string{consts.OperatorConfigAquaServerApiKey: data},
+		}
+		Expect(k8sclient.Create(context.Background(), config)).To(Succeed())
+		operatorconfig.SetReader(k8sclient)
+	}
+
+	BeforeEach(func() {
+		logins = nil
+		api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/login":
+				credentials := map[string]string{}
+				_ = json.NewDecoder(r.Body).Decode(&credentials)
+				logins = append(logins, credentials["id"]+":"+credentials["password"])
+				_, _ = w.Write([]byte(`{"token":"token"}`))
+			case "/api/v1/version":
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				_, _ = w.Write([]byte(`{"version":"2022.4.5"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sclient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&operatorv1alpha1.AquaServer{ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua"}},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf(consts.AdminPasswordSecretName, "aqua"), Namespace: "aqua"},
+				Data:       map[string][]byte{consts.AdminPasswordSecretKey: []byte("secret")},
+			},
+		).Build()
+
+		namespace, found := os.LookupEnv("OPERATOR_NAMESPACE")
+		Expect(os.Setenv("OPERATOR_NAMESPACE", "aqua-operator")).To(Succeed())
+		DeferCleanup(func() {
+			api.Close()
+			operatorconfig.SetReader(nil)
+			if found {
+				_ = os.Setenv("OPERATOR_NAMESPACE", namespace)
+			} else {
+				_ = os.Unsetenv("OPERATOR_NAMESPACE")
+			}
+		})
+	})
+
+	It("addresses the service of the server by default", func() {
+		operatorconfig.SetReader(k8sclient)
+		url, insecure, err := AquaServerApiURL("aqua", "aqua")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal("http://aqua-server.aqua.svc:8080"))
+		Expect(insecure).To(BeFalse())
+	})
+
+	It("addresses the API of the operator configuration", func() {
+		configure("url: https://{service}.{namespace}.example.com\ntlsNoVerify: true")
+		url, insecure, err := AquaServerApiURL("aqua", "aqua")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal("https://aqua-server.aqua.example.com"))
+		Expect(insecure).To(BeTrue())
+	})
+
+	It("returns a client of the configured API logged in as administrator", func() {
+		configure("url: " + api.URL)
+		serverApi, err := NewAquaServerApiClient(k8sclient, "aqua", "aqua")
+		Expect(err).NotTo(HaveOccurred())
+
+		version, err := serverApi.Version()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("2022.4.5"))
+		Expect(logins).To(Equal([]string{"administrator:secret"}))
+	})
+})
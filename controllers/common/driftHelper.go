@@ -0,0 +1,263 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// recorder Emits the events of the CRs, no events are emitted until it is set
+var recorder record.EventRecorder
+
+// SetEventRecorder sets the recorder the helpers emit the events of the CRs with
+func SetEventRecorder(r record.EventRecorder) {
+	recorder = r
+}
+
+func recordEvent(cr client.Object, eventType, reason, message string) {
+	if recorder != nil {
+		recorder.Event(cr, eventType, reason, message)
+	}
+}
+
+// ReconcilePaused checks spec.paused and the pause annotation of the cr, and emits an event when it is paused
+func ReconcilePaused(cr client.Object, paused bool) bool {
+	reason := "spec.paused is true"
+	if !paused {
+		if cr.GetAnnotations()[consts.PauseReconcileAnnotation] != "true" {
+			return false
+		}
+		reason = fmt.Sprintf("the %s annotation is true", consts.PauseReconcileAnnotation)
+	}
+
+	log.Info("Skip reconcile: paused", "Reason", reason, "Namespace", cr.GetNamespace(), "Name", cr.GetName())
+	recordEvent(cr, corev1.EventTypeNormal, "ReconcilePaused", fmt.Sprintf("Not reconciled while %s", reason))
+	return true
+}
+
+// GetDriftPolicy returns the drift policy of the common section
+func GetDriftPolicy(common *operatorv1alpha1.AquaCommon) *operatorv1alpha1.AquaDriftPolicy {
+	if common == nil {
+		return nil
+	}
+
+	return common.DriftPolicy
+}
+
+type DriftParameters struct {
+	Policy *operatorv1alpha1.AquaDriftPolicy
+	Drifts *[]operatorv1alpha1.AquaDrift
+	Client client.Client
+	Cr     client.Object
+}
+
+// AquaDriftHelper detects the manual changes to the objects owned by a CR, the rendered fields the managed fields
+// give to another manager with a different value, and applies the drift policy of the CR to them
+type AquaDriftHelper struct {
+	Parameters DriftParameters
+}
+
+func NewAquaDriftHelper(policy *operatorv1alpha1.AquaDriftPolicy,
+	drifts *[]operatorv1alpha1.AquaDrift,
+	k8sclient client.Client,
+	cr client.Object) *AquaDriftHelper {
+	params := DriftParameters{
+		Policy: policy,
+		Drifts: drifts,
+		Client: k8sclient,
+		Cr:     cr,
+	}
+
+	return &AquaDriftHelper{
+		Parameters: params,
+	}
+}
+
+// Reconcile detects the rendered fields another manager changed on found and applies the policy to them. The
+// enforced changes are reverted on found, the reported and ignored ones are left to their manager by the apply of
+// desired. The changes are recorded in the status of the CR and emitted as events. Returns true when found had
+// changes to revert.
+func (dh *AquaDriftHelper) Reconcile(found, desired client.Object) (bool, error) {
+	ops, reverts, err := k8s.DetectDrift(found, desired, consts.FieldManager, consts.LegacyFieldManager)
+	if err != nil {
+		return false, err
+	}
+
+	kind := objectKind(found, dh.Parameters.Client)
+	_, revert, err := dh.ReconcileOperations(kind, found.GetName(), ops)
+	if err != nil || !revert {
+		return false, err
+	}
+
+	enforced := []k8s.PatchOperation{}
+	for i := range ops {
+		if dh.action(kind, found.GetName(), ops[i].Path) == operatorv1alpha1.AquaDriftActionEnforce {
+			enforced = append(enforced, reverts[i])
+		}
+	}
+
+	data, err := json.Marshal(enforced)
+	if err != nil {
+		return false, err
+	}
+
+	// the reverted fields get back the values of the rendering, so the apply doesn't conflict on them
+	err = dh.Parameters.Client.Patch(context.Background(), found, client.RawPatch(types.JSONPatchType, data),
+		client.FieldOwner(consts.FieldManager))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReconcileOperations applies the policy to the changes of an object, an owned object or one the operator syncs out
+// of the cluster. The reported and enforced changes are recorded in the status of the CR and emitted as events.
+// Returns the changes to keep, and true when the object has changes to revert.
+func (dh *AquaDriftHelper) ReconcileOperations(kind, name string, ops []k8s.PatchOperation) ([]k8s.PatchOperation, bool, error) {
+	reqLogger := log.WithValues("Drift Phase", "Reconcile Drift")
+
+	byAction := map[operatorv1alpha1.AquaDriftAction][]k8s.PatchOperation{}
+	for _, op := range ops {
+		action := dh.action(kind, name, op.Path)
+		byAction[action] = append(byAction[action], op)
+	}
+
+	kept := append(append([]k8s.PatchOperation{}, byAction[operatorv1alpha1.AquaDriftActionReport]...), byAction[operatorv1alpha1.AquaDriftActionIgnore]...)
+
+	changed := dh.record(kind, name, operatorv1alpha1.AquaDriftActionReport, byAction[operatorv1alpha1.AquaDriftActionReport])
+	if dh.record(kind, name, operatorv1alpha1.AquaDriftActionEnforce, byAction[operatorv1alpha1.AquaDriftActionEnforce]) {
+		changed = true
+	}
+
+	if changed {
+		reqLogger.Info("Recorded drift", "Kind", kind, "Name", name)
+		// update a copy, so the spec of this reconcile isn't replaced with the stored one
+		cr := dh.Parameters.Cr.DeepCopyObject().(client.Object)
+		err := dh.Parameters.Client.Status().Update(context.Background(), cr)
+		if err != nil {
+			return nil, false, err
+		}
+		dh.Parameters.Cr.SetResourceVersion(cr.GetResourceVersion())
+	}
+
+	return kept, len(byAction[operatorv1alpha1.AquaDriftActionEnforce]) > 0, nil
+}
+
+// action returns the action of the first rule matching the field, or the default one
+func (dh *AquaDriftHelper) action(kind, name, path string) operatorv1alpha1.AquaDriftAction {
+	policy := dh.Parameters.Policy
+	if policy == nil {
+		return operatorv1alpha1.AquaDriftActionEnforce
+	}
+
+	for _, rule := range policy.Rules {
+		if len(rule.Kind) > 0 && rule.Kind != kind {
+			continue
+		}
+		if len(rule.Name) > 0 && rule.Name != name {
+			continue
+		}
+
+		matched := len(rule.Paths) == 0
+		for _, prefix := range rule.Paths {
+			if k8s.PathMatches(path, prefix) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			return normalizeAction(rule.Action)
+		}
+	}
+
+	return normalizeAction(policy.Default)
+}
+
+// record adds the drift to the status and emits its event, unless the same drift is the last one recorded. A
+// reported drift replaces the previous one of the object, and is removed once the object doesn't drift anymore.
+func (dh *AquaDriftHelper) record(kind, name string, action operatorv1alpha1.AquaDriftAction, ops []k8s.PatchOperation) bool {
+	drifts := dh.Parameters.Drifts
+
+	last := -1
+	for i, drift := range *drifts {
+		if drift.Kind == kind && drift.Name == name && drift.Action == action {
+			last = i
+		}
+	}
+
+	if len(ops) == 0 {
+		if last >= 0 && action == operatorv1alpha1.AquaDriftActionReport {
+			*drifts = append((*drifts)[:last], (*drifts)[last+1:]...)
+			recordEvent(dh.Parameters.Cr, corev1.EventTypeNormal, "DriftResolved",
+				fmt.Sprintf("%s/%s matches the rendering of the operator again", kind, name))
+			return true
+		}
+		return false
+	}
+
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return false
+	}
+	summary := string(data)
+
+	if last >= 0 && (*drifts)[last].Patch == summary {
+		return false
+	}
+	if last >= 0 && action == operatorv1alpha1.AquaDriftActionReport {
+		*drifts = append((*drifts)[:last], (*drifts)[last+1:]...)
+	}
+
+	*drifts = append(*drifts, operatorv1alpha1.AquaDrift{
+		Kind:       kind,
+		Name:       name,
+		Action:     action,
+		Patch:      summary,
+		DetectedAt: metav1.Now(),
+	})
+	if len(*drifts) > consts.MaxDriftRecords {
+		*drifts = (*drifts)[len(*drifts)-consts.MaxDriftRecords:]
+	}
+
+	if action == operatorv1alpha1.AquaDriftActionEnforce {
+		recordEvent(dh.Parameters.Cr, corev1.EventTypeWarning, "DriftReverted",
+			fmt.Sprintf("Reverted the manual changes to %s/%s: %s", kind, name, summary))
+	} else {
+		recordEvent(dh.Parameters.Cr, corev1.EventTypeWarning, "DriftDetected",
+			fmt.Sprintf("Kept the manual changes to %s/%s: %s", kind, name, summary))
+	}
+
+	return true
+}
+
+func normalizeAction(action operatorv1alpha1.AquaDriftAction) operatorv1alpha1.AquaDriftAction {
+	switch action {
+	case operatorv1alpha1.AquaDriftActionReport, operatorv1alpha1.AquaDriftActionIgnore:
+		return action
+	}
+
+	return operatorv1alpha1.AquaDriftActionEnforce
+}
+
+func objectKind(obj client.Object, k8sclient client.Client) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; len(kind) > 0 {
+		return kind
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, k8sclient.Scheme())
+	if err != nil {
+		return fmt.Sprintf("%T", obj)
+	}
+	return gvk.Kind
+}
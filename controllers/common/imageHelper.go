@@ -0,0 +1,199 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"strings"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/operatorconfig"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/registry"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MapPodImages rewrites the pod images with the operator image registry mapping and adds its global pull secret
+func MapPodImages(podSpec *corev1.PodSpec) error {
+	config, err := operatorconfig.Get()
+	if err != nil {
+		return err
+	}
+
+	mapping := config.ImageRegistryMapping
+	if mapping == nil {
+		return nil
+	}
+
+	for i := range podSpec.InitContainers {
+		podSpec.InitContainers[i].Image = mapping.MapImage(podSpec.InitContainers[i].Image)
+	}
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Image = mapping.MapImage(podSpec.Containers[i].Image)
+	}
+
+	if len(mapping.PullSecret) != 0 {
+		for _, secret := range podSpec.ImagePullSecrets {
+			if secret.Name == mapping.PullSecret {
+				return nil
+			}
+		}
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, corev1.LocalObjectReference{Name: mapping.PullSecret})
+	}
+
+	return nil
+}
+
+// GetImageVerification returns the image verification of the common section, nil if not defined
+func GetImageVerification(common *operatorv1alpha1.AquaCommon) *operatorv1alpha1.AquaImageVerification {
+	if common == nil {
+		return nil
+	}
+
+	return common.ImageVerification
+}
+
+// PinImages pins the images of the pod specs of the CR to their digests with PinPodImages and records them in images,
+// the images of its status. Only the images are patched, the rest of the status is left to the reconcile.
+func PinImages(k8sclient client.Client,
+	cr client.Object,
+	verification *operatorv1alpha1.AquaImageVerification,
+	images *[]operatorv1alpha1.AquaImageStatus,
+	podSpecs ...*corev1.PodSpec) error {
+	var pinned []operatorv1alpha1.AquaImageStatus
+	for _, podSpec := range podSpecs {
+		podImages, err := PinPodImages(k8sclient, cr.GetNamespace(), verification, podSpec, *images)
+		if err != nil {
+			return err
+		}
+
+	next:
+		for _, image := range podImages {
+			for _, status := range pinned {
+				if status.Image == image.Image {
+					continue next
+				}
+			}
+			pinned = append(pinned, image)
+		}
+	}
+
+	if reflect.DeepEqual(pinned, *images) {
+		return nil
+	}
+
+	// patch a copy, so the spec of this reconcile isn't replaced with the stored one
+	base := cr.DeepCopyObject().(client.Object)
+	*images = pinned
+	patched := cr.DeepCopyObject().(client.Object)
+	err := k8sclient.Status().Patch(context.TODO(), patched, client.MergeFrom(base))
+	if err != nil {
+		return err
+	}
+	cr.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
+
+// PinPodImages replaces the container images of the pod spec with image@digest, the digest is resolved
+// through the registry with the pod pull secrets. When a cosign public key is set, a new image is verified
+// and an error is returned if it has no matching signature, so the workload keeps its current images.
+// Images found in current are not resolved again, the returned list holds the images of the pod spec.
+func PinPodImages(k8sclient client.Client,
+	namespace string,
+	verification *operatorv1alpha1.AquaImageVerification,
+	podSpec *corev1.PodSpec,
+	current []operatorv1alpha1.AquaImageStatus) ([]operatorv1alpha1.AquaImageStatus, error) {
+	if verification == nil {
+		return nil, nil
+	}
+
+	reqLogger := log.WithValues("Image Verification Phase", "Pin Pod Images")
+
+	var publicKey []byte
+	keyChecksum := ""
+	if verification.CosignPublicKey != nil {
+		value, err := secrets.GetSecretValue(k8sclient, namespace, verification.CosignPublicKey)
+		if err != nil {
+			return current, err
+		}
+		publicKey = []byte(value)
+		keyChecksum = fmt.Sprintf("%x", sha256.Sum256(publicKey))
+	}
+
+	pullSecrets := []string{}
+	for _, secret := range podSpec.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, secret.Name)
+	}
+
+	images := []operatorv1alpha1.AquaImageStatus{}
+	pin := func(container *corev1.Container) error {
+		image := container.Image
+
+		for _, status := range images {
+			if status.Image == image {
+				container.Image = pinnedImage(image, status.Digest)
+				return nil
+			}
+		}
+
+		for _, status := range current {
+			if status.Image == image && status.VerifiedKey == keyChecksum {
+				container.Image = pinnedImage(image, status.Digest)
+				images = append(images, status)
+				return nil
+			}
+		}
+
+		registryClient, err := registry.NewClient(k8sclient, namespace, container.Image, pullSecrets)
+		if err != nil {
+			return err
+		}
+
+		digest, err := registryClient.ResolveDigest()
+		if err != nil {
+			return err
+		}
+
+		if publicKey != nil {
+			err = registryClient.VerifyCosignSignature(digest, publicKey)
+			if err != nil {
+				return err
+			}
+			reqLogger.Info("Verified image signature", "Image", image, "Digest", digest)
+		}
+
+		reqLogger.Info("Pinned image to digest", "Image", image, "Digest", digest)
+		container.Image = pinnedImage(image, digest)
+		images = append(images, operatorv1alpha1.AquaImageStatus{
+			Image:       image,
+			Digest:      digest,
+			VerifiedKey: keyChecksum,
+		})
+
+		return nil
+	}
+
+	for i := range podSpec.InitContainers {
+		if err := pin(&podSpec.InitContainers[i]); err != nil {
+			return current, err
+		}
+	}
+	for i := range podSpec.Containers {
+		if err := pin(&podSpec.Containers[i]); err != nil {
+			return current, err
+		}
+	}
+
+	return images, nil
+}
+
+func pinnedImage(image, digest string) string {
+	if strings.Contains(image, "@") {
+		return image
+	}
+
+	return fmt.Sprintf("%s@%s", image, digest)
+}
@@ -133,8 +133,6 @@ func UpdateAquaCommon(common *operatorv1alpha1.AquaCommon, name string, admin bo
 }
 
 func UpdateAquaAuditDB(auditDb *operatorv1alpha1.AuditDBInformation, name string) *operatorv1alpha1.AuditDBInformation {
-	password := extra.CreateRundomPassword()
-
 	if auditDb != nil {
 		if auditDb.AuditDBSecret == nil {
 			auditDb.AuditDBSecret = &operatorv1alpha1.AquaSecret{
@@ -148,7 +146,6 @@ func UpdateAquaAuditDB(auditDb *operatorv1alpha1.AuditDBInformation, name string
 				Host:     fmt.Sprintf(consts.AuditDbServiceName, name),
 				Port:     5432,
 				Username: "postgres",
-				Password: password,
 			}
 		}
 	} else {
@@ -161,7 +158,6 @@ func UpdateAquaAuditDB(auditDb *operatorv1alpha1.AuditDBInformation, name string
 				Host:     fmt.Sprintf(consts.AuditDbServiceName, name),
 				Port:     5432,
 				Username: "postgres",
-				Password: password,
 			},
 		}
 	}
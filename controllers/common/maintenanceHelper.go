@@ -0,0 +1,174 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/cron"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// MaintenanceWindowOpen checks the maintenance window is open at the time, and returns the start of the next
+// window when it isn't. Without a window the disruptive changes are always allowed
+func MaintenanceWindowOpen(window *operatorv1alpha1.AquaMaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	if window == nil {
+		return true, time.Time{}, nil
+	}
+
+	if window.Duration.Duration <= 0 {
+		return false, time.Time{}, fmt.Errorf("invalid maintenance window duration %q", window.Duration.Duration)
+	}
+
+	schedule, err := cron.Parse(window.Schedule, window.TimeZone)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	// the last window starting within the duration is still open
+	start := schedule.Next(now.Add(-window.Duration.Duration))
+	if start.IsZero() {
+		return false, time.Time{}, fmt.Errorf("maintenance window schedule %q never starts", window.Schedule)
+	}
+
+	return !start.After(now), start, nil
+}
+
+type MaintenanceParameters struct {
+	Window *operatorv1alpha1.AquaMaintenanceWindow
+	Status **operatorv1alpha1.AquaMaintenanceStatus
+	Client client.Client
+	Cr     client.Object
+}
+
+// AquaMaintenanceHelper holds back the disruptive changes to the objects owned by a CR until its maintenance
+// window opens
+type AquaMaintenanceHelper struct {
+	Parameters MaintenanceParameters
+}
+
+func NewAquaMaintenanceHelper(window *operatorv1alpha1.AquaMaintenanceWindow,
+	status **operatorv1alpha1.AquaMaintenanceStatus,
+	k8sclient client.Client,
+	cr client.Object) *AquaMaintenanceHelper {
+	params := MaintenanceParameters{
+		Window: window,
+		Status: status,
+		Client: k8sclient,
+		Cr:     cr,
+	}
+
+	return &AquaMaintenanceHelper{
+		Parameters: params,
+	}
+}
+
+// Defer returns how long the change waits for the maintenance window, 0 when it can be applied now. A change that
+// isn't disruptive is never deferred. The deferred changes are listed in the status of the CR until applied.
+func (mh *AquaMaintenanceHelper) Defer(change string, disruptive bool) (time.Duration, error) {
+	reqLogger := log.WithValues("Maintenance Phase", "Defer Disruptive Change")
+
+	open, next := true, time.Time{}
+	if disruptive {
+		var err error
+		open, next, err = MaintenanceWindowOpen(mh.Parameters.Window, time.Now())
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	status := *mh.Parameters.Status
+	index := -1
+	if status != nil {
+		for i, deferred := range status.Deferred {
+			if deferred == change {
+				index = i
+			}
+		}
+	}
+
+	changed := false
+	if open && index >= 0 {
+		status.Deferred = append(status.Deferred[:index], status.Deferred[index+1:]...)
+		if len(status.Deferred) == 0 {
+			*mh.Parameters.Status = nil
+		}
+		changed = true
+	} else if !open {
+		if status == nil {
+			status = &operatorv1alpha1.AquaMaintenanceStatus{}
+			*mh.Parameters.Status = status
+		}
+		if index < 0 {
+			status.Deferred = append(status.Deferred, change)
+			reqLogger.Info("Deferred until the maintenance window opens", "Change", change, "Until", next)
+			recordEvent(mh.Parameters.Cr, corev1.EventTypeNormal, "UpdateDeferred",
+				fmt.Sprintf("%s deferred until %s", change, next.Format(time.RFC3339)))
+			changed = true
+		}
+		until := metav1.NewTime(next)
+		if status.DeferredUntil == nil || !status.DeferredUntil.Equal(&until) {
+			status.DeferredUntil = &until
+			status.Message = fmt.Sprintf("update deferred until %s", next.Format(time.RFC3339))
+			changed = true
+		}
+	}
+
+	if changed {
+		// update a copy, so the spec of this reconcile isn't replaced with the stored one
+		cr := mh.Parameters.Cr.DeepCopyObject().(client.Object)
+		err := mh.Parameters.Client.Status().Update(context.Background(), cr)
+		if err != nil {
+			return 0, err
+		}
+		mh.Parameters.Cr.SetResourceVersion(cr.GetResourceVersion())
+	}
+
+	if open {
+		return 0, nil
+	}
+	return time.Until(next), nil
+}
+
+// MaintenanceResult requeues the CR when the next maintenance window opens, to apply the changes deferred to it
+func MaintenanceResult(status *operatorv1alpha1.AquaMaintenanceStatus) reconcile.Result {
+	if status == nil || status.DeferredUntil == nil {
+		return reconcile.Result{}
+	}
+
+	wait := time.Until(status.DeferredUntil.Time)
+	if wait <= 0 {
+		return reconcile.Result{Requeue: true}
+	}
+	return reconcile.Result{RequeueAfter: wait}
+}
+
+// ImagesChanged checks the pod spec runs other images than the current one, changing them restarts the pods
+func ImagesChanged(current, desired corev1.PodSpec) bool {
+	images := func(spec corev1.PodSpec) map[string]string {
+		byName := map[string]string{}
+		for _, container := range spec.InitContainers {
+			byName["init/"+container.Name] = container.Image
+		}
+		for _, container := range spec.Containers {
+			byName[container.Name] = container.Image
+		}
+		return byName
+	}
+
+	currentImages, desiredImages := images(current), images(desired)
+	if len(currentImages) != len(desiredImages) {
+		return true
+	}
+	for name, image := range desiredImages {
+		if currentImages[name] != image {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,396 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type RolloutParameters struct {
+	Strategy   *operatorv1alpha1.AquaRolloutStrategy
+	Status     *operatorv1alpha1.AquaRolloutStatus
+	Conditions *[]metav1.Condition
+	Client     client.Client
+	Scheme     *runtime.Scheme
+	Cr         client.Object
+}
+
+// AquaRolloutHelper keeps a bounded revision history of a rendered deployment, and rolls the deployment back to
+// the last healthy revision when a rollout misses its progress deadline
+type AquaRolloutHelper struct {
+	Parameters RolloutParameters
+}
+
+func NewAquaRolloutHelper(strategy *operatorv1alpha1.AquaRolloutStrategy,
+	status *operatorv1alpha1.AquaRolloutStatus,
+	conditions *[]metav1.Condition,
+	k8sclient client.Client,
+	scheme *runtime.Scheme,
+	cr client.Object) *AquaRolloutHelper {
+	params := RolloutParameters{
+		Strategy:   strategy,
+		Status:     status,
+		Conditions: conditions,
+		Client:     k8sclient,
+		Scheme:     scheme,
+		Cr:         cr,
+	}
+
+	return &AquaRolloutHelper{
+		Parameters: params,
+	}
+}
+
+// SetProgressDeadline sets the progress deadline of the strategy on the rendered deployment
+func (rh *AquaRolloutHelper) SetProgressDeadline(deployment *appsv1.Deployment) {
+	deadline := int32(consts.DefaultProgressDeadlineSeconds)
+	if rh.Parameters.Strategy != nil && rh.Parameters.Strategy.ProgressDeadlineSeconds != nil {
+		deadline = *rh.Parameters.Strategy.ProgressDeadlineSeconds
+	}
+
+	deployment.Spec.ProgressDeadlineSeconds = &deadline
+}
+
+// ReconcileDeployment updates found to the desired rendering and records it as a new revision. A rendering that was
+// rolled back from is not applied again until it changes. Returns true when the deployment was updated.
+func (rh *AquaRolloutHelper) ReconcileDeployment(objectName string, found, desired *appsv1.Deployment) (bool, error) {
+	reqLogger := log.WithValues("Rollout Phase", "Reconcile Deployment")
+
+	status := rh.Parameters.Status
+	originalStatus := status.DeepCopy()
+	originalConditions := append([]metav1.Condition{}, *rh.Parameters.Conditions...)
+
+	hash, err := extra.GenerateMD5ForSpec(desired.Spec)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := rh.reconcile(objectName, found, desired, hash)
+	if err != nil {
+		reqLogger.Error(err, "Failed to reconcile the deployment revisions", "Deployment.Name", desired.Name)
+		return false, err
+	}
+
+	if !equality.Semantic.DeepEqual(originalStatus, status) ||
+		!equality.Semantic.DeepEqual(originalConditions, *rh.Parameters.Conditions) {
+		// update a copy, so the spec of this reconcile isn't replaced with the stored one
+		cr := rh.Parameters.Cr.DeepCopyObject().(client.Object)
+		err = rh.Parameters.Client.Status().Update(context.Background(), cr)
+		if err != nil {
+			return updated, err
+		}
+		rh.Parameters.Cr.SetResourceVersion(cr.GetResourceVersion())
+	}
+
+	return updated, nil
+}
+
+func (rh *AquaRolloutHelper) reconcile(objectName string, found, desired *appsv1.Deployment, hash string) (bool, error) {
+	reqLogger := log.WithValues("Rollout Phase", "Reconcile Deployment")
+	status := rh.Parameters.Status
+
+	if value, ok := rh.Parameters.Cr.GetAnnotations()[consts.RollbackAnnotation]; ok {
+		return rh.manualRollback(found, desired, hash, value)
+	}
+
+	if len(status.RolledBackFrom) != 0 && status.RolledBackFrom != hash {
+		reqLogger.Info("The spec changed since the rollback, rolling out the new rendering", "Deployment.Name", desired.Name)
+		status.RolledBackFrom = ""
+	}
+
+	if len(status.RolledBackFrom) == 0 {
+		update, err := rh.applier().Apply(objectName, desired)
+		if err != nil {
+			return false, err
+		}
+		if update {
+			revision, err := rh.recordRevision(desired, hash)
+			if err != nil {
+				return true, err
+			}
+			status.Revision = revision
+
+			return true, rh.pruneRevisions(desired.Name)
+		}
+	}
+
+	// the deployment controller hasn't seen the last update yet
+	if found.Status.ObservedGeneration < found.Generation {
+		return false, nil
+	}
+
+	if k8s.IsDeploymentRolledOut(found) {
+		if status.Revision == 0 {
+			revision, err := rh.recordRevision(desired, hash)
+			if err != nil {
+				return false, err
+			}
+			status.Revision = revision
+		}
+		status.LastHealthyRevision = status.Revision
+
+		if len(status.RolledBackFrom) == 0 {
+			rh.setDegraded(metav1.ConditionFalse, "RolloutSucceeded", fmt.Sprintf("Revision %d is ready", status.Revision))
+		}
+		return false, nil
+	}
+
+	if !k8s.IsDeploymentProgressDeadlineExceeded(found) || len(status.RolledBackFrom) != 0 {
+		return false, nil
+	}
+
+	if rh.Parameters.Strategy != nil && rh.Parameters.Strategy.DisableAutoRollback {
+		rh.setDegraded(metav1.ConditionTrue, "ProgressDeadlineExceeded",
+			fmt.Sprintf("Revision %d did not become ready in time, automatic rollback is disabled", status.Revision))
+		return false, nil
+	}
+
+	if status.LastHealthyRevision == 0 || status.LastHealthyRevision == status.Revision {
+		rh.setDegraded(metav1.ConditionTrue, "ProgressDeadlineExceeded",
+			fmt.Sprintf("Revision %d did not become ready in time, there is no healthy revision to roll back to", status.Revision))
+		return false, nil
+	}
+
+	revision, err := rh.getRevision(desired.Name, status.LastHealthyRevision)
+	if err != nil {
+		return false, err
+	}
+	if revision == nil {
+		rh.setDegraded(metav1.ConditionTrue, "ProgressDeadlineExceeded",
+			fmt.Sprintf("Revision %d did not become ready in time, healthy revision %d was pruned", status.Revision, status.LastHealthyRevision))
+		return false, nil
+	}
+
+	reqLogger.Info("Rollout missed its progress deadline, rolling back", "Deployment.Name", desired.Name,
+		"From", status.Revision, "To", revision.Revision)
+	failed := status.Revision
+	err = rh.rollback(desired, revision, hash)
+	if err != nil {
+		return false, err
+	}
+	rh.setDegraded(metav1.ConditionTrue, "RolledBack",
+		fmt.Sprintf("Revision %d did not become ready in time, rolled back to revision %d until the spec changes", failed, revision.Revision))
+
+	return true, nil
+}
+
+func (rh *AquaRolloutHelper) manualRollback(found, desired *appsv1.Deployment, hash, value string) (bool, error) {
+	reqLogger := log.WithValues("Rollout Phase", "Manual Rollback")
+
+	err := rh.removeRollbackAnnotation()
+	if err != nil {
+		return false, err
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		rh.setDegraded(metav1.ConditionTrue, "RollbackFailed", fmt.Sprintf("Invalid revision %q in %s", value, consts.RollbackAnnotation))
+		return false, nil
+	}
+
+	revision, err := rh.getRevision(desired.Name, number)
+	if err != nil {
+		return false, err
+	}
+	if revision == nil {
+		rh.setDegraded(metav1.ConditionTrue, "RollbackFailed", fmt.Sprintf("Revision %d of %s was not found", number, desired.Name))
+		return false, nil
+	}
+
+	reqLogger.Info("Rolling back by annotation", "Deployment.Name", desired.Name, "To", number)
+	err = rh.rollback(desired, revision, hash)
+	if err != nil {
+		return false, err
+	}
+
+	if len(rh.Parameters.Status.RolledBackFrom) != 0 {
+		rh.setDegraded(metav1.ConditionTrue, "ManualRollback",
+			fmt.Sprintf("Rolled back to revision %d by %s until the spec changes", number, consts.RollbackAnnotation))
+	}
+
+	return true, nil
+}
+
+// rollback applies the rendering of the revision, and holds back the desired rendering unless it is the same one
+func (rh *AquaRolloutHelper) rollback(desired *appsv1.Deployment, revision *appsv1.ControllerRevision, hash string) error {
+	spec := appsv1.DeploymentSpec{}
+	err := json.Unmarshal(revision.Data.Raw, &spec)
+	if err != nil {
+		return err
+	}
+
+	deployment := desired.DeepCopy()
+	deployment.Spec = spec
+	_, err = rh.applier().Apply("rollback deployment", deployment)
+	if err != nil {
+		return err
+	}
+
+	status := rh.Parameters.Status
+	status.Revision = revision.Revision
+	status.RolledBackFrom = ""
+	if revision.Labels[consts.RevisionHashLabel] != hash {
+		status.RolledBackFrom = hash
+	}
+
+	return nil
+}
+
+func (rh *AquaRolloutHelper) applier() *AquaApplyHelper {
+	return NewAquaApplyHelper(rh.Parameters.Client, rh.Parameters.Scheme, rh.Parameters.Cr)
+}
+
+func (rh *AquaRolloutHelper) removeRollbackAnnotation() error {
+	// patch a copy, so the spec of this reconcile isn't replaced with the stored one
+	cr := rh.Parameters.Cr.DeepCopyObject().(client.Object)
+	data := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, consts.RollbackAnnotation))
+	err := rh.Parameters.Client.Patch(context.Background(), cr, client.RawPatch(types.MergePatchType, data))
+	if err != nil {
+		return err
+	}
+
+	rh.Parameters.Cr.SetAnnotations(cr.GetAnnotations())
+	rh.Parameters.Cr.SetResourceVersion(cr.GetResourceVersion())
+	return nil
+}
+
+// recordRevision stores the rendering as a new revision, a rendering seen before keeps its revision number, so the
+// numbers in the status and in the rollback annotation always point at the same rendering
+func (rh *AquaRolloutHelper) recordRevision(deployment *appsv1.Deployment, hash string) (int64, error) {
+	revisions, err := rh.listRevisions(deployment.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	next := int64(1)
+	if len(revisions) > 0 {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+
+	data, err := json.Marshal(deployment.Spec)
+	if err != nil {
+		return 0, err
+	}
+
+	name := fmt.Sprintf(consts.RevisionName, deployment.Name, hash[:10])
+	for i := range revisions {
+		if revisions[i].Name == name {
+			return revisions[i].Revision, nil
+		}
+	}
+
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: deployment.Namespace,
+			Labels: map[string]string{
+				"deployedby":             "aqua-operator",
+				"aquasecoperator_cr":     rh.Parameters.Cr.GetName(),
+				consts.RevisionOfLabel:   deployment.Name,
+				consts.RevisionHashLabel: hash,
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: next,
+	}
+
+	err = controllerutil.SetControllerReference(rh.Parameters.Cr, revision, rh.Parameters.Scheme)
+	if err != nil {
+		return 0, err
+	}
+
+	err = rh.Parameters.Client.Create(context.Background(), revision)
+	if errors.IsAlreadyExists(err) {
+		err = rh.Parameters.Client.Get(context.Background(), client.ObjectKeyFromObject(revision), revision)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return revision.Revision, nil
+}
+
+// pruneRevisions deletes the oldest revisions over the history limit, the current and last healthy ones are kept
+func (rh *AquaRolloutHelper) pruneRevisions(deploymentName string) error {
+	limit := consts.DefaultRevisionHistoryLimit
+	if rh.Parameters.Strategy != nil && rh.Parameters.Strategy.RevisionHistoryLimit != nil {
+		limit = int(*rh.Parameters.Strategy.RevisionHistoryLimit)
+	}
+
+	revisions, err := rh.listRevisions(deploymentName)
+	if err != nil {
+		return err
+	}
+
+	status := rh.Parameters.Status
+	for i := 0; i < len(revisions) && len(revisions)-i > limit; i++ {
+		if revisions[i].Revision == status.Revision || revisions[i].Revision == status.LastHealthyRevision {
+			continue
+		}
+
+		err = rh.Parameters.Client.Delete(context.Background(), &revisions[i])
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rh *AquaRolloutHelper) getRevision(deploymentName string, number int64) (*appsv1.ControllerRevision, error) {
+	revisions, err := rh.listRevisions(deploymentName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range revisions {
+		if revisions[i].Revision == number {
+			return &revisions[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// listRevisions returns the revisions of the deployment, oldest first
+func (rh *AquaRolloutHelper) listRevisions(deploymentName string) ([]appsv1.ControllerRevision, error) {
+	list := &appsv1.ControllerRevisionList{}
+	err := rh.Parameters.Client.List(context.Background(), list,
+		client.InNamespace(rh.Parameters.Cr.GetNamespace()),
+		client.MatchingLabels{consts.RevisionOfLabel: deploymentName})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Revision < list.Items[j].Revision
+	})
+
+	return list.Items, nil
+}
+
+func (rh *AquaRolloutHelper) setDegraded(status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(rh.Parameters.Conditions, metav1.Condition{
+		Type:               operatorv1alpha1.AquaConditionDegraded,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: rh.Parameters.Cr.GetGeneration(),
+	})
+}
@@ -0,0 +1,107 @@
+package common
+
+import (
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rollout helper", func() {
+	var (
+		helper *AquaRolloutHelper
+		status *operatorv1alpha1.AquaRolloutStatus
+	)
+
+	rendering := func(image string) (*appsv1.Deployment, string) {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "aqua-server", Namespace: "aqua"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "aqua-server", Image: image}}},
+				},
+			},
+		}
+		hash, err := extra.GenerateMD5ForSpec(deployment.Spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		return deployment, hash
+	}
+
+	record := func(image string) int64 {
+		deployment, hash := rendering(image)
+		revision, err := helper.recordRevision(deployment, hash)
+		Expect(err).NotTo(HaveOccurred())
+
+		return revision
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		cr := &operatorv1alpha1.AquaServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua", UID: "uid"},
+		}
+		cr.Status.Rollout = &operatorv1alpha1.AquaRolloutStatus{}
+		status = cr.Status.Rollout
+
+		limit := int32(2)
+		helper = NewAquaRolloutHelper(&operatorv1alpha1.AquaRolloutStrategy{RevisionHistoryLimit: &limit},
+			status, &cr.Status.Conditions, fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr).Build(), scheme, cr)
+	})
+
+	It("numbers new renderings in order", func() {
+		Expect(record("server:6.5")).To(Equal(int64(1)))
+		Expect(record("server:2022.4")).To(Equal(int64(2)))
+	})
+
+	It("keeps the number of a rendering seen before", func() {
+		Expect(record("server:6.5")).To(Equal(int64(1)))
+		Expect(record("server:2022.4")).To(Equal(int64(2)))
+		Expect(record("server:6.5")).To(Equal(int64(1)))
+		Expect(record("server:2022.4.5")).To(Equal(int64(3)))
+
+		revision, err := helper.getRevision("aqua-server", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision).NotTo(BeNil())
+		_, hash := rendering("server:6.5")
+		Expect(revision.Labels).To(HaveKeyWithValue(consts.RevisionHashLabel, hash))
+	})
+
+	It("still finds the last healthy revision once it is recorded again", func() {
+		status.LastHealthyRevision = record("server:6.5")
+		status.Revision = record("server:2022.4")
+		status.Revision = record("server:6.5")
+
+		revision, err := helper.getRevision("aqua-server", status.LastHealthyRevision)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revision).NotTo(BeNil())
+		Expect(revision.Revision).To(Equal(status.Revision))
+	})
+
+	It("prunes the oldest revisions but keeps the running and last healthy ones", func() {
+		status.LastHealthyRevision = record("server:6.2")
+		record("server:6.5")
+		record("server:2022.4")
+		status.Revision = record("server:2022.4.5")
+		Expect(helper.pruneRevisions("aqua-server")).To(Succeed())
+
+		revisions, err := helper.listRevisions("aqua-server")
+		Expect(err).NotTo(HaveOccurred())
+		var numbers []int64
+		for _, revision := range revisions {
+			numbers = append(numbers, revision.Revision)
+		}
+		Expect(numbers).To(Equal([]int64{1, 3, 4}))
+	})
+})
@@ -0,0 +1,120 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// MoveToSecret copies a plaintext spec value into the secret the ref points to and clears the value,
+// a missing ref is set to the default name and key. Only a secret the CR owns is written, the secrets of the user are
+// never changed. Returns true when the spec was changed.
+func MoveToSecret(k8sclient client.Client,
+	scheme *runtime.Scheme,
+	cr metav1.Object,
+	value *string,
+	ref **operatorv1alpha1.AquaSecret,
+	defaultName, defaultKey, description string) (bool, error) {
+	if len(*value) == 0 {
+		return false, nil
+	}
+
+	if *ref == nil {
+		*ref = &operatorv1alpha1.AquaSecret{
+			Name: defaultName,
+			Key:  defaultKey,
+		}
+	}
+
+	reqLogger := log.WithValues("Secret Ref Phase", "Move Plaintext Value To Secret")
+
+	found := &corev1.Secret{}
+	err := k8sclient.Get(context.TODO(), types.NamespacedName{Name: (*ref).Name, Namespace: cr.GetNamespace()}, found)
+	if err != nil && errors.IsNotFound(err) {
+		secret := secrets.CreateSecret(cr.GetName(),
+			cr.GetNamespace(),
+			fmt.Sprintf("%s-requirments", cr.GetName()),
+			description,
+			(*ref).Name,
+			(*ref).Key,
+			*value)
+		if err := controllerutil.SetControllerReference(cr, secret, scheme); err != nil {
+			return false, err
+		}
+
+		reqLogger.Info("Creating a New Secret From Plaintext Spec Value", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+		err = k8sclient.Create(context.TODO(), secret)
+		if err != nil {
+			return false, err
+		}
+	} else if err != nil {
+		return false, err
+	} else {
+		if !metav1.IsControlledBy(found, cr) {
+			return false, fmt.Errorf("the spec has a plaintext value for the secret %s, which %s doesn't own: "+
+				"remove the plaintext value, or the reference to the secret", found.Name, cr.GetName())
+		}
+		if found.Data == nil {
+			found.Data = map[string][]byte{}
+		}
+		found.Data[(*ref).Key] = []byte(*value)
+
+		reqLogger.Info("Updating Secret From Plaintext Spec Value", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
+		err = k8sclient.Update(context.TODO(), found)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	*value = ""
+	return true, nil
+}
+
+// MoveDatabasePasswords moves the external database password into the common database secret
+// and the audit database password into the audit database secret
+func MoveDatabasePasswords(k8sclient client.Client,
+	scheme *runtime.Scheme,
+	cr metav1.Object,
+	common *operatorv1alpha1.AquaCommon,
+	externalDb *operatorv1alpha1.AquaDatabaseInformation,
+	auditDb *operatorv1alpha1.AuditDBInformation) (bool, error) {
+	changed := false
+
+	if externalDb != nil && common != nil {
+		moved, err := MoveToSecret(k8sclient, scheme, cr,
+			&externalDb.Password,
+			&common.DatabaseSecret,
+			fmt.Sprintf(consts.ScalockDbPasswordSecretName, cr.GetName()),
+			consts.ScalockDbPasswordSecretKey,
+			"Secret for aqua database password")
+		if err != nil {
+			return false, err
+		}
+		changed = changed || moved
+	}
+
+	if auditDb != nil && auditDb.Data != nil {
+		moved, err := MoveToSecret(k8sclient, scheme, cr,
+			&auditDb.Data.Password,
+			&auditDb.AuditDBSecret,
+			fmt.Sprintf(consts.AuditDbPasswordSecretName, cr.GetName()),
+			consts.ScalockDbPasswordSecretKey,
+			"Secret for aqua audit database password")
+		if err != nil {
+			return false, err
+		}
+		changed = changed || moved
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,181 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/secretstore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type SecretStoreParameters struct {
+	Store     *operatorv1alpha1.AquaSecretStore
+	Namespace string
+	Client    client.Client
+	Scheme    *runtime.Scheme
+	Cr        metav1.Object
+}
+
+// AquaSecretStoreHelper materializes the secret store values into the secrets the workloads use
+type AquaSecretStoreHelper struct {
+	Parameters SecretStoreParameters
+}
+
+func NewAquaSecretStoreHelper(store *operatorv1alpha1.AquaSecretStore,
+	namespace string,
+	k8sclient client.Client,
+	scheme *runtime.Scheme,
+	cr metav1.Object) *AquaSecretStoreHelper {
+	params := SecretStoreParameters{
+		Store:     store,
+		Namespace: namespace,
+		Client:    k8sclient,
+		Scheme:    scheme,
+		Cr:        cr,
+	}
+
+	return &AquaSecretStoreHelper{
+		Parameters: params,
+	}
+}
+
+// GetSecretStore returns the secret store of the common section, nil if not defined
+func GetSecretStore(common *operatorv1alpha1.AquaCommon) *operatorv1alpha1.AquaSecretStore {
+	if common == nil {
+		return nil
+	}
+
+	return common.SecretStore
+}
+
+// RefreshInterval returns how often the store values are read
+func (ss *AquaSecretStoreHelper) RefreshInterval() time.Duration {
+	if ss.Parameters.Store.RefreshInterval != nil && ss.Parameters.Store.RefreshInterval.Duration > 0 {
+		return ss.Parameters.Store.RefreshInterval.Duration
+	}
+
+	return consts.SecretStoreRefreshInterval
+}
+
+// SyncSecret creates or updates the secret with the data built from the store values of the given keys.
+// The store is read only when the refresh interval passed or the store definition changed,
+// the returned bool reports a change of the secret data.
+func (ss *AquaSecretStoreHelper) SyncSecret(name string, build func(values map[string]string) *corev1.Secret, keys ...string) (bool, error) {
+	reqLogger := log.WithValues("Secret Store Phase", "Sync Secret")
+
+	hash, err := extra.GenerateMD5ForSpec(ss.Parameters.Store)
+	if err != nil {
+		return false, err
+	}
+
+	found := &corev1.Secret{}
+	err = ss.Parameters.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: ss.Parameters.Namespace}, found)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	exists := err == nil
+
+	if exists && found.Annotations[consts.SecretStoreHashAnnotation] == hash {
+		synced, err := time.Parse(time.RFC3339, found.Annotations[consts.SecretStoreSyncedAnnotation])
+		if err == nil && time.Since(synced) < ss.RefreshInterval() {
+			return false, nil
+		}
+	}
+
+	reqLogger.Info("Reading secret store", "Secret.Namespace", ss.Parameters.Namespace, "Secret.Name", name)
+	values, err := secretstore.Read(ss.Parameters.Client, ss.Parameters.Namespace, ss.Parameters.Store, keys...)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read secret store", "Secret.Name", name)
+		return false, err
+	}
+
+	secret := build(values)
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[consts.SecretStoreHashAnnotation] = hash
+	secret.Annotations[consts.SecretStoreSyncedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+
+	if !exists {
+		if err := controllerutil.SetControllerReference(ss.Parameters.Cr, secret, ss.Parameters.Scheme); err != nil {
+			return false, err
+		}
+
+		reqLogger.Info("Creating a New Secret From Secret Store", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+		err = ss.Parameters.Client.Create(context.TODO(), secret)
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	// keep keys the store doesn't manage, the secret may be shared with other credentials
+	changed := !equality.Semantic.DeepDerivative(secret.Data, found.Data)
+	if found.Data == nil {
+		found.Data = map[string][]byte{}
+	}
+	for key, value := range secret.Data {
+		found.Data[key] = value
+	}
+	if found.Annotations == nil {
+		found.Annotations = map[string]string{}
+	}
+	for key, value := range secret.Annotations {
+		found.Annotations[key] = value
+	}
+
+	reqLogger.Info("Updating Secret From Secret Store", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name, "Changed", changed)
+	err = ss.Parameters.Client.Update(context.TODO(), found)
+	if err != nil {
+		return false, err
+	}
+
+	return changed, nil
+}
+
+// SyncSecretKeys materializes the store keys into an opaque secret, keys maps the secret keys to the store keys
+func (ss *AquaSecretStoreHelper) SyncSecretKeys(name, app, description string, keys map[string]string) (bool, error) {
+	storeKeys := []string{}
+	for _, storeKey := range keys {
+		storeKeys = append(storeKeys, storeKey)
+	}
+
+	return ss.SyncSecret(name, func(values map[string]string) *corev1.Secret {
+		data := map[string][]byte{}
+		for secretKey, storeKey := range keys {
+			data[secretKey] = []byte(values[storeKey])
+		}
+
+		return &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "core/v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ss.Parameters.Namespace,
+				Labels: map[string]string{
+					"app":                app,
+					"deployedby":         "aqua-operator",
+					"aquasecoperator_cr": ss.Parameters.Cr.GetName(),
+				},
+				Annotations: map[string]string{
+					"description": description,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+	}, storeKeys...)
+}
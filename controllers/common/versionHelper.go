@@ -0,0 +1,92 @@
+package common
+
+import (
+	"context"
+	"reflect"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/versions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveVersion returns the Aqua version to roll out for the requested version. When the requested version
+// can't be upgraded to directly from the deployed one, the next version on the upgrade path is returned and the
+// following step is taken once it is rolled out. An unsupported upgrade keeps the deployed version and returns an error.
+func ResolveVersion(requested, deployed string, allowAnyVersion bool) (string, error) {
+	if allowAnyVersion {
+		return requested, nil
+	}
+
+	requestedRelease, ok := versions.Lookup(requested)
+	if !ok {
+		if len(deployed) != 0 {
+			return deployed, &versions.UnsupportedVersionError{Version: requested}
+		}
+		return consts.LatestVersion, &versions.UnsupportedVersionError{Version: requested}
+	}
+
+	// Nothing rolled out yet, or it was rolled out with allowAnyVersion
+	if _, ok := versions.Lookup(deployed); !ok {
+		return requested, nil
+	}
+
+	path, err := versions.UpgradePath(deployed, requested)
+	if err != nil {
+		return deployed, err
+	}
+
+	if len(path) == 0 || path[0] == requestedRelease.Version {
+		return requested, nil
+	}
+
+	return path[0], nil
+}
+
+// ResolveInfrastructureVersion sets infra.Version to the version to roll out in this reconcile, see ResolveVersion.
+// The change must not be written back to the CR, so the requested version is kept.
+func ResolveInfrastructureVersion(infra *operatorv1alpha1.AquaInfrastructure, deployed string, allowAnyVersion bool) {
+	if infra == nil {
+		return
+	}
+
+	reqLogger := log.WithValues("Version Phase", "Resolve Version")
+
+	version, err := ResolveVersion(infra.Version, deployed, allowAnyVersion)
+	if err != nil {
+		reqLogger.Error(err, "Rejected the requested version", "Requested", infra.Version, "Deployed", deployed, "Using", version)
+	} else if version != infra.Version {
+		reqLogger.Info("Stepping through an intermediate version", "Requested", infra.Version, "Deployed", deployed, "Next", version)
+	}
+
+	infra.Version = version
+}
+
+// UpdateStatus updates the status of the CR through a copy, the update reads the stored object back and would replace
+// the spec of this reconcile, like the version ResolveInfrastructureVersion resolved, with the requested one
+func UpdateStatus(k8sclient client.Client, cr client.Object) error {
+	updated := cr.DeepCopyObject().(client.Object)
+	err := k8sclient.Status().Update(context.Background(), updated)
+	if err != nil {
+		return err
+	}
+	cr.SetResourceVersion(updated.GetResourceVersion())
+
+	return nil
+}
+
+// UpdateVersionStatus records the rolled out version and the upgrades available from it, returns true if changed
+func UpdateVersionStatus(version *string, availableUpgrades *[]string, rolledOut string) bool {
+	upgrades := versions.AvailableUpgrades(rolledOut)
+	if len(upgrades) == 0 {
+		upgrades = nil
+	}
+
+	if *version == rolledOut && reflect.DeepEqual(*availableUpgrades, upgrades) {
+		return false
+	}
+
+	*version = rolledOut
+	*availableUpgrades = upgrades
+	return true
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"context"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/versions"
+	testutils "github.com/aquasecurity/aqua-operator/test/utils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Version helper", func() {
+	DescribeTable("ResolveVersion",
+		func(requested, deployed string, allowAnyVersion bool, version string, failed bool) {
+			result, err := ResolveVersion(requested, deployed, allowAnyVersion)
+			Expect(result).To(Equal(version))
+			Expect(err != nil).To(Equal(failed))
+		},
+		Entry("a first deployment", "6.5", "", false, "6.5", false),
+		Entry("the deployed version", "2022.4", "2022.4", false, "2022.4", false),
+		Entry("a patch release of the deployed version", "2022.4.5", "2022.4", false, "2022.4.5", false),
+		Entry("a direct upgrade", "2022.4", "6.5", false, "2022.4", false),
+		Entry("an upgrade through an intermediate version", "2022.4", "6.2", false, "6.5", false),
+		Entry("the last step of an upgrade", "2022.4.5", "6.5", false, "2022.4.5", false),
+		Entry("a downgrade keeps the deployed version", "6.5", "2022.4", false, "2022.4", true),
+		Entry("an unsupported version keeps the deployed version", "5.3", "6.5", false, "6.5", true),
+		Entry("an unsupported first deployment gets the latest version", "5.3", "", false, consts.LatestVersion, true),
+		Entry("a version deployed with allowAnyVersion", "6.5", "7.0-custom", false, "6.5", false),
+		Entry("allowAnyVersion", "5.3", "2022.4", true, "5.3", false),
+	)
+
+	It("returns the unsupported upgrade", func() {
+		_, err := ResolveVersion("6.5", "2022.4", false)
+		Expect(err).To(Equal(&versions.UnsupportedUpgradeError{From: "2022.4", To: "6.5"}))
+	})
+
+	Describe("ResolveInfrastructureVersion", func() {
+		It("sets the next version on the upgrade path", func() {
+			infra := &operatorv1alpha1.AquaInfrastructure{Version: "2022.4"}
+			ResolveInfrastructureVersion(infra, "6.2", false)
+			Expect(infra.Version).To(Equal("6.5"))
+		})
+
+		It("ignores a missing infrastructure", func() {
+			Expect(func() { ResolveInfrastructureVersion(nil, "6.2", false) }).NotTo(Panic())
+		})
+	})
+
+	Describe("UpdateVersionStatus", func() {
+		It("records the rolled out version and its upgrades once", func() {
+			version := ""
+			var upgrades []string
+			Expect(UpdateVersionStatus(&version, &upgrades, "6.2")).To(BeTrue())
+			Expect(version).To(Equal("6.2"))
+			Expect(upgrades).To(Equal([]string{"6.5", "2022.4"}))
+			Expect(UpdateVersionStatus(&version, &upgrades, "6.2")).To(BeFalse())
+		})
+
+		It("has no upgrades from the latest version", func() {
+			version := "6.5"
+			upgrades := []string{"2022.4"}
+			Expect(UpdateVersionStatus(&version, &upgrades, consts.LatestVersion)).To(BeTrue())
+			Expect(upgrades).To(BeNil())
+		})
+	})
+
+	Describe("UpdateStatus", func() {
+		It("keeps the version resolved for the reconcile through a status write", func() {
+			scheme := runtime.NewScheme()
+			Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+			cr := &operatorv1alpha1.AquaServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua"},
+				Spec: operatorv1alpha1.AquaServerSpec{
+					Infrastructure: &operatorv1alpha1.AquaInfrastructure{Version: "2022.4"},
+				},
+				Status: operatorv1alpha1.AquaServerStatus{Version: "6.2"},
+			}
+			k8sclient := testutils.StatusClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr).Build()}
+			Expect(k8sclient.Get(context.Background(), client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+
+			ResolveInfrastructureVersion(cr.Spec.Infrastructure, cr.Status.Version, false)
+			cr.Status.State = operatorv1alpha1.AquaDeploymentStatePending
+			Expect(UpdateStatus(k8sclient, cr)).To(Succeed())
+
+			// the render and the version status see the resolved version
+			Expect(cr.Spec.Infrastructure.Version).To(Equal("6.5"))
+			Expect(UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, cr.Spec.Infrastructure.Version)).To(BeTrue())
+			Expect(UpdateStatus(k8sclient, cr)).To(Succeed())
+
+			stored := &operatorv1alpha1.AquaServer{}
+			Expect(k8sclient.Get(context.Background(), client.ObjectKeyFromObject(cr), stored)).To(Succeed())
+			Expect(stored.Spec.Infrastructure.Version).To(Equal("2022.4"))
+			Expect(stored.Status.Version).To(Equal("6.5"))
+			Expect(stored.Status.State).To(Equal(operatorv1alpha1.AquaDeploymentStatePending))
+			Expect(cr.ResourceVersion).To(Equal(stored.ResourceVersion))
+		})
+	})
+})
@@ -0,0 +1,286 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aquaconsole
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/controllers/common"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+)
+
+var log = logf.Log.WithName("controller_aquaconsole")
+
+// AquaConsoleReconciler syncs the CRs of a kind into the objects of the console of an Aqua server, through its API
+type AquaConsoleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Resource The kind of the CRs and of the objects of the console
+	Resource ConsoleResource
+}
+
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaenforcergroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaenforcergroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaenforcergroups/finalizers,verbs=update
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaregistryintegrations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaregistryintegrations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaregistryintegrations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaruntimepolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaruntimepolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaruntimepolicies/finalizers,verbs=update
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaservers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile writes the object of the CR to the console when it is missing or the spec changed, and applies the drift
+// policy to the changes made in the console. The console is compared again every sync interval.
+func (r *AquaConsoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	kind := r.Resource.Kind()
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name, "Kind", kind)
+	reqLogger.Info("Reconciling " + kind)
+
+	instance := r.Resource.New()
+	err := r.Client.Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	// the object of the console isn't owned by the CR, the finalizer deletes it
+	if instance.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(instance, consts.AquaConsoleFinalizer) {
+			err = r.removeFromConsole(instance)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	sync := instance.GetConsoleSync()
+	if common.ReconcilePaused(instance, sync.Paused) {
+		return reconcile.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(instance, consts.AquaConsoleFinalizer) {
+		controllerutil.AddFinalizer(instance, consts.AquaConsoleFinalizer)
+		err = r.Client.Update(context.TODO(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	err = r.syncToConsole(instance)
+	if err != nil {
+		reqLogger.Error(err, "Failed to sync the "+kind+" into the Aqua server")
+		status := instance.GetConsoleStatus()
+		status.State = operatorv1alpha1.AquaConsoleStateFailed
+		status.Message = err.Error()
+		status.ObservedGeneration = instance.GetGeneration()
+		_ = r.Client.Status().Update(context.Background(), instance)
+		return reconcile.Result{}, err
+	}
+
+	interval := consts.ConsoleSyncInterval
+	if sync.SyncInterval != nil && sync.SyncInterval.Duration > 0 {
+		interval = sync.SyncInterval.Duration
+	}
+
+	return reconcile.Result{RequeueAfter: interval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AquaConsoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(strings.ToLower(r.Resource.Kind())+"-controller").
+		WithOptions(controller.Options{Reconciler: r}).
+		// the status updates of the controller don't trigger another sync, the console is read every sync interval.
+		// The annotations still do, to resume a CR paused by annotation
+		For(r.Resource.New(), builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Complete(r)
+}
+
+/*	----------------------------------------------------------------------------------------------------------------
+							Aqua Console
+	----------------------------------------------------------------------------------------------------------------
+*/
+
+// syncToConsole creates the object in the console, or updates it when the rendering changed or the drift policy
+// reverts the changes made in the console, then records the sync in the status
+func (r *AquaConsoleReconciler) syncToConsole(cr ConsoleObject) error {
+	kind := r.Resource.Kind()
+	name := r.Resource.Name(cr)
+	reqLogger := log.WithValues("Console Phase", "Sync "+kind, "Name", name)
+
+	sync := cr.GetConsoleSync()
+	status := cr.GetConsoleStatus()
+
+	api, err := common.NewAquaServerApiClient(r.Client, cr.GetNamespace(), sync.Server)
+	if err != nil {
+		return err
+	}
+
+	desired, secretFields, err := r.Resource.Render(r.Client, cr)
+	if err != nil {
+		return err
+	}
+	hash, err := extra.GenerateMD5ForSpec(desired)
+	if err != nil {
+		return err
+	}
+
+	// the object of the previous name is left behind like the one of a deleted CR
+	if len(status.Name) != 0 && status.Name != name && deletedWithCR(sync, status) {
+		reqLogger.Info("Deleting the renamed object from the console", "Previous", status.Name)
+		err = api.Delete(r.Resource.Path(status.Name))
+		if err != nil {
+			return err
+		}
+	}
+
+	live, err := api.Get(r.Resource.Path(name))
+	if err != nil {
+		return err
+	}
+
+	state := operatorv1alpha1.AquaConsoleStateSynced
+	if live == nil {
+		// the creation is recorded first, the object is still the one of the CR when the status write after it fails
+		status.Name = name
+		status.Created = true
+		err = common.UpdateStatus(r.Client, cr)
+		if err != nil {
+			return err
+		}
+
+		reqLogger.Info("Creating the object in the console")
+		err = api.Create(r.Resource.Path(""), desired)
+	} else if status.Name != name {
+		// another CR or a user of the console owns the object, it is only synced into when adopted
+		if !sync.Adopt {
+			return fmt.Errorf("%s %s already exists in the console, set adopt to sync the CR into it", kind, name)
+		}
+
+		reqLogger.Info("Adopting the object of the console")
+		status.Created = false
+		err = api.Update(r.Resource.Path(name), desired)
+	} else if status.Hash != hash {
+		reqLogger.Info("Updating the object in the console")
+		err = api.Update(r.Resource.Path(name), desired)
+	} else {
+		compared := map[string]interface{}{}
+		for key, value := range desired {
+			compared[key] = value
+		}
+		for _, field := range secretFields {
+			delete(compared, field)
+		}
+
+		driftHelper := common.NewAquaDriftHelper(sync.DriftPolicy, &status.Drifts, r.Client, cr)
+		kept, revert, driftErr := driftHelper.ReconcileOperations(kind, name, k8s.DiffJSON(compared, live))
+		if driftErr != nil {
+			return driftErr
+		}
+		if revert {
+			reqLogger.Info("Reverting the changes made in the console")
+			k8s.PreserveJSONFields(desired, kept)
+			err = api.Update(r.Resource.Path(name), desired)
+		}
+
+		for _, drift := range status.Drifts {
+			if drift.Kind == kind && drift.Name == name && drift.Action == operatorv1alpha1.AquaDriftActionReport {
+				state = operatorv1alpha1.AquaConsoleStateDrifted
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	status.State = state
+	status.Message = ""
+	status.Name = name
+	status.Hash = hash
+	status.ObservedGeneration = cr.GetGeneration()
+	status.LastSync = &now
+
+	return common.UpdateStatus(r.Client, cr)
+}
+
+// removeFromConsole deletes the object of the CR from the console, unless the deletion policy orphans it, and removes
+// the finalizer of the CR
+func (r *AquaConsoleReconciler) removeFromConsole(cr ConsoleObject) error {
+	reqLogger := log.WithValues("Console Phase", "Remove "+r.Resource.Kind())
+
+	sync := cr.GetConsoleSync()
+	status := cr.GetConsoleStatus()
+	name := status.Name
+	if len(name) != 0 && deletedWithCR(sync, status) {
+		api, err := common.NewAquaServerApiClient(r.Client, cr.GetNamespace(), sync.Server)
+		if err != nil && errors.IsNotFound(err) {
+			// the server and its console are gone
+			reqLogger.Info("The Aqua server doesn't exist anymore, nothing to delete", "Server", sync.Server)
+		} else if err != nil {
+			return err
+		} else {
+			err = api.Delete(r.Resource.Path(name))
+			if err != nil {
+				return fmt.Errorf("deleting %s from the console: %v", name, err)
+			}
+			reqLogger.Info("Deleted the object from the console", "Name", name)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(cr, consts.AquaConsoleFinalizer)
+	return r.Client.Update(context.TODO(), cr)
+}
+
+// deletedWithCR returns true when the object of the console is deleted with the CR, by default only the objects the
+// operator created are
+func deletedWithCR(sync *operatorv1alpha1.AquaConsoleSync, status *operatorv1alpha1.AquaConsoleStatus) bool {
+	switch sync.DeletionPolicy {
+	case consts.ConsoleDeletionPolicyOrphan:
+		return false
+	case consts.ConsoleDeletionPolicyDelete:
+		return true
+	}
+
+	return status.Created
+}
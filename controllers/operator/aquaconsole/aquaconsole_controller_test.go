@@ -0,0 +1,206 @@
+package aquaconsole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/operatorconfig"
+	testutils "github.com/aquasecurity/aqua-operator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeConsole Serves the registries of the Aqua API
+type fakeConsole struct {
+	mutex      sync.Mutex
+	registries map[string]map[string]interface{}
+}
+
+func (c *fakeConsole) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/registries/")
+	switch {
+	case r.URL.Path == "/api/v1/login":
+		_, _ = w.Write([]byte(`{"token":"token"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/registries":
+		registry := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&registry)
+		c.registries[registry["name"].(string)] = registry
+	case name == r.URL.Path:
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodPut:
+		if _, ok := c.registries[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		registry := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&registry)
+		c.registries[name] = registry
+	case r.Method == http.MethodDelete:
+		if _, ok := c.registries[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(c.registries, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		registry, ok := c.registries[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(registry)
+	}
+}
+
+func (c *fakeConsole) registry(name string) map[string]interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.registries[name]
+}
+
+var _ = Describe("Console sync", func() {
+	var (
+		ctx        context.Context
+		console    *fakeConsole
+		reconciler *AquaConsoleReconciler
+		cr         *operatorv1alpha1.AquaRegistryIntegration
+	)
+
+	// reconcile creates the CR and syncs it into the console
+	reconcile := func() error {
+		Expect(reconciler.Client.Create(ctx, cr)).To(Succeed())
+		return reconciler.syncToConsole(cr)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		console = &fakeConsole{registries: map[string]map[string]interface{}{
+			"Docker Hub": {"name": "Docker Hub", "type": "HUB", "url": "https://docker.io", "auto_pull": true},
+		}}
+		server := httptest.NewServer(console)
+		DeferCleanup(server.Close)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		// the API of the AquaServers is the fake console
+		Expect(os.Setenv("OPERATOR_NAMESPACE", "aqua-operator")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "OPERATOR_NAMESPACE")
+		operatorconfig.SetReader(fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: consts.OperatorConfigMapName, Namespace: "aqua-operator"},
+			Data:       map[string]string{consts.OperatorConfigAquaServerApiKey: "url: " + server.URL},
+		}).Build())
+		DeferCleanup(func() { operatorconfig.SetReader(nil) })
+
+		k8sclient := testutils.StatusClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&operatorv1alpha1.AquaServer{ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua"}},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf(consts.AdminPasswordSecretName, "aqua"), Namespace: "aqua"},
+				Data:       map[string][]byte{consts.AdminPasswordSecretKey: []byte("secret")},
+			},
+		).Build()}
+		reconciler = &AquaConsoleReconciler{Client: k8sclient, Scheme: scheme, Resource: RegistryIntegrations}
+
+		cr = &operatorv1alpha1.AquaRegistryIntegration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "registry",
+				Namespace:  "aqua",
+				Finalizers: []string{consts.AquaConsoleFinalizer},
+			},
+			Spec: operatorv1alpha1.AquaRegistryIntegrationSpec{
+				AquaConsoleSync: operatorv1alpha1.AquaConsoleSync{Server: "aqua"},
+				Type:            "V2",
+				URL:             "https://registry.aquasec.com",
+			},
+		}
+	})
+
+	It("creates a missing object and deletes it with the CR", func() {
+		Expect(reconcile()).To(Succeed())
+
+		Expect(cr.Status.Name).To(Equal("registry"))
+		Expect(cr.Status.Created).To(BeTrue())
+		Expect(cr.Status.State).To(Equal(operatorv1alpha1.AquaConsoleStateSynced))
+		Expect(console.registry("registry")).To(HaveKeyWithValue("url", "https://registry.aquasec.com"))
+
+		stored := &operatorv1alpha1.AquaRegistryIntegration{}
+		Expect(reconciler.Client.Get(ctx, client.ObjectKeyFromObject(cr), stored)).To(Succeed())
+		Expect(stored.Status.Created).To(BeTrue())
+
+		Expect(reconciler.removeFromConsole(cr)).To(Succeed())
+		Expect(console.registry("registry")).To(BeNil())
+	})
+
+	It("updates the object it created when the spec changes", func() {
+		Expect(reconcile()).To(Succeed())
+
+		cr.Spec.URL = "https://mirror.aquasec.com"
+		Expect(reconciler.syncToConsole(cr)).To(Succeed())
+
+		Expect(cr.Status.Created).To(BeTrue())
+		Expect(console.registry("registry")).To(HaveKeyWithValue("url", "https://mirror.aquasec.com"))
+	})
+
+	It("refuses an object of the console it didn't create", func() {
+		cr.Spec.Name = "Docker Hub"
+
+		Expect(reconcile()).To(MatchError("AquaRegistryIntegration Docker Hub already exists in the console, set adopt to sync the CR into it"))
+		Expect(console.registry("Docker Hub")).To(HaveKeyWithValue("url", "https://docker.io"))
+		Expect(cr.Status.Name).To(BeEmpty())
+
+		Expect(reconciler.removeFromConsole(cr)).To(Succeed())
+		Expect(console.registry("Docker Hub")).NotTo(BeNil())
+	})
+
+	It("adopts an object of the console and keeps it with the CR", func() {
+		cr.Spec.Name = "Docker Hub"
+		cr.Spec.Adopt = true
+
+		Expect(reconcile()).To(Succeed())
+
+		Expect(cr.Status.Name).To(Equal("Docker Hub"))
+		Expect(cr.Status.Created).To(BeFalse())
+		Expect(console.registry("Docker Hub")).To(HaveKeyWithValue("url", "https://registry.aquasec.com"))
+
+		Expect(reconciler.removeFromConsole(cr)).To(Succeed())
+		Expect(console.registry("Docker Hub")).NotTo(BeNil())
+	})
+
+	It("deletes an adopted object with the Delete deletion policy", func() {
+		cr.Spec.Name = "Docker Hub"
+		cr.Spec.Adopt = true
+		cr.Spec.DeletionPolicy = consts.ConsoleDeletionPolicyDelete
+
+		Expect(reconcile()).To(Succeed())
+		Expect(reconciler.removeFromConsole(cr)).To(Succeed())
+		Expect(console.registry("Docker Hub")).To(BeNil())
+	})
+
+	It("keeps an object it created with the Orphan deletion policy", func() {
+		cr.Spec.DeletionPolicy = consts.ConsoleDeletionPolicyOrphan
+
+		Expect(reconcile()).To(Succeed())
+		Expect(reconciler.removeFromConsole(cr)).To(Succeed())
+		Expect(console.registry("registry")).NotTo(BeNil())
+	})
+})
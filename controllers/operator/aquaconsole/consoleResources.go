@@ -0,0 +1,241 @@
+package aquaconsole
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConsoleObject A CR the console controller syncs into the Aqua server
+type ConsoleObject interface {
+	client.Object
+	GetConsoleSync() *operatorv1alpha1.AquaConsoleSync
+	GetConsoleStatus() *operatorv1alpha1.AquaConsoleStatus
+}
+
+// ConsoleResource Renders the CRs of a kind into the objects of the Aqua API
+type ConsoleResource interface {
+	// Kind Kind of the CRs
+	Kind() string
+	// New returns an empty CR of the kind
+	New() ConsoleObject
+	// Path returns the API path of the collection of the objects, or of the object when name isn't empty
+	Path(name string) string
+	// Name returns the name of the object of the CR in the console
+	Name(cr ConsoleObject) string
+	// Render returns the object of the CR in the API, and its fields the API doesn't return
+	Render(k8sclient client.Client, cr ConsoleObject) (map[string]interface{}, []string, error)
+}
+
+var (
+	// EnforcerGroups Syncs the AquaEnforcerGroups into the enforcer groups of the console
+	EnforcerGroups ConsoleResource = enforcerGroups{}
+
+	// RegistryIntegrations Syncs the AquaRegistryIntegrations into the registries of the console
+	RegistryIntegrations ConsoleResource = registryIntegrations{}
+
+	// RuntimePolicies Syncs the AquaRuntimePolicies into the runtime policies of the console
+	RuntimePolicies ConsoleResource = runtimePolicies{}
+)
+
+type enforcerGroups struct{}
+
+func (enforcerGroups) Kind() string {
+	return "AquaEnforcerGroup"
+}
+
+func (enforcerGroups) New() ConsoleObject {
+	return &operatorv1alpha1.AquaEnforcerGroup{}
+}
+
+func (enforcerGroups) Path(name string) string {
+	return objectPath("/api/v1/hostsbatch", name)
+}
+
+func (enforcerGroups) Name(obj ConsoleObject) string {
+	return consoleName(obj, obj.(*operatorv1alpha1.AquaEnforcerGroup).Spec.Name)
+}
+
+func (r enforcerGroups) Render(_ client.Client, obj ConsoleObject) (map[string]interface{}, []string, error) {
+	cr := obj.(*operatorv1alpha1.AquaEnforcerGroup)
+	group, err := settings(cr.Spec.Settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	group["id"] = r.Name(cr)
+	group["type"] = "agent"
+	if len(cr.Spec.Type) != 0 {
+		group["type"] = cr.Spec.Type
+	}
+	if len(cr.Spec.Description) != 0 {
+		group["description"] = cr.Spec.Description
+	}
+	if len(cr.Spec.LogicalName) != 0 {
+		group["logicalname"] = cr.Spec.LogicalName
+	}
+	if len(cr.Spec.Gateways) != 0 {
+		group["gateways"] = cr.Spec.Gateways
+	}
+	group["enforce"] = cr.Spec.Enforce
+	if orchestrator := cr.Spec.Orchestrator; orchestrator != nil {
+		group["orchestrator"] = map[string]interface{}{
+			"type":            orchestrator.Type,
+			"namespace":       orchestrator.Namespace,
+			"service_account": orchestrator.ServiceAccount,
+		}
+	}
+
+	group, err = normalize(group)
+	return group, nil, err
+}
+
+type registryIntegrations struct{}
+
+func (registryIntegrations) Kind() string {
+	return "AquaRegistryIntegration"
+}
+
+func (registryIntegrations) New() ConsoleObject {
+	return &operatorv1alpha1.AquaRegistryIntegration{}
+}
+
+func (registryIntegrations) Path(name string) string {
+	return objectPath("/api/v1/registries", name)
+}
+
+func (registryIntegrations) Name(obj ConsoleObject) string {
+	return consoleName(obj, obj.(*operatorv1alpha1.AquaRegistryIntegration).Spec.Name)
+}
+
+func (r registryIntegrations) Render(k8sclient client.Client, obj ConsoleObject) (map[string]interface{}, []string, error) {
+	cr := obj.(*operatorv1alpha1.AquaRegistryIntegration)
+	registry, err := settings(cr.Spec.Settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	registry["name"] = r.Name(cr)
+	registry["type"] = cr.Spec.Type
+	if len(cr.Spec.URL) != 0 {
+		registry["url"] = cr.Spec.URL
+	}
+	if len(cr.Spec.Username) != 0 {
+		registry["username"] = cr.Spec.Username
+	}
+	if len(cr.Spec.Prefixes) != 0 {
+		registry["prefixes"] = cr.Spec.Prefixes
+	}
+	registry["auto_pull"] = cr.Spec.AutoPull
+
+	// the API doesn't return the password, a new one is written when the hash of the rendering changes
+	secretFields := []string{}
+	if cr.Spec.PasswordSecretRef != nil {
+		password, err := secrets.GetSecretValue(k8sclient, cr.Namespace, cr.Spec.PasswordSecretRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		registry["password"] = password
+		secretFields = append(secretFields, "password")
+	}
+
+	registry, err = normalize(registry)
+	return registry, secretFields, err
+}
+
+type runtimePolicies struct{}
+
+func (runtimePolicies) Kind() string {
+	return "AquaRuntimePolicy"
+}
+
+func (runtimePolicies) New() ConsoleObject {
+	return &operatorv1alpha1.AquaRuntimePolicy{}
+}
+
+func (runtimePolicies) Path(name string) string {
+	return objectPath("/api/v2/runtime_policies", name)
+}
+
+func (runtimePolicies) Name(obj ConsoleObject) string {
+	return consoleName(obj, obj.(*operatorv1alpha1.AquaRuntimePolicy).Spec.Name)
+}
+
+func (r runtimePolicies) Render(_ client.Client, obj ConsoleObject) (map[string]interface{}, []string, error) {
+	cr := obj.(*operatorv1alpha1.AquaRuntimePolicy)
+	policy, err := settings(cr.Spec.Controls)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policy["name"] = r.Name(cr)
+	if len(cr.Spec.Description) != 0 {
+		policy["description"] = cr.Spec.Description
+	}
+	policy["enabled"] = cr.Spec.Enabled == nil || *cr.Spec.Enabled
+	policy["enforce"] = cr.Spec.Enforce
+	if scope := cr.Spec.Scope; scope != nil {
+		variables := []interface{}{}
+		for _, variable := range scope.Variables {
+			variables = append(variables, map[string]interface{}{
+				"attribute": variable.Attribute,
+				"value":     variable.Value,
+			})
+		}
+		policy["scope"] = map[string]interface{}{
+			"expression": scope.Expression,
+			"variables":  variables,
+		}
+	}
+
+	policy, err = normalize(policy)
+	return policy, nil, err
+}
+
+// consoleName returns the name of the object in the console, the name of the CR when the spec doesn't set one
+func consoleName(cr ConsoleObject, name string) string {
+	if len(name) != 0 {
+		return name
+	}
+
+	return cr.GetName()
+}
+
+func objectPath(collection, name string) string {
+	if len(name) == 0 {
+		return collection
+	}
+
+	return collection + "/" + url.PathEscape(name)
+}
+
+// settings returns the fields of the API the spec passes as is, the fields of the spec are set over them
+func settings(raw *runtime.RawExtension) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if raw == nil || len(raw.Raw) == 0 {
+		return fields, nil
+	}
+
+	err := json.Unmarshal(raw.Raw, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("the settings aren't a JSON object: %v", err)
+	}
+
+	return fields, nil
+}
+
+// normalize returns the object as decoded from JSON, so it compares with the objects the API returns
+func normalize(obj map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := map[string]interface{}{}
+	return normalized, json.Unmarshal(data, &normalized)
+}
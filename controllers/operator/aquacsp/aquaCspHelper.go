@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
 	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/versions"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -26,6 +27,16 @@ func newAquaCspHelper(cr *v1alpha1.AquaCsp) *AquaCspHelper {
 	}
 }
 
+// infrastructureFor returns the infrastructure of the components upgraded in the phase, with their version
+func (csp *AquaCspHelper) infrastructureFor(phase v1alpha1.AquaUpgradePhase) *v1alpha1.AquaInfrastructure {
+	infra := csp.Parameters.AquaCsp.Spec.Infrastructure.DeepCopy()
+	if infra != nil {
+		infra.Version = componentVersion(csp.Parameters.AquaCsp, phase)
+	}
+
+	return infra
+}
+
 func (csp *AquaCspHelper) newAquaDatabase(cr *v1alpha1.AquaCsp) *v1alpha1.AquaDatabase {
 	labels := map[string]string{
 		"app":                cr.Name + "-csp",
@@ -48,12 +59,13 @@ func (csp *AquaCspHelper) newAquaDatabase(cr *v1alpha1.AquaCsp) *v1alpha1.AquaDa
 			Annotations: annotations,
 		},
 		Spec: v1alpha1.AquaDatabaseSpec{
-			Infrastructure: csp.Parameters.AquaCsp.Spec.Infrastructure,
-			Common:         csp.Parameters.AquaCsp.Spec.Common,
-			DbService:      csp.Parameters.AquaCsp.Spec.DbService,
-			DiskSize:       csp.Parameters.AquaCsp.Spec.Common.DbDiskSize,
-			RunAsNonRoot:   csp.Parameters.AquaCsp.Spec.RunAsNonRoot,
-			AuditDB:        csp.Parameters.AquaCsp.Spec.AuditDB,
+			Infrastructure:    csp.infrastructureFor(v1alpha1.AquaUpgradePhaseDatabase),
+			Common:            csp.Parameters.AquaCsp.Spec.Common,
+			DbService:         csp.Parameters.AquaCsp.Spec.DbService,
+			DiskSize:          csp.Parameters.AquaCsp.Spec.Common.DbDiskSize,
+			RunAsNonRoot:      csp.Parameters.AquaCsp.Spec.RunAsNonRoot,
+			AuditDB:           csp.Parameters.AquaCsp.Spec.AuditDB,
+			MaintenanceWindow: csp.Parameters.AquaCsp.Spec.MaintenanceWindow,
 		},
 	}
 
@@ -82,14 +94,15 @@ func (csp *AquaCspHelper) newAquaGateway(cr *v1alpha1.AquaCsp) *v1alpha1.AquaGat
 			Annotations: annotations,
 		},
 		Spec: v1alpha1.AquaGatewaySpec{
-			Infrastructure: csp.Parameters.AquaCsp.Spec.Infrastructure,
-			Common:         csp.Parameters.AquaCsp.Spec.Common,
-			GatewayService: csp.Parameters.AquaCsp.Spec.GatewayService,
-			ExternalDb:     csp.Parameters.AquaCsp.Spec.ExternalDb,
-			RunAsNonRoot:   csp.Parameters.AquaCsp.Spec.RunAsNonRoot,
-			Envs:           csp.Parameters.AquaCsp.Spec.GatewayEnvs,
-			AuditDB:        csp.Parameters.AquaCsp.Spec.AuditDB,
-			Route:          csp.Parameters.AquaCsp.Spec.Route,
+			Infrastructure:    csp.infrastructureFor(v1alpha1.AquaUpgradePhaseGateway),
+			Common:            csp.Parameters.AquaCsp.Spec.Common,
+			GatewayService:    csp.Parameters.AquaCsp.Spec.GatewayService,
+			ExternalDb:        csp.Parameters.AquaCsp.Spec.ExternalDb,
+			RunAsNonRoot:      csp.Parameters.AquaCsp.Spec.RunAsNonRoot,
+			Envs:              csp.Parameters.AquaCsp.Spec.GatewayEnvs,
+			AuditDB:           csp.Parameters.AquaCsp.Spec.AuditDB,
+			Route:             csp.Parameters.AquaCsp.Spec.Route,
+			MaintenanceWindow: csp.Parameters.AquaCsp.Spec.MaintenanceWindow,
 		},
 	}
 
@@ -118,18 +131,19 @@ func (csp *AquaCspHelper) newAquaServer(cr *v1alpha1.AquaCsp) *v1alpha1.AquaServ
 			Annotations: annotations,
 		},
 		Spec: v1alpha1.AquaServerSpec{
-			Infrastructure: csp.Parameters.AquaCsp.Spec.Infrastructure,
-			Common:         csp.Parameters.AquaCsp.Spec.Common,
-			ServerService:  csp.Parameters.AquaCsp.Spec.ServerService,
-			ExternalDb:     csp.Parameters.AquaCsp.Spec.ExternalDb,
-			LicenseToken:   csp.Parameters.AquaCsp.Spec.LicenseToken,
-			AdminPassword:  csp.Parameters.AquaCsp.Spec.AdminPassword,
-			Enforcer:       csp.Parameters.AquaCsp.Spec.Enforcer,
-			RunAsNonRoot:   csp.Parameters.AquaCsp.Spec.RunAsNonRoot,
-			Envs:           csp.Parameters.AquaCsp.Spec.ServerEnvs,
-			ConfigMapData:  csp.Parameters.AquaCsp.Spec.ServerConfigMapData,
-			AuditDB:        csp.Parameters.AquaCsp.Spec.AuditDB,
-			Route:          csp.Parameters.AquaCsp.Spec.Route,
+			Infrastructure:    csp.infrastructureFor(v1alpha1.AquaUpgradePhaseServer),
+			Common:            csp.Parameters.AquaCsp.Spec.Common,
+			ServerService:     csp.Parameters.AquaCsp.Spec.ServerService,
+			ExternalDb:        csp.Parameters.AquaCsp.Spec.ExternalDb,
+			LicenseToken:      csp.Parameters.AquaCsp.Spec.LicenseToken,
+			AdminPassword:     csp.Parameters.AquaCsp.Spec.AdminPassword,
+			Enforcer:          csp.Parameters.AquaCsp.Spec.Enforcer,
+			RunAsNonRoot:      csp.Parameters.AquaCsp.Spec.RunAsNonRoot,
+			Envs:              csp.Parameters.AquaCsp.Spec.ServerEnvs,
+			ConfigMapData:     csp.Parameters.AquaCsp.Spec.ServerConfigMapData,
+			AuditDB:           csp.Parameters.AquaCsp.Spec.AuditDB,
+			Route:             csp.Parameters.AquaCsp.Spec.Route,
+			MaintenanceWindow: csp.Parameters.AquaCsp.Spec.MaintenanceWindow,
 		},
 	}
 
@@ -165,7 +179,7 @@ func (csp *AquaCspHelper) newAquaEnforcer(cr *v1alpha1.AquaCsp) *v1alpha1.AquaEn
 			Annotations: annotations,
 		},
 		Spec: v1alpha1.AquaEnforcerSpec{
-			Infrastructure: csp.Parameters.AquaCsp.Spec.Infrastructure,
+			Infrastructure: csp.infrastructureFor(v1alpha1.AquaUpgradePhaseEnforcer),
 			Common:         csp.Parameters.AquaCsp.Spec.Common,
 			Gateway: &v1alpha1.AquaGatewayInformation{
 				Host: fmt.Sprintf("%s-gateway", cr.Name),
@@ -182,6 +196,7 @@ func (csp *AquaCspHelper) newAquaEnforcer(cr *v1alpha1.AquaCsp) *v1alpha1.AquaEn
 			},
 			RunAsNonRoot:           csp.Parameters.AquaCsp.Spec.RunAsNonRoot,
 			EnforcerUpdateApproved: csp.Parameters.AquaCsp.Spec.EnforcerUpdateApproved,
+			MaintenanceWindow:      csp.Parameters.AquaCsp.Spec.MaintenanceWindow,
 		},
 	}
 
@@ -199,9 +214,15 @@ func (csp *AquaCspHelper) newAquaKubeEnforcer(cr *v1alpha1.AquaCsp) *v1alpha1.Aq
 		registry = cr.Spec.DeployKubeEnforcer.Registry
 	}
 
-	tag := consts.LatestVersion
-	if cr.Spec.Infrastructure.Version != "" {
-		tag = cr.Spec.Infrastructure.Version
+	version := componentVersion(cr, v1alpha1.AquaUpgradePhaseKubeEnforcer)
+	release, ok := versions.Lookup(version)
+	if !ok {
+		release = versions.Latest()
+	}
+
+	tag := release.KubeEnforcer
+	if !ok && version != "" {
+		tag = version
 	}
 	if cr.Spec.DeployKubeEnforcer.ImageTag != "" {
 		tag = cr.Spec.DeployKubeEnforcer.ImageTag
@@ -220,7 +241,7 @@ func (csp *AquaCspHelper) newAquaKubeEnforcer(cr *v1alpha1.AquaCsp) *v1alpha1.Aq
 	AquaStarboardDetails := v1alpha1.AquaStarboardDetails{
 		AllowAnyVersion: true,
 		Infrastructure: &v1alpha1.AquaInfrastructure{
-			Version:        consts.StarboardVersion,
+			Version:        release.Starboard,
 			ServiceAccount: "starboard-operator",
 		},
 		Config: v1alpha1.AquaStarboardConfig{
@@ -252,7 +273,10 @@ func (csp *AquaCspHelper) newAquaKubeEnforcer(cr *v1alpha1.AquaCsp) *v1alpha1.Aq
 				ClusterName:     "Default-cluster-name",
 				ImagePullSecret: cr.Spec.Common.ImagePullSecret,
 			},
-			Token:                  consts.DefaultKubeEnforcerToken,
+			TokenSecretRef: &v1alpha1.AquaSecret{
+				Name: fmt.Sprintf(consts.KubeEnforcerTokenSecretName, cr.Name),
+				Key:  consts.KubeEnforcerTokenSecretKey,
+			},
 			EnforcerUpdateApproved: cr.Spec.EnforcerUpdateApproved,
 			AllowAnyVersion:        cr.Spec.Common.AllowAnyVersion,
 			ImageData: &v1alpha1.AquaImage{
@@ -261,7 +285,10 @@ func (csp *AquaCspHelper) newAquaKubeEnforcer(cr *v1alpha1.AquaCsp) *v1alpha1.Aq
 				Tag:        tag,
 				PullPolicy: "Always",
 			},
-			DeployStarboard: &AquaStarboardDetails,
+			DeployStarboard:   &AquaStarboardDetails,
+			ImageVerification: cr.Spec.Common.ImageVerification,
+			DriftPolicy:       cr.Spec.Common.DriftPolicy,
+			MaintenanceWindow: cr.Spec.MaintenanceWindow,
 		},
 	}
 
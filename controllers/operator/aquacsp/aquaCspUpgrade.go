@@ -0,0 +1,228 @@
+package aquacsp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/controllers/common"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/versions"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+/*	----------------------------------------------------------------------------------------------------------------
+							Ordered Upgrade
+	----------------------------------------------------------------------------------------------------------------
+*/
+
+func upgradePhaseIndex(phase v1alpha1.AquaUpgradePhase) int {
+	for i, p := range v1alpha1.AquaUpgradePhases {
+		if p == phase {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// componentVersion returns the version of the components upgraded in the phase, during an upgrade the
+// components of the phases not reached yet keep the version upgraded from
+func componentVersion(cr *v1alpha1.AquaCsp, phase v1alpha1.AquaUpgradePhase) string {
+	upgrade := cr.Status.Upgrade
+	if upgrade == nil {
+		return cr.Spec.Infrastructure.Version
+	}
+
+	if upgradePhaseIndex(phase) <= upgradePhaseIndex(upgrade.Phase) {
+		return upgrade.ToVersion
+	}
+
+	return upgrade.FromVersion
+}
+
+// reconcileUpgrade starts an ordered upgrade when the resolved version differs from the rolled out one, and moves a
+// running upgrade to the next phase once the components of the current phase are healthy on the new version
+func (r *AquaCspReconciler) reconcileUpgrade(cr *v1alpha1.AquaCsp) error {
+	reqLogger := log.WithValues("CSP - Upgrade Phase", "Reconcile Upgrade")
+
+	original := cr.Status.DeepCopy()
+
+	if cr.Status.Upgrade == nil {
+		deployed := r.getDeployedVersion(cr)
+		common.ResolveInfrastructureVersion(cr.Spec.Infrastructure, deployed, cr.Spec.Common.AllowAnyVersion)
+		version := cr.Spec.Infrastructure.Version
+
+		if len(deployed) == 0 {
+			// New deployment, all the components are installed with the same version
+			done, _, err := r.isUpgraded(cr, version, v1alpha1.AquaUpgradePhases...)
+			if err != nil {
+				return err
+			}
+			if done {
+				common.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, version)
+			}
+		} else if deployed == version {
+			common.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, version)
+		} else {
+			reqLogger.Info("Starting an ordered upgrade", "From", deployed, "To", version)
+			cr.Status.Version = deployed
+			cr.Status.Upgrade = &v1alpha1.AquaCspUpgradeStatus{
+				FromVersion: deployed,
+				ToVersion:   version,
+				Phase:       v1alpha1.AquaUpgradePhasePending,
+			}
+		}
+	}
+
+	if upgrade := cr.Status.Upgrade; upgrade != nil {
+		upgrade.Paused = cr.Spec.Upgrade != nil && cr.Spec.Upgrade.Paused
+		if upgrade.Paused {
+			upgrade.Message = "Paused by spec.upgrade.paused"
+		}
+
+		for !upgrade.Paused {
+			done, message, err := r.isUpgraded(cr, upgrade.ToVersion, upgrade.Phase)
+			if err != nil {
+				return err
+			}
+			if !done {
+				upgrade.Message = message
+				break
+			}
+
+			next := upgradePhaseIndex(upgrade.Phase) + 1
+			if next == len(v1alpha1.AquaUpgradePhases) {
+				reqLogger.Info("Ordered upgrade completed", "From", upgrade.FromVersion, "To", upgrade.ToVersion)
+				common.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, upgrade.ToVersion)
+				cr.Spec.Infrastructure.Version = upgrade.ToVersion
+				cr.Status.Upgrade = nil
+				break
+			}
+
+			reqLogger.Info("Starting upgrade phase", "Phase", v1alpha1.AquaUpgradePhases[next], "To", upgrade.ToVersion)
+			upgrade.Phase = v1alpha1.AquaUpgradePhases[next]
+			upgrade.Message = ""
+		}
+	}
+
+	if !reflect.DeepEqual(original, &cr.Status) {
+		return common.UpdateStatus(r.Client, cr)
+	}
+
+	return nil
+}
+
+// getDeployedVersion returns the version the components were upgraded to, a CSP deployed before the version was
+// recorded gets the version of its server
+func (r *AquaCspReconciler) getDeployedVersion(cr *v1alpha1.AquaCsp) string {
+	if len(cr.Status.Version) != 0 {
+		return cr.Status.Version
+	}
+
+	server := &v1alpha1.AquaServer{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, server)
+	if err != nil {
+		return ""
+	}
+
+	return server.Status.Version
+}
+
+// isUpgraded checks the components of the phases run the version and are healthy, the message tells what is waited for
+func (r *AquaCspReconciler) isUpgraded(cr *v1alpha1.AquaCsp, version string, phases ...v1alpha1.AquaUpgradePhase) (bool, string, error) {
+	for _, phase := range phases {
+		var obj interface{}
+		kind := ""
+
+		switch phase {
+		case v1alpha1.AquaUpgradePhaseDatabase:
+			if cr.Spec.DbService == nil {
+				continue
+			}
+			obj, kind = &v1alpha1.AquaDatabase{}, "AquaDatabase"
+		case v1alpha1.AquaUpgradePhaseServer:
+			obj, kind = &v1alpha1.AquaServer{}, "AquaServer"
+		case v1alpha1.AquaUpgradePhaseGateway:
+			obj, kind = &v1alpha1.AquaGateway{}, "AquaGateway"
+		case v1alpha1.AquaUpgradePhaseKubeEnforcer:
+			if cr.Spec.DeployKubeEnforcer == nil {
+				continue
+			}
+			obj, kind = &v1alpha1.AquaKubeEnforcer{}, "AquaKubeEnforcer"
+		case v1alpha1.AquaUpgradePhaseEnforcer:
+			if cr.Spec.Enforcer == nil {
+				continue
+			}
+			obj, kind = &v1alpha1.AquaEnforcer{}, "AquaEnforcer"
+		default:
+			continue
+		}
+
+		rolledOut, state, err := r.getComponentVersion(cr, obj)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return false, fmt.Sprintf("Waiting for %s %s to be created", kind, cr.Name), nil
+			}
+			return false, "", err
+		}
+
+		if rolledOut != version || !reflect.DeepEqual(v1alpha1.AquaDeploymentStateRunning, state) {
+			message := fmt.Sprintf("Waiting for %s %s to roll out %s, state %s", kind, cr.Name, version, state)
+			if phase == v1alpha1.AquaUpgradePhaseDatabase {
+				message = fmt.Sprintf("Waiting for %s %s to back up the database and roll out %s, state %s", kind, cr.Name, version, state)
+			}
+			if reflect.DeepEqual(v1alpha1.AquaEnforcerUpdatePendingApproval, state) {
+				message = fmt.Sprintf("%s %s update is pending approval, set spec.updateEnforcer", kind, cr.Name)
+			}
+			return false, message, nil
+		}
+
+		if phase == v1alpha1.AquaUpgradePhaseServer {
+			apiVersion, err := r.getServerApiVersion(cr)
+			if err != nil {
+				return false, fmt.Sprintf("Waiting for the server API to report version %s: %v", version, err), nil
+			}
+			if !versions.IsReleaseOf(apiVersion, version) {
+				return false, fmt.Sprintf("Waiting for the server API to report version %s, it reports %s", version, apiVersion), nil
+			}
+		}
+	}
+
+	return true, "", nil
+}
+
+func (r *AquaCspReconciler) getComponentVersion(cr *v1alpha1.AquaCsp, obj interface{}) (string, v1alpha1.AquaDeploymentState, error) {
+	key := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}
+
+	switch component := obj.(type) {
+	case *v1alpha1.AquaDatabase:
+		err := r.Client.Get(context.TODO(), key, component)
+		return component.Status.Version, component.Status.State, err
+	case *v1alpha1.AquaServer:
+		err := r.Client.Get(context.TODO(), key, component)
+		return component.Status.Version, component.Status.State, err
+	case *v1alpha1.AquaGateway:
+		err := r.Client.Get(context.TODO(), key, component)
+		return component.Status.Version, component.Status.State, err
+	case *v1alpha1.AquaKubeEnforcer:
+		err := r.Client.Get(context.TODO(), key, component)
+		return component.Status.Version, component.Status.State, err
+	case *v1alpha1.AquaEnforcer:
+		err := r.Client.Get(context.TODO(), key, component)
+		return component.Status.Version, component.Status.State, err
+	}
+
+	return "", "", fmt.Errorf("unknown component %T", obj)
+}
+
+// getServerApiVersion returns the version reported by the API of the server, see common.NewAquaServerApiClient
+func (r *AquaCspReconciler) getServerApiVersion(cr *v1alpha1.AquaCsp) (string, error) {
+	api, err := common.NewAquaServerApiClient(r.Client, cr.Namespace, cr.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return api.Version()
+}
@@ -0,0 +1,38 @@
+package aquacsp
+
+import (
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ordered upgrade", func() {
+	It("starts with the pending phase", func() {
+		Expect(upgradePhaseIndex(v1alpha1.AquaUpgradePhasePending)).To(Equal(0))
+		Expect(upgradePhaseIndex(v1alpha1.AquaUpgradePhaseDatabase)).To(Equal(1))
+		Expect(upgradePhaseIndex(v1alpha1.AquaUpgradePhaseEnforcer)).To(Equal(len(v1alpha1.AquaUpgradePhases) - 1))
+	})
+
+	DescribeTable("componentVersion",
+		func(upgradePhase, phase v1alpha1.AquaUpgradePhase, version string) {
+			cr := &v1alpha1.AquaCsp{
+				Spec: v1alpha1.AquaCspSpec{Infrastructure: &v1alpha1.AquaInfrastructure{Version: "2022.4"}},
+				Status: v1alpha1.AquaCspStatus{Upgrade: &v1alpha1.AquaCspUpgradeStatus{
+					FromVersion: "6.5",
+					ToVersion:   "2022.4",
+					Phase:       upgradePhase,
+				}},
+			}
+			Expect(componentVersion(cr, phase)).To(Equal(version))
+		},
+		Entry("a pending upgrade keeps the database", v1alpha1.AquaUpgradePhasePending, v1alpha1.AquaUpgradePhaseDatabase, "6.5"),
+		Entry("the phase of the upgrade", v1alpha1.AquaUpgradePhaseServer, v1alpha1.AquaUpgradePhaseServer, "2022.4"),
+		Entry("a phase already upgraded", v1alpha1.AquaUpgradePhaseServer, v1alpha1.AquaUpgradePhaseDatabase, "2022.4"),
+		Entry("a phase not reached yet", v1alpha1.AquaUpgradePhaseServer, v1alpha1.AquaUpgradePhaseEnforcer, "6.5"),
+	)
+
+	It("uses the spec version without an upgrade", func() {
+		cr := &v1alpha1.AquaCsp{Spec: v1alpha1.AquaCspSpec{Infrastructure: &v1alpha1.AquaInfrastructure{Version: "2022.4"}}}
+		Expect(componentVersion(cr, v1alpha1.AquaUpgradePhaseServer)).To(Equal("2022.4"))
+	})
+})
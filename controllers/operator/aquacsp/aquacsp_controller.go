@@ -92,16 +92,44 @@ func (r *AquaCspReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcile.Result{}, err
 	}
 
+	if common.ReconcilePaused(instance, instance.Spec.Paused) {
+		return reconcile.Result{}, nil
+	}
+
+	scrubbed, err := r.scrubPlaintextSecrets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if scrubbed {
+		reqLogger.Info("Moved plaintext credentials from the AquaCsp spec into secrets")
+		err = r.Client.Update(context.Background(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	instance = r.updateCspObject(instance)
 
+	err = r.reconcileUpgrade(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	if instance.Spec.Infrastructure.Requirements {
 		reqLogger.Info("Start Setup Requirement For Aqua CSP...")
 
 		if instance.Spec.RegistryData != nil {
 			marketplace := extra.IsMarketPlace()
 			if !marketplace {
-				reqLogger.Info("Start Setup Aqua Image Secret Secret")
-				_, err = r.CreateImagePullSecret(instance)
+				store := common.GetSecretStore(instance.Spec.Common)
+				if store != nil && len(store.Keys.RegistryUsername) > 0 && len(store.Keys.RegistryPassword) > 0 {
+					reqLogger.Info("Start Syncing Aqua Image Pull Secret From Secret Store")
+					err = r.SyncImagePullSecret(instance)
+				} else {
+					reqLogger.Info("Start Setup Aqua Image Secret Secret")
+					_, err = r.CreateImagePullSecret(instance)
+				}
 				if err != nil {
 					return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, err
 				}
@@ -146,12 +174,13 @@ func (r *AquaCspReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 		dbstatus, _ = r.WaitForDatabase(instance)
 	} else if instance.Spec.ExternalDb != nil {
-		if len(instance.Spec.ExternalDb.Password) != 0 {
-			_, err = r.CreateDbPasswordSecret(
-				instance,
-				fmt.Sprintf(consts.ScalockDbPasswordSecretName, instance.Name),
-				consts.ScalockDbPasswordSecretKey,
-				instance.Spec.ExternalDb.Password)
+		store := common.GetSecretStore(instance.Spec.Common)
+		if store != nil && len(store.Keys.DbPassword) > 0 {
+			reqLogger.Info("Start Syncing Database Password Secret From Secret Store")
+			err = r.SyncDbPasswordSecret(instance,
+				instance.Spec.Common.DatabaseSecret.Name,
+				instance.Spec.Common.DatabaseSecret.Key,
+				store.Keys.DbPassword)
 			if err != nil {
 				return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, err
 			}
@@ -182,14 +211,16 @@ func (r *AquaCspReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 			instance.Spec.AuditDB = common.UpdateAquaAuditDB(instance.Spec.AuditDB, instance.Name)
 			exist := secrets.CheckIfSecretExists(r.Client, instance.Spec.AuditDB.AuditDBSecret.Name, instance.Namespace)
-			if !exist {
-				_, err = r.CreateDbPasswordSecret(instance,
+			if store != nil && len(store.Keys.AuditDbPassword) > 0 {
+				err = r.SyncDbPasswordSecret(instance,
 					instance.Spec.AuditDB.AuditDBSecret.Name,
 					instance.Spec.AuditDB.AuditDBSecret.Key,
-					instance.Spec.AuditDB.Data.Password)
+					store.Keys.AuditDbPassword)
 				if err != nil {
 					return reconcile.Result{}, err
 				}
+			} else if !exist {
+				reqLogger.Error(syserrors.New("For using split external db you must define the audit db password, or define the secret name and key in auditDB section!"), "Missing external audit database password definition")
 			}
 		}
 	}
@@ -238,12 +269,12 @@ func (r *AquaCspReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				!reflect.DeepEqual(v1alpha1.AquaEnforcerWaiting, currentStatus) &&
 				!reflect.DeepEqual(v1alpha1.AquaDeploymentStateRunning, currentStatus) {
 				instance.Status.State = v1alpha1.AquaDeploymentStateRunning
-				_ = r.Client.Status().Update(context.Background(), instance)
+				_ = common.UpdateStatus(r.Client, instance)
 			}
 		} else {
 			if !reflect.DeepEqual(serverGatewayStatus, currentStatus) {
 				instance.Status.State = serverGatewayStatus
-				_ = r.Client.Status().Update(context.Background(), instance)
+				_ = common.UpdateStatus(r.Client, instance)
 			}
 			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
 		}
@@ -251,7 +282,7 @@ func (r *AquaCspReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		reqLogger.Info("CSP Deployment: Waiting internal for database to start")
 		if !reflect.DeepEqual(v1alpha1.AquaDeploymentStateWaitingDB, instance.Status.State) {
 			instance.Status.State = v1alpha1.AquaDeploymentStateWaitingDB
-			_ = r.Client.Status().Update(context.Background(), instance)
+			_ = common.UpdateStatus(r.Client, instance)
 		}
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
 	}
@@ -279,11 +310,15 @@ func (r *AquaCspReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		crStatus := r.WaitForEnforcersReady(instance, waitForEnforcer, waitForKubeEnforcer)
 		if !reflect.DeepEqual(instance.Status.State, crStatus) {
 			instance.Status.State = crStatus
-			_ = r.Client.Status().Update(context.Background(), instance)
+			_ = common.UpdateStatus(r.Client, instance)
 		}
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
 	}
 
+	if store := common.GetSecretStore(instance.Spec.Common); store != nil {
+		return ctrl.Result{RequeueAfter: common.NewAquaSecretStoreHelper(store, instance.Namespace, r.Client, r.Scheme, instance).RefreshInterval()}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -313,6 +348,53 @@ func (r *AquaCspReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	----------------------------------------------------------------------------------------------------------------
 */
 
+// scrubPlaintextSecrets moves the plaintext credentials of the spec into owned secrets and sets the matching refs
+func (r *AquaCspReconciler) scrubPlaintextSecrets(cr *v1alpha1.AquaCsp) (bool, error) {
+	if cr.Spec.Common == nil {
+		cr.Spec.Common = &v1alpha1.AquaCommon{}
+	}
+
+	adminMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.AdminPassword,
+		&cr.Spec.Common.AdminPassword,
+		fmt.Sprintf(consts.AdminPasswordSecretName, cr.Name),
+		consts.AdminPasswordSecretKey,
+		"Secret for aqua admin password")
+	if err != nil {
+		return false, err
+	}
+
+	licenseMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.LicenseToken,
+		&cr.Spec.Common.AquaLicense,
+		fmt.Sprintf(consts.LicenseTokenSecretName, cr.Name),
+		consts.LicenseTokenSecretKey,
+		"Secret for aqua license token")
+	if err != nil {
+		return false, err
+	}
+
+	registryMoved := false
+	if cr.Spec.RegistryData != nil {
+		registryMoved, err = common.MoveToSecret(r.Client, r.Scheme, cr,
+			&cr.Spec.RegistryData.Password,
+			&cr.Spec.RegistryData.PasswordSecretRef,
+			fmt.Sprintf(consts.RegistryPasswordSecretName, cr.Name),
+			consts.RegistryPasswordSecretKey,
+			"Secret for aqua registry password")
+		if err != nil {
+			return false, err
+		}
+	}
+
+	dbMoved, err := common.MoveDatabasePasswords(r.Client, r.Scheme, cr, cr.Spec.Common, cr.Spec.ExternalDb, cr.Spec.AuditDB)
+	if err != nil {
+		return false, err
+	}
+
+	return adminMoved || licenseMoved || registryMoved || dbMoved, nil
+}
+
 func (r *AquaCspReconciler) updateCspObject(cr *v1alpha1.AquaCsp) *v1alpha1.AquaCsp {
 	admin := false
 	license := false
@@ -325,6 +407,11 @@ func (r *AquaCspReconciler) updateCspObject(cr *v1alpha1.AquaCsp) *v1alpha1.Aqua
 		license = true
 	}
 
+	if store := common.GetSecretStore(cr.Spec.Common); store != nil {
+		admin = admin || len(store.Keys.AdminPassword) != 0
+		license = license || len(store.Keys.LicenseToken) != 0
+	}
+
 	registry := consts.Registry
 	if cr.Spec.RegistryData != nil {
 		if len(cr.Spec.RegistryData.URL) > 0 {
@@ -418,6 +505,44 @@ func (r *AquaCspReconciler) InstallAquaDatabase(cr *v1alpha1.AquaCsp) (reconcile
 			// Spec updated - return and requeue
 			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
 		}
+
+		storageClass := aquadb.Spec.Common.StorageClass
+		if found.Spec.DiskSize != aquadb.Spec.DiskSize ||
+			(found.Spec.Common != nil && found.Spec.Common.StorageClass != storageClass) {
+			found.Spec.DiskSize = aquadb.Spec.DiskSize
+			if found.Spec.Common != nil {
+				found.Spec.Common.StorageClass = storageClass
+			}
+			err = r.Client.Update(context.Background(), found)
+			if err != nil {
+				reqLogger.Error(err, "Aqua CSP: Failed to update aqua database storage.", "AquaDatabase.Namespace", found.Namespace, "AquaDatabase.Name", found.Name)
+				return reconcile.Result{}, err
+			}
+			// Spec updated - return and requeue
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
+		}
+
+		if !reflect.DeepEqual(found.Spec.MaintenanceWindow, aquadb.Spec.MaintenanceWindow) {
+			found.Spec.MaintenanceWindow = aquadb.Spec.MaintenanceWindow
+			err = r.Client.Update(context.Background(), found)
+			if err != nil {
+				reqLogger.Error(err, "Aqua CSP: Failed to update aqua database maintenance window.", "AquaDatabase.Namespace", found.Namespace, "AquaDatabase.Name", found.Name)
+				return reconcile.Result{}, err
+			}
+			// Spec updated - return and requeue
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
+		}
+
+		if found.Spec.Infrastructure != nil && found.Spec.Infrastructure.Version != aquadb.Spec.Infrastructure.Version {
+			found.Spec.Infrastructure.Version = aquadb.Spec.Infrastructure.Version
+			err = r.Client.Update(context.Background(), found)
+			if err != nil {
+				reqLogger.Error(err, "Aqua CSP: Failed to update aqua database version.", "AquaDatabase.Namespace", found.Namespace, "AquaDatabase.Name", found.Name)
+				return reconcile.Result{}, err
+			}
+			// Spec updated - return and requeue
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
+		}
 	}
 
 	// AquaDatabase already exists - don't requeue
@@ -466,6 +591,8 @@ func (r *AquaCspReconciler) InstallAquaGateway(cr *v1alpha1.AquaCsp) (reconcile.
 			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
 		}
 
+		// a component is paused on its own CR
+		aquagw.Spec.Paused = found.Spec.Paused
 		update := !reflect.DeepEqual(aquagw.Spec, found.Spec)
 
 		reqLogger.Info("Checking for AquaGateway Upgrade", "aquagw", aquagw.Spec, "found", found.Spec, "update bool", update)
@@ -527,6 +654,8 @@ func (r *AquaCspReconciler) InstallAquaServer(cr *v1alpha1.AquaCsp) (reconcile.R
 			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
 		}
 
+		// a component is paused on its own CR
+		aquasr.Spec.Paused = found.Spec.Paused
 		update := !reflect.DeepEqual(aquasr.Spec, found.Spec)
 
 		reqLogger.Info("Checking for AquaServer Upgrade", "aquasr", aquasr.Spec, "found", found.Spec, "update bool", update)
@@ -624,6 +753,8 @@ func (r *AquaCspReconciler) InstallAquaEnforcer(cr *v1alpha1.AquaCsp) (reconcile
 	// AquaEnforcer already exists - don't requeue
 
 	if found != nil {
+		// a component is paused on its own CR
+		enforcer.Spec.Paused = found.Spec.Paused
 		update := !reflect.DeepEqual(enforcer.Spec, found.Spec)
 
 		reqLogger.Info("Checking for AquaEnforcer Upgrade", "enforcer", enforcer.Spec, "found", found.Spec, "update bool", update)
@@ -647,6 +778,11 @@ func (r *AquaCspReconciler) InstallAquaKubeEnforcer(cr *v1alpha1.AquaCsp) (recon
 	reqLogger := log.WithValues("CSP - AquaKubeEnforcer Phase", "Install Aqua Enforcer")
 	reqLogger.Info("Start installing AquaKubeEnforcer")
 
+	_, err := r.CreateKubeEnforcerTokenSecret(cr)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Define a new AquaEnforcer object
 	cspHelper := newAquaCspHelper(cr)
 	enforcer := cspHelper.newAquaKubeEnforcer(cr)
@@ -658,7 +794,7 @@ func (r *AquaCspReconciler) InstallAquaKubeEnforcer(cr *v1alpha1.AquaCsp) (recon
 
 	// Check if this AquaKubeEnforcer already exists
 	found := &v1alpha1.AquaKubeEnforcer{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: enforcer.Name, Namespace: enforcer.Namespace}, found)
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: enforcer.Name, Namespace: enforcer.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Creating a New Aqua KubeEnforcer", "AquaKubeEnforcer.Namespace", enforcer.Namespace, "AquaKubeEnforcer.Name", enforcer.Name)
 		err = r.Client.Create(context.TODO(), enforcer)
@@ -673,6 +809,8 @@ func (r *AquaCspReconciler) InstallAquaKubeEnforcer(cr *v1alpha1.AquaCsp) (recon
 	// AquaEnforcer already exists - don't requeue
 
 	if found != nil {
+		// a component is paused on its own CR
+		enforcer.Spec.Paused = found.Spec.Paused
 		update := !reflect.DeepEqual(enforcer.Spec, found.Spec)
 
 		reqLogger.Info("Checking for AquaKubeEnforcer Upgrade", "kube-enforcer", enforcer.Spec, "found", found.Spec, "update bool", update)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/controllers/common"
 	"github.com/aquasecurity/aqua-operator/controllers/ocp"
 	"github.com/aquasecurity/aqua-operator/pkg/consts"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
@@ -30,12 +31,17 @@ func (r *AquaCspReconciler) CreateImagePullSecret(cr *operatorv1alpha1.AquaCsp)
 	if cr.Spec.Common != nil && cr.Spec.Common.ImagePullSecret != "" {
 		secretName = cr.Spec.Common.ImagePullSecret
 	}
+	registry, err := secrets.GetRegistryData(r.Client, cr.Namespace, *cr.Spec.RegistryData)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Define a new secret object
 	secret := secrets.CreatePullImageSecret(cr.Name,
 		cr.Namespace,
 		fmt.Sprintf("%s-requirments", cr.Name),
 		secretName,
-		*cr.Spec.RegistryData)
+		registry)
 
 	// Set AquaCspKind instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
@@ -44,7 +50,7 @@ func (r *AquaCspReconciler) CreateImagePullSecret(cr *operatorv1alpha1.AquaCsp)
 
 	// Check if this secret already exists
 	found := &corev1.Secret{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Creating a New Aqua Image Pull Secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
 		err = r.Client.Create(context.TODO(), secret)
@@ -62,6 +68,41 @@ func (r *AquaCspReconciler) CreateImagePullSecret(cr *operatorv1alpha1.AquaCsp)
 	return reconcile.Result{Requeue: true}, nil
 }
 
+// SyncImagePullSecret materializes the registry credentials from the secret store into the image pull secret
+func (r *AquaCspReconciler) SyncImagePullSecret(cr *operatorv1alpha1.AquaCsp) error {
+	store := cr.Spec.Common.SecretStore
+	secretName := fmt.Sprintf(consts.PullImageSecretName, cr.Name)
+	if cr.Spec.Common.ImagePullSecret != "" {
+		secretName = cr.Spec.Common.ImagePullSecret
+	}
+
+	storeHelper := common.NewAquaSecretStoreHelper(store, cr.Namespace, r.Client, r.Scheme, cr)
+	_, err := storeHelper.SyncSecret(secretName, func(values map[string]string) *corev1.Secret {
+		registry := *cr.Spec.RegistryData
+		registry.Username = values[store.Keys.RegistryUsername]
+		registry.Password = values[store.Keys.RegistryPassword]
+
+		return secrets.CreatePullImageSecret(cr.Name,
+			cr.Namespace,
+			fmt.Sprintf("%s-requirments", cr.Name),
+			secretName,
+			registry)
+	}, store.Keys.RegistryUsername, store.Keys.RegistryPassword)
+
+	return err
+}
+
+// SyncDbPasswordSecret materializes a database password from the secret store
+func (r *AquaCspReconciler) SyncDbPasswordSecret(cr *operatorv1alpha1.AquaCsp, name, key, storeKey string) error {
+	storeHelper := common.NewAquaSecretStoreHelper(cr.Spec.Common.SecretStore, cr.Namespace, r.Client, r.Scheme, cr)
+	_, err := storeHelper.SyncSecretKeys(name,
+		fmt.Sprintf("%s-requirments", cr.Name),
+		"Secret for aqua database password",
+		map[string]string{key: storeKey})
+
+	return err
+}
+
 func (r *AquaCspReconciler) CreateDbPasswordSecret(cr *operatorv1alpha1.AquaCsp, name, key, password string) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Csp Requirments Phase", "Create Db Password Secret")
 	reqLogger.Info("Start creating aqua db password secret")
@@ -100,6 +141,44 @@ func (r *AquaCspReconciler) CreateDbPasswordSecret(cr *operatorv1alpha1.AquaCsp,
 	return reconcile.Result{Requeue: true}, nil
 }
 
+func (r *AquaCspReconciler) CreateKubeEnforcerTokenSecret(cr *operatorv1alpha1.AquaCsp) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Csp Requirments Phase", "Create KubeEnforcer Token Secret")
+	reqLogger.Info("Start creating aqua kube-enforcer token secret")
+
+	// Define a new secret object
+	secret := secrets.CreateSecret(cr.Name,
+		cr.Namespace,
+		fmt.Sprintf("%s-requirments", cr.Name),
+		"Secret for aqua kube-enforcer token",
+		fmt.Sprintf(consts.KubeEnforcerTokenSecretName, cr.Name),
+		consts.KubeEnforcerTokenSecretKey,
+		consts.DefaultKubeEnforcerToken)
+
+	// Set AquaCspKind instance as the owner and controller
+	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Check if this secret already exists
+	found := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
+	if err != nil && errors.IsNotFound(err) {
+		reqLogger.Info("Creating a New Aqua KubeEnforcer Token Secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+		err = r.Client.Create(context.TODO(), secret)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		return reconcile.Result{}, nil
+	} else if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Secret already exists - don't requeue
+	reqLogger.Info("Skip reconcile: Aqua KubeEnforcer Token Secret Already Exists", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
+	return reconcile.Result{}, nil
+}
+
 func (r *AquaCspReconciler) CreateAquaServiceAccount(cr *operatorv1alpha1.AquaCsp) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Csp Requirments Phase", "Create Aqua Service Account")
 	reqLogger.Info("Start creating aqua service account")
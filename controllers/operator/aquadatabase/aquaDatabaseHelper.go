@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -245,3 +246,238 @@ func (db *AquaDatabaseHelper) newService(cr *v1alpha1.AquaDatabase, name, app st
 
 	return service
 }
+
+func (db *AquaDatabaseHelper) newStorageMigrationJob(cr *v1alpha1.AquaDatabase, name, sourcePvc, targetPvc string) *batchv1.Job {
+	pullPolicy, registry, repository, tag := extra.GetImageData("database", cr.Spec.Infrastructure.Version, cr.Spec.DbService.ImageData, cr.Spec.Common.AllowAnyVersion)
+
+	image := os.Getenv("RELATED_IMAGE_DATABASE")
+	if image == "" {
+		image = fmt.Sprintf("%s/%s:%s", registry, repository, tag)
+	}
+
+	labels := map[string]string{
+		"app":                name,
+		"deployedby":         "aqua-operator",
+		"aquasecoperator_cr": cr.Name,
+		"aqua.component":     "database",
+	}
+	annotations := map[string]string{
+		"description": "Copy the aqua database files to a new persistent volume claim",
+	}
+
+	backoffLimit := int32(2)
+	runAsUser := int64(0)
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   cr.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: cr.Spec.Infrastructure.ServiceAccount,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:            "storage-migration",
+							Image:           image,
+							ImagePullPolicy: corev1.PullPolicy(pullPolicy),
+							Command: []string{
+								"sh",
+								"-c",
+								consts.DBStorageMigrationCommand,
+							},
+							SecurityContext: &corev1.SecurityContext{
+								RunAsUser: &runAsUser,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "source",
+									MountPath: "/source",
+									ReadOnly:  true,
+								},
+								{
+									Name:      "target",
+									MountPath: "/target",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "source",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: sourcePvc,
+								},
+							},
+						},
+						{
+							Name: "target",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: targetPvc,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if cr.Spec.DbService.NodeSelector != nil {
+		if len(cr.Spec.DbService.NodeSelector) > 0 {
+			job.Spec.Template.Spec.NodeSelector = cr.Spec.DbService.NodeSelector
+		}
+	}
+
+	if cr.Spec.DbService.Tolerations != nil {
+		if len(cr.Spec.DbService.Tolerations) > 0 {
+			job.Spec.Template.Spec.Tolerations = cr.Spec.DbService.Tolerations
+		}
+	}
+
+	if len(cr.Spec.Common.ImagePullSecret) != 0 {
+		job.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{
+			corev1.LocalObjectReference{
+				Name: cr.Spec.Common.ImagePullSecret,
+			},
+		}
+	}
+
+	return job
+}
+
+// newBackupJob dumps the database with the image it currently runs, so the dump matches the running server version
+func (db *AquaDatabaseHelper) newBackupJob(cr *v1alpha1.AquaDatabase, name, pvcName, host string, dbSecret *v1alpha1.AquaSecret, current corev1.PodSpec) *batchv1.Job {
+	return db.newDatabaseJob(cr, name, pvcName, host, dbSecret, current,
+		consts.DBBackupCommand,
+		"Back up the aqua database before upgrading it",
+		corev1.EnvVar{Name: "BACKUP_NAME", Value: name})
+}
+
+// newRestoreJob loads a dump of the backup claim into the database
+func (db *AquaDatabaseHelper) newRestoreJob(cr *v1alpha1.AquaDatabase, name, pvcName, host string, dbSecret *v1alpha1.AquaSecret, current corev1.PodSpec, file string) *batchv1.Job {
+	return db.newDatabaseJob(cr, name, pvcName, host, dbSecret, current,
+		consts.DBRestoreCommand,
+		"Restore the aqua database from a backup",
+		corev1.EnvVar{Name: "BACKUP_FILE", Value: file})
+}
+
+// newDatabaseJob runs the command with the database image, connected to the database and with the backup claim mounted on /backup
+func (db *AquaDatabaseHelper) newDatabaseJob(cr *v1alpha1.AquaDatabase, name, pvcName, host string, dbSecret *v1alpha1.AquaSecret, current corev1.PodSpec, command, description string, env corev1.EnvVar) *batchv1.Job {
+	labels := map[string]string{
+		"app":                name,
+		"deployedby":         "aqua-operator",
+		"aquasecoperator_cr": cr.Name,
+		"aqua.component":     "database",
+	}
+	annotations := map[string]string{
+		"description": description,
+	}
+
+	image := ""
+	pullPolicy := corev1.PullIfNotPresent
+	var securityContext *corev1.SecurityContext
+	if len(current.Containers) > 0 {
+		image = current.Containers[0].Image
+		pullPolicy = current.Containers[0].ImagePullPolicy
+		securityContext = current.Containers[0].SecurityContext
+	}
+
+	backoffLimit := int32(2)
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   cr.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: current.ServiceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					SecurityContext:    current.SecurityContext,
+					ImagePullSecrets:   current.ImagePullSecrets,
+					NodeSelector:       current.NodeSelector,
+					Tolerations:        current.Tolerations,
+					Containers: []corev1.Container{
+						{
+							Name:            "backup",
+							Image:           image,
+							ImagePullPolicy: pullPolicy,
+							SecurityContext: securityContext,
+							Command: []string{
+								"sh",
+								"-c",
+								command,
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "PGHOST",
+									Value: host,
+								},
+								{
+									Name:  "PGUSER",
+									Value: "postgres",
+								},
+								{
+									Name: "PGPASSWORD",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: dbSecret.Name,
+											},
+											Key: dbSecret.Key,
+										},
+									},
+								},
+								env,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "backup",
+									MountPath: "/backup",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "backup",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: pvcName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return job
+}
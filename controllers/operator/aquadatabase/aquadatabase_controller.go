@@ -29,8 +29,12 @@ import (
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/serviceaccounts"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"reflect"
@@ -63,6 +67,8 @@ type AquaDatabaseReconciler struct {
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -90,13 +96,32 @@ func (r *AquaDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
+
+	if common.ReconcilePaused(instance, instance.Spec.Paused) {
+		return reconcile.Result{}, nil
+	}
+	scrubbed, err := r.scrubPlaintextSecrets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if scrubbed {
+		reqLogger.Info("Moved plaintext credentials from the AquaDatabase spec into secrets")
+		err = r.Client.Update(context.Background(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	createDatabaseSecret := instance.Spec.Common == nil || instance.Spec.Common.DatabaseSecret == nil
 
 	instance = r.updateDatabaseObject(instance)
 
+	common.ResolveInfrastructureVersion(instance.Spec.Infrastructure, instance.Status.Version, instance.Spec.Common.AllowAnyVersion)
+
 	if !reflect.DeepEqual(v1alpha1.AquaDeploymentStateRunning, instance.Status.State) {
 		instance.Status.State = v1alpha1.AquaDeploymentStatePending
-		_ = r.Client.Status().Update(context.Background(), instance)
+		_ = common.UpdateStatus(r.Client, instance)
 	}
 
 	if len(instance.Spec.Infrastructure.ServiceAccount) > 0 &&
@@ -129,7 +154,8 @@ func (r *AquaDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			}
 		}
 
-		pvcName := fmt.Sprintf(consts.DbPvcName, instance.Name)
+		dbDeployName := fmt.Sprintf(consts.DbDeployName, instance.Name)
+		pvcName := r.getDatabasePvcName(instance, dbDeployName, fmt.Sprintf(consts.DbPvcName, instance.Name))
 		dbAppName := fmt.Sprintf("%s-db", instance.Name)
 		reqLogger.Info("Start Creating aqua db pvc")
 		_, err = r.InstallDatabasePvc(
@@ -139,13 +165,25 @@ func (r *AquaDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			return reconcile.Result{}, err
 		}
 
+		reqLogger.Info("Start Checking aqua db storage")
+		// a finished migration switches the deployment to the claim of the new storage class
+		pvcName, migrating, err := r.ReconcileDatabaseStorage(instance, dbDeployName, pvcName)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if migrating {
+			reqLogger.Info("Aqua db storage migration in progress, waiting for it to finish")
+			return reconcile.Result{RequeueAfter: consts.DbStorageMigrationRequeue}, nil
+		}
+
 		reqLogger.Info("Start Creating aqua db deployment")
 		_, err = r.InstallDatabaseDeployment(
 			instance,
 			instance.Spec.Common.DatabaseSecret,
-			fmt.Sprintf(consts.DbDeployName, instance.Name),
+			dbDeployName,
 			pvcName,
-			dbAppName)
+			dbAppName,
+			fmt.Sprintf(consts.DbServiceName, instance.Name))
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -171,13 +209,14 @@ func (r *AquaDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				_, err = r.CreateDbPasswordSecret(instance,
 					instance.Spec.AuditDB.AuditDBSecret.Name,
 					instance.Spec.AuditDB.AuditDBSecret.Key,
-					instance.Spec.AuditDB.Data.Password)
+					extra.CreateRundomPassword())
 				if err != nil {
 					return reconcile.Result{}, err
 				}
 			}
 
-			auditPvcName := fmt.Sprintf(consts.AuditDbPvcName, instance.Name)
+			auditDeployName := fmt.Sprintf(consts.AuditDbDeployName, instance.Name)
+			auditPvcName := r.getDatabasePvcName(instance, auditDeployName, fmt.Sprintf(consts.AuditDbPvcName, instance.Name))
 			auditDBAppName := fmt.Sprintf("%s-audit-db", instance.Name)
 			reqLogger.Info("Start Creating aqua audit-db pvc")
 			_, err = r.InstallDatabasePvc(
@@ -187,6 +226,16 @@ func (r *AquaDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				return reconcile.Result{}, err
 			}
 
+			reqLogger.Info("Start Checking aqua audit-db storage")
+			auditPvcName, migrating, err = r.ReconcileDatabaseStorage(instance, auditDeployName, auditPvcName)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if migrating {
+				reqLogger.Info("Aqua audit-db storage migration in progress, waiting for it to finish")
+				return reconcile.Result{RequeueAfter: consts.DbStorageMigrationRequeue}, nil
+			}
+
 			reqLogger.Info("Start Creating aqua audit-db service")
 			_, err = r.InstallDatabaseService(
 				instance,
@@ -201,9 +250,10 @@ func (r *AquaDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			_, err = r.InstallDatabaseDeployment(
 				instance,
 				instance.Spec.AuditDB.AuditDBSecret,
-				fmt.Sprintf(consts.AuditDbDeployName, instance.Name),
+				auditDeployName,
 				auditPvcName,
-				auditDBAppName)
+				auditDBAppName,
+				instance.Spec.AuditDB.Data.Host)
 			if err != nil {
 				return reconcile.Result{}, err
 			}
@@ -215,10 +265,10 @@ func (r *AquaDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	if !reflect.DeepEqual(v1alpha1.AquaDeploymentStateRunning, instance.Status.State) {
 		instance.Status.State = v1alpha1.AquaDeploymentStateRunning
-		_ = r.Client.Status().Update(context.Background(), instance)
+		_ = common.UpdateStatus(r.Client, instance)
 	}
 
-	return ctrl.Result{}, nil
+	return common.MaintenanceResult(instance.Status.Maintenance), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -232,6 +282,7 @@ func (r *AquaDatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&batchv1.Job{}).
 		Complete(r)
 }
 
@@ -242,6 +293,11 @@ func (r *AquaDatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 ----------------------------------------------------------------------------------------------------------------
 */
+// scrubPlaintextSecrets moves the plaintext audit database password of the spec into an owned secret and sets the matching ref
+func (r *AquaDatabaseReconciler) scrubPlaintextSecrets(cr *v1alpha1.AquaDatabase) (bool, error) {
+	return common.MoveDatabasePasswords(r.Client, r.Scheme, cr, cr.Spec.Common, nil, cr.Spec.AuditDB)
+}
+
 func (r *AquaDatabaseReconciler) updateDatabaseObject(cr *v1alpha1.AquaDatabase) *v1alpha1.AquaDatabase {
 
 	cr.Spec.Infrastructure = common.UpdateAquaInfrastructure(cr.Spec.Infrastructure, cr.Name, cr.Namespace)
@@ -250,7 +306,7 @@ func (r *AquaDatabaseReconciler) updateDatabaseObject(cr *v1alpha1.AquaDatabase)
 	return cr
 }
 
-func (r *AquaDatabaseReconciler) InstallDatabaseDeployment(cr *v1alpha1.AquaDatabase, dbSecret *v1alpha1.AquaSecret, deployName, pvcName, app string) (reconcile.Result, error) {
+func (r *AquaDatabaseReconciler) InstallDatabaseDeployment(cr *v1alpha1.AquaDatabase, dbSecret *v1alpha1.AquaSecret, deployName, pvcName, app, serviceName string) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Database deployment Phase", "Install Database Deployment")
 	reqLogger.Info("Start installing aqua database deployment")
 
@@ -263,17 +319,28 @@ func (r *AquaDatabaseReconciler) InstallDatabaseDeployment(cr *v1alpha1.AquaData
 		pvcName,
 		app)
 
+	if err := common.MapPodImages(&deployment.Spec.Template.Spec); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := common.PinImages(r.Client, cr, common.GetImageVerification(cr.Spec.Common), &cr.Status.Images, &deployment.Spec.Template.Spec); err != nil {
+		reqLogger.Error(err, "Aqua Database: Image verification failed, keeping the current workload")
+		return reconcile.Result{}, err
+	}
+
 	// Set AquaCspKind instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, deployment, r.Scheme); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 	// Check if this deployment already exists
 	found := &appsv1.Deployment{}
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Creating a New Aqua Database Deployment", "Dervice.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
-		err = r.Client.Create(context.TODO(), deployment)
+		_, err = apply.Apply("AquaDatabase deployment", deployment)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -284,15 +351,36 @@ func (r *AquaDatabaseReconciler) InstallDatabaseDeployment(cr *v1alpha1.AquaData
 	}
 
 	if found != nil {
-		size := deployment.Spec.Replicas
-		if *found.Spec.Replicas != *size {
-			found.Spec.Replicas = size
-			err = r.Client.Status().Update(context.Background(), found)
+		imagesChanged := !reflect.DeepEqual(containerImages(found.Spec.Template.Spec), containerImages(deployment.Spec.Template.Spec))
+		maintenance := common.NewAquaMaintenanceHelper(cr.Spec.MaintenanceWindow, &cr.Status.Maintenance, r.Client, cr)
+		wait, err := maintenance.Defer(fmt.Sprintf("Deployment %s image update", found.Name), imagesChanged)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+
+		// A new database image is rolled out only after the database is dumped with the current one
+		if imagesChanged {
+			backedUp, err := r.BackupDatabase(cr, dbSecret, found, deployment, serviceName)
 			if err != nil {
-				reqLogger.Error(err, "Database Aqua: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
 				return reconcile.Result{}, err
 			}
+			if !backedUp {
+				reqLogger.Info("Aqua Database: Waiting for the pre-upgrade backup", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
+				return reconcile.Result{RequeueAfter: consts.DbStorageMigrationRequeue}, nil
+			}
 
+			reqLogger.Info("Aqua Database: Upgrading the database image", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
+		}
+
+		update, err := apply.Apply("AquaDatabase deployment", deployment)
+		if err != nil {
+			reqLogger.Error(err, "Aqua Database: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
+			return reconcile.Result{}, err
+		}
+		if update {
 			// Spec updated - return and requeue
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -332,11 +420,16 @@ func (r *AquaDatabaseReconciler) InstallDatabaseDeployment(cr *v1alpha1.AquaData
 			for _, pod := range podsToAppend {
 				cr.Status.Nodes = append(cr.Status.Nodes, pod)
 			}
-			err := r.Client.Status().Update(context.Background(), cr)
+			err := common.UpdateStatus(r.Client, cr)
 			if err != nil {
 				return reconcile.Result{}, err
 			}
 		}
+
+		if k8s.IsDeploymentRolledOut(found) &&
+			common.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, cr.Spec.Infrastructure.Version) {
+			_ = common.UpdateStatus(r.Client, cr)
+		}
 	}
 
 	// Deployment already exists - don't requeue
@@ -344,6 +437,157 @@ func (r *AquaDatabaseReconciler) InstallDatabaseDeployment(cr *v1alpha1.AquaData
 	return reconcile.Result{}, nil
 }
 
+// BackupDatabase dumps the database to the backup claim of the deployment before desired replaces its images,
+// returns true once the backup job for these images succeeded
+func (r *AquaDatabaseReconciler) BackupDatabase(cr *v1alpha1.AquaDatabase, dbSecret *v1alpha1.AquaSecret, current, desired *appsv1.Deployment, serviceName string) (bool, error) {
+	reqLogger := log.WithValues("Database Upgrade Phase", "Backup Database")
+
+	hash, err := extra.GenerateMD5ForSpec(containerImages(desired.Spec.Template.Spec))
+	if err != nil {
+		return false, err
+	}
+	jobName := fmt.Sprintf(consts.DbBackupJobName, current.Name, hash[:10])
+	pvcName := fmt.Sprintf(consts.DbBackupPvcName, current.Name)
+
+	job := &batchv1.Job{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: jobName, Namespace: cr.Namespace}, job)
+	if err != nil && errors.IsNotFound(err) {
+		_, err = r.InstallDatabasePvc(cr, pvcName)
+		if err != nil {
+			return false, err
+		}
+
+		helper := newAquaDatabaseHelper(cr)
+		job = helper.newBackupJob(cr, jobName, pvcName, serviceName, dbSecret, current.Spec.Template.Spec)
+		if err := controllerutil.SetControllerReference(cr, job, r.Scheme); err != nil {
+			return false, err
+		}
+
+		reqLogger.Info("Creating a New Aqua Database Backup Job", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+		err = r.Client.Create(context.TODO(), job)
+		if err != nil {
+			return false, err
+		}
+
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	finished, failed := k8s.IsJobFinished(job)
+	if !finished {
+		return false, nil
+	}
+
+	if failed {
+		return false, fmt.Errorf("aqua database backup job %s failed, the database is not upgraded, delete the job to retry", jobName)
+	}
+
+	if cr.Status.LastBackup != jobName {
+		cr.Status.LastBackup = jobName
+		_ = common.UpdateStatus(r.Client, cr)
+	}
+
+	return true, nil
+}
+
+// DatabaseInstance A postgres deployment of the AquaDatabase, with the service and password secret its jobs connect with
+type DatabaseInstance struct {
+	DeployName  string
+	ServiceName string
+	Secret      *v1alpha1.AquaSecret
+}
+
+// GetDatabaseInstances returns the database of the CR and, with a split database, its audit database
+func GetDatabaseInstances(cr *v1alpha1.AquaDatabase) []DatabaseInstance {
+	secret := &v1alpha1.AquaSecret{
+		Name: fmt.Sprintf(consts.ScalockDbPasswordSecretName, cr.Name),
+		Key:  consts.ScalockDbPasswordSecretKey,
+	}
+	if cr.Spec.Common != nil && cr.Spec.Common.DatabaseSecret != nil {
+		secret = cr.Spec.Common.DatabaseSecret
+	}
+
+	instances := []DatabaseInstance{
+		{
+			DeployName:  fmt.Sprintf(consts.DbDeployName, cr.Name),
+			ServiceName: fmt.Sprintf(consts.DbServiceName, cr.Name),
+			Secret:      secret,
+		},
+	}
+
+	if cr.Spec.Common != nil && cr.Spec.Common.SplitDB {
+		auditDB := common.UpdateAquaAuditDB(cr.Spec.AuditDB.DeepCopy(), cr.Name)
+		instances = append(instances, DatabaseInstance{
+			DeployName:  fmt.Sprintf(consts.AuditDbDeployName, cr.Name),
+			ServiceName: fmt.Sprintf(consts.AuditDbServiceName, cr.Name),
+			Secret:      auditDB.AuditDBSecret,
+		})
+	}
+
+	return instances
+}
+
+// RunBackup creates a job dumping the database instance to its backup claim, the claim is created when missing
+func (r *AquaDatabaseReconciler) RunBackup(cr *v1alpha1.AquaDatabase, instance DatabaseInstance, jobName string) (*batchv1.Job, error) {
+	current, err := r.getDatabaseDeployment(cr, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	pvcName := fmt.Sprintf(consts.DbBackupPvcName, instance.DeployName)
+	_, err = r.InstallDatabasePvc(cr, pvcName)
+	if err != nil {
+		return nil, err
+	}
+
+	job := newAquaDatabaseHelper(cr).newBackupJob(cr, jobName, pvcName, instance.ServiceName, instance.Secret, current.Spec.Template.Spec)
+	return job, r.createDatabaseJob(cr, job)
+}
+
+// RunRestore creates a job loading a dump of the backup claim of the database instance, file is the dump name in the claim
+func (r *AquaDatabaseReconciler) RunRestore(cr *v1alpha1.AquaDatabase, instance DatabaseInstance, jobName, file string) (*batchv1.Job, error) {
+	current, err := r.getDatabaseDeployment(cr, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	pvcName := fmt.Sprintf(consts.DbBackupPvcName, instance.DeployName)
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: pvcName, Namespace: cr.Namespace}, &corev1.PersistentVolumeClaim{})
+	if err != nil {
+		return nil, err
+	}
+
+	job := newAquaDatabaseHelper(cr).newRestoreJob(cr, jobName, pvcName, instance.ServiceName, instance.Secret, current.Spec.Template.Spec, file)
+	return job, r.createDatabaseJob(cr, job)
+}
+
+func (r *AquaDatabaseReconciler) getDatabaseDeployment(cr *v1alpha1.AquaDatabase, instance DatabaseInstance) (*appsv1.Deployment, error) {
+	current := &appsv1.Deployment{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.DeployName, Namespace: cr.Namespace}, current)
+	return current, err
+}
+
+func (r *AquaDatabaseReconciler) createDatabaseJob(cr *v1alpha1.AquaDatabase, job *batchv1.Job) error {
+	reqLogger := log.WithValues("Database Maintenance Phase", "Create Job")
+
+	if err := controllerutil.SetControllerReference(cr, job, r.Scheme); err != nil {
+		return err
+	}
+
+	reqLogger.Info("Creating a New Aqua Database Job", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+	return r.Client.Create(context.TODO(), job)
+}
+
+func containerImages(podSpec corev1.PodSpec) []string {
+	images := []string{}
+	for _, container := range podSpec.Containers {
+		images = append(images, container.Image)
+	}
+
+	return images
+}
+
 func (r *AquaDatabaseReconciler) InstallDatabaseService(cr *v1alpha1.AquaDatabase, serviceName, app string, servicePort int32) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Database Requirements Phase", "Install Database Service")
 	reqLogger.Info("Start installing aqua database service")
@@ -415,6 +659,362 @@ func (r *AquaDatabaseReconciler) InstallDatabasePvc(cr *v1alpha1.AquaDatabase, n
 	return reconcile.Result{Requeue: true}, nil
 }
 
+/*	----------------------------------------------------------------------------------------------------------------
+							Aqua Database Storage
+	----------------------------------------------------------------------------------------------------------------
+*/
+
+// getDatabasePvcName returns the claim currently mounted by the database deployment, a storage class
+// migration moves the database to a new claim so the name is kept in the status.
+func (r *AquaDatabaseReconciler) getDatabasePvcName(cr *v1alpha1.AquaDatabase, deployName, defaultName string) string {
+	for _, storage := range cr.Status.Storage {
+		if storage.Deployment == deployName && len(storage.PvcName) > 0 {
+			return storage.PvcName
+		}
+	}
+
+	return defaultName
+}
+
+func (r *AquaDatabaseReconciler) getDatabaseStorageStatus(cr *v1alpha1.AquaDatabase, deployName, pvcName string) *v1alpha1.AquaDatabaseStorageStatus {
+	for index := range cr.Status.Storage {
+		if cr.Status.Storage[index].Deployment == deployName {
+			return &cr.Status.Storage[index]
+		}
+	}
+
+	cr.Status.Storage = append(cr.Status.Storage, v1alpha1.AquaDatabaseStorageStatus{
+		Deployment: deployName,
+		PvcName:    pvcName,
+	})
+
+	return &cr.Status.Storage[len(cr.Status.Storage)-1]
+}
+
+// ReconcileDatabaseStorage expands the database claim when the disk size grows and moves the data to a
+// new claim when the storage class changes. It returns the claim the deployment mounts, the new one once a migration
+// finished, and true while a migration keeps the database down.
+func (r *AquaDatabaseReconciler) ReconcileDatabaseStorage(cr *v1alpha1.AquaDatabase, deployName, pvcName string) (string, bool, error) {
+	reqLogger := log.WithValues("Database Requirements Phase", "Reconcile Database Storage")
+	reqLogger.Info("Start reconciling aqua database storage", "PersistentVolumeClaim.Name", pvcName)
+
+	found := &corev1.PersistentVolumeClaim{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: pvcName, Namespace: cr.Namespace}, found)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return pvcName, false, nil
+		}
+		return pvcName, false, err
+	}
+
+	previous := v1alpha1.AquaDatabaseStorageStatus{}
+	for _, storage := range cr.Status.Storage {
+		if storage.Deployment == deployName {
+			previous = storage
+		}
+	}
+	storage := r.getDatabaseStorageStatus(cr, deployName, pvcName)
+
+	currentClass := ""
+	if found.Spec.StorageClassName != nil {
+		currentClass = *found.Spec.StorageClassName
+	}
+
+	desiredClass := cr.Spec.Common.StorageClass
+	migrating := storage.MigrationState == v1alpha1.AquaStorageMigrationScalingDown ||
+		storage.MigrationState == v1alpha1.AquaStorageMigrationCopying
+
+	// a new migration stops the database, a running one goes on
+	maintenance := common.NewAquaMaintenanceHelper(cr.Spec.MaintenanceWindow, &cr.Status.Maintenance, r.Client, cr)
+	wait, err := maintenance.Defer(fmt.Sprintf("Deployment %s storage migration", deployName),
+		!migrating && len(desiredClass) > 0 && desiredClass != currentClass)
+	if err != nil {
+		return pvcName, false, err
+	}
+	if wait > 0 {
+		reqLogger.Info("Aqua database storage migration deferred until the maintenance window opens", "StorageClass", desiredClass)
+	}
+
+	if migrating || (wait == 0 && len(desiredClass) > 0 && desiredClass != currentClass) {
+		inProgress, err := r.MigrateDatabaseStorage(cr, storage, found, deployName)
+		if err != nil {
+			return pvcName, false, err
+		}
+
+		if !reflect.DeepEqual(previous, *storage) {
+			err = common.UpdateStatus(r.Client, cr)
+			if err != nil {
+				return pvcName, false, err
+			}
+		}
+
+		if inProgress {
+			return pvcName, true, nil
+		}
+
+		if storage.PvcName != found.Name {
+			// the size is checked on the claim the data was moved to
+			pvcName = storage.PvcName
+			found = &corev1.PersistentVolumeClaim{}
+			err = r.Client.Get(context.TODO(), types.NamespacedName{Name: pvcName, Namespace: cr.Namespace}, found)
+			if err != nil {
+				return pvcName, false, err
+			}
+			currentClass = ""
+			if found.Spec.StorageClassName != nil {
+				currentClass = *found.Spec.StorageClassName
+			}
+		}
+	}
+
+	desiredSize := resource.MustParse(fmt.Sprintf("%dGi", cr.Spec.DiskSize))
+	requestedSize := found.Spec.Resources.Requests[corev1.ResourceStorage]
+
+	resizeState := getStorageResizeState(found)
+	message := ""
+	if desiredSize.Cmp(requestedSize) > 0 {
+		allowed, err := r.isVolumeExpansionAllowed(currentClass)
+		if err != nil {
+			return pvcName, false, err
+		}
+
+		if allowed {
+			reqLogger.Info("Expanding Aqua Database PersistentVolumeClaim", "PersistentVolumeClaim.Name", found.Name, "From", requestedSize.String(), "To", desiredSize.String())
+			found.Spec.Resources.Requests[corev1.ResourceStorage] = desiredSize
+			err = r.Client.Update(context.TODO(), found)
+			if err != nil {
+				reqLogger.Error(err, "Aqua Database: Failed to expand PersistentVolumeClaim.", "PersistentVolumeClaim.Namespace", found.Namespace, "PersistentVolumeClaim.Name", found.Name)
+				return pvcName, false, err
+			}
+			requestedSize = desiredSize
+			resizeState = v1alpha1.AquaStorageResizePending
+		} else {
+			resizeState = v1alpha1.AquaStorageResizeNotSupported
+			message = fmt.Sprintf("storage class %q doesn't allow volume expansion, change the storage class to migrate the database to a larger claim", currentClass)
+		}
+	} else if desiredSize.Cmp(requestedSize) < 0 {
+		message = fmt.Sprintf("claim size %s can't be reduced to %s", requestedSize.String(), desiredSize.String())
+	}
+
+	if len(resizeState) == 0 && len(previous.ResizeState) > 0 &&
+		previous.ResizeState != v1alpha1.AquaStorageResizeNotSupported {
+		resizeState = v1alpha1.AquaStorageResizeCompleted
+	}
+
+	capacity := found.Status.Capacity[corev1.ResourceStorage]
+	storage.PvcName = found.Name
+	storage.StorageClass = currentClass
+	storage.RequestedSize = requestedSize.String()
+	storage.Capacity = capacity.String()
+	storage.ResizeState = resizeState
+	if storage.MigrationState != v1alpha1.AquaStorageMigrationFailed {
+		storage.Message = message
+	}
+
+	if !reflect.DeepEqual(previous, *storage) {
+		err = common.UpdateStatus(r.Client, cr)
+		if err != nil {
+			return pvcName, false, err
+		}
+	}
+
+	return pvcName, false, nil
+}
+
+// getStorageResizeState maps the claim resize conditions to the reported resize state
+func getStorageResizeState(pvc *corev1.PersistentVolumeClaim) v1alpha1.AquaStorageResizeState {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimResizing:
+			return v1alpha1.AquaStorageResizeInProgress
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			return v1alpha1.AquaStorageFileSystemResizePending
+		}
+	}
+
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if ok && capacity.Cmp(requested) < 0 {
+		return v1alpha1.AquaStorageResizePending
+	}
+
+	return ""
+}
+
+func (r *AquaDatabaseReconciler) isVolumeExpansionAllowed(storageClassName string) (bool, error) {
+	if len(storageClassName) == 0 {
+		return false, nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, storageClass)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
+
+// MigrateDatabaseStorage copies the database files to a claim of the requested storage class.
+// The database deployment is scaled down while the copy job runs and switched to the new claim once it succeeds,
+// a failed job is kept for inspection and the migration is retried after it is deleted.
+func (r *AquaDatabaseReconciler) MigrateDatabaseStorage(cr *v1alpha1.AquaDatabase, storage *v1alpha1.AquaDatabaseStorageStatus, source *corev1.PersistentVolumeClaim, deployName string) (bool, error) {
+	reqLogger := log.WithValues("Database Requirements Phase", "Migrate Database Storage")
+
+	storageClass := cr.Spec.Common.StorageClass
+	target := fmt.Sprintf(consts.DbPvcMigrationName, deployName, storageClass)
+	jobName := fmt.Sprintf(consts.DbStorageMigrationJobName, deployName)
+
+	if storage.MigrationState == v1alpha1.AquaStorageMigrationCopying && len(storage.MigrationTarget) > 0 {
+		target = storage.MigrationTarget
+	}
+
+	job := &batchv1.Job{}
+	jobErr := r.Client.Get(context.TODO(), types.NamespacedName{Name: jobName, Namespace: cr.Namespace}, job)
+	if jobErr != nil && !errors.IsNotFound(jobErr) {
+		return false, jobErr
+	}
+
+	if storage.MigrationState == v1alpha1.AquaStorageMigrationFailed && storage.MigrationTarget == target {
+		if jobErr == nil {
+			reqLogger.Info("Skip storage migration: previous migration job failed, delete it to retry", "Job.Name", jobName)
+			return false, nil
+		}
+	}
+
+	deployment := &appsv1.Deployment{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: deployName, Namespace: cr.Namespace}, deployment)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	deploymentExists := err == nil
+
+	if storage.MigrationState != v1alpha1.AquaStorageMigrationCopying {
+		reqLogger.Info("Start migrating aqua database storage", "From", source.Name, "To", target, "StorageClass", storageClass)
+		storage.MigrationState = v1alpha1.AquaStorageMigrationScalingDown
+		storage.MigrationTarget = target
+		storage.Message = fmt.Sprintf("migrating %s to storage class %q", source.Name, storageClass)
+
+		if deploymentExists {
+			if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0 {
+				deployment.Spec.Replicas = extra.Int32Ptr(0)
+				err = r.Client.Update(context.TODO(), deployment)
+				if err != nil {
+					return false, err
+				}
+				return true, nil
+			}
+
+			if deployment.Status.Replicas != 0 {
+				return true, nil
+			}
+		}
+
+		helper := newAquaDatabaseHelper(cr)
+		pvc := pvcs.CreatePersistentVolumeClaim(cr.Name,
+			cr.Namespace,
+			fmt.Sprintf("%s-database", cr.Name),
+			"Persistent Volume Claim for aqua database server",
+			target,
+			storageClass,
+			cr.Spec.DiskSize)
+		if err := controllerutil.SetControllerReference(cr, pvc, r.Scheme); err != nil {
+			return false, err
+		}
+		err = r.Client.Create(context.TODO(), pvc)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return false, err
+		}
+
+		if jobErr == nil {
+			policy := metav1.DeletePropagationBackground
+			err = r.Client.Delete(context.TODO(), job, &client.DeleteOptions{PropagationPolicy: &policy})
+			if err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+			return true, nil
+		}
+
+		job = helper.newStorageMigrationJob(cr, jobName, source.Name, target)
+		if err := common.MapPodImages(&job.Spec.Template.Spec); err != nil {
+			return false, err
+		}
+		if err := controllerutil.SetControllerReference(cr, job, r.Scheme); err != nil {
+			return false, err
+		}
+		reqLogger.Info("Creating a New Aqua Database Storage Migration Job", "Job.Namespace", job.Namespace, "Job.Name", job.Name)
+		err = r.Client.Create(context.TODO(), job)
+		if err != nil {
+			return false, err
+		}
+
+		storage.MigrationState = v1alpha1.AquaStorageMigrationCopying
+		return true, nil
+	}
+
+	if jobErr != nil {
+		// the job was removed while copying, start over
+		storage.MigrationState = v1alpha1.AquaStorageMigrationScalingDown
+		return true, nil
+	}
+
+	finished, failed := k8s.IsJobFinished(job)
+	if !finished {
+		return true, nil
+	}
+
+	replicas := extra.Int32Ptr(int32(cr.Spec.DbService.Replicas))
+	if failed {
+		reqLogger.Info("Aqua database storage migration failed, keeping the current claim", "Job.Name", job.Name)
+		storage.MigrationState = v1alpha1.AquaStorageMigrationFailed
+		storage.Message = fmt.Sprintf("migration job %s failed, delete it to retry", job.Name)
+		if deploymentExists {
+			deployment.Spec.Replicas = replicas
+			err = r.Client.Update(context.TODO(), deployment)
+			if err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	if deploymentExists {
+		for index := range deployment.Spec.Template.Spec.Volumes {
+			volume := &deployment.Spec.Template.Spec.Volumes[index]
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == source.Name {
+				volume.PersistentVolumeClaim.ClaimName = target
+			}
+		}
+		deployment.Spec.Replicas = replicas
+		err = r.Client.Update(context.TODO(), deployment)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	policy := metav1.DeletePropagationBackground
+	err = r.Client.Delete(context.TODO(), job, &client.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	reqLogger.Info("Aqua database storage migrated, the previous claim is kept until the AquaDatabase is deleted", "From", source.Name, "To", target)
+	storage.PvcName = target
+	storage.StorageClass = storageClass
+	storage.MigrationState = v1alpha1.AquaStorageMigrationCompleted
+	storage.MigrationTarget = ""
+	storage.Message = fmt.Sprintf("migrated from %s, the previous claim can be deleted", source.Name)
+
+	return false, nil
+}
+
 func (r *AquaDatabaseReconciler) CreateDbPasswordSecret(cr *v1alpha1.AquaDatabase, name, key, password string) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Database Requirements Phase", "Create Db Password Secret")
 	reqLogger.Info("Start creating aqua db password secret")
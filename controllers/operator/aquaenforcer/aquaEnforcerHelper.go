@@ -16,6 +16,8 @@ import (
 // EnforcerParameters :
 type EnforcerParameters struct {
 	Enforcer *v1alpha1.AquaEnforcer
+	// RhcosVersions The OpenShift versions detected per node group, "" for the enforcers of a CR without groups
+	RhcosVersions map[string]string
 }
 
 // AquaEnforcerHelper :
@@ -33,36 +35,6 @@ func newAquaEnforcerHelper(cr *v1alpha1.AquaEnforcer) *AquaEnforcerHelper {
 	}
 }
 
-// CreateTokenSecret : Create Enforcer Token Secret For The Enforcer connection to the aqua csp environment
-func (enf *AquaEnforcerHelper) CreateTokenSecret(cr *v1alpha1.AquaEnforcer) *corev1.Secret {
-	labels := map[string]string{
-		"app":                cr.Name + "-requirments",
-		"deployedby":         "aqua-operator",
-		"aquasecoperator_cr": cr.Name,
-	}
-	annotations := map[string]string{
-		"description": "Secret for aqua database password",
-	}
-	token := &corev1.Secret{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "core/v1",
-			Kind:       "Secret",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        fmt.Sprintf(consts.EnforcerTokenSecretName, cr.Name),
-			Namespace:   cr.Namespace,
-			Labels:      labels,
-			Annotations: annotations,
-		},
-		Type: corev1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			consts.EnforcerTokenSecretKey: []byte(cr.Spec.Token),
-		},
-	}
-
-	return token
-}
-
 func (enf *AquaEnforcerHelper) CreateConfigMap(cr *v1alpha1.AquaEnforcer) *corev1.ConfigMap {
 
 	labels := map[string]string{
@@ -90,6 +62,8 @@ func (enf *AquaEnforcerHelper) CreateConfigMap(cr *v1alpha1.AquaEnforcer) *corev
 
 	if len(cr.Spec.RhcosVersion) != 0 {
 		data["AQUA_OCP_FULL_VERSION"] = cr.Spec.RhcosVersion
+	} else if version := enf.Parameters.RhcosVersions[""]; len(version) != 0 {
+		data["AQUA_OCP_FULL_VERSION"] = version
 	}
 
 	configMap := &corev1.ConfigMap{
@@ -374,22 +348,7 @@ func (enf *AquaEnforcerHelper) CreateDaemonSet(cr *v1alpha1.AquaEnforcer) *appsv
 		ds.Spec.Template.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
 			Privileged: &privileged,
 			Capabilities: &corev1.Capabilities{
-				Add: []corev1.Capability{
-					"SYS_ADMIN",
-					"NET_ADMIN",
-					"NET_RAW",
-					"SYS_PTRACE",
-					"KILL",
-					"MKNOD",
-					"SETGID",
-					"SETUID",
-					"SYS_MODULE",
-					"AUDIT_CONTROL",
-					"SYSLOG",
-					"SYS_CHROOT",
-					"SYS_RESOURCE",
-					"IPC_LOCK",
-				},
+				Add: enforcerCapabilities,
 			},
 		}
 	}
@@ -444,6 +403,10 @@ func (enf *AquaEnforcerHelper) CreateDaemonSet(cr *v1alpha1.AquaEnforcer) *appsv
 		ds.Spec.Template.Spec.Volumes = append(ds.Spec.Template.Spec.Volumes, mtlsAquaEnforcerVolume...)
 	}
 
+	if cr.Spec.SecurityProfile != nil {
+		enf.setSecurityProfile(ds, cr.Spec.SecurityProfile)
+	}
+
 	return ds
 }
 
@@ -22,19 +22,31 @@ import (
 	"fmt"
 	"github.com/aquasecurity/aqua-operator/controllers/common"
 	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/aquaapi"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
-	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/openshift"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -50,6 +62,8 @@ var log = logf.Log.WithName("controller_aquaenforcer")
 type AquaEnforcerReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Reader Reads the nodes and the OpenShift ClusterVersion from the API server, so they aren't cached
+	Reader client.Reader
 }
 
 //+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaenforcers,verbs=get;list;watch;create;update;patch;delete
@@ -60,6 +74,10 @@ type AquaEnforcerReconciler struct {
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquaservers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -87,9 +105,39 @@ func (r *AquaEnforcerReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
+	// the SecurityContextConstraints are cluster scoped, the finalizer deletes them with the CR
+	if instance.GetDeletionTimestamp() != nil {
+		if controllerutil.ContainsFinalizer(instance, consts.AquaEnforcerFinalizer) {
+			err = r.removeSecurityContextConstraints(instance)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if common.ReconcilePaused(instance, instance.Spec.Paused) {
+		return reconcile.Result{}, nil
+	}
+
+	scrubbed, err := r.scrubPlaintextSecrets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if scrubbed {
+		reqLogger.Info("Moved plaintext credentials from the AquaEnforcer spec into secrets")
+		err = r.Client.Update(context.Background(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	instance = r.updateEnforcerObject(instance)
 	r.Client.Update(context.Background(), instance)
 
+	common.ResolveInfrastructureVersion(instance.Spec.Infrastructure, instance.Status.Version, instance.Spec.Common.AllowAnyVersion)
+
 	rbacHelper := common.NewAquaRbacHelper(
 		instance.Spec.Infrastructure,
 		instance.Name,
@@ -104,49 +152,166 @@ func (r *AquaEnforcerReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
+	err = newAquaEnforcerHelper(instance).ValidateSecurityProfile()
+	if err != nil {
+		reqLogger.Error(err, "Aqua Enforcer: Invalid security profile")
+		return reconcile.Result{}, err
+	}
+
+	err = r.syncSecurityContextConstraints(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = newAquaEnforcerHelper(instance).ValidateEnforcerGroup()
+	if err != nil {
+		reqLogger.Error(err, "Aqua Enforcer: Invalid enforcer group")
+		return reconcile.Result{}, err
+	}
+	if instance.Spec.EnforcerGroup == nil && instance.Status.EnforcerGroup != nil {
+		instance.Status.EnforcerGroup = nil
+		_ = common.UpdateStatus(r.Client, instance)
+	}
+
 	currentStatus := instance.Status.State
 	if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, currentStatus) &&
 		!reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdatePendingApproval, currentStatus) &&
 		!reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdateInProgress, currentStatus) {
 		instance.Status.State = operatorv1alpha1.AquaDeploymentStatePending
-		_ = r.Client.Status().Update(context.Background(), instance)
+		_ = common.UpdateStatus(r.Client, instance)
 	}
 
 	if instance.Spec.EnforcerService != nil {
-		if len(instance.Spec.Token) != 0 {
+		store := common.GetSecretStore(instance.Spec.Common)
+		if instance.Spec.EnforcerGroup != nil {
+			if instance.Spec.Secret == nil {
+				instance.Spec.Secret = &operatorv1alpha1.AquaSecret{
+					Name: fmt.Sprintf(consts.EnforcerTokenSecretName, instance.Name),
+					Key:  consts.EnforcerTokenSecretKey,
+				}
+			}
+
+			_, err = r.SyncEnforcerGroupToken(instance)
+			if err != nil {
+				reqLogger.Error(err, "Aqua Enforcer: Failed to get the token of the enforcer group")
+				return reconcile.Result{}, err
+			}
+		} else if store != nil && len(store.Keys.EnforcerToken) != 0 {
 			instance.Spec.Secret = &operatorv1alpha1.AquaSecret{
 				Name: fmt.Sprintf(consts.EnforcerTokenSecretName, instance.Name),
 				Key:  consts.EnforcerTokenSecretKey,
 			}
 
-			_, err = r.InstallEnforcerToken(instance)
+			_, err = r.SyncEnforcerToken(instance)
 			if err != nil {
 				return reconcile.Result{}, err
 			}
 		} else if instance.Spec.Secret == nil {
 			reqLogger.Error(syserrors.New("You must specifie the enforcer token or the token secret name and key"), "Missing enforcer token")
 		} else {
-			exists := secrets.CheckIfSecretExists(r.Client, instance.Spec.Secret.Name, instance.Namespace)
-			if !exists {
+			found := &corev1.Secret{}
+			err = r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.Secret.Name, Namespace: instance.Namespace}, found)
+			if err != nil && errors.IsNotFound(err) {
 				reqLogger.Error(syserrors.New("You must specifie the enforcer token or the token secret name and key"), "Missing enforcer token")
+			} else if err != nil {
+				return reconcile.Result{}, err
+			} else {
+				// Adding token to the hashed data, for restart pods if token is changed
+				hash, err := extra.GenerateMD5ForSpec(found.Data)
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+				instance.Spec.ConfigMapChecksum += hash
+			}
+		}
+
+		enforcerHelper := newAquaEnforcerHelper(instance)
+		err = enforcerHelper.ValidateNodeGroups()
+		if err != nil {
+			reqLogger.Error(err, "Aqua Enforcer: Invalid node groups")
+			return reconcile.Result{}, err
+		}
 
+		enforcerHelper.Parameters.RhcosVersions, err = r.detectRhcosVersions(instance, enforcerHelper)
+		if err != nil {
+			reqLogger.Error(err, "Aqua Enforcer: Failed to detect the OpenShift version of the nodes")
+			return reconcile.Result{}, err
+		}
+
+		runtimes, err := r.detectContainerRuntimes(instance, enforcerHelper)
+		if err != nil {
+			reqLogger.Error(err, "Aqua Enforcer: Failed to detect the container runtime of the nodes")
+			return reconcile.Result{}, err
+		}
+
+		// every group gets the checksum of the token and of its own ConfigMap
+		tokenChecksum := instance.Spec.ConfigMapChecksum
+		daemonSets := []*appsv1.DaemonSet{}
+		configMaps := []*corev1.ConfigMap{}
+		detected := []operatorv1alpha1.AquaEnforcerContainerRuntimeStatus{}
+		for _, group := range enforcerHelper.NodeGroups() {
+			instance.Spec.ConfigMapChecksum = tokenChecksum
+			configMap := enforcerHelper.CreateGroupConfigMap(instance, group)
+			_, err = r.addEnforcerConfigMap(instance, configMap)
+
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			configMaps = append(configMaps, configMap)
+
+			// a DaemonSet per runtime of the nodes of the group, the group DaemonSet when no runtime is detected
+			groupRuntimes := []string{}
+			for runtime := range runtimes[group.Name] {
+				groupRuntimes = append(groupRuntimes, runtime)
+			}
+			sort.Strings(groupRuntimes)
+			if len(groupRuntimes) == 0 {
+				daemonSets = append(daemonSets, enforcerHelper.CreateGroupDaemonSet(instance, group))
+			}
+			for _, runtime := range groupRuntimes {
+				ds := enforcerHelper.CreateGroupDaemonSet(instance, group)
+				enforcerHelper.SetContainerRuntime(ds, runtime, len(groupRuntimes) > 1)
+				daemonSets = append(daemonSets, ds)
+				detected = append(detected, operatorv1alpha1.AquaEnforcerContainerRuntimeStatus{
+					NodeGroup: group.Name,
+					Runtime:   runtime,
+					DaemonSet: ds.Name,
+					Nodes:     runtimes[group.Name][runtime],
+				})
 			}
 		}
 
-		_, err = r.addEnforcerConfigMap(instance)
+		err = r.updateContainerRuntimes(instance, detected)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		_, err = r.InstallEnforcerDaemonSet(instance, daemonSets)
 
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 
-		_, err = r.InstallEnforcerDaemonSet(instance)
+		err = r.removeStaleNodeGroups(instance, daemonSets, configMaps)
 
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
-	return ctrl.Result{}, nil
+	result := common.MaintenanceResult(instance.Status.Maintenance)
+	if store := common.GetSecretStore(instance.Spec.Common); store != nil {
+		result = ctrl.Result{RequeueAfter: common.NewAquaSecretStoreHelper(store, instance.Namespace, r.Client, r.Scheme, instance).RefreshInterval()}
+	}
+
+	// the nodes are read again to follow the cluster upgrades and the new nodes
+	if (len(instance.Status.RhcosVersions) != 0 || instance.Spec.ContainerRuntime != nil) && !result.Requeue &&
+		(result.RequeueAfter == 0 || result.RequeueAfter > consts.EnforcerNodesRefreshInterval) {
+		result.RequeueAfter = consts.EnforcerNodesRefreshInterval
+	}
+
+	return result, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -158,15 +323,94 @@ func (r *AquaEnforcerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&appsv1.DaemonSet{}).
+		Watches(&source.Kind{Type: &corev1.Node{}}, handler.EnqueueRequestsFromMapFunc(r.nodeRequests), builder.WithPredicates(nodeChanged())).
 		For(&operatorv1alpha1.AquaEnforcer{}).
 		Complete(r)
 }
 
+// nodeRequests enqueues the AquaEnforcers that detect the container runtimes, so a new node is labeled with its
+// runtime and gets the enforcer of its runtime without waiting for the next reconcile
+func (r *AquaEnforcerReconciler) nodeRequests(obj client.Object) []reconcile.Request {
+	list := &operatorv1alpha1.AquaEnforcerList{}
+	if err := r.Client.List(context.TODO(), list); err != nil {
+		log.Error(err, "Failed to list the AquaEnforcers of a node", "Node.Name", obj.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, item := range list.Items {
+		if item.Spec.ContainerRuntime != nil {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace}})
+		}
+	}
+
+	return requests
+}
+
+// nodeChanged passes the node creations and the updates of the labels, taints or container runtime of a node, the
+// status heartbeats of the kubelet don't trigger reconciles
+func nodeChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return false
+			}
+			return oldNode.Status.NodeInfo.ContainerRuntimeVersion != newNode.Status.NodeInfo.ContainerRuntimeVersion ||
+				!reflect.DeepEqual(oldNode.Labels, newNode.Labels) ||
+				!reflect.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
 /*	----------------------------------------------------------------------------------------------------------------
 							Aqua Enforcer
 	----------------------------------------------------------------------------------------------------------------
 */
 
+// scrubPlaintextSecrets moves the plaintext token and enforcer group password of the spec into owned secrets and sets
+// the matching refs
+func (r *AquaEnforcerReconciler) scrubPlaintextSecrets(cr *operatorv1alpha1.AquaEnforcer) (bool, error) {
+	tokenMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.Token,
+		&cr.Spec.Secret,
+		fmt.Sprintf(consts.EnforcerTokenSecretName, cr.Name),
+		consts.EnforcerTokenSecretKey,
+		"Secret for aqua enforcer token")
+	if err != nil {
+		return false, err
+	}
+
+	if cr.Spec.EnforcerGroup == nil || cr.Spec.EnforcerGroup.Login == nil {
+		return tokenMoved, nil
+	}
+
+	passwordMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.EnforcerGroup.Login.Password,
+		&cr.Spec.EnforcerGroup.Login.PasswordSecretRef,
+		fmt.Sprintf(consts.EnforcerGroupPasswordSecretName, cr.Name),
+		consts.EnforcerGroupPasswordSecretKey,
+		"Aqua enforcer group login password")
+	if err != nil {
+		return false, err
+	}
+
+	return tokenMoved || passwordMoved, nil
+}
+
 func (r *AquaEnforcerReconciler) updateEnforcerObject(cr *operatorv1alpha1.AquaEnforcer) *operatorv1alpha1.AquaEnforcer {
 	version := cr.Spec.Infrastructure.Version
 	if len(version) == 0 {
@@ -204,141 +448,641 @@ func (r *AquaEnforcerReconciler) updateEnforcerObject(cr *operatorv1alpha1.AquaE
 	return cr
 }
 
-func (r *AquaEnforcerReconciler) InstallEnforcerDaemonSet(cr *operatorv1alpha1.AquaEnforcer) (reconcile.Result, error) {
+// InstallEnforcerDaemonSet creates or updates the DaemonSets of the node groups and sets the state from all of them,
+// the state is running once every group is ready
+func (r *AquaEnforcerReconciler) InstallEnforcerDaemonSet(cr *operatorv1alpha1.AquaEnforcer, daemonSets []*appsv1.DaemonSet) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Aqua Enforcer DaemonSet Phase", "Install Aqua Enforcer DaemonSet")
 	reqLogger.Info("Start installing enforcer")
 
-	// Define a new DaemonSet object
-	enforcerHelper := newAquaEnforcerHelper(cr)
-	ds := enforcerHelper.CreateDaemonSet(cr)
+	apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
 
-	// Set AquaEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, ds, r.Scheme); err != nil {
-		return reconcile.Result{}, err
+	updateEnforcerApproved := true
+	if cr.Spec.EnforcerUpdateApproved != nil {
+		updateEnforcerApproved = *cr.Spec.EnforcerUpdateApproved
 	}
 
-	// Check if this DaemonSet already exists
-	found := &appsv1.DaemonSet{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: ds.Name, Namespace: ds.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Creating a New Aqua Enforcer", "DaemonSet.Namespace", ds.Namespace, "DaemonSet.Name", ds.Name)
-		err = patch.DefaultAnnotator.SetLastAppliedAnnotation(ds)
-		if err != nil {
-			reqLogger.Error(err, "Unable to set default for k8s-objectmatcher", err)
-		}
-		err = r.Client.Create(context.TODO(), ds)
-		if err != nil {
+	founds := []*appsv1.DaemonSet{}
+	pendingApproval := false
+	requeue := false
+	var wait time.Duration
+	// the images of all the node groups are pinned together, so the status holds all of them
+	podSpecs := []*corev1.PodSpec{}
+	for _, ds := range daemonSets {
+		if err := common.MapPodImages(&ds.Spec.Template.Spec); err != nil {
 			return reconcile.Result{}, err
 		}
+		podSpecs = append(podSpecs, &ds.Spec.Template.Spec)
+	}
 
-		return reconcile.Result{}, nil
-	} else if err != nil {
+	if err := common.PinImages(r.Client, cr, common.GetImageVerification(cr.Spec.Common), &cr.Status.Images, podSpecs...); err != nil {
+		reqLogger.Error(err, "Aqua Enforcer: Image verification failed, keeping the current workload")
 		return reconcile.Result{}, err
 	}
 
-	if found != nil {
-		updateEnforcerApproved := true
-		if cr.Spec.EnforcerUpdateApproved != nil {
-			updateEnforcerApproved = *cr.Spec.EnforcerUpdateApproved
+	for _, ds := range daemonSets {
+		// Set AquaEnforcer instance as the owner and controller
+		if err := controllerutil.SetControllerReference(cr, ds, r.Scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// Check if this DaemonSet already exists
+		found := &appsv1.DaemonSet{}
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: ds.Name, Namespace: ds.Namespace}, found)
+		if err != nil && errors.IsNotFound(err) {
+			reqLogger.Info("Creating a New Aqua Enforcer", "DaemonSet.Namespace", ds.Namespace, "DaemonSet.Name", ds.Name)
+			_, err = apply.Apply("AquaEnforcer daemonset", ds)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			requeue = true
+			continue
+		} else if err != nil {
+			return reconcile.Result{}, err
 		}
 
-		update, err := k8s.CheckForK8sObjectUpdate("AquaEnforcer daemonset", found, ds)
+		drift := common.NewAquaDriftHelper(common.GetDriftPolicy(cr.Spec.Common), &cr.Status.Drifts, r.Client, cr)
+		if _, err = drift.Reconcile(found, ds); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		update, err := apply.Changed(ds)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 
+		maintenance := common.NewAquaMaintenanceHelper(cr.Spec.MaintenanceWindow, &cr.Status.Maintenance, r.Client, cr)
+		deferred, err := maintenance.Defer(fmt.Sprintf("DaemonSet %s update", found.Name), update && updateEnforcerApproved)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if deferred > 0 {
+			wait = deferred
+			continue
+		}
+
 		if update && updateEnforcerApproved {
-			err = r.Client.Update(context.Background(), ds)
+			_, err = apply.Apply("AquaEnforcer daemonset", ds)
 			if err != nil {
 				reqLogger.Error(err, "Aqua Enforcer: Failed to update Daemonset.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
 				return reconcile.Result{}, err
 			}
-			// Spec updated - return and requeue
-			return reconcile.Result{Requeue: true}, nil
+			// Spec updated - requeue once every group is applied
+			requeue = true
+			continue
 		} else if update && !updateEnforcerApproved {
+			pendingApproval = true
+		}
+
+		founds = append(founds, found)
+	}
+
+	if wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+	if requeue {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	nodeGroups := []operatorv1alpha1.AquaEnforcerNodeGroupStatus{}
+	desired, ready := int32(0), int32(0)
+	rolledOut := true
+	for _, found := range founds {
+		desired += found.Status.DesiredNumberScheduled
+		ready += found.Status.NumberReady
+		rolledOut = rolledOut && k8s.IsDaemonSetRolledOut(found)
+		if group := found.Labels[consts.EnforcerGroupLabel]; group != "" {
+			nodeGroups = append(nodeGroups, operatorv1alpha1.AquaEnforcerNodeGroupStatus{
+				Name:                   group,
+				DaemonSet:              found.Name,
+				DesiredNumberScheduled: found.Status.DesiredNumberScheduled,
+				NumberReady:            found.Status.NumberReady,
+			})
+		}
+	}
+	if len(nodeGroups) == 0 {
+		nodeGroups = nil
+	}
+
+	statusChanged := false
+	if !reflect.DeepEqual(nodeGroups, cr.Status.NodeGroups) {
+		cr.Status.NodeGroups = nodeGroups
+		statusChanged = true
+	}
+
+	currentState := cr.Status.State
+	if pendingApproval {
+		if !reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdatePendingApproval, currentState) {
 			cr.Status.State = operatorv1alpha1.AquaEnforcerUpdatePendingApproval
-			_ = r.Client.Status().Update(context.Background(), cr)
-		} else {
-			currentState := cr.Status.State
-			if found.Status.DesiredNumberScheduled != found.Status.NumberReady {
-				if !reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdateInProgress, currentState) &&
-					!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStatePending, currentState) {
-					cr.Status.State = operatorv1alpha1.AquaEnforcerUpdateInProgress
-					_ = r.Client.Status().Update(context.Background(), cr)
-				}
-			} else if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, currentState) &&
-				found.Status.NumberReady > 0 {
-				cr.Status.State = operatorv1alpha1.AquaDeploymentStateRunning
-				_ = r.Client.Status().Update(context.Background(), cr)
+			statusChanged = true
+		}
+	} else {
+		if desired != ready {
+			if !reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdateInProgress, currentState) &&
+				!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStatePending, currentState) {
+				cr.Status.State = operatorv1alpha1.AquaEnforcerUpdateInProgress
+				statusChanged = true
 			}
+		} else if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, currentState) &&
+			ready > 0 {
+			cr.Status.State = operatorv1alpha1.AquaDeploymentStateRunning
+			statusChanged = true
 		}
+
+		if rolledOut &&
+			common.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, cr.Spec.Infrastructure.Version) {
+			statusChanged = true
+		}
+	}
+
+	if statusChanged {
+		_ = common.UpdateStatus(r.Client, cr)
 	}
 
-	// DaemonSet already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua Enforcer DaemonSet Already Exists", "DaemonSet.Namespace", found.Namespace, "DaemonSet.Name", found.Name)
+	// DaemonSets already exist - don't requeue
+	reqLogger.Info("Skip reconcile: Aqua Enforcer DaemonSets Already Exist", "DaemonSet.Namespace", cr.Namespace, "DaemonSets", len(founds))
 	return reconcile.Result{}, nil
 }
 
-func (r *AquaEnforcerReconciler) InstallEnforcerToken(cr *operatorv1alpha1.AquaEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("Enforcer Requirements Phase", "Create Aqua Enforcer Token Secret")
-	reqLogger.Info("Start creating enforcer token secret")
+// detectRhcosVersions returns the OpenShift version of the RHCOS nodes of each node group, when the cluster is
+// OpenShift and rhcosVersion isn't set. The nodes of a group are the ones its enforcers can run on, a group whose
+// nodes are upgraded keeps its version until they all are, see openshift.PoolVersion
+func (r *AquaEnforcerReconciler) detectRhcosVersions(cr *operatorv1alpha1.AquaEnforcer, enforcerHelper *AquaEnforcerHelper) (map[string]string, error) {
+	if strings.ToLower(cr.Spec.Infrastructure.Platform) != consts.OpenShiftPlatform || len(cr.Spec.RhcosVersion) != 0 || r.Reader == nil {
+		return nil, r.updateRhcosVersions(cr, nil)
+	}
 
-	// Define a new DaemonSet object
-	enforcerHelper := newAquaEnforcerHelper(cr)
-	token := enforcerHelper.CreateTokenSecret(cr)
-	// Adding token to the hashed data, for restart pods if token is changed
-	hash, err := extra.GenerateMD5ForSpec(token.Data)
+	clusterVersion, err := openshift.GetClusterVersion(context.TODO(), r.Reader)
+	if err != nil || clusterVersion == nil {
+		return nil, err
+	}
+
+	previous := map[string]string{}
+	for _, detected := range cr.Status.RhcosVersions {
+		previous[detected.NodeGroup] = detected.Version
+	}
+
+	versions := map[string]string{}
+	detected := []operatorv1alpha1.AquaEnforcerRhcosVersion{}
+	for _, group := range enforcerHelper.NodeGroups() {
+		if len(group.RhcosVersion) != 0 {
+			continue
+		}
+
+		scheduled, err := r.groupNodes(enforcerHelper, group)
+		if err != nil {
+			return nil, err
+		}
+
+		version, osImages, updating := openshift.PoolVersion(clusterVersion, scheduled, previous[group.Name])
+		if len(version) == 0 {
+			continue
+		}
+		if version != previous[group.Name] {
+			log.Info("Aqua Enforcer: Detected the OpenShift version of the nodes", "NodeGroup", group.Name, "Version", version)
+		}
+		versions[group.Name] = version
+		detected = append(detected, operatorv1alpha1.AquaEnforcerRhcosVersion{
+			NodeGroup:     group.Name,
+			Version:       version,
+			OSImages:      osImages,
+			UpdatingNodes: updating,
+		})
+	}
+
+	return versions, r.updateRhcosVersions(cr, detected)
+}
+
+// updateRhcosVersions records the detected versions in the status
+func (r *AquaEnforcerReconciler) updateRhcosVersions(cr *operatorv1alpha1.AquaEnforcer, detected []operatorv1alpha1.AquaEnforcerRhcosVersion) error {
+	if len(detected) == 0 {
+		detected = nil
+	}
+	if reflect.DeepEqual(detected, cr.Status.RhcosVersions) {
+		return nil
+	}
+
+	cr.Status.RhcosVersions = detected
+	return common.UpdateStatus(r.Client, cr)
+}
+
+// detectContainerRuntimes returns the container runtimes of the nodes of each node group, sorted, and the number of
+// nodes running them. The nodes are labeled with their runtime, the DaemonSets of a group whose nodes run several
+// runtimes select them with the label. Returns nil when containerRuntime isn't set
+func (r *AquaEnforcerReconciler) detectContainerRuntimes(cr *operatorv1alpha1.AquaEnforcer, enforcerHelper *AquaEnforcerHelper) (map[string]map[string]int32, error) {
+	if cr.Spec.ContainerRuntime == nil || r.Reader == nil {
+		return nil, nil
+	}
+
+	runtimes := map[string]map[string]int32{}
+	for _, group := range enforcerHelper.NodeGroups() {
+		scheduled, err := r.groupNodes(enforcerHelper, group)
+		if err != nil {
+			return nil, err
+		}
+
+		runtimes[group.Name] = map[string]int32{}
+		for i := range scheduled {
+			node := &scheduled[i]
+			runtime := NodeRuntime(node)
+			runtimes[group.Name][runtime]++
+
+			if node.Labels[consts.ContainerRuntimeNodeLabel] == runtime {
+				continue
+			}
+			patch := client.MergeFrom(node.DeepCopy())
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[consts.ContainerRuntimeNodeLabel] = runtime
+			err = r.Client.Patch(context.TODO(), node, patch)
+			if err != nil {
+				return nil, err
+			}
+			log.Info("Aqua Enforcer: Labeled the node with its container runtime", "Node", node.Name, "Runtime", runtime)
+		}
+	}
+
+	return runtimes, nil
+}
+
+// updateContainerRuntimes records the detected runtimes in the status
+func (r *AquaEnforcerReconciler) updateContainerRuntimes(cr *operatorv1alpha1.AquaEnforcer, detected []operatorv1alpha1.AquaEnforcerContainerRuntimeStatus) error {
+	if len(detected) == 0 {
+		detected = nil
+	}
+	if reflect.DeepEqual(detected, cr.Status.ContainerRuntimes) {
+		return nil
+	}
+
+	cr.Status.ContainerRuntimes = detected
+	return common.UpdateStatus(r.Client, cr)
+}
+
+// groupNodes returns the nodes the enforcers of the group can run on, its node selector matches them and its
+// tolerations allow their taints
+func (r *AquaEnforcerReconciler) groupNodes(enforcerHelper *AquaEnforcerHelper, group operatorv1alpha1.AquaEnforcerNodeGroup) ([]corev1.Node, error) {
+	nodes := &corev1.NodeList{}
+	err := r.Reader.List(context.TODO(), nodes, client.MatchingLabels(enforcerHelper.nodeSelector(group)))
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
-	cr.Spec.ConfigMapChecksum += hash
 
-	// Set AquaEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, token, r.Scheme); err != nil {
-		return reconcile.Result{}, err
+	tolerations := enforcerHelper.tolerations(group)
+	scheduled := []corev1.Node{}
+	for _, node := range nodes.Items {
+		if tolerated(node.Spec.Taints, tolerations) {
+			scheduled = append(scheduled, node)
+		}
 	}
 
-	// Check if this Secret already exists
-	found := &corev1.Secret{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: token.Name, Namespace: token.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Creating a New Enforcer Token Secret", "Secret.Namespace", token.Namespace, "Secret.Name", token.Name)
-		err = r.Client.Create(context.TODO(), token)
+	return scheduled, nil
+}
+
+// tolerated returns true when the tolerations allow pods on a node with the taints
+func tolerated(taints []corev1.Taint, tolerations []corev1.Toleration) bool {
+	for i := range taints {
+		if taints[i].Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		found := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(&taints[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// syncSecurityContextConstraints creates or updates the SecurityContextConstraints of the security profile on
+// OpenShift, or deletes them once the profile is removed
+func (r *AquaEnforcerReconciler) syncSecurityContextConstraints(cr *operatorv1alpha1.AquaEnforcer) error {
+	reqLogger := log.WithValues("Enforcer Requirements Phase", "Sync SecurityContextConstraints")
+
+	if strings.ToLower(cr.Spec.Infrastructure.Platform) != consts.OpenShiftPlatform || cr.Spec.SecurityProfile == nil {
+		if controllerutil.ContainsFinalizer(cr, consts.AquaEnforcerFinalizer) {
+			return r.removeSecurityContextConstraints(cr)
+		}
+		return nil
+	}
+
+	// only the finalizers are patched, the spec holds the infrastructure version resolved for this reconcile
+	if !controllerutil.ContainsFinalizer(cr, consts.AquaEnforcerFinalizer) {
+		err := k8s.PatchFinalizers(context.TODO(), r.Client, cr,
+			append(append([]string{}, cr.GetFinalizers()...), consts.AquaEnforcerFinalizer))
 		if err != nil {
-			return reconcile.Result{}, err
+			return err
 		}
+	}
 
-		return reconcile.Result{}, nil
+	scc := newAquaEnforcerHelper(cr).CreateSecurityContextConstraints(cr)
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(scc.GroupVersionKind())
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: scc.GetName()}, found)
+	if err != nil && errors.IsNotFound(err) {
+		reqLogger.Info("Aqua Enforcer: Creating a New SecurityContextConstraints", "SecurityContextConstraints.Name", scc.GetName())
+		return r.Client.Create(context.TODO(), scc)
 	} else if err != nil {
+		return err
+	}
+
+	// the constraints are owned by the operator, the fields it sets are replaced
+	desired := found.DeepCopy()
+	changed := false
+	for key, value := range scc.Object {
+		if key == "metadata" || key == "apiVersion" || key == "kind" {
+			continue
+		}
+		if !equality.Semantic.DeepEqual(found.Object[key], value) {
+			desired.Object[key] = value
+			changed = true
+		}
+	}
+	if !equality.Semantic.DeepEqual(found.GetLabels(), scc.GetLabels()) ||
+		!equality.Semantic.DeepEqual(found.GetAnnotations(), scc.GetAnnotations()) {
+		desired.SetLabels(scc.GetLabels())
+		desired.SetAnnotations(scc.GetAnnotations())
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	reqLogger.Info("Aqua Enforcer: Updating the SecurityContextConstraints", "SecurityContextConstraints.Name", scc.GetName())
+	return r.Client.Update(context.TODO(), desired)
+}
+
+// removeSecurityContextConstraints deletes the SecurityContextConstraints of the CR and removes its finalizer
+func (r *AquaEnforcerReconciler) removeSecurityContextConstraints(cr *operatorv1alpha1.AquaEnforcer) error {
+	reqLogger := log.WithValues("Enforcer Finalizer Phase", "Remove SecurityContextConstraints")
+
+	scc := &unstructured.Unstructured{}
+	scc.SetAPIVersion("security.openshift.io/v1")
+	scc.SetKind("SecurityContextConstraints")
+	scc.SetName(fmt.Sprintf(consts.EnforcerSCCName, cr.Namespace, cr.Name))
+	err := r.Client.Delete(context.TODO(), scc)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return err
+	}
+	if err == nil {
+		reqLogger.Info("Aqua Enforcer: Deleted the SecurityContextConstraints", "SecurityContextConstraints.Name", scc.GetName())
+	}
+
+	finalizers := []string{}
+	for _, finalizer := range cr.GetFinalizers() {
+		if finalizer != consts.AquaEnforcerFinalizer {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	return k8s.PatchFinalizers(context.TODO(), r.Client, cr, finalizers)
+}
+
+// removeStaleNodeGroups deletes the enforcer DaemonSets and ConfigMaps of the CR that aren't rendered anymore, the ones
+// of a removed group, or the ungrouped ones once groups are set
+func (r *AquaEnforcerReconciler) removeStaleNodeGroups(cr *operatorv1alpha1.AquaEnforcer, daemonSets []*appsv1.DaemonSet, configMaps []*corev1.ConfigMap) error {
+	reqLogger := log.WithValues("Aqua Enforcer DaemonSet Phase", "Remove Stale Node Groups")
+
+	rendered := map[string]bool{}
+	for _, ds := range daemonSets {
+		rendered["DaemonSet/"+ds.Name] = true
+	}
+	for _, configMap := range configMaps {
+		rendered["ConfigMap/"+configMap.Name] = true
+	}
+
+	foundDaemonSets := &appsv1.DaemonSetList{}
+	err := r.Client.List(context.TODO(), foundDaemonSets, client.InNamespace(cr.Namespace),
+		client.MatchingLabels{"aquasecoperator_cr": cr.Name, "aqua.component": "enforcer"})
+	if err != nil {
+		return err
+	}
+	foundConfigMaps := &corev1.ConfigMapList{}
+	err = r.Client.List(context.TODO(), foundConfigMaps, client.InNamespace(cr.Namespace),
+		client.MatchingLabels{"aquasecoperator_cr": cr.Name, "app": "aqua-csp-enforcer"})
+	if err != nil {
+		return err
+	}
+
+	stale := []client.Object{}
+	for i := range foundDaemonSets.Items {
+		if ds := &foundDaemonSets.Items[i]; !rendered["DaemonSet/"+ds.Name] {
+			stale = append(stale, ds)
+		}
+	}
+	for i := range foundConfigMaps.Items {
+		if configMap := &foundConfigMaps.Items[i]; !rendered["ConfigMap/"+configMap.Name] {
+			stale = append(stale, configMap)
+		}
+	}
+
+	for _, obj := range stale {
+		if !metav1.IsControlledBy(obj, cr) {
+			continue
+		}
+		reqLogger.Info("Aqua Enforcer: Deleting a stale node group object", "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+		err = r.Client.Delete(context.TODO(), obj)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncEnforcerGroupToken writes the token of the enforcer group to the token secret. The Aqua API is called when the
+// secret has no token, the group changed or rotateToken changed, the group is created when it doesn't exist. Only the
+// token of a group the operator created, or one adopt is set for, is rotated
+func (r *AquaEnforcerReconciler) SyncEnforcerGroupToken(cr *operatorv1alpha1.AquaEnforcer) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Enforcer Requirements Phase", "Sync Aqua Enforcer Group Token")
+
+	name := EnforcerGroupName(cr)
+	previous := cr.Status.EnforcerGroup
+	status := &operatorv1alpha1.AquaEnforcerGroupTokenStatus{
+		Name:        name,
+		RotateToken: cr.Spec.EnforcerGroup.RotateToken,
+	}
+	rotate := false
+	if previous != nil && previous.Name == name {
+		status.LastRotation = previous.LastRotation
+		status.Created = previous.Created
+		rotate = previous.RotateToken != status.RotateToken
+	}
+
+	found := &corev1.Secret{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: cr.Spec.Secret.Name, Namespace: cr.Namespace}, found)
+	if err != nil && !errors.IsNotFound(err) {
 		return reconcile.Result{}, err
 	}
+	exists := err == nil
+
+	token := ""
+	if exists && (previous == nil || previous.Name == name) {
+		// the token of another group is replaced
+		token = string(found.Data[cr.Spec.Secret.Key])
+	}
 
-	if !equality.Semantic.DeepDerivative(token.Data, found.Data) {
-		found = token
-		log.Info("Aqua Enforcer: Updating Enforcer Token Secret", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
-		err := r.Client.Update(context.TODO(), found)
+	if len(token) == 0 || previous == nil || previous.Name != name || rotate {
+		api, err := r.newAquaApiClient(cr)
 		if err != nil {
-			log.Error(err, "Failed to update Enforcer Token Secret", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
 			return reconcile.Result{}, err
 		}
 
-		return reconcile.Result{Requeue: true}, nil
+		group, err := api.GetEnforcerGroup(name)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// a group the API returns no token of needs a new one
+		if group != nil && len(group.Token) == 0 && len(token) == 0 {
+			rotate = true
+		}
+
+		// the group may be shared with the enforcers of other clusters, they disconnect when its token changes
+		if group != nil && rotate && !status.Created && !cr.Spec.EnforcerGroup.Adopt {
+			return reconcile.Result{}, fmt.Errorf("the enforcer group %s wasn't created by the operator, set adopt to give it a new token", name)
+		}
+
+		if group == nil || rotate {
+			token, err = newEnforcerToken()
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			now := metav1.Now()
+			status.LastRotation = &now
+
+			if group == nil {
+				reqLogger.Info("Aqua Enforcer: Creating a New Enforcer Group", "EnforcerGroup.Name", name)
+				err = api.CreateEnforcerGroup(newAquaEnforcerHelper(cr).CreateEnforcerGroup(cr, token))
+				status.Created = true
+			} else {
+				reqLogger.Info("Aqua Enforcer: Rotating the Token of the Enforcer Group", "EnforcerGroup.Name", name)
+				group.Token = token
+				err = api.UpdateEnforcerGroup(group)
+			}
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		} else if len(group.Token) != 0 {
+			token = group.Token
+		}
+	}
+
+	if !exists {
+		secret := secrets.CreateSecret(cr.Name,
+			cr.Namespace,
+			fmt.Sprintf("%s-requirments", cr.Name),
+			"Secret for aqua enforcer token",
+			cr.Spec.Secret.Name,
+			cr.Spec.Secret.Key,
+			token)
+		err = controllerutil.SetControllerReference(cr, secret, r.Scheme)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		reqLogger.Info("Aqua Enforcer: Creating a New Token Secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+		err = r.Client.Create(context.TODO(), secret)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		found = secret
+	} else if string(found.Data[cr.Spec.Secret.Key]) != token {
+		if found.Data == nil {
+			found.Data = map[string][]byte{}
+		}
+		found.Data[cr.Spec.Secret.Key] = []byte(token)
+
+		reqLogger.Info("Aqua Enforcer: Updating the Token Secret", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
+		err = r.Client.Update(context.TODO(), found)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if !reflect.DeepEqual(previous, status) {
+		// the spec holds the defaulted token secret of this reconcile
+		cr.Status.EnforcerGroup = status
+		err = common.UpdateStatus(r.Client, cr)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
-	// Secret already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua Enforcer Token Secret Already Exists", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
+	// Adding token to the hashed data, for restart pods if token is changed
+	data := found.Data
+	if data == nil {
+		data = map[string][]byte{cr.Spec.Secret.Key: []byte(token)}
+	}
+	hash, err := extra.GenerateMD5ForSpec(data)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	cr.Spec.ConfigMapChecksum += hash
+
 	return reconcile.Result{}, nil
 }
 
-func (r *AquaEnforcerReconciler) addEnforcerConfigMap(cr *operatorv1alpha1.AquaEnforcer) (reconcile.Result, error) {
+// newAquaApiClient returns a client of the Aqua API the enforcer group is managed through, the API of the AquaServer
+// or the one of the login
+func (r *AquaEnforcerReconciler) newAquaApiClient(cr *operatorv1alpha1.AquaEnforcer) (*aquaapi.Client, error) {
+	group := cr.Spec.EnforcerGroup
+	if group.Login != nil {
+		password := group.Login.Password
+		if group.Login.PasswordSecretRef != nil {
+			value, err := secrets.GetSecretValue(r.Client, cr.Namespace, group.Login.PasswordSecretRef)
+			if err != nil {
+				return nil, err
+			}
+			password = value
+		}
+
+		return aquaapi.NewClient(group.Login.Host, group.Login.Username, password, group.Login.Insecure), nil
+	}
+
+	return common.NewAquaServerApiClient(r.Client, cr.Namespace, group.Server)
+}
+
+// SyncEnforcerToken materializes the enforcer token from the secret store
+func (r *AquaEnforcerReconciler) SyncEnforcerToken(cr *operatorv1alpha1.AquaEnforcer) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Enforcer Requirements Phase", "Sync Aqua Enforcer Token Secret")
+	reqLogger.Info("Start syncing enforcer token secret from secret store")
+
+	storeHelper := common.NewAquaSecretStoreHelper(cr.Spec.Common.SecretStore, cr.Namespace, r.Client, r.Scheme, cr)
+	_, err := storeHelper.SyncSecretKeys(cr.Spec.Secret.Name,
+		cr.Name+"-requirments",
+		"Secret for aqua enforcer token",
+		map[string]string{cr.Spec.Secret.Key: cr.Spec.Common.SecretStore.Keys.EnforcerToken})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Adding token to the hashed data, for restart pods if token is changed
+	found := &corev1.Secret{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: cr.Spec.Secret.Name, Namespace: cr.Namespace}, found)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	hash, err := extra.GenerateMD5ForSpec(found.Data)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	cr.Spec.ConfigMapChecksum += hash
+
+	return reconcile.Result{}, nil
+}
+
+func (r *AquaEnforcerReconciler) addEnforcerConfigMap(cr *operatorv1alpha1.AquaEnforcer, configMap *corev1.ConfigMap) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Enforcer Requirements Phase", "Create ConfigMap")
 	reqLogger.Info("Start creating ConfigMap")
 	//reqLogger.Info(fmt.Sprintf("cr object : %v", cr.ObjectMeta))
 
-	// Define a new ClusterRoleBinding object
-	enforcerHelper := newAquaEnforcerHelper(cr)
-
-	configMap := enforcerHelper.CreateConfigMap(cr)
 	// Adding configmap to the hashed data, for restart pods if token is changed
 	hash, err := extra.GenerateMD5ForSpec(configMap.Data)
 	if err != nil {
@@ -351,12 +1095,14 @@ func (r *AquaEnforcerReconciler) addEnforcerConfigMap(cr *operatorv1alpha1.AquaE
 		return reconcile.Result{}, err
 	}
 
+	apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 	// Check if this ConfigMap already exists
 	foundConfigMap := &corev1.ConfigMap{}
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, foundConfigMap)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Aqua Enforcer: Creating a New ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
-		err = r.Client.Create(context.TODO(), configMap)
+		_, err = apply.Apply("AquaEnforcer configmap", configMap)
 		if err != nil {
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -366,16 +1112,20 @@ func (r *AquaEnforcerReconciler) addEnforcerConfigMap(cr *operatorv1alpha1.AquaE
 		return reconcile.Result{}, err
 	}
 
-	// Check if the ConfigMap Data, matches the found Data
-	if !equality.Semantic.DeepDerivative(configMap.Data, foundConfigMap.Data) {
-		foundConfigMap = configMap
-		log.Info("Aqua Enforcer: Updating ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-		err := r.Client.Update(context.TODO(), foundConfigMap)
-		if err != nil {
-			log.Error(err, "Failed to update ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-			return reconcile.Result{}, err
-		}
+	drift := common.NewAquaDriftHelper(common.GetDriftPolicy(cr.Spec.Common), &cr.Status.Drifts, r.Client, cr)
+	_, err = drift.Reconcile(foundConfigMap, configMap)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
+	// Apply the ConfigMap Data, reverting the manual changes the drift policy enforces
+	update, err := apply.Apply("AquaEnforcer configmap", configMap)
+	if err != nil {
+		log.Error(err, "Failed to apply ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
+		return reconcile.Result{}, err
+	}
+	if update {
+		log.Info("Aqua Enforcer: Applied ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
 		return reconcile.Result{Requeue: true}, nil
 	}
 
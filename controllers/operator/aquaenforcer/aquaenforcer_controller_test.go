@@ -0,0 +1,83 @@
+package aquaenforcer
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SecurityContextConstraints", func() {
+	var (
+		ctx        context.Context
+		reconciler *AquaEnforcerReconciler
+		cr         *operatorv1alpha1.AquaEnforcer
+	)
+
+	stored := func() *operatorv1alpha1.AquaEnforcer {
+		found := &operatorv1alpha1.AquaEnforcer{}
+		Expect(reconciler.Client.Get(ctx, client.ObjectKeyFromObject(cr), found)).To(Succeed())
+		return found
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		cr = &operatorv1alpha1.AquaEnforcer{
+			ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua", Finalizers: []string{"other"}},
+			Spec: operatorv1alpha1.AquaEnforcerSpec{
+				Infrastructure:  &operatorv1alpha1.AquaInfrastructure{Version: "2022.4", Platform: "openshift"},
+				Common:          &operatorv1alpha1.AquaCommon{},
+				SecurityProfile: &operatorv1alpha1.AquaEnforcerSecurityProfile{Type: "restricted"},
+			},
+		}
+		k8sclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr).Build()
+		Expect(k8sclient.Get(ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+		reconciler = &AquaEnforcerReconciler{Client: k8sclient, Scheme: scheme, Reader: k8sclient}
+	})
+
+	It("adds the finalizer without writing the resolved version", func() {
+		// resolved in memory for the reconcile, an upgrade step for example
+		cr.Spec.Infrastructure.Version = "2022.4.5"
+		Expect(reconciler.syncSecurityContextConstraints(cr)).To(Succeed())
+
+		found := stored()
+		Expect(found.Finalizers).To(Equal([]string{"other", consts.AquaEnforcerFinalizer}))
+		Expect(found.Spec.Infrastructure.Version).To(Equal("2022.4"))
+		Expect(cr.Finalizers).To(Equal(found.Finalizers))
+		Expect(cr.ResourceVersion).To(Equal(found.ResourceVersion))
+		Expect(cr.Spec.Infrastructure.Version).To(Equal("2022.4.5"))
+
+		scc := &unstructured.Unstructured{}
+		scc.SetAPIVersion("security.openshift.io/v1")
+		scc.SetKind("SecurityContextConstraints")
+		Expect(reconciler.Client.Get(ctx, client.ObjectKey{Name: fmt.Sprintf(consts.EnforcerSCCName, cr.Namespace, cr.Name)}, scc)).To(Succeed())
+	})
+
+	It("removes the finalizer without writing the resolved version once the profile is removed", func() {
+		Expect(reconciler.syncSecurityContextConstraints(cr)).To(Succeed())
+
+		cr.Spec.SecurityProfile = nil
+		cr.Spec.Infrastructure.Version = "2022.4.5"
+		Expect(reconciler.syncSecurityContextConstraints(cr)).To(Succeed())
+
+		found := stored()
+		Expect(found.Finalizers).To(Equal([]string{"other"}))
+		Expect(found.Spec.SecurityProfile).NotTo(BeNil())
+		Expect(found.Spec.Infrastructure.Version).To(Equal("2022.4"))
+		Expect(cr.Finalizers).To(Equal([]string{"other"}))
+	})
+})
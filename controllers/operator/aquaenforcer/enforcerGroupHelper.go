@@ -0,0 +1,72 @@
+package aquaenforcer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/aquaapi"
+)
+
+// ValidateEnforcerGroup checks the enforcer group is reached through an AquaServer or a login, not both
+func (enf *AquaEnforcerHelper) ValidateEnforcerGroup() error {
+	group := enf.Parameters.Enforcer.Spec.EnforcerGroup
+	if group == nil {
+		return nil
+	}
+
+	if len(group.Server) == 0 && group.Login == nil {
+		return fmt.Errorf("the enforcer group needs the server or the login of the Aqua API")
+	}
+	if len(group.Server) != 0 && group.Login != nil {
+		return fmt.Errorf("the enforcer group sets both the server %s and a login", group.Server)
+	}
+	if group.Login != nil && group.Login.PasswordSecretRef == nil && len(group.Login.Password) == 0 {
+		return fmt.Errorf("the login of the enforcer group has no password")
+	}
+
+	return nil
+}
+
+// EnforcerGroupName returns the ID of the enforcer group, the name of the CR when the spec doesn't set one
+func EnforcerGroupName(cr *v1alpha1.AquaEnforcer) string {
+	if len(cr.Spec.EnforcerGroup.Name) != 0 {
+		return cr.Spec.EnforcerGroup.Name
+	}
+
+	return cr.Name
+}
+
+// CreateEnforcerGroup returns the enforcer group of the enforcers of the CR with the token
+func (enf *AquaEnforcerHelper) CreateEnforcerGroup(cr *v1alpha1.AquaEnforcer, token string) *aquaapi.EnforcerGroup {
+	orchestrator := "kubernetes"
+	if strings.ToLower(cr.Spec.Infrastructure.Platform) == consts.OpenShiftPlatform {
+		orchestrator = "openshift"
+	}
+
+	return &aquaapi.EnforcerGroup{
+		ID:          EnforcerGroupName(cr),
+		Description: fmt.Sprintf("Enforcers of the AquaEnforcer %s/%s, created by the aqua operator", cr.Namespace, cr.Name),
+		Type:        "agent",
+		Token:       token,
+		Orchestrator: &aquaapi.EnforcerGroupOrchestrator{
+			Type:           orchestrator,
+			Namespace:      cr.Namespace,
+			ServiceAccount: cr.Spec.Infrastructure.ServiceAccount,
+		},
+	}
+}
+
+// newEnforcerToken returns a random token for an enforcer group
+func newEnforcerToken() (string, error) {
+	token := make([]byte, 16)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,192 @@
+package aquaenforcer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/aquaapi"
+	testutils "github.com/aquasecurity/aqua-operator/test/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeAquaApi Serves the enforcer groups of the Aqua API
+type fakeAquaApi struct {
+	mutex  sync.Mutex
+	groups map[string]*aquaapi.EnforcerGroup
+}
+
+func (a *fakeAquaApi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/hostsbatch/")
+	switch {
+	case r.URL.Path == "/api/v1/login":
+		_, _ = w.Write([]byte(`{"token":"token"}`))
+	case r.Method == http.MethodGet && id != r.URL.Path:
+		group, ok := a.groups[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(group)
+	case r.Method == http.MethodPost || r.Method == http.MethodPut:
+		group := &aquaapi.EnforcerGroup{}
+		_ = json.NewDecoder(r.Body).Decode(group)
+		a.groups[group.ID] = group
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (a *fakeAquaApi) token(id string) string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if group, ok := a.groups[id]; ok {
+		return group.Token
+	}
+	return ""
+}
+
+var _ = Describe("Enforcer group token", func() {
+	var (
+		ctx        context.Context
+		api        *fakeAquaApi
+		reconciler *AquaEnforcerReconciler
+		cr         *operatorv1alpha1.AquaEnforcer
+	)
+
+	// syncToken defaults the token secret like the reconcile and syncs the token of the group
+	syncToken := func() error {
+		if cr.Spec.Secret == nil {
+			cr.Spec.Secret = &operatorv1alpha1.AquaSecret{
+				Name: fmt.Sprintf(consts.EnforcerTokenSecretName, cr.Name),
+				Key:  consts.EnforcerTokenSecretKey,
+			}
+		}
+		_, err := reconciler.SyncEnforcerGroupToken(cr)
+		return err
+	}
+
+	storedToken := func() string {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Name: fmt.Sprintf(consts.EnforcerTokenSecretName, cr.Name), Namespace: cr.Namespace}
+		Expect(reconciler.Client.Get(ctx, key, secret)).To(Succeed())
+		return string(secret.Data[consts.EnforcerTokenSecretKey])
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		api = &fakeAquaApi{groups: map[string]*aquaapi.EnforcerGroup{}}
+		server := httptest.NewServer(api)
+		DeferCleanup(server.Close)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		cr = &operatorv1alpha1.AquaEnforcer{
+			ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua"},
+			Spec: operatorv1alpha1.AquaEnforcerSpec{
+				Infrastructure: &operatorv1alpha1.AquaInfrastructure{Platform: "kubernetes"},
+				Common:         &operatorv1alpha1.AquaCommon{},
+				EnforcerGroup: &operatorv1alpha1.AquaEnforcerGroupToken{
+					Login: &operatorv1alpha1.AquaLogin{Host: server.URL, Username: "administrator", Password: "secret"},
+				},
+			},
+		}
+		k8sclient := testutils.StatusClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr).Build()}
+		Expect(k8sclient.Get(ctx, client.ObjectKeyFromObject(cr), cr)).To(Succeed())
+		reconciler = &AquaEnforcerReconciler{Client: k8sclient, Scheme: scheme, Reader: k8sclient}
+	})
+
+	It("keeps the defaulted token secret of the reconcile through the status update", func() {
+		Expect(syncToken()).To(Succeed())
+
+		Expect(cr.Spec.Secret).To(Equal(&operatorv1alpha1.AquaSecret{
+			Name: fmt.Sprintf(consts.EnforcerTokenSecretName, cr.Name),
+			Key:  consts.EnforcerTokenSecretKey,
+		}))
+		Expect(cr.Status.EnforcerGroup.Name).To(Equal("aqua"))
+		Expect(cr.Status.EnforcerGroup.Created).To(BeTrue())
+		Expect(storedToken()).To(Equal(api.token("aqua")))
+		Expect(newAquaEnforcerHelper(cr).getEnvVars(cr)).NotTo(BeEmpty())
+
+		stored := &operatorv1alpha1.AquaEnforcer{}
+		Expect(reconciler.Client.Get(ctx, client.ObjectKeyFromObject(cr), stored)).To(Succeed())
+		Expect(stored.Spec.Secret).To(BeNil())
+		Expect(stored.Status.EnforcerGroup.Name).To(Equal("aqua"))
+	})
+
+	It("keeps the defaulted token secret when the token is rotated", func() {
+		Expect(syncToken()).To(Succeed())
+		created := api.token("aqua")
+
+		cr.Spec.Secret = nil
+		cr.Spec.EnforcerGroup.RotateToken = "2022-06-01"
+		Expect(syncToken()).To(Succeed())
+
+		Expect(cr.Spec.Secret).NotTo(BeNil())
+		Expect(api.token("aqua")).NotTo(Equal(created))
+		Expect(storedToken()).To(Equal(api.token("aqua")))
+		Expect(cr.Status.EnforcerGroup.RotateToken).To(Equal("2022-06-01"))
+		Expect(cr.Status.EnforcerGroup.LastRotation).NotTo(BeNil())
+	})
+
+	Describe("an existing group", func() {
+		BeforeEach(func() {
+			api.groups["aqua"] = &aquaapi.EnforcerGroup{ID: "aqua", Type: "agent", Token: "shared"}
+		})
+
+		It("uses its token", func() {
+			Expect(syncToken()).To(Succeed())
+
+			Expect(storedToken()).To(Equal("shared"))
+			Expect(cr.Status.EnforcerGroup.Created).To(BeFalse())
+		})
+
+		It("keeps its token on rotation unless it is adopted", func() {
+			Expect(syncToken()).To(Succeed())
+
+			cr.Spec.EnforcerGroup.RotateToken = "2022-06-01"
+			Expect(syncToken()).To(MatchError("the enforcer group aqua wasn't created by the operator, set adopt to give it a new token"))
+			Expect(api.token("aqua")).To(Equal("shared"))
+			Expect(storedToken()).To(Equal("shared"))
+
+			cr.Spec.EnforcerGroup.Adopt = true
+			Expect(syncToken()).To(Succeed())
+			Expect(api.token("aqua")).NotTo(Equal("shared"))
+			Expect(storedToken()).To(Equal(api.token("aqua")))
+			Expect(cr.Status.EnforcerGroup.Created).To(BeFalse())
+		})
+
+		It("is given a token when the API returns none only if it is adopted", func() {
+			api.groups["aqua"].Token = ""
+
+			Expect(syncToken()).To(MatchError("the enforcer group aqua wasn't created by the operator, set adopt to give it a new token"))
+			Expect(api.token("aqua")).To(BeEmpty())
+
+			cr.Spec.EnforcerGroup.Adopt = true
+			Expect(syncToken()).To(Succeed())
+			Expect(api.token("aqua")).NotTo(BeEmpty())
+			Expect(storedToken()).To(Equal(api.token("aqua")))
+		})
+	})
+})
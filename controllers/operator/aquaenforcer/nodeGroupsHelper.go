@@ -0,0 +1,147 @@
+package aquaenforcer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NodeGroups returns the node groups of the CR, a single unnamed group rendering the <name>-agent DaemonSet and the
+// aqua-csp-enforcer ConfigMap when it has none
+func (enf *AquaEnforcerHelper) NodeGroups() []v1alpha1.AquaEnforcerNodeGroup {
+	groups := enf.Parameters.Enforcer.Spec.NodeGroups
+	if len(groups) == 0 {
+		return []v1alpha1.AquaEnforcerNodeGroup{{}}
+	}
+
+	return groups
+}
+
+// ValidateNodeGroups checks the names of the groups are unique and no node can be selected by two groups. The
+// selections of two groups are disjoint when they require different values for the same label
+func (enf *AquaEnforcerHelper) ValidateNodeGroups() error {
+	cr := enf.Parameters.Enforcer
+	names := map[string]bool{}
+	for _, group := range cr.Spec.NodeGroups {
+		if group.Name == "" {
+			return fmt.Errorf("node group without a name")
+		}
+		if names[group.Name] {
+			return fmt.Errorf("node group %s is defined twice", group.Name)
+		}
+		names[group.Name] = true
+		if len(group.NodeSelector) == 0 {
+			return fmt.Errorf("node group %s has no node selector", group.Name)
+		}
+	}
+
+	for i, a := range cr.Spec.NodeGroups {
+		for _, b := range cr.Spec.NodeGroups[i+1:] {
+			if !disjoint(enf.nodeSelector(a), enf.nodeSelector(b)) {
+				return fmt.Errorf("node groups %s and %s can select the same nodes, their node selectors must require different values for a label", a.Name, b.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CreateGroupConfigMap returns the ConfigMap of the group, the settings of the group replacing the ones of the spec
+func (enf *AquaEnforcerHelper) CreateGroupConfigMap(cr *v1alpha1.AquaEnforcer, group v1alpha1.AquaEnforcerNodeGroup) *corev1.ConfigMap {
+	configMap := enf.CreateConfigMap(cr)
+	if group.Name == "" {
+		return configMap
+	}
+
+	configMap.Name = fmt.Sprintf(consts.EnforcerGroupConfigMapName, group.Name)
+	configMap.Labels[consts.EnforcerGroupLabel] = group.Name
+
+	if group.Gateway != nil {
+		configMap.Data["AQUA_SERVER"] = fmt.Sprintf("%s:%d", group.Gateway.Host, group.Gateway.Port)
+	}
+	if len(group.LogicalName) != 0 {
+		configMap.Data["AQUA_LOGICAL_NAME"] = group.LogicalName
+	}
+	if group.AquaExpressMode != nil {
+		configMap.Data["AQUA_EXPRESS_MODE"] = strconv.FormatBool(*group.AquaExpressMode)
+	}
+	if len(group.RhcosVersion) != 0 {
+		configMap.Data["AQUA_OCP_FULL_VERSION"] = group.RhcosVersion
+	} else if version := enf.Parameters.RhcosVersions[group.Name]; len(version) != 0 && len(cr.Spec.RhcosVersion) == 0 {
+		configMap.Data["AQUA_OCP_FULL_VERSION"] = version
+	}
+	for key, value := range group.ConfigData {
+		configMap.Data[key] = value
+	}
+
+	return configMap
+}
+
+// CreateGroupDaemonSet returns the DaemonSet of the group, running on the nodes of the group only
+func (enf *AquaEnforcerHelper) CreateGroupDaemonSet(cr *v1alpha1.AquaEnforcer, group v1alpha1.AquaEnforcerNodeGroup) *appsv1.DaemonSet {
+	ds := enf.CreateDaemonSet(cr)
+	if group.Name == "" {
+		return ds
+	}
+
+	name := fmt.Sprintf(consts.EnforcerGroupDaemonSetName, cr.Name, group.Name)
+	ds.Name = name
+	ds.Spec.Template.Name = name
+
+	// the selector tells the groups apart
+	ds.Labels[consts.EnforcerGroupLabel] = group.Name
+	ds.Spec.Template.Labels[consts.EnforcerGroupLabel] = group.Name
+	ds.Spec.Selector.MatchLabels[consts.EnforcerGroupLabel] = group.Name
+
+	ds.Spec.Template.Spec.Containers[0].EnvFrom[0].ConfigMapRef.Name = fmt.Sprintf(consts.EnforcerGroupConfigMapName, group.Name)
+	ds.Spec.Template.Spec.NodeSelector = enf.nodeSelector(group)
+
+	ds.Spec.Template.Spec.Tolerations = enf.tolerations(group)
+	if group.Resources != nil {
+		ds.Spec.Template.Spec.Containers[0].Resources = *group.Resources
+	}
+
+	return ds
+}
+
+// nodeSelector returns the node selector of the deploy settings with the one of the group added
+func (enf *AquaEnforcerHelper) nodeSelector(group v1alpha1.AquaEnforcerNodeGroup) map[string]string {
+	selector := map[string]string{}
+	if service := enf.Parameters.Enforcer.Spec.EnforcerService; service != nil {
+		for key, value := range service.NodeSelector {
+			selector[key] = value
+		}
+	}
+	for key, value := range group.NodeSelector {
+		selector[key] = value
+	}
+
+	return selector
+}
+
+// tolerations returns the tolerations of the group, the ones of the deploy settings when it has none
+func (enf *AquaEnforcerHelper) tolerations(group v1alpha1.AquaEnforcerNodeGroup) []corev1.Toleration {
+	if group.Tolerations != nil {
+		return group.Tolerations
+	}
+	if service := enf.Parameters.Enforcer.Spec.EnforcerService; service != nil {
+		return service.Tolerations
+	}
+
+	return nil
+}
+
+// disjoint returns true when no node can match both selectors
+func disjoint(a, b map[string]string) bool {
+	for key, value := range a {
+		if other, ok := b[key]; ok && other != value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,215 @@
+package aquaenforcer
+
+import (
+	"fmt"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func nodeGroupsEnforcer(nodeSelector map[string]string, groups ...operatorv1alpha1.AquaEnforcerNodeGroup) *operatorv1alpha1.AquaEnforcer {
+	return &operatorv1alpha1.AquaEnforcer{
+		ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua"},
+		Spec: operatorv1alpha1.AquaEnforcerSpec{
+			Infrastructure: &operatorv1alpha1.AquaInfrastructure{Version: "2022.4", Platform: "kubernetes"},
+			Common:         &operatorv1alpha1.AquaCommon{},
+			EnforcerService: &operatorv1alpha1.AquaService{
+				NodeSelector: nodeSelector,
+				Tolerations:  []corev1.Toleration{{Key: "deploy", Operator: corev1.TolerationOpExists}},
+			},
+			Gateway:    &operatorv1alpha1.AquaGatewayInformation{Host: "aqua-gateway", Port: 8443},
+			Secret:     &operatorv1alpha1.AquaSecret{Name: "enforcer-token", Key: "token"},
+			NodeGroups: groups,
+		},
+	}
+}
+
+func nodeGroup(name string, nodeSelector map[string]string) operatorv1alpha1.AquaEnforcerNodeGroup {
+	return operatorv1alpha1.AquaEnforcerNodeGroup{Name: name, NodeSelector: nodeSelector}
+}
+
+var _ = Describe("Node groups", func() {
+	DescribeTable("ValidateNodeGroups",
+		func(nodeSelector map[string]string, groups []operatorv1alpha1.AquaEnforcerNodeGroup, expected string) {
+			err := newAquaEnforcerHelper(nodeGroupsEnforcer(nodeSelector, groups...)).ValidateNodeGroups()
+			if len(expected) == 0 {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expected))
+			}
+		},
+		Entry("no groups", nil, nil, ""),
+		Entry("different values of a label",
+			nil,
+			[]operatorv1alpha1.AquaEnforcerNodeGroup{
+				nodeGroup("a", map[string]string{"zone": "a"}),
+				nodeGroup("b", map[string]string{"zone": "b", "pool": "gpu"}),
+			},
+			""),
+		Entry("different labels",
+			nil,
+			[]operatorv1alpha1.AquaEnforcerNodeGroup{
+				nodeGroup("a", map[string]string{"zone": "a"}),
+				nodeGroup("gpu", map[string]string{"pool": "gpu"}),
+			},
+			"node groups a and gpu can select the same nodes, their node selectors must require different values for a label"),
+		Entry("the same selector",
+			nil,
+			[]operatorv1alpha1.AquaEnforcerNodeGroup{
+				nodeGroup("a", map[string]string{"zone": "a"}),
+				nodeGroup("b", map[string]string{"zone": "b"}),
+				nodeGroup("c", map[string]string{"zone": "a"}),
+			},
+			"node groups a and c can select the same nodes, their node selectors must require different values for a label"),
+		Entry("the node selector of deploy added to the groups",
+			map[string]string{"os": "linux"},
+			[]operatorv1alpha1.AquaEnforcerNodeGroup{
+				nodeGroup("a", map[string]string{"zone": "a"}),
+				nodeGroup("gpu", map[string]string{"pool": "gpu"}),
+			},
+			"node groups a and gpu can select the same nodes, their node selectors must require different values for a label"),
+		Entry("a group replacing a label of the node selector of deploy",
+			map[string]string{"zone": "a"},
+			[]operatorv1alpha1.AquaEnforcerNodeGroup{
+				nodeGroup("b", map[string]string{"zone": "b"}),
+				nodeGroup("gpu", map[string]string{"pool": "gpu"}),
+			},
+			""),
+		Entry("a group without a name",
+			nil,
+			[]operatorv1alpha1.AquaEnforcerNodeGroup{nodeGroup("", map[string]string{"zone": "a"})},
+			"node group without a name"),
+		Entry("a group defined twice",
+			nil,
+			[]operatorv1alpha1.AquaEnforcerNodeGroup{
+				nodeGroup("a", map[string]string{"zone": "a"}),
+				nodeGroup("a", map[string]string{"zone": "b"}),
+			},
+			"node group a is defined twice"),
+		Entry("a group without a node selector",
+			nil,
+			[]operatorv1alpha1.AquaEnforcerNodeGroup{nodeGroup("a", nil)},
+			"node group a has no node selector"),
+	)
+
+	DescribeTable("disjoint",
+		func(a, b map[string]string, expected bool) {
+			Expect(disjoint(a, b)).To(Equal(expected))
+			Expect(disjoint(b, a)).To(Equal(expected))
+		},
+		Entry("different values", map[string]string{"zone": "a"}, map[string]string{"zone": "b"}, true),
+		Entry("different values of one of the labels", map[string]string{"zone": "a", "os": "linux"}, map[string]string{"zone": "b", "os": "linux"}, true),
+		Entry("the same values", map[string]string{"zone": "a"}, map[string]string{"zone": "a"}, false),
+		Entry("different labels", map[string]string{"zone": "a"}, map[string]string{"pool": "gpu"}, false),
+		Entry("an empty selector", map[string]string{"zone": "a"}, map[string]string{}, false),
+	)
+
+	Describe("CreateGroupDaemonSet", func() {
+		It("renders the DaemonSet of the CR for the unnamed group", func() {
+			cr := nodeGroupsEnforcer(map[string]string{"os": "linux"})
+			helper := newAquaEnforcerHelper(cr)
+
+			Expect(helper.CreateGroupDaemonSet(cr, helper.NodeGroups()[0])).To(Equal(helper.CreateDaemonSet(cr)))
+		})
+
+		It("selects the nodes of the group with its settings", func() {
+			resources := &corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}}
+			group := nodeGroup("gpu", map[string]string{"pool": "gpu"})
+			group.Tolerations = []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}}
+			group.Resources = resources
+			cr := nodeGroupsEnforcer(map[string]string{"os": "linux", "pool": "default"}, group)
+
+			ds := newAquaEnforcerHelper(cr).CreateGroupDaemonSet(cr, group)
+			Expect(ds.Name).To(Equal("aqua-gpu-agent"))
+			Expect(ds.Spec.Template.Name).To(Equal("aqua-gpu-agent"))
+			Expect(ds.Labels).To(HaveKeyWithValue(consts.EnforcerGroupLabel, "gpu"))
+			Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue(consts.EnforcerGroupLabel, "gpu"))
+			Expect(ds.Spec.Selector.MatchLabels).To(HaveKeyWithValue(consts.EnforcerGroupLabel, "gpu"))
+			Expect(ds.Spec.Template.Spec.Containers[0].EnvFrom[0].ConfigMapRef.Name).To(Equal("aqua-csp-enforcer-gpu"))
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"os": "linux", "pool": "gpu"}))
+			Expect(ds.Spec.Template.Spec.Tolerations).To(Equal(group.Tolerations))
+			Expect(ds.Spec.Template.Spec.Containers[0].Resources).To(Equal(*resources))
+
+			// the node selector of deploy is kept
+			Expect(cr.Spec.EnforcerService.NodeSelector).To(Equal(map[string]string{"os": "linux", "pool": "default"}))
+		})
+
+		DescribeTable("tolerations",
+			func(tolerations []corev1.Toleration, expected []corev1.Toleration) {
+				group := nodeGroup("gpu", map[string]string{"pool": "gpu"})
+				group.Tolerations = tolerations
+				cr := nodeGroupsEnforcer(nil, group)
+
+				Expect(newAquaEnforcerHelper(cr).CreateGroupDaemonSet(cr, group).Spec.Template.Spec.Tolerations).To(Equal(expected))
+			},
+			Entry("the ones of deploy when the group has none", nil,
+				[]corev1.Toleration{{Key: "deploy", Operator: corev1.TolerationOpExists}}),
+			Entry("none when the group clears them", []corev1.Toleration{}, []corev1.Toleration{}),
+			Entry("the ones of the group",
+				[]corev1.Toleration{{Key: "group", Operator: corev1.TolerationOpExists}},
+				[]corev1.Toleration{{Key: "group", Operator: corev1.TolerationOpExists}}),
+		)
+	})
+
+	Describe("CreateGroupConfigMap", func() {
+		It("renders the ConfigMap of the CR for the unnamed group", func() {
+			cr := nodeGroupsEnforcer(nil)
+			helper := newAquaEnforcerHelper(cr)
+
+			Expect(helper.CreateGroupConfigMap(cr, helper.NodeGroups()[0])).To(Equal(helper.CreateConfigMap(cr)))
+		})
+
+		It("replaces the settings of the spec with the ones of the group", func() {
+			express := true
+			group := nodeGroup("edge", map[string]string{"zone": "edge"})
+			group.Gateway = &operatorv1alpha1.AquaGatewayInformation{Host: "edge-gateway", Port: 3622}
+			group.LogicalName = "edge"
+			group.AquaExpressMode = &express
+			group.ConfigData = map[string]string{"AQUA_ENFORCE_MODE": "true", "AQUA_LOGICAL_NAME": "edge-override"}
+			cr := nodeGroupsEnforcer(nil, group)
+
+			configMap := newAquaEnforcerHelper(cr).CreateGroupConfigMap(cr, group)
+			Expect(configMap.Name).To(Equal(fmt.Sprintf(consts.EnforcerGroupConfigMapName, "edge")))
+			Expect(configMap.Labels).To(HaveKeyWithValue(consts.EnforcerGroupLabel, "edge"))
+			Expect(configMap.Data).To(HaveKeyWithValue("AQUA_SERVER", "edge-gateway:3622"))
+			Expect(configMap.Data).To(HaveKeyWithValue("AQUA_EXPRESS_MODE", "true"))
+			Expect(configMap.Data).To(HaveKeyWithValue("AQUA_ENFORCE_MODE", "true"))
+			Expect(configMap.Data).To(HaveKeyWithValue("AQUA_LOGICAL_NAME", "edge-override"))
+		})
+
+		DescribeTable("AQUA_OCP_FULL_VERSION",
+			func(specVersion, groupVersion string, detected map[string]string, configData map[string]string, expected string) {
+				group := nodeGroup("edge", map[string]string{"zone": "edge"})
+				group.RhcosVersion = groupVersion
+				group.ConfigData = configData
+				cr := nodeGroupsEnforcer(nil, group)
+				cr.Spec.RhcosVersion = specVersion
+				helper := newAquaEnforcerHelper(cr)
+				helper.Parameters.RhcosVersions = detected
+
+				configMap := helper.CreateGroupConfigMap(cr, group)
+				if len(expected) == 0 {
+					Expect(configMap.Data).NotTo(HaveKey("AQUA_OCP_FULL_VERSION"))
+				} else {
+					Expect(configMap.Data).To(HaveKeyWithValue("AQUA_OCP_FULL_VERSION", expected))
+				}
+			},
+			Entry("none", "", "", nil, nil, ""),
+			Entry("the detected version of the group", "", "",
+				map[string]string{"edge": "4.10.3", "": "4.9.1"}, nil, "4.10.3"),
+			Entry("the version of the group over the detected one", "", "4.11.0",
+				map[string]string{"edge": "4.10.3"}, nil, "4.11.0"),
+			Entry("the version of the spec over the detected one", "4.8.0", "",
+				map[string]string{"edge": "4.10.3"}, nil, "4.8.0"),
+			Entry("the version of the group over the one of the spec", "4.8.0", "4.11.0", nil, nil, "4.11.0"),
+			Entry("configData over the version of the group", "", "4.11.0",
+				map[string]string{"edge": "4.10.3"}, map[string]string{"AQUA_OCP_FULL_VERSION": "4.12.0"}, "4.12.0"),
+		)
+	})
+})
@@ -0,0 +1,106 @@
+package aquaenforcer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// UnknownRuntime The runtime of the nodes running none of the known runtimes, their enforcers keep the default mounts
+const UnknownRuntime = "unknown"
+
+// runtimeSockets The default socket paths of the known runtimes
+var runtimeSockets = map[string]string{
+	"containerd": "/run/containerd/containerd.sock",
+	"cri-o":      "/var/run/crio/crio.sock",
+	"docker":     "/var/run/docker.sock",
+}
+
+// NodeRuntime returns the container runtime of the node from its containerRuntimeVersion, e.g. containerd://1.6.6
+func NodeRuntime(node *corev1.Node) string {
+	runtime := strings.SplitN(node.Status.NodeInfo.ContainerRuntimeVersion, "://", 2)[0]
+	if _, ok := runtimeSockets[runtime]; !ok {
+		return UnknownRuntime
+	}
+
+	return runtime
+}
+
+// SetContainerRuntime renders the DaemonSet of a group for the nodes running the runtime: it mounts the socket of the
+// runtime and adds its env. When the nodes of the group run several runtimes, mixed is true and the DaemonSet only
+// runs on the nodes labeled with the runtime
+func (enf *AquaEnforcerHelper) SetContainerRuntime(ds *appsv1.DaemonSet, runtime string, mixed bool) {
+	if mixed {
+		ds.Name = fmt.Sprintf(consts.EnforcerRuntimeDaemonSetName, ds.Name, strings.ReplaceAll(runtime, "-", ""))
+		ds.Spec.Template.Name = ds.Name
+
+		// the selector tells the runtimes apart
+		ds.Labels[consts.EnforcerRuntimeLabel] = runtime
+		ds.Spec.Template.Labels[consts.EnforcerRuntimeLabel] = runtime
+		ds.Spec.Selector.MatchLabels[consts.EnforcerRuntimeLabel] = runtime
+
+		// the node selector can be the one of the spec, it is copied before adding the runtime
+		nodeSelector := map[string]string{consts.ContainerRuntimeNodeLabel: runtime}
+		for key, value := range ds.Spec.Template.Spec.NodeSelector {
+			nodeSelector[key] = value
+		}
+		ds.Spec.Template.Spec.NodeSelector = nodeSelector
+	}
+
+	socket, ok := runtimeSockets[runtime]
+	if !ok {
+		return
+	}
+	env := []corev1.EnvVar{}
+	for _, settings := range enf.Parameters.Enforcer.Spec.ContainerRuntime.Runtimes {
+		if settings.Name == runtime {
+			if len(settings.SocketPath) != 0 {
+				socket = settings.SocketPath
+			}
+			env = settings.Env
+		}
+	}
+
+	// the var-run volume mounts /var/run of the host, a link to /run on most hosts
+	socket = path.Clean(socket)
+	mountPath := socket
+	if strings.HasPrefix(socket, "/run/") {
+		mountPath = "/var" + socket
+	}
+
+	// the endpoint is a default, env of the spec can set another one
+	container := &ds.Spec.Template.Spec.Containers[0]
+	endpoint := corev1.EnvVar{Name: "CONTAINER_RUNTIME_ENDPOINT", Value: "unix://" + mountPath}
+	for _, e := range container.Env {
+		if e.Name == endpoint.Name {
+			endpoint = e
+		}
+	}
+	container.Env = extra.AppendEnvVar(container.Env, endpoint)
+	for _, e := range env {
+		container.Env = extra.AppendEnvVar(container.Env, e)
+	}
+
+	if strings.HasPrefix(mountPath, "/var/run/") {
+		return
+	}
+	socketType := corev1.HostPathSocket
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "runtime-socket",
+		MountPath: mountPath,
+	})
+	ds.Spec.Template.Spec.Volumes = append(ds.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "runtime-socket",
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: socket,
+				Type: &socketType,
+			},
+		},
+	})
+}
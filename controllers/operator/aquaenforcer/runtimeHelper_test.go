@@ -0,0 +1,164 @@
+package aquaenforcer
+
+import (
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func runtimeEnforcer(runtimes ...operatorv1alpha1.AquaEnforcerRuntimeSettings) *operatorv1alpha1.AquaEnforcer {
+	cr := nodeGroupsEnforcer(map[string]string{"os": "linux"})
+	cr.Spec.ContainerRuntime = &operatorv1alpha1.AquaEnforcerContainerRuntime{Runtimes: runtimes}
+	return cr
+}
+
+func envValue(env []corev1.EnvVar, name string) string {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func runtimeSocketVolume(volumes []corev1.Volume) *corev1.Volume {
+	for i := range volumes {
+		if volumes[i].Name == "runtime-socket" {
+			return &volumes[i]
+		}
+	}
+	return nil
+}
+
+var _ = Describe("Container runtimes", func() {
+	DescribeTable("NodeRuntime",
+		func(version, expected string) {
+			node := &corev1.Node{Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{ContainerRuntimeVersion: version}}}
+			Expect(NodeRuntime(node)).To(Equal(expected))
+		},
+		Entry("containerd", "containerd://1.6.6", "containerd"),
+		Entry("cri-o", "cri-o://1.24.1", "cri-o"),
+		Entry("docker", "docker://20.10.17", "docker"),
+		Entry("another runtime", "rkt://1.30.0", UnknownRuntime),
+		Entry("no version", "", UnknownRuntime),
+	)
+
+	Describe("SetContainerRuntime", func() {
+		It("mounts a socket of /run through the var-run volume", func() {
+			cr := runtimeEnforcer()
+			helper := newAquaEnforcerHelper(cr)
+			ds := helper.CreateDaemonSet(cr)
+			volumes := len(ds.Spec.Template.Spec.Volumes)
+
+			helper.SetContainerRuntime(ds, "containerd", false)
+
+			Expect(envValue(ds.Spec.Template.Spec.Containers[0].Env, "CONTAINER_RUNTIME_ENDPOINT")).To(Equal("unix:///var/run/containerd/containerd.sock"))
+			Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(volumes))
+			Expect(ds.Name).To(Equal(helper.CreateDaemonSet(cr).Name))
+		})
+
+		It("mounts a socket out of /var/run with its own volume", func() {
+			cr := runtimeEnforcer(operatorv1alpha1.AquaEnforcerRuntimeSettings{Name: "containerd", SocketPath: "/var/lib/k0s/run/containerd.sock"})
+			helper := newAquaEnforcerHelper(cr)
+			ds := helper.CreateDaemonSet(cr)
+
+			helper.SetContainerRuntime(ds, "containerd", false)
+
+			container := ds.Spec.Template.Spec.Containers[0]
+			Expect(envValue(container.Env, "CONTAINER_RUNTIME_ENDPOINT")).To(Equal("unix:///var/lib/k0s/run/containerd.sock"))
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "runtime-socket", MountPath: "/var/lib/k0s/run/containerd.sock"}))
+			volume := runtimeSocketVolume(ds.Spec.Template.Spec.Volumes)
+			Expect(volume).NotTo(BeNil())
+			Expect(volume.HostPath.Path).To(Equal("/var/lib/k0s/run/containerd.sock"))
+			Expect(*volume.HostPath.Type).To(Equal(corev1.HostPathSocket))
+		})
+
+		It("keeps the endpoint set by the env of the spec", func() {
+			cr := runtimeEnforcer()
+			cr.Spec.Envs = []corev1.EnvVar{{Name: "CONTAINER_RUNTIME_ENDPOINT", Value: "unix:///custom/crio.sock"}}
+			helper := newAquaEnforcerHelper(cr)
+			ds := helper.CreateDaemonSet(cr)
+
+			helper.SetContainerRuntime(ds, "cri-o", false)
+
+			Expect(envValue(ds.Spec.Template.Spec.Containers[0].Env, "CONTAINER_RUNTIME_ENDPOINT")).To(Equal("unix:///custom/crio.sock"))
+			Expect(runtimeSocketVolume(ds.Spec.Template.Spec.Volumes)).To(BeNil())
+		})
+
+		It("adds the env of the runtime settings", func() {
+			cr := runtimeEnforcer(operatorv1alpha1.AquaEnforcerRuntimeSettings{
+				Name: "docker",
+				Env:  []corev1.EnvVar{{Name: "AQUA_DOCKER_API", Value: "1.41"}},
+			})
+			helper := newAquaEnforcerHelper(cr)
+			ds := helper.CreateDaemonSet(cr)
+
+			helper.SetContainerRuntime(ds, "docker", false)
+
+			env := ds.Spec.Template.Spec.Containers[0].Env
+			Expect(envValue(env, "AQUA_DOCKER_API")).To(Equal("1.41"))
+			Expect(envValue(env, "CONTAINER_RUNTIME_ENDPOINT")).To(Equal("unix:///var/run/docker.sock"))
+		})
+
+		It("keeps the default mounts of an unknown runtime", func() {
+			cr := runtimeEnforcer()
+			helper := newAquaEnforcerHelper(cr)
+			ds := helper.CreateDaemonSet(cr)
+
+			helper.SetContainerRuntime(ds, UnknownRuntime, false)
+
+			Expect(ds).To(Equal(helper.CreateDaemonSet(cr)))
+		})
+
+		It("names and selects the DaemonSet of a runtime of a mixed group", func() {
+			cr := runtimeEnforcer()
+			helper := newAquaEnforcerHelper(cr)
+			ds := helper.CreateDaemonSet(cr)
+			name := ds.Name
+
+			helper.SetContainerRuntime(ds, "cri-o", true)
+
+			Expect(ds.Name).To(Equal(name + "-crio"))
+			Expect(ds.Spec.Template.Name).To(Equal(ds.Name))
+			Expect(ds.Labels).To(HaveKeyWithValue(consts.EnforcerRuntimeLabel, "cri-o"))
+			Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue(consts.EnforcerRuntimeLabel, "cri-o"))
+			Expect(ds.Spec.Selector.MatchLabels).To(HaveKeyWithValue(consts.EnforcerRuntimeLabel, "cri-o"))
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"os": "linux", consts.ContainerRuntimeNodeLabel: "cri-o"}))
+			Expect(cr.Spec.EnforcerService.NodeSelector).To(Equal(map[string]string{"os": "linux"}))
+		})
+	})
+
+	DescribeTable("nodeChanged",
+		func(update func(node *corev1.Node), expected bool) {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"os": "linux"}},
+				Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{ContainerRuntimeVersion: "containerd://1.6.6"}},
+			}
+			updated := node.DeepCopy()
+			update(updated)
+
+			Expect(nodeChanged().Update(event.UpdateEvent{ObjectOld: node, ObjectNew: updated})).To(Equal(expected))
+		},
+		Entry("a heartbeat", func(node *corev1.Node) {
+			node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+		}, false),
+		Entry("a new runtime", func(node *corev1.Node) {
+			node.Status.NodeInfo.ContainerRuntimeVersion = "cri-o://1.24.1"
+		}, true),
+		Entry("a new label", func(node *corev1.Node) {
+			node.Labels[consts.ContainerRuntimeNodeLabel] = "containerd"
+		}, true),
+		Entry("a new taint", func(node *corev1.Node) {
+			node.Spec.Taints = []corev1.Taint{{Key: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+		}, true),
+	)
+
+	It("passes the new nodes", func() {
+		Expect(nodeChanged().Create(event.CreateEvent{Object: &corev1.Node{}})).To(BeTrue())
+	})
+})
@@ -0,0 +1,196 @@
+package aquaenforcer
+
+import (
+	"fmt"
+
+	"github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	PrivilegedProfile = "privileged"
+	RestrictedProfile = "restricted"
+	CustomProfile     = "custom"
+)
+
+// enforcerCapabilities The capabilities Aqua requires to run the enforcer without privileged mode
+var enforcerCapabilities = []corev1.Capability{
+	"SYS_ADMIN",
+	"NET_ADMIN",
+	"NET_RAW",
+	"SYS_PTRACE",
+	"KILL",
+	"MKNOD",
+	"SETGID",
+	"SETUID",
+	"SYS_MODULE",
+	"AUDIT_CONTROL",
+	"SYSLOG",
+	"SYS_CHROOT",
+	"SYS_RESOURCE",
+	"IPC_LOCK",
+}
+
+// readOnlyHostMounts The host mounts the restricted and custom profiles mount read only, the enforcer only reads
+// them and connects to the runtime sockets
+var readOnlyHostMounts = map[string]bool{
+	"var-run": true,
+	"dev":     true,
+}
+
+// ValidateSecurityProfile checks the custom profile lists its capabilities
+func (enf *AquaEnforcerHelper) ValidateSecurityProfile() error {
+	profile := enf.Parameters.Enforcer.Spec.SecurityProfile
+	if profile == nil {
+		return nil
+	}
+
+	if profile.Type == CustomProfile && len(profile.Capabilities) == 0 {
+		return fmt.Errorf("the custom security profile has no capabilities")
+	}
+	if profile.Type != CustomProfile && len(profile.Capabilities) != 0 {
+		return fmt.Errorf("capabilities are only set by the custom security profile, not %s", profile.Type)
+	}
+
+	return nil
+}
+
+// capabilities returns the capabilities of the restricted and custom profiles
+func capabilities(profile *v1alpha1.AquaEnforcerSecurityProfile) []corev1.Capability {
+	if profile.Type == CustomProfile {
+		return profile.Capabilities
+	}
+
+	return enforcerCapabilities
+}
+
+// setSecurityProfile sets the security context of the enforcer container from the profile, in place of the one of
+// runAsNonRoot
+func (enf *AquaEnforcerHelper) setSecurityProfile(ds *appsv1.DaemonSet, profile *v1alpha1.AquaEnforcerSecurityProfile) {
+	container := &ds.Spec.Template.Spec.Containers[0]
+	delete(ds.Annotations, "container.apparmor.security.beta.kubernetes.io/aqua-agent")
+
+	if profile.Type == PrivilegedProfile {
+		privileged := true
+		container.SecurityContext = &corev1.SecurityContext{
+			Privileged: &privileged,
+		}
+		return
+	}
+
+	privileged := false
+	allowPrivilegeEscalation := false
+	seccompProfile := profile.SeccompProfile
+	if seccompProfile == nil {
+		seccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	container.SecurityContext = &corev1.SecurityContext{
+		Privileged:               &privileged,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities: &corev1.Capabilities{
+			Add:  capabilities(profile),
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: seccompProfile,
+	}
+
+	appArmorProfile := profile.AppArmorProfile
+	if len(appArmorProfile) == 0 {
+		appArmorProfile = "unconfined"
+	}
+	if ds.Spec.Template.Annotations == nil {
+		ds.Spec.Template.Annotations = map[string]string{}
+	}
+	ds.Spec.Template.Annotations["container.apparmor.security.beta.kubernetes.io/"+container.Name] = appArmorProfile
+
+	for i := range container.VolumeMounts {
+		if readOnlyHostMounts[container.VolumeMounts[i].Name] {
+			container.VolumeMounts[i].ReadOnly = true
+		}
+	}
+}
+
+// CreateSecurityContextConstraints returns the OpenShift SecurityContextConstraints allowing the enforcers of the
+// security profile, and no more, to the service account of the enforcers. It is built unstructured, the OpenShift API
+// of the operator has no allowPrivilegeEscalation
+func (enf *AquaEnforcerHelper) CreateSecurityContextConstraints(cr *v1alpha1.AquaEnforcer) *unstructured.Unstructured {
+	profile := cr.Spec.SecurityProfile
+	privileged := profile.Type == PrivilegedProfile
+
+	allowedCapabilities := []interface{}{}
+	seccompProfiles := []interface{}{}
+	// the fields not set are left out, the API server returns them empty
+	if !privileged {
+		for _, capability := range capabilities(profile) {
+			allowedCapabilities = append(allowedCapabilities, string(capability))
+		}
+		seccompProfiles = append(seccompProfiles, "runtime/default")
+		if seccomp := profile.SeccompProfile; seccomp != nil {
+			switch seccomp.Type {
+			case corev1.SeccompProfileTypeUnconfined:
+				seccompProfiles = []interface{}{"unconfined"}
+			case corev1.SeccompProfileTypeLocalhost:
+				if seccomp.LocalhostProfile != nil {
+					seccompProfiles = []interface{}{"localhost/" + *seccomp.LocalhostProfile}
+				}
+			}
+		}
+	} else {
+		seccompProfiles = append(seccompProfiles, "*")
+	}
+
+	seLinuxContext := "MustRunAs"
+	if privileged {
+		seLinuxContext = "RunAsAny"
+	}
+
+	scc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "security.openshift.io/v1",
+		"kind":       "SecurityContextConstraints",
+		"metadata": map[string]interface{}{
+			"name": fmt.Sprintf(consts.EnforcerSCCName, cr.Namespace, cr.Name),
+			"labels": map[string]interface{}{
+				"app":                cr.Name + "-requirments",
+				"deployedby":         "aqua-operator",
+				"aquasecoperator_cr": cr.Name,
+				"aqua.component":     "enforcer",
+			},
+			"annotations": map[string]interface{}{
+				"kubernetes.io/description": fmt.Sprintf("Allows the %s security profile of the aqua enforcers of %s/%s", profile.Type, cr.Namespace, cr.Name),
+			},
+		},
+		"allowHostDirVolumePlugin": true,
+		"allowHostIPC":             false,
+		"allowHostNetwork":         false,
+		"allowHostPID":             true,
+		"allowHostPorts":           false,
+		"allowPrivilegeEscalation": privileged,
+		"allowPrivilegedContainer": privileged,
+		"readOnlyRootFilesystem":   false,
+		"seccompProfiles":          seccompProfiles,
+		"fsGroup":                  map[string]interface{}{"type": "RunAsAny"},
+		"runAsUser":                map[string]interface{}{"type": "RunAsAny"},
+		"seLinuxContext":           map[string]interface{}{"type": seLinuxContext},
+		"supplementalGroups":       map[string]interface{}{"type": "RunAsAny"},
+		"users": []interface{}{
+			fmt.Sprintf("system:serviceaccount:%s:%s", cr.Namespace, cr.Spec.Infrastructure.ServiceAccount),
+		},
+		"volumes": []interface{}{
+			"configMap",
+			"downwardAPI",
+			"emptyDir",
+			"hostPath",
+			"persistentVolumeClaim",
+			"projected",
+			"secret",
+		},
+	}}
+	if len(allowedCapabilities) != 0 {
+		scc.Object["allowedCapabilities"] = allowedCapabilities
+	}
+
+	return scc
+}
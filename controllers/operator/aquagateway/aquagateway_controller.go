@@ -25,7 +25,6 @@ import (
 	consts "github.com/aquasecurity/aqua-operator/pkg/consts"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
 	secrets2 "github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
-	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -59,6 +58,7 @@ type AquaGatewayReconciler struct {
 //+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquagateways/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquagateways/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=route,resources=routes,verbs=get;list;watch;create;update;patch;delete
@@ -89,9 +89,28 @@ func (r *AquaGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return reconcile.Result{}, err
 	}
 
+	if common2.ReconcilePaused(instance, instance.Spec.Paused) {
+		return reconcile.Result{}, nil
+	}
+
+	scrubbed, err := r.scrubPlaintextSecrets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if scrubbed {
+		reqLogger.Info("Moved plaintext credentials from the AquaGateway spec into secrets")
+		err = r.Client.Update(context.Background(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	instance = r.updateGatewayObject(instance)
 	r.Client.Update(context.Background(), instance)
 
+	common2.ResolveInfrastructureVersion(instance.Spec.Infrastructure, instance.Status.Version, instance.Spec.Common.AllowAnyVersion)
+
 	rbacHelper := common2.NewAquaRbacHelper(
 		instance.Spec.Infrastructure,
 		instance.Name,
@@ -109,7 +128,7 @@ func (r *AquaGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, instance.Status.State) &&
 		!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentUpdateInProgress, instance.Status.State) {
 		instance.Status.State = operatorv1alpha1.AquaDeploymentStatePending
-		r.Client.Status().Update(context.Background(), instance)
+		common2.UpdateStatus(r.Client, instance)
 	}
 
 	if instance.Spec.Common.SplitDB {
@@ -144,7 +163,7 @@ func (r *AquaGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return common2.MaintenanceResult(instance.Status.Maintenance), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -171,6 +190,15 @@ func (r *AquaGatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	----------------------------------------------------------------------------------------------------------------
 */
 
+// scrubPlaintextSecrets moves the plaintext database passwords of the spec into owned secrets and sets the matching refs
+func (r *AquaGatewayReconciler) scrubPlaintextSecrets(cr *operatorv1alpha1.AquaGateway) (bool, error) {
+	if cr.Spec.Common == nil {
+		cr.Spec.Common = &operatorv1alpha1.AquaCommon{}
+	}
+
+	return common2.MoveDatabasePasswords(r.Client, r.Scheme, cr, cr.Spec.Common, cr.Spec.ExternalDb, cr.Spec.AuditDB)
+}
+
 func (r *AquaGatewayReconciler) updateGatewayObject(cr *operatorv1alpha1.AquaGateway) *operatorv1alpha1.AquaGateway {
 	cr.Spec.Infrastructure = common2.UpdateAquaInfrastructure(cr.Spec.Infrastructure, cr.Name, cr.Namespace)
 	cr.Spec.Common = common2.UpdateAquaCommon(cr.Spec.Common, cr.Name, false, false)
@@ -191,21 +219,34 @@ func (r *AquaGatewayReconciler) InstallGatewayDeployment(cr *operatorv1alpha1.Aq
 	gatewayHelper := newAquaGatewayHelper(cr)
 	deployment := gatewayHelper.newDeployment(cr)
 
+	if cr.Status.Rollout == nil {
+		cr.Status.Rollout = &operatorv1alpha1.AquaRolloutStatus{}
+	}
+	rollout := common2.NewAquaRolloutHelper(cr.Spec.GatewayService.Rollout, cr.Status.Rollout, &cr.Status.Conditions, r.Client, r.Scheme, cr)
+	rollout.SetProgressDeadline(deployment)
+
+	if err := common2.MapPodImages(&deployment.Spec.Template.Spec); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := common2.PinImages(r.Client, cr, common2.GetImageVerification(cr.Spec.Common), &cr.Status.Images, &deployment.Spec.Template.Spec); err != nil {
+		reqLogger.Error(err, "Aqua Gateway: Image verification failed, keeping the current workload")
+		return reconcile.Result{}, err
+	}
+
 	// Set AquaGateway instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, deployment, r.Scheme); err != nil {
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, err
 	}
 
+	apply := common2.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 	// Check if this deployment already exists
 	found := &appsv1.Deployment{}
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Creating a New Aqua Gateway Deployment", "Dervice.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
-		err = patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment)
-		if err != nil {
-			reqLogger.Error(err, "Unable to set default for k8s-objectmatcher", err)
-		}
-		err = r.Client.Create(context.TODO(), deployment)
+		_, err = apply.Apply("AquaGateway deployment", deployment)
 		if err != nil {
 			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, err
 		}
@@ -216,16 +257,27 @@ func (r *AquaGatewayReconciler) InstallGatewayDeployment(cr *operatorv1alpha1.Aq
 	}
 
 	if found != nil {
-		update, err := k8s.CheckForK8sObjectUpdate("AquaGateway deployment", found, deployment)
+		drift := common2.NewAquaDriftHelper(common2.GetDriftPolicy(cr.Spec.Common), &cr.Status.Drifts, r.Client, cr)
+		if _, err = drift.Reconcile(found, deployment); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		maintenance := common2.NewAquaMaintenanceHelper(cr.Spec.MaintenanceWindow, &cr.Status.Maintenance, r.Client, cr)
+		wait, err := maintenance.Defer(fmt.Sprintf("Deployment %s image update", found.Name),
+			common2.ImagesChanged(found.Spec.Template.Spec, deployment.Spec.Template.Spec))
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+
+		update, err := rollout.ReconcileDeployment("AquaGateway deployment", found, deployment)
+		if err != nil {
+			reqLogger.Error(err, "Aqua Gateway: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
+			return reconcile.Result{}, err
+		}
 		if update {
-			err = r.Client.Update(context.Background(), deployment)
-			if err != nil {
-				reqLogger.Error(err, "Aqua Gateway: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
-				return reconcile.Result{}, err
-			}
 			// Spec updated - return and requeue
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -247,7 +299,7 @@ func (r *AquaGatewayReconciler) InstallGatewayDeployment(cr *operatorv1alpha1.Aq
 		// Update status.Nodes if needed
 		if !reflect.DeepEqual(podNames, cr.Status.Nodes) {
 			cr.Status.Nodes = podNames
-			_ = r.Client.Status().Update(context.Background(), cr)
+			_ = common2.UpdateStatus(r.Client, cr)
 		}
 
 		currentState := cr.Status.State
@@ -255,11 +307,18 @@ func (r *AquaGatewayReconciler) InstallGatewayDeployment(cr *operatorv1alpha1.Aq
 			if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentUpdateInProgress, currentState) &&
 				!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStatePending, currentState) {
 				cr.Status.State = operatorv1alpha1.AquaDeploymentUpdateInProgress
-				_ = r.Client.Status().Update(context.Background(), cr)
+				_ = common2.UpdateStatus(r.Client, cr)
 			}
 		} else if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, currentState) {
 			cr.Status.State = operatorv1alpha1.AquaDeploymentStateRunning
-			_ = r.Client.Status().Update(context.Background(), cr)
+			_ = common2.UpdateStatus(r.Client, cr)
+		}
+
+		// A rolled back deployment doesn't run the version of the spec
+		if k8s.IsDeploymentRolledOut(found) &&
+			(cr.Status.Rollout == nil || len(cr.Status.Rollout.RolledBackFrom) == 0) &&
+			common2.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, cr.Spec.Infrastructure.Version) {
+			_ = common2.UpdateStatus(r.Client, cr)
 		}
 	}
 
@@ -8,6 +8,7 @@ import (
 	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
 	rbac2 "github.com/aquasecurity/aqua-operator/pkg/utils/k8s/rbac"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/versions"
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -85,6 +86,10 @@ func (enf *AquaKubeEnforcerHelper) CreateKubeEnforcerClusterRole(name string, na
 			Resources: []string{
 				"configauditreports",
 				"clusterconfigauditreports",
+				"vulnerabilityreports",
+				"exposedsecretreports",
+				"rbacassessmentreports",
+				"clusterrbacassessmentreports",
 			},
 			Verbs: []string{
 				"get", "list", "watch",
@@ -452,7 +457,9 @@ func (enf *AquaKubeEnforcerHelper) CreateMutatingWebhook(cr, namespace, name, ap
 	return mutateWebhook
 }
 
-func (enf *AquaKubeEnforcerHelper) CreateKEConfigMap(cr, namespace, name, app, gwAddress, clusterName string, starboard bool) *corev1.ConfigMap {
+// CreateKEConfigMap renders the configuration of the kube-enforcer, with the reports it watches when starboard or
+// trivy-operator is deployed, scanner is empty otherwise
+func (enf *AquaKubeEnforcerHelper) CreateKEConfigMap(cr, namespace, name, app, gwAddress, clusterName string, scanner operatorv1alpha1.AquaStarboardScanner) *corev1.ConfigMap {
 	configMapData := map[string]string{
 		"AQUA_ENABLE_CACHE":            "yes",
 		"AQUA_CACHE_EXPIRATION_PERIOD": "60",
@@ -462,9 +469,15 @@ func (enf *AquaKubeEnforcerHelper) CreateKEConfigMap(cr, namespace, name, app, g
 		"AQUA_TLS_PORT":                "8443",
 		"CLUSTER_NAME":                 clusterName,
 	}
-	if starboard {
+	switch scanner {
+	case operatorv1alpha1.AquaStarboardScannerStarboard:
 		configMapData["AQUA_KAP_ADD_ALL_CONTROL"] = "true"
 		configMapData["AQUA_WATCH_CONFIG_AUDIT_REPORT"] = "true"
+	case operatorv1alpha1.AquaStarboardScannerTrivyOperator:
+		configMapData["AQUA_KAP_ADD_ALL_CONTROL"] = "true"
+		configMapData["AQUA_WATCH_VULNERABILITY_REPORT"] = "true"
+		configMapData["AQUA_WATCH_EXPOSED_SECRET_REPORT"] = "true"
+		configMapData["AQUA_WATCH_RBAC_ASSESSMENT_REPORT"] = "true"
 	}
 
 	labels := map[string]string{
@@ -851,6 +864,18 @@ func (ebf *AquaKubeEnforcerHelper) newStarboard(cr *operatorv1alpha1.AquaKubeEnf
 
 	_, registry, repository, tag := extra.GetImageData("kube-enforcer", cr.Spec.Infrastructure.Version, cr.Spec.KubeEnforcerService.ImageData, cr.Spec.AllowAnyVersion)
 
+	// Starboard defaults to the version released with the KubeEnforcer version
+	infra := cr.Spec.DeployStarboard.Infrastructure
+	if infra == nil || len(infra.Version) == 0 {
+		if release, ok := versions.Lookup(cr.Spec.Infrastructure.Version); ok {
+			infra = infra.DeepCopy()
+			if infra == nil {
+				infra = &operatorv1alpha1.AquaInfrastructure{}
+			}
+			infra.Version = release.Starboard
+		}
+	}
+
 	labels := map[string]string{
 		"app":                cr.Name + "-kube-enforcer",
 		"deployedby":         "aqua-operator",
@@ -872,7 +897,7 @@ func (ebf *AquaKubeEnforcerHelper) newStarboard(cr *operatorv1alpha1.AquaKubeEnf
 			Annotations: annotations,
 		},
 		Spec: v1alpha1.AquaStarboardSpec{
-			Infrastructure:                cr.Spec.DeployStarboard.Infrastructure,
+			Infrastructure:                infra,
 			AllowAnyVersion:               cr.Spec.DeployStarboard.AllowAnyVersion,
 			StarboardService:              cr.Spec.DeployStarboard.StarboardService,
 			Config:                        cr.Spec.DeployStarboard.Config,
@@ -888,7 +913,12 @@ func (ebf *AquaKubeEnforcerHelper) newStarboard(cr *operatorv1alpha1.AquaKubeEnf
 			CisKubernetesBenchmarkEnabled: cr.Spec.DeployStarboard.CisKubernetesBenchmarkEnabled,
 			VulnerabilityScannerEnabled:   cr.Spec.DeployStarboard.VulnerabilityScannerEnabled,
 			BatchDeleteLimit:              cr.Spec.DeployStarboard.BatchDeleteLimit,
-			BatchDeleteDelay:              cr.Spec.DeployStarboard.BatchDeleteLimit,
+			BatchDeleteDelay:              cr.Spec.DeployStarboard.BatchDeleteDelay,
+			Scanner:                       cr.Spec.DeployStarboard.Scanner,
+			Settings:                      cr.Spec.DeployStarboard.Settings,
+			Policies:                      cr.Spec.DeployStarboard.Policies,
+			DriftPolicy:                   cr.Spec.DriftPolicy,
+			MaintenanceWindow:             cr.Spec.MaintenanceWindow,
 		},
 	}
 	return aquasb
@@ -0,0 +1,33 @@
+package aquakubeenforcer
+
+import (
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KubeEnforcer helper", func() {
+	It("passes the starboard settings to the AquaStarboard", func() {
+		cr := &operatorv1alpha1.AquaKubeEnforcer{
+			ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua"},
+			Spec: operatorv1alpha1.AquaKubeEnforcerSpec{
+				Infrastructure:      &operatorv1alpha1.AquaInfrastructure{Version: "2022.4"},
+				KubeEnforcerService: &operatorv1alpha1.AquaService{},
+				DeployStarboard: &operatorv1alpha1.AquaStarboardDetails{
+					BatchDeleteLimit:        "10",
+					BatchDeleteDelay:        "30s",
+					ConcurrentScanJobsLimit: "5",
+					ScanJobRetryAfter:       "1m",
+				},
+			},
+		}
+		starboard := newAquaKubeEnforcerHelper(cr).newStarboard(cr)
+
+		Expect(starboard.Spec.BatchDeleteLimit).To(Equal("10"))
+		Expect(starboard.Spec.BatchDeleteDelay).To(Equal("30s"))
+		Expect(starboard.Spec.ConcurrentScanJobsLimit).To(Equal("5"))
+		Expect(starboard.Spec.ScanJobRetryAfter).To(Equal("1m"))
+	})
+})
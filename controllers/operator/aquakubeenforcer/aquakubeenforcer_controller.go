@@ -34,16 +34,15 @@ import (
 	"github.com/aquasecurity/aqua-operator/apis/aquasecurity/v1alpha1"
 	"github.com/aquasecurity/aqua-operator/controllers/common"
 	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/reconciler"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/rbac"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
-	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -120,36 +119,32 @@ func (r *AquaKubeEnforcerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return reconcile.Result{}, err
 	}
 
-	// Check if the Memcached instance is marked to be deleted, which is
-	// indicated by the deletion timestamp being set.
-	isMemcachedMarkedToBeDeleted := instance.GetDeletionTimestamp() != nil
-	if isMemcachedMarkedToBeDeleted {
-		if controllerutil.ContainsFinalizer(instance, consts.AquaKubeEnforcerFinalizer) {
-			// Run finalization logic for memcachedFinalizer. If the
-			// finalization logic fails, don't remove the finalizer so
-			// that we can retry during the next reconciliation.
-			if err := r.KubeEnforcerFinalizer(instance); err != nil {
-				return ctrl.Result{}, err
-			}
+	if common.ReconcilePaused(instance, instance.Spec.Paused) {
+		return reconcile.Result{}, nil
+	}
 
-			// Remove KubeEnforcerFinalizer. Once all finalizers have been
-			// removed, the object will be deleted.
-			controllerutil.RemoveFinalizer(instance, consts.AquaKubeEnforcerFinalizer)
-			err := r.Update(ctx, instance)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		}
+	// The objects of the kube-enforcer, the finalizer removes its cluster scoped objects on deletion
+	component := r.kubeEnforcerComponent(instance)
+	components := reconciler.New(r.Client, r.Scheme, common.NewAquaApplyHelper(r.Client, r.Scheme, instance))
+	deleted, err := components.Finalize(ctx, instance, component)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if deleted {
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// Add finalizer for this CR
-	if !controllerutil.ContainsFinalizer(instance, consts.AquaKubeEnforcerFinalizer) {
-		controllerutil.AddFinalizer(instance, consts.AquaKubeEnforcerFinalizer)
-		err = r.Update(ctx, instance)
+	scrubbed, err := r.scrubPlaintextSecrets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if scrubbed {
+		reqLogger.Info("Moved plaintext credentials from the AquaKubeEnforcer spec into secrets")
+		err = r.Client.Update(context.Background(), instance)
 		if err != nil {
-			return ctrl.Result{}, err
+			return reconcile.Result{}, err
 		}
+		return reconcile.Result{Requeue: true}, nil
 	}
 
 	instance = r.updateKubeEnforcerObject(instance)
@@ -160,7 +155,7 @@ func (r *AquaKubeEnforcerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		!reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdatePendingApproval, currentStatus) &&
 		!reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdateInProgress, currentStatus) {
 		instance.Status.State = operatorv1alpha1.AquaDeploymentStatePending
-		_ = r.Client.Status().Update(context.Background(), instance)
+		_ = common.UpdateStatus(r.Client, instance)
 	}
 
 	if instance.Spec.Config.ImagePullSecret == "" && !extra.IsMarketPlace() {
@@ -175,74 +170,11 @@ func (r *AquaKubeEnforcerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	}
 
 	instance.Spec.Infrastructure = common.UpdateAquaInfrastructure(instance.Spec.Infrastructure, consts.AquaKubeEnforcerClusterRoleBidingName, instance.Namespace)
-
-	_, err = r.addKubeEnforcerClusterRole(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.createAquaServiceAccount(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	if strings.ToLower(instance.Spec.Infrastructure.Platform) == consts.OpenShiftPlatform &&
-		rbac.CheckIfClusterRoleExists(r.Client, consts.ClusterReaderRole) &&
-		!rbac.CheckIfClusterRoleBindingExists(r.Client, consts.AquaKubeEnforcerSAClusterReaderRoleBind) {
-		_, err = r.CreateClusterReaderRoleBinding(instance)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
-	}
+	common.ResolveInfrastructureVersion(instance.Spec.Infrastructure, instance.Status.Version, instance.Spec.AllowAnyVersion)
 
 	instance.Spec.KubeEnforcerService = r.updateKubeEnforcerServerObject(instance.Spec.KubeEnforcerService, instance.Spec.ImageData)
 
-	_, err = r.addKEClusterRoleBinding(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKubeEnforcerRole(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKERoleBinding(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKEValidatingWebhook(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKEMutatingWebhook(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKEConfigMap(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKESecretToken(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKESecretSSL(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKEService(instance)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	_, err = r.addKEDeployment(instance)
+	result, err := components.Reconcile(ctx, instance, component)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -251,7 +183,10 @@ func (r *AquaKubeEnforcerReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		r.installAquaStarboard(instance)
 	}
 
-	return ctrl.Result{}, nil
+	if result.Requeue || result.RequeueAfter > 0 {
+		return result, nil
+	}
+	return common.MaintenanceResult(instance.Status.Maintenance), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -280,7 +215,7 @@ func (r *AquaKubeEnforcerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 */
 
 func GetKECerts() *KubeEnforcerCertificates {
-	certs, err := createKECerts()
+	certs, err := NewKECerts(extra.GetCurrentNameSpace())
 	if err != nil {
 		return nil
 	}
@@ -288,7 +223,8 @@ func GetKECerts() *KubeEnforcerCertificates {
 	return certs
 }
 
-func createKECerts() (*KubeEnforcerCertificates, error) {
+// NewKECerts creates a CA and the webhook server certificate of the kube-enforcer service in the namespace
+func NewKECerts(namespace string) (*KubeEnforcerCertificates, error) {
 	certs := &KubeEnforcerCertificates{}
 	// set up our CA certificate
 	ca := &x509.Certificate{
@@ -338,8 +274,6 @@ func createKECerts() (*KubeEnforcerCertificates, error) {
 		return certs, err
 	}
 
-	namespace := extra.GetCurrentNameSpace()
-
 	// set up our server certificate
 	cert := &x509.Certificate{
 		BasicConstraintsValid: false,
@@ -394,6 +328,69 @@ func createKECerts() (*KubeEnforcerCertificates, error) {
 	return certs, nil
 }
 
+// RotateCertificates replaces the webhook certificates of the kube-enforcer, its SSL secret and the CA bundle
+// of its webhooks, then restarts its pods so they serve the new certificate
+func RotateCertificates(k8sclient client.Client, cr *operatorv1alpha1.AquaKubeEnforcer) error {
+	reqLogger := log.WithValues("KubeEnforcer Certificates Phase", "Rotate Certificates")
+
+	certs, err := NewKECerts(cr.Namespace)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	err = k8sclient.Get(context.TODO(), types.NamespacedName{Name: consts.AquaKubeEnforcerSSLSecretName, Namespace: cr.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+	secret.Data["aqua_ke.key"] = certs.ServerKey
+	secret.Data["aqua_ke.crt"] = certs.ServerCert
+	err = k8sclient.Update(context.TODO(), secret)
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Updated the kube-enforcer SSL secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+
+	validating := &admissionv1.ValidatingWebhookConfiguration{}
+	err = k8sclient.Get(context.TODO(), types.NamespacedName{Name: consts.AquaKubeEnforcerValidatingWebhookConfigurationName}, validating)
+	if err != nil {
+		return err
+	}
+	for i := range validating.Webhooks {
+		validating.Webhooks[i].ClientConfig.CABundle = certs.CACert
+	}
+	err = k8sclient.Update(context.TODO(), validating)
+	if err != nil {
+		return err
+	}
+
+	mutating := &admissionv1.MutatingWebhookConfiguration{}
+	err = k8sclient.Get(context.TODO(), types.NamespacedName{Name: consts.AquaKubeEnforcerMutantingWebhookConfigurationName}, mutating)
+	if err != nil {
+		return err
+	}
+	for i := range mutating.Webhooks {
+		mutating.Webhooks[i].ClientConfig.CABundle = certs.CACert
+	}
+	err = k8sclient.Update(context.TODO(), mutating)
+	if err != nil {
+		return err
+	}
+	reqLogger.Info("Updated the kube-enforcer webhooks CA bundle")
+
+	deployment := &appsv1.Deployment{}
+	err = k8sclient.Get(context.TODO(), types.NamespacedName{Name: consts.AquaKubeEnforcerClusterRoleBidingName, Namespace: cr.Namespace}, deployment)
+	if err != nil {
+		return err
+	}
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.Annotations[consts.CertsRotatedAtAnnotation] = time.Now().Format(time.RFC3339)
+
+	return k8sclient.Update(context.TODO(), deployment)
+}
+
 /*
 ----------------------------------------------------------------------------------------------------------------
 
@@ -421,6 +418,47 @@ func (r *AquaKubeEnforcerReconciler) updateKubeEnforcerServerObject(serviceObjec
 	return serviceObject
 }
 
+// scrubPlaintextSecrets moves the plaintext token and registry password of the spec into owned secrets and sets the matching refs
+func (r *AquaKubeEnforcerReconciler) scrubPlaintextSecrets(cr *operatorv1alpha1.AquaKubeEnforcer) (bool, error) {
+	tokenMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.Token,
+		&cr.Spec.TokenSecretRef,
+		fmt.Sprintf(consts.KubeEnforcerTokenSecretName, cr.Name),
+		consts.KubeEnforcerTokenSecretKey,
+		"Secret for aqua kube-enforcer token")
+	if err != nil {
+		return false, err
+	}
+
+	registryMoved := false
+	if cr.Spec.RegistryData != nil {
+		registryMoved, err = common.MoveToSecret(r.Client, r.Scheme, cr,
+			&cr.Spec.RegistryData.Password,
+			&cr.Spec.RegistryData.PasswordSecretRef,
+			fmt.Sprintf(consts.RegistryPasswordSecretName, cr.Name),
+			consts.RegistryPasswordSecretKey,
+			"Secret for aqua registry password")
+		if err != nil {
+			return false, err
+		}
+	}
+
+	starboardRegistryMoved := false
+	if cr.Spec.DeployStarboard != nil && cr.Spec.DeployStarboard.RegistryData != nil {
+		starboardRegistryMoved, err = common.MoveToSecret(r.Client, r.Scheme, cr,
+			&cr.Spec.DeployStarboard.RegistryData.Password,
+			&cr.Spec.DeployStarboard.RegistryData.PasswordSecretRef,
+			fmt.Sprintf(consts.RegistryPasswordSecretName, cr.Name+"-starboard"),
+			consts.RegistryPasswordSecretKey,
+			"Secret for aqua starboard registry password")
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return tokenMoved || registryMoved || starboardRegistryMoved, nil
+}
+
 func (r *AquaKubeEnforcerReconciler) updateKubeEnforcerObject(cr *operatorv1alpha1.AquaKubeEnforcer) *operatorv1alpha1.AquaKubeEnforcer {
 	if secrets.CheckIfSecretExists(r.Client, consts.MtlsAquaKubeEnforcerSecretName, cr.Namespace) {
 		log.Info(fmt.Sprintf("%s secret found, enabling mtls", consts.MtlsAquaKubeEnforcerSecretName))
@@ -429,9 +467,178 @@ func (r *AquaKubeEnforcerReconciler) updateKubeEnforcerObject(cr *operatorv1alph
 	return cr
 }
 
-func (r *AquaKubeEnforcerReconciler) addKEDeployment(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
+// kubeEnforcerComponent declares the objects of the kube-enforcer, the deployment is rolled out once its
+// permissions, configuration and certificates are in place
+func (r *AquaKubeEnforcerReconciler) kubeEnforcerComponent(cr *operatorv1alpha1.AquaKubeEnforcer) *reconciler.Component {
+	enforcerHelper := newAquaKubeEnforcerHelper(cr)
+
+	return &reconciler.Component{
+		Name:          "AquaKubeEnforcer",
+		ConditionType: operatorv1alpha1.AquaConditionReady,
+		Conditions:    &cr.Status.Conditions,
+		Finalizer:     consts.AquaKubeEnforcerFinalizer,
+		Finalize: func() error {
+			return r.KubeEnforcerFinalizer(cr)
+		},
+		Objects: []reconciler.Object{
+			{
+				Name: "cluster role",
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateKubeEnforcerClusterRole(cr.Name, cr.Namespace), nil
+				},
+			},
+			{
+				Name: "service account",
+				Mode: reconciler.CreateOnly,
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateKEServiceAccount(cr.Name,
+						cr.Namespace,
+						fmt.Sprintf("%s-requirments", cr.Name),
+						cr.Spec.Infrastructure.ServiceAccount), nil
+				},
+			},
+			{
+				Name:      "cluster reader role binding",
+				DependsOn: []string{"service account"},
+				Mode:      reconciler.CreateOnly,
+				Enabled: func() bool {
+					return strings.ToLower(cr.Spec.Infrastructure.Platform) == consts.OpenShiftPlatform &&
+						rbac.CheckIfClusterRoleExists(r.Client, consts.ClusterReaderRole)
+				},
+				Build: func() (client.Object, error) {
+					return rbac.CreateClusterRoleBinding(
+						cr.Name,
+						cr.Namespace,
+						consts.AquaKubeEnforcerSAClusterReaderRoleBind,
+						fmt.Sprintf("%s-kube-enforcer-cluster-reader", cr.Name),
+						"Deploy Aqua KubeEnforcer Cluster Reader Role Binding",
+						"aqua-kube-enforcer-sa",
+						consts.ClusterReaderRole), nil
+				},
+			},
+			{
+				Name:      "cluster role binding",
+				DependsOn: []string{"cluster role", "service account"},
+				Mode:      reconciler.CreateOnly,
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateClusterRoleBinding(cr.Name,
+						cr.Namespace,
+						consts.AquaKubeEnforcerClusterRoleBidingName,
+						"ke-crb",
+						cr.Spec.Infrastructure.ServiceAccount,
+						consts.AquaKubeEnforcerClusterRoleBidingName), nil
+				},
+			},
+			{
+				Name: "role",
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateKubeEnforcerRole(cr.Name, cr.Namespace, consts.AquaKubeEnforcerClusterRoleBidingName, fmt.Sprintf("%s-requirments", cr.Name)), nil
+				},
+			},
+			{
+				Name:      "role binding",
+				DependsOn: []string{"role", "service account"},
+				Mode:      reconciler.CreateOnly,
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateRoleBinding(cr.Name,
+						cr.Namespace,
+						consts.AquaKubeEnforcerClusterRoleBidingName,
+						"ke-rb",
+						cr.Spec.Infrastructure.ServiceAccount,
+						consts.AquaKubeEnforcerClusterRoleBidingName), nil
+				},
+			},
+			{
+				Name: "service",
+				Mode: reconciler.CreateOnly,
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateKEService(cr.Name,
+						cr.Namespace,
+						consts.AquaKubeEnforcerClusterRoleBidingName,
+						"ke-service"), nil
+				},
+			},
+			{
+				Name:      "validating webhook",
+				DependsOn: []string{"service"},
+				Mode:      reconciler.CreateOnly,
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateValidatingWebhook(
+						cr.Name,
+						cr.Namespace,
+						consts.AquaKubeEnforcerValidatingWebhookConfigurationName,
+						"ke-validatingwebhook",
+						consts.AquaKubeEnforcerClusterRoleBidingName,
+						r.Certs.CACert,
+						cr.Spec.MutatingWebhookTimeout,
+					), nil
+				},
+			},
+			{
+				Name:      "mutating webhook",
+				DependsOn: []string{"service"},
+				Mode:      reconciler.CreateOnly,
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateMutatingWebhook(
+						cr.Name,
+						cr.Namespace,
+						consts.AquaKubeEnforcerMutantingWebhookConfigurationName,
+						"ke-mutatingwebhook",
+						consts.AquaKubeEnforcerClusterRoleBidingName,
+						r.Certs.CACert,
+						cr.Spec.MutatingWebhookTimeout,
+					), nil
+				},
+			},
+			{
+				Name:  "configmap",
+				Build: func() (client.Object, error) { return r.buildKEConfigMap(cr) },
+				BeforeApply: func(found, desired client.Object) (time.Duration, error) {
+					if found == nil {
+						return 0, nil
+					}
+					// the apply reverts the manual changes the drift policy enforces
+					drift := common.NewAquaDriftHelper(cr.Spec.DriftPolicy, &cr.Status.Drifts, r.Client, cr)
+					_, err := drift.Reconcile(found, desired)
+					return 0, err
+				},
+			},
+			{
+				Name:  "token secret",
+				Build: func() (client.Object, error) { return r.buildKETokenSecret(cr) },
+			},
+			{
+				Name: "ssl secret",
+				Mode: reconciler.CreateOnly,
+				Build: func() (client.Object, error) {
+					return enforcerHelper.CreateKESSLSecret(cr.Name,
+						cr.Namespace,
+						consts.AquaKubeEnforcerSSLSecretName,
+						"ke-ssl-secret",
+						r.Certs.ServerKey,
+						r.Certs.ServerCert), nil
+				},
+			},
+			{
+				Name: "deployment",
+				DependsOn: []string{"service account", "cluster role binding", "role binding",
+					"configmap", "token secret", "ssl secret"},
+				Build:       func() (client.Object, error) { return r.buildKEDeployment(cr) },
+				BeforeApply: r.deferKEDeployment(cr),
+				Update:      r.updateKEDeployment(cr),
+				Ready: func(found client.Object) (bool, string) {
+					if !k8s.IsDeploymentReady(found.(*appsv1.Deployment), int(cr.Spec.KubeEnforcerService.Replicas)) {
+						return false, "waiting for the replicas to be ready"
+					}
+					return true, ""
+				},
+			},
+		},
+	}
+}
+
+func (r *AquaKubeEnforcerReconciler) buildKEDeployment(cr *operatorv1alpha1.AquaKubeEnforcer) (client.Object, error) {
 	reqLogger := log.WithValues("KubeEnforcer Deployment Phase", "Create Deployment")
-	reqLogger.Info("Start creating deployment")
 
 	pullPolicy, registry, repository, tag := extra.GetImageData("kube-enforcer", cr.Spec.Infrastructure.Version, cr.Spec.KubeEnforcerService.ImageData, cr.Spec.AllowAnyVersion)
 
@@ -444,422 +651,97 @@ func (r *AquaKubeEnforcerReconciler) addKEDeployment(cr *operatorv1alpha1.AquaKu
 		pullPolicy,
 		repository)
 
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, deployment, r.Scheme); err != nil {
-		return reconcile.Result{}, err
+	if err := common.MapPodImages(&deployment.Spec.Template.Spec); err != nil {
+		return nil, err
 	}
 
-	// Check if this object already exists
-	found := &appsv1.Deployment{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New deployment", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
-		err = patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment)
-		if err != nil {
-			reqLogger.Error(err, "Unable to set default for k8s-objectmatcher", err)
+	if err := common.PinImages(r.Client, cr, cr.Spec.ImageVerification, &cr.Status.Images, &deployment.Spec.Template.Spec); err != nil {
+		reqLogger.Error(err, "Aqua KubeEnforcer: Image verification failed, keeping the current workload")
+		return nil, err
+	}
+
+	return deployment, nil
+}
+
+// deferKEDeployment records the manual changes of the deployment and defers its image updates to the maintenance
+// window
+func (r *AquaKubeEnforcerReconciler) deferKEDeployment(cr *operatorv1alpha1.AquaKubeEnforcer) func(found, desired client.Object) (time.Duration, error) {
+	return func(found, desired client.Object) (time.Duration, error) {
+		if found == nil {
+			return 0, nil
 		}
 
-		err = r.Client.Create(context.TODO(), deployment)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
+		drift := common.NewAquaDriftHelper(cr.Spec.DriftPolicy, &cr.Status.Drifts, r.Client, cr)
+		if _, err := drift.Reconcile(found, desired); err != nil {
+			return 0, err
 		}
 
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
+		maintenance := common.NewAquaMaintenanceHelper(cr.Spec.MaintenanceWindow, &cr.Status.Maintenance, r.Client, cr)
+		return maintenance.Defer(fmt.Sprintf("Deployment %s image update", found.GetName()),
+			common.ImagesChanged(found.(*appsv1.Deployment).Spec.Template.Spec, desired.(*appsv1.Deployment).Spec.Template.Spec))
 	}
+}
 
-	if found != nil {
+// updateKEDeployment applies the deployment once its update is approved, and keeps the state of the kube-enforcer
+// in the status
+func (r *AquaKubeEnforcerReconciler) updateKEDeployment(cr *operatorv1alpha1.AquaKubeEnforcer) func(found, desired client.Object) (bool, error) {
+	return func(foundObject, desired client.Object) (bool, error) {
+		reqLogger := log.WithValues("KubeEnforcer Deployment Phase", "Update Deployment")
+		found := foundObject.(*appsv1.Deployment)
 
 		updateEnforcerApproved := true
 		if cr.Spec.EnforcerUpdateApproved != nil {
 			updateEnforcerApproved = *cr.Spec.EnforcerUpdateApproved
 		}
 
-		update, err := k8s.CheckForK8sObjectUpdate("AquaKubeEnforcer deployment", found, deployment)
+		apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+		update, err := apply.Changed(desired)
 		if err != nil {
-			return reconcile.Result{}, err
+			return false, err
 		}
 
 		if update && updateEnforcerApproved {
-			err = r.Client.Update(context.Background(), deployment)
+			_, err = apply.Apply("AquaKubeEnforcer deployment", desired)
 			if err != nil {
 				reqLogger.Error(err, "Aqua KubeEnforcer: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
-				return reconcile.Result{}, err
+				return false, err
 			}
-			// Spec updated - return and requeue
-			return reconcile.Result{Requeue: true}, nil
+			return true, nil
 		} else if update && !updateEnforcerApproved {
 			cr.Status.State = operatorv1alpha1.AquaEnforcerUpdatePendingApproval
-			_ = r.Client.Status().Update(context.Background(), cr)
-		} else {
-			currentState := cr.Status.State
-			if !k8s.IsDeploymentReady(found, int(cr.Spec.KubeEnforcerService.Replicas)) {
-				if !reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdateInProgress, currentState) &&
-					!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStatePending, currentState) {
-					cr.Status.State = operatorv1alpha1.AquaEnforcerUpdateInProgress
-					_ = r.Client.Status().Update(context.Background(), cr)
-				}
-			} else if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, currentState) {
-				cr.Status.State = operatorv1alpha1.AquaDeploymentStateRunning
-				_ = r.Client.Status().Update(context.Background(), cr)
-			}
-		}
-	}
-
-	// object already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua KubeEnforcer Deployment Exists", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
-	return reconcile.Result{}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKubeEnforcerClusterRole(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create Aqua KubeEnforcer Cluster Role")
-	reqLogger.Info("Start creating kube-enforcer cluster role")
-
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	crole := enforcerHelper.CreateKubeEnforcerClusterRole(cr.Name, cr.Namespace)
-
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, crole, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this ClusterRole already exists
-	found := &rbacv1.ClusterRole{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: crole.Name}, found)
-
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New ClusterRole", "ClusterRole.Namespace", crole.Namespace, "ClusterRole.Name", crole.Name)
-		err = r.Client.Create(context.TODO(), crole)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if the ClusterRole Rules, matches the found Rules
-	equal, err := k8s.CompareByHash(crole.Rules, found.Rules)
-
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	if !equal {
-		found = crole
-		log.Info("Aqua KubeEnforcer: Updating ClusterRole", "ClusterRole.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
-		err := r.Client.Update(context.TODO(), found)
-		if err != nil {
-			log.Error(err, "Failed to update ClusterRole", "ClusterRole.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
-			return reconcile.Result{}, err
-		}
-
-		return reconcile.Result{Requeue: true}, nil
-	}
-
-	// ClusterRole already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua ClusterRole Exists", "ClusterRole.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKEClusterRoleBinding(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create ClusterRoleBinding")
-	reqLogger.Info("Start creating ClusterRole")
-
-	// Define a new ClusterRoleBinding object
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	crb := enforcerHelper.CreateClusterRoleBinding(cr.Name,
-		cr.Namespace,
-		consts.AquaKubeEnforcerClusterRoleBidingName,
-		"ke-crb",
-		cr.Spec.Infrastructure.ServiceAccount,
-		consts.AquaKubeEnforcerClusterRoleBidingName)
-
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, crb, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this ClusterRoleBinding already exists
-	found := &rbacv1.ClusterRoleBinding{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: crb.Name, Namespace: crb.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New ClusterRoleBinding", "ClusterRoleBinding.Namespace", crb.Namespace, "ClusterRoleBinding.Name", crb.Name)
-		err = r.Client.Create(context.TODO(), crb)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// ClusterRoleBinding already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua ClusterRoleBinding Exists", "ClusterRoleBinding.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) CreateClusterReaderRoleBinding(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create KubeEnforcer ClusterReaderRoleBinding")
-	reqLogger.Info("Start creating KubeEnforcer ClusterReaderRoleBinding")
-
-	crb := rbac.CreateClusterRoleBinding(
-		cr.Name,
-		cr.Namespace,
-		consts.AquaKubeEnforcerSAClusterReaderRoleBind,
-		fmt.Sprintf("%s-kube-enforcer-cluster-reader", cr.Name),
-		"Deploy Aqua KubeEnforcer Cluster Reader Role Binding",
-		"aqua-kube-enforcer-sa",
-		consts.ClusterReaderRole)
-
-	// Set AquaKube-enforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, crb, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this ClusterRoleBinding already exists
-	found := &rbacv1.ClusterRoleBinding{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: crb.Name}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New KubeEnfocer ClusterReaderRoleBinding", "ClusterReaderRoleBinding.Namespace", crb.Namespace, "ClusterReaderRoleBinding.Name", crb.Name)
-		err = r.Client.Create(context.TODO(), crb)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// ClusterRoleBinding already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua KubeEnforcer ClusterReaderRoleBinding Exists", "ClusterRoleBinding.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) createAquaServiceAccount(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create Aqua KubeEnforcer Service Account")
-	reqLogger.Info("Start creating aqua kube-enforcer service account")
-
-	// Define a new service account object
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	sa := enforcerHelper.CreateKEServiceAccount(cr.Name,
-		cr.Namespace,
-		fmt.Sprintf("%s-requirments", cr.Name),
-		cr.Spec.Infrastructure.ServiceAccount)
-
-	// Set AquaKubeEnforcerKind instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, sa, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this service account already exists
-	found := &corev1.ServiceAccount{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: sa.Name, Namespace: sa.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Creating a New Aqua Service Account", "ServiceAccount.Namespace", sa.Namespace, "ServiceAccount.Name", sa.Name)
-		err = r.Client.Create(context.TODO(), sa)
-		if err != nil {
-			return reconcile.Result{}, err
+			_ = common.UpdateStatus(r.Client, cr)
+			return false, nil
 		}
 
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Service account already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua Service Account Already Exists", "ServiceAccount.Namespace", found.Namespace, "ServiceAccount.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKubeEnforcerRole(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create Aqua KubeEnforcer Role")
-	reqLogger.Info("Start creating kube-enforcer role")
-
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	role := enforcerHelper.CreateKubeEnforcerRole(cr.Name, cr.Namespace, consts.AquaKubeEnforcerClusterRoleBidingName, fmt.Sprintf("%s-requirments", cr.Name))
-
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, role, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this Role already exists
-	found := &rbacv1.Role{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: role.Name, Namespace: role.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New ClusterRole", "ClusterRole.Namespace", role.Namespace, "ClusterRole.Name", role.Name)
-		err = r.Client.Create(context.TODO(), role)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
+		currentState := cr.Status.State
+		if !k8s.IsDeploymentReady(found, int(cr.Spec.KubeEnforcerService.Replicas)) {
+			if !reflect.DeepEqual(operatorv1alpha1.AquaEnforcerUpdateInProgress, currentState) &&
+				!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStatePending, currentState) {
+				cr.Status.State = operatorv1alpha1.AquaEnforcerUpdateInProgress
+				_ = common.UpdateStatus(r.Client, cr)
+			}
+		} else if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, currentState) {
+			cr.Status.State = operatorv1alpha1.AquaDeploymentStateRunning
+			_ = common.UpdateStatus(r.Client, cr)
 		}
 
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if the Role Rules, matches the found Rules
-	equal, err := k8s.CompareByHash(role.Rules, found.Rules)
-
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	if !equal {
-		found = role
-		log.Info("Aqua KubeEnforcer: Updating Role", "Role.Namespace", found.Namespace, "Role.Name", found.Name)
-		err := r.Client.Update(context.TODO(), found)
-		if err != nil {
-			log.Error(err, "Failed to update Role", "Role.Namespace", found.Namespace, "Role.Name", found.Name)
-			return reconcile.Result{}, err
+		if k8s.IsDeploymentRolledOut(found) &&
+			common.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, cr.Spec.Infrastructure.Version) {
+			_ = common.UpdateStatus(r.Client, cr)
 		}
 
-		return reconcile.Result{Requeue: true}, nil
+		return false, nil
 	}
-
-	// ClusterRole already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua ClusterRole Exists", "ClusterRole.Namespace", found.Namespace, "ClusterRole.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
 }
 
-func (r *AquaKubeEnforcerReconciler) addKERoleBinding(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create RoleBinding")
-	reqLogger.Info("Start creating RoleBinding")
-
-	// Define a new ClusterRoleBinding object
+func (r *AquaKubeEnforcerReconciler) buildKEConfigMap(cr *operatorv1alpha1.AquaKubeEnforcer) (client.Object, error) {
 	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	rb := enforcerHelper.CreateRoleBinding(cr.Name,
-		cr.Namespace,
-		consts.AquaKubeEnforcerClusterRoleBidingName,
-		"ke-rb",
-		cr.Spec.Infrastructure.ServiceAccount,
-		consts.AquaKubeEnforcerClusterRoleBidingName)
-
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, rb, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this RoleBinding already exists
-	found := &rbacv1.RoleBinding{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: rb.Name, Namespace: rb.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New ClusterRoleBinding", "ClusterRoleBinding.Namespace", rb.Namespace, "ClusterRoleBinding.Name", rb.Name)
-		err = r.Client.Create(context.TODO(), rb)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// RoleBinding already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua RoleBinding Exists", "RoleBinding.Namespace", found.Namespace, "Role.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKEValidatingWebhook(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create ValidatingWebhookConfiguration")
-	reqLogger.Info("Start creating ValidatingWebhookConfiguration")
-
-	// Log the validatingWebhookTimeout value from the CRD before passing it to the helper function
-	reqLogger.Info("ValidatingWebhookTimeout from CRD", "validatingWebhookTimeout", cr.Spec.ValidatingWebhookTimeout)
-
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	validWebhook := enforcerHelper.CreateValidatingWebhook(
-		cr.Name,
-		cr.Namespace,
-		consts.AquaKubeEnforcerValidatingWebhookConfigurationName,
-		"ke-validatingwebhook",
-		consts.AquaKubeEnforcerClusterRoleBidingName,
-		r.Certs.CACert,
-		cr.Spec.MutatingWebhookTimeout,
-	)
-
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, validWebhook, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this ValidatingWebhookConfiguration already exists
-	found := &admissionv1.ValidatingWebhookConfiguration{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: validWebhook.Name, Namespace: validWebhook.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New ValidatingWebhookConfiguration", "ValidatingWebhook.Namespace", validWebhook.Namespace, "ClusterRoleBinding.Name", validWebhook.Name)
-		err = r.Client.Create(context.TODO(), validWebhook)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// ValidatingWebhookConfiguration already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua ValidatingWebhookConfiguration Exists", "ValidatingWebhookConfiguration.Namespace", found.Namespace, "ValidatingWebhookConfiguration.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKEMutatingWebhook(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create MutatingWebhookConfiguration")
-	reqLogger.Info("Start creating MutatingWebhookConfiguration")
-
-	// Log the MutatingWebhookTimeout value from the CRD before passing it to the helper function
-	reqLogger.Info("MutatingWebhookTimeout from CRD", "mutatingWebhookTimeout", cr.Spec.MutatingWebhookTimeout)
-
-	// Define a new MutatingWebhookConfiguration object
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	mutateWebhook := enforcerHelper.CreateMutatingWebhook(
-		cr.Name,
-		cr.Namespace,
-		consts.AquaKubeEnforcerMutantingWebhookConfigurationName,
-		"ke-mutatingwebhook",
-		consts.AquaKubeEnforcerClusterRoleBidingName,
-		r.Certs.CACert,
-		cr.Spec.MutatingWebhookTimeout,
-	)
-
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, mutateWebhook, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this MutatingWebhookConfiguration already exists
-	found := &admissionv1.MutatingWebhookConfiguration{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: mutateWebhook.Name, Namespace: mutateWebhook.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New MutatingWebhookConfiguration", "MutatingWebhook.Namespace", mutateWebhook.Namespace, "MutatingWebhook.Name", mutateWebhook.Name, "MutatingWebhook.Timeout")
-		err = r.Client.Create(context.TODO(), mutateWebhook)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// MutatingWebhookConfiguration already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua MutatingWebhookConfiguration Exists", "MutatingWebhookConfiguration.Namespace", found.Namespace, "MutatingWebhookConfiguration.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKEConfigMap(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create ConfigMap")
-	reqLogger.Info("Start creating ConfigMap")
-	//reqLogger.Info(fmt.Sprintf("cr object : %v", cr.ObjectMeta))
-
-	// Define a new ClusterRoleBinding object
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	deployStarboard := false
+	var scanner operatorv1alpha1.AquaStarboardScanner
 	if cr.Spec.DeployStarboard != nil {
-		deployStarboard = true
+		scanner = operatorv1alpha1.AquaStarboardScannerStarboard
+		if cr.Spec.DeployStarboard.Scanner == operatorv1alpha1.AquaStarboardScannerTrivyOperator {
+			scanner = operatorv1alpha1.AquaStarboardScannerTrivyOperator
+		}
 	}
 	configMap := enforcerHelper.CreateKEConfigMap(cr.Name,
 		cr.Namespace,
@@ -867,189 +749,59 @@ func (r *AquaKubeEnforcerReconciler) addKEConfigMap(cr *operatorv1alpha1.AquaKub
 		"ke-configmap",
 		cr.Spec.Config.GatewayAddress,
 		cr.Spec.Config.ClusterName,
-		deployStarboard)
+		scanner)
 	// Adding configmap to the hashed data, for restart pods if token is changed
 	hash, err := extra.GenerateMD5ForSpec(configMap.Data)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
 	cr.Spec.ConfigMapChecksum += hash
 
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, configMap, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this ConfigMap already exists
-	foundConfigMap := &corev1.ConfigMap{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, foundConfigMap)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
-		err = r.Client.Create(context.TODO(), configMap)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
+	return configMap, nil
+}
 
-	// Check if the ConfigMap Data, matches the found Data
-	if !equality.Semantic.DeepDerivative(configMap.Data, foundConfigMap.Data) {
-		foundConfigMap = configMap
-		log.Info("Aqua KubeEnforcer: Updating ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-		err := r.Client.Update(context.TODO(), foundConfigMap)
+func (r *AquaKubeEnforcerReconciler) buildKETokenSecret(cr *operatorv1alpha1.AquaKubeEnforcer) (client.Object, error) {
+	token := cr.Spec.Token
+	if cr.Spec.TokenSecretRef != nil {
+		value, err := secrets.GetSecretValue(r.Client, cr.Namespace, cr.Spec.TokenSecretRef)
 		if err != nil {
-			log.Error(err, "Failed to update ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-			return reconcile.Result{}, err
+			return nil, err
 		}
-
-		return reconcile.Result{Requeue: true}, nil
+		token = value
 	}
 
-	// MutatingWebhookConfiguration already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua KubeEnforcer ConfigMap Exists", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKESecretToken(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create Token Secret")
-	reqLogger.Info("Start creating token secret")
-
 	enforcerHelper := newAquaKubeEnforcerHelper(cr)
 	tokenSecret := enforcerHelper.CreateKETokenSecret(cr.Name,
 		cr.Namespace,
 		"aqua-kube-enforcer-token",
 		"ke-token-secret",
-		cr.Spec.Token)
+		token)
 	// Adding secret to the hashed data, for restart pods if token is changed
 	hash, err := extra.GenerateMD5ForSpec(tokenSecret.Data)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
 	cr.Spec.ConfigMapChecksum += hash
 
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, tokenSecret, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this object already exists
-	found := &corev1.Secret{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: tokenSecret.Name, Namespace: tokenSecret.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New token secret", "Secret.Namespace", tokenSecret.Namespace, "Secret.Name", tokenSecret.Name)
-		err = r.Client.Create(context.TODO(), tokenSecret)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	if !equality.Semantic.DeepDerivative(tokenSecret.Data, found.Data) {
-		found = tokenSecret
-		log.Info("Aqua Enforcer: Updating KubeEnforcer Token Secret", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
-		err := r.Client.Update(context.TODO(), found)
-		if err != nil {
-			log.Error(err, "Failed to update KubeEnforcer Token Secret", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
-			return reconcile.Result{}, err
-		}
-
-		return reconcile.Result{Requeue: true}, nil
-	}
-
-	// object already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua KubeEnforcer Token Secret Exists", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKESecretSSL(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create SSL Secret")
-	reqLogger.Info("Start creating ssl secret")
-
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	sslSecret := enforcerHelper.CreateKESSLSecret(cr.Name,
-		cr.Namespace,
-		"kube-enforcer-ssl",
-		"ke-ssl-secret",
-		r.Certs.ServerKey,
-		r.Certs.ServerCert)
-
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, sslSecret, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this object already exists
-	found := &corev1.Secret{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: sslSecret.Name, Namespace: sslSecret.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New ssl secret", "Secret.Namespace", sslSecret.Namespace, "Secret.Name", sslSecret.Name)
-		err = r.Client.Create(context.TODO(), sslSecret)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// object already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua KubeEnforcer SSL Secret Exists", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
-}
-
-func (r *AquaKubeEnforcerReconciler) addKEService(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
-	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create Service")
-	reqLogger.Info("Start creating service")
-
-	enforcerHelper := newAquaKubeEnforcerHelper(cr)
-	service := enforcerHelper.CreateKEService(cr.Name,
-		cr.Namespace,
-		consts.AquaKubeEnforcerClusterRoleBidingName,
-		"ke-service")
-
-	// Set AquaKubeEnforcer instance as the owner and controller
-	if err := controllerutil.SetControllerReference(cr, service, r.Scheme); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// Check if this object already exists
-	found := &corev1.Service{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua KubeEnforcer: Creating a New service", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
-		err = r.Client.Create(context.TODO(), service)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	// object already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua KubeEnforcer Service Exists", "Service.Namespace", found.Namespace, "Service.Name", found.Name)
-	return reconcile.Result{Requeue: true}, nil
+	return tokenSecret, nil
 }
 
 func (r *AquaKubeEnforcerReconciler) CreateImagePullSecret(cr *operatorv1alpha1.AquaKubeEnforcer) (reconcile.Result, error) {
 	reqLogger := log.WithValues("KubeEnforcer Requirements Phase", "Create Image Pull Secret")
 	reqLogger.Info("Start creating aqua images pull secret")
 
+	registry, err := secrets.GetRegistryData(r.Client, cr.Namespace, *cr.Spec.RegistryData)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Define a new secret object
 	secret := secrets.CreatePullImageSecret(
 		cr.Name,
 		cr.Namespace,
 		"ke-image-pull-secret",
 		cr.Spec.Config.ImagePullSecret,
-		*cr.Spec.RegistryData)
+		registry)
 
 	// Set AquaKubeEnforcerKind instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
@@ -1058,7 +810,7 @@ func (r *AquaKubeEnforcerReconciler) CreateImagePullSecret(cr *operatorv1alpha1.
 
 	// Check if this secret already exists
 	found := &corev1.Secret{}
-	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Creating a New Aqua Image Pull Secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
 		err = r.Client.Create(context.TODO(), secret)
@@ -1120,6 +872,8 @@ func (r *AquaKubeEnforcerReconciler) installAquaStarboard(cr *operatorv1alpha1.A
 			return reconcile.Result{Requeue: true, RequeueAfter: time.Duration(0)}, nil
 		}
 
+		// starboard is paused on its own CR
+		aquasb.Spec.Paused = found.Spec.Paused
 		update := !reflect.DeepEqual(aquasb.Spec, found.Spec)
 
 		reqLogger.Info("Checking for AquaStarboard Upgrade", "aquasb", aquasb.Spec, "found", found.Spec, "update bool", update)
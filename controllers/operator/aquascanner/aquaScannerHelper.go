@@ -62,7 +62,7 @@ func (as *AquaScannerHelper) CreateConfigMap(cr *v1alpha1.AquaScanner) *corev1.C
 	return configMap
 }
 
-func (as *AquaScannerHelper) CreateTokenSecret(cr *v1alpha1.AquaScanner) *corev1.Secret {
+func (as *AquaScannerHelper) CreateTokenSecret(cr *v1alpha1.AquaScanner, password string) *corev1.Secret {
 	labels := map[string]string{
 		"app":                cr.Name + "-requirments",
 		"deployedby":         "aqua-operator",
@@ -85,7 +85,7 @@ func (as *AquaScannerHelper) CreateTokenSecret(cr *v1alpha1.AquaScanner) *corev1
 		Type: corev1.SecretTypeOpaque,
 		Data: map[string][]byte{
 			"AQUA_SCANNER_USERNAME": []byte(cr.Spec.Login.Username),
-			"AQUA_SCANNER_PASSWORD": []byte(cr.Spec.Login.Password),
+			"AQUA_SCANNER_PASSWORD": []byte(password),
 		},
 	}
 
@@ -268,7 +268,20 @@ func (as *AquaScannerHelper) newDeployment(cr *v1alpha1.AquaScanner) *appsv1.Dep
 		deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, cr.Spec.ScannerService.Volumes...)
 	}
 
-	if len(cr.Spec.Login.Token) != 0 {
+	if cr.Spec.Login.TokenSecretRef != nil {
+		deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name: "AQUA_TOKEN",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cr.Spec.Login.TokenSecretRef.Name,
+					},
+					Key: cr.Spec.Login.TokenSecretRef.Key,
+				},
+			},
+		})
+		deployment.Spec.Template.Spec.Containers[0].Args = append(deployment.Spec.Template.Spec.Containers[0].Args, tokenarg...)
+	} else if len(cr.Spec.Login.Token) != 0 {
 		deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "AQUA_TOKEN", Value: cr.Spec.Login.Token})
 		deployment.Spec.Template.Spec.Containers[0].Args = append(deployment.Spec.Template.Spec.Containers[0].Args, tokenarg...)
 	} else {
@@ -18,6 +18,7 @@ package aquascanner
 
 import (
 	"context"
+	"fmt"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
 	"reflect"
 
@@ -25,10 +26,8 @@ import (
 	"github.com/aquasecurity/aqua-operator/pkg/consts"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
-	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -86,9 +85,28 @@ func (r *AquaScannerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return reconcile.Result{}, err
 	}
 
+	if common.ReconcilePaused(instance, instance.Spec.Paused) {
+		return reconcile.Result{}, nil
+	}
+
+	scrubbed, err := r.scrubPlaintextSecrets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if scrubbed {
+		reqLogger.Info("Moved plaintext credentials from the AquaScanner spec into secrets")
+		err = r.Client.Update(context.Background(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	instance = r.updateScannerObject(instance)
 	r.Client.Update(context.Background(), instance)
 
+	common.ResolveInfrastructureVersion(instance.Spec.Infrastructure, instance.Status.Version, instance.Spec.Common.AllowAnyVersion)
+
 	rbacHelper := common.NewAquaRbacHelper(
 		instance.Spec.Infrastructure,
 		instance.Name,
@@ -106,7 +124,7 @@ func (r *AquaScannerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, instance.Status.State) &&
 		!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentUpdateInProgress, instance.Status.State) {
 		instance.Status.State = operatorv1alpha1.AquaDeploymentStatePending
-		_ = r.Client.Status().Update(context.Background(), instance)
+		_ = common.UpdateStatus(r.Client, instance)
 	}
 
 	if instance.Spec.ScannerService != nil {
@@ -116,7 +134,11 @@ func (r *AquaScannerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
-	return ctrl.Result{}, nil
+	if store := common.GetSecretStore(instance.Spec.Common); store != nil {
+		return ctrl.Result{RequeueAfter: common.NewAquaSecretStoreHelper(store, instance.Namespace, r.Client, r.Scheme, instance).RefreshInterval()}, nil
+	}
+
+	return common.MaintenanceResult(instance.Status.Maintenance), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -136,6 +158,35 @@ func (r *AquaScannerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	----------------------------------------------------------------------------------------------------------------
 */
 
+// scrubPlaintextSecrets moves the plaintext login password and token of the spec into an owned secret and sets the matching refs
+func (r *AquaScannerReconciler) scrubPlaintextSecrets(cr *operatorv1alpha1.AquaScanner) (bool, error) {
+	if cr.Spec.Login == nil {
+		return false, nil
+	}
+
+	passwordMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.Login.Password,
+		&cr.Spec.Login.PasswordSecretRef,
+		fmt.Sprintf(consts.ScannerLoginSecretName, cr.Name),
+		consts.ScannerLoginPasswordKey,
+		"Aqua Scanner login password and token")
+	if err != nil {
+		return false, err
+	}
+
+	tokenMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.Login.Token,
+		&cr.Spec.Login.TokenSecretRef,
+		fmt.Sprintf(consts.ScannerLoginSecretName, cr.Name),
+		consts.ScannerLoginTokenKey,
+		"Aqua Scanner login password and token")
+	if err != nil {
+		return false, err
+	}
+
+	return passwordMoved || tokenMoved, nil
+}
+
 func (r *AquaScannerReconciler) updateScannerObject(cr *operatorv1alpha1.AquaScanner) *operatorv1alpha1.AquaScanner {
 	version := cr.Spec.Infrastructure.Version
 	if len(version) == 0 {
@@ -168,21 +219,28 @@ func (r *AquaScannerReconciler) InstallScannerDeployment(cr *operatorv1alpha1.Aq
 
 	deployment := scannerHelper.newDeployment(cr)
 
+	if err := common.MapPodImages(&deployment.Spec.Template.Spec); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := common.PinImages(r.Client, cr, common.GetImageVerification(cr.Spec.Common), &cr.Status.Images, &deployment.Spec.Template.Spec); err != nil {
+		reqLogger.Error(err, "Aqua Scanner: Image verification failed, keeping the current workload")
+		return reconcile.Result{}, err
+	}
+
 	// Set AquaScanner instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, deployment, r.Scheme); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 	// Check if this deployment already exists
 	found := &appsv1.Deployment{}
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Creating a New Aqua Scanner Deployment", "Dervice.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
-		err = patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment)
-		if err != nil {
-			reqLogger.Error(err, "Unable to set default for k8s-objectmatcher", err)
-		}
-		err = r.Client.Create(context.TODO(), deployment)
+		_, err = apply.Apply("AquaScanner deployment", deployment)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -193,16 +251,27 @@ func (r *AquaScannerReconciler) InstallScannerDeployment(cr *operatorv1alpha1.Aq
 	}
 
 	if found != nil {
-		update, err := k8s.CheckForK8sObjectUpdate("AquaScanner deployment", found, deployment)
+		drift := common.NewAquaDriftHelper(common.GetDriftPolicy(cr.Spec.Common), &cr.Status.Drifts, r.Client, cr)
+		if _, err = drift.Reconcile(found, deployment); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		maintenance := common.NewAquaMaintenanceHelper(cr.Spec.MaintenanceWindow, &cr.Status.Maintenance, r.Client, cr)
+		wait, err := maintenance.Defer(fmt.Sprintf("Deployment %s image update", found.Name),
+			common.ImagesChanged(found.Spec.Template.Spec, deployment.Spec.Template.Spec))
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+
+		update, err := apply.Apply("AquaScanner deployment", deployment)
+		if err != nil {
+			reqLogger.Error(err, "Aqua Scanner: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
+			return reconcile.Result{}, err
+		}
 		if update {
-			err = r.Client.Update(context.Background(), deployment)
-			if err != nil {
-				reqLogger.Error(err, "Aqua Scanner: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
-				return reconcile.Result{}, err
-			}
 			// Spec updated - return and requeue
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -230,11 +299,16 @@ func (r *AquaScannerReconciler) InstallScannerDeployment(cr *operatorv1alpha1.Aq
 			if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentUpdateInProgress, currentState) &&
 				!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStatePending, currentState) {
 				cr.Status.State = operatorv1alpha1.AquaDeploymentUpdateInProgress
-				_ = r.Client.Status().Update(context.Background(), cr)
+				_ = common.UpdateStatus(r.Client, cr)
 			}
 		} else if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, currentState) {
 			cr.Status.State = operatorv1alpha1.AquaDeploymentStateRunning
-			_ = r.Client.Status().Update(context.Background(), cr)
+			_ = common.UpdateStatus(r.Client, cr)
+		}
+
+		if k8s.IsDeploymentRolledOut(found) &&
+			common.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, cr.Spec.Infrastructure.Version) {
+			_ = common.UpdateStatus(r.Client, cr)
 		}
 	}
 
@@ -247,8 +321,22 @@ func (r *AquaScannerReconciler) addScannerSecret(cr *operatorv1alpha1.AquaScanne
 	reqLogger := log.WithValues("Scanner Requirements Phase", "Create Scanner Secret")
 	reqLogger.Info("Start creating Scanner secret")
 
+	store := common.GetSecretStore(cr.Spec.Common)
+	if store != nil && len(store.Keys.ScannerUsername) > 0 && len(store.Keys.ScannerPassword) > 0 {
+		return r.syncScannerSecret(cr)
+	}
+
+	password := cr.Spec.Login.Password
+	if cr.Spec.Login.PasswordSecretRef != nil {
+		value, err := secrets.GetSecretValue(r.Client, cr.Namespace, cr.Spec.Login.PasswordSecretRef)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		password = value
+	}
+
 	scannerHelper := newAquaScannerHelper(cr)
-	scannerSecret := scannerHelper.CreateTokenSecret(cr)
+	scannerSecret := scannerHelper.CreateTokenSecret(cr, password)
 	// Adding secret to the hashed data, for restart pods if token is changed
 	hash, err := extra.GenerateMD5ForSpec(scannerSecret.Data)
 	if err != nil {
@@ -256,41 +344,69 @@ func (r *AquaScannerReconciler) addScannerSecret(cr *operatorv1alpha1.AquaScanne
 	}
 	cr.Spec.ConfigMapChecksum += hash
 
+	if cr.Spec.Login.TokenSecretRef != nil {
+		token, err := secrets.GetSecretValue(r.Client, cr.Namespace, cr.Spec.Login.TokenSecretRef)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		hash, err := extra.GenerateMD5ForSpec(token)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		cr.Spec.ConfigMapChecksum += hash
+	}
+
 	// Set AquaScanner instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, scannerSecret, r.Scheme); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	// Check if this object already exists
-	found := &corev1.Secret{}
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: scannerSecret.Name, Namespace: scannerSecret.Namespace}, found)
-	if err != nil && errors.IsNotFound(err) {
-		reqLogger.Info("Aqua Scanner: Creating a New scanner secret", "Secret.Namespace", scannerSecret.Namespace, "Secret.Name", scannerSecret.Name)
-		err = r.Client.Create(context.TODO(), scannerSecret)
-		if err != nil {
-			return reconcile.Result{Requeue: true}, nil
-		}
-		return reconcile.Result{}, nil
-	} else if err != nil {
+	apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+	update, err := apply.Apply("AquaScanner secret", scannerSecret)
+	if err != nil {
+		log.Error(err, "Failed to apply Secret", "Secret.Namespace", scannerSecret.Namespace, "Secret.Name", scannerSecret.Name)
 		return reconcile.Result{}, err
 	}
-	// Check if the secret Data, matches the found Data
-	if !equality.Semantic.DeepDerivative(scannerSecret.Data, found.Data) {
-		found = scannerSecret
-		log.Info("Aqua Scanner: Updating Scanner Token Secret", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
-		err := r.Client.Update(context.TODO(), found)
-		if err != nil {
-			log.Error(err, "Failed to update Secret", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
-			return reconcile.Result{}, err
-		}
+	if update {
 		return reconcile.Result{Requeue: true}, nil
 	}
 
 	// object already exists - don't requeue
-	reqLogger.Info("Skip reconcile: Aqua Scanner Secret Exists", "Secret.Namespace", found.Namespace, "Secret.Name", found.Name)
+	reqLogger.Info("Skip reconcile: Aqua Scanner Secret Exists", "Secret.Namespace", scannerSecret.Namespace, "Secret.Name", scannerSecret.Name)
 	return reconcile.Result{Requeue: true}, nil
 }
 
+// syncScannerSecret materializes the scanner username and password from the secret store
+func (r *AquaScannerReconciler) syncScannerSecret(cr *operatorv1alpha1.AquaScanner) (reconcile.Result, error) {
+	store := cr.Spec.Common.SecretStore
+	storeHelper := common.NewAquaSecretStoreHelper(store, cr.Namespace, r.Client, r.Scheme, cr)
+	_, err := storeHelper.SyncSecretKeys(consts.ScannerSecretName,
+		cr.Name+"-requirments",
+		"Aqua Scanner username and password",
+		map[string]string{
+			"AQUA_SCANNER_USERNAME": store.Keys.ScannerUsername,
+			"AQUA_SCANNER_PASSWORD": store.Keys.ScannerPassword,
+		})
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Adding secret to the hashed data, for restart pods if the credentials are changed
+	found := &corev1.Secret{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: consts.ScannerSecretName, Namespace: cr.Namespace}, found)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	hash, err := extra.GenerateMD5ForSpec(found.Data)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	cr.Spec.ConfigMapChecksum += hash
+
+	return reconcile.Result{}, nil
+}
+
 func (r *AquaScannerReconciler) addScannerConfigMap(cr *operatorv1alpha1.AquaScanner) (reconcile.Result, error) {
 	reqLogger := log.WithValues("Scanner Requirements Phase", "Create ConfigMap")
 	reqLogger.Info("Start creating ConfigMap")
@@ -312,12 +428,14 @@ func (r *AquaScannerReconciler) addScannerConfigMap(cr *operatorv1alpha1.AquaSca
 		return reconcile.Result{}, err
 	}
 
+	apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 	// Check if this ConfigMap already exists
 	foundConfigMap := &corev1.ConfigMap{}
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, foundConfigMap)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Aqua Scanner: Creating a New ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
-		err = r.Client.Create(context.TODO(), configMap)
+		_, err = apply.Apply("AquaScanner configmap", configMap)
 		if err != nil {
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -327,15 +445,20 @@ func (r *AquaScannerReconciler) addScannerConfigMap(cr *operatorv1alpha1.AquaSca
 		return reconcile.Result{}, err
 	}
 
-	// Check if the ConfigMap Data, matches the found Data
-	if !equality.Semantic.DeepDerivative(configMap.Data, foundConfigMap.Data) {
-		foundConfigMap = configMap
-		log.Info("Aqua Scanner: Updating ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-		err := r.Client.Update(context.TODO(), foundConfigMap)
-		if err != nil {
-			log.Error(err, "Failed to update ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-			return reconcile.Result{}, err
-		}
+	drift := common.NewAquaDriftHelper(common.GetDriftPolicy(cr.Spec.Common), &cr.Status.Drifts, r.Client, cr)
+	_, err = drift.Reconcile(foundConfigMap, configMap)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Apply the ConfigMap Data, reverting the manual changes the drift policy enforces
+	update, err := apply.Apply("AquaScanner configmap", configMap)
+	if err != nil {
+		log.Error(err, "Failed to apply ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
+		return reconcile.Result{}, err
+	}
+	if update {
+		log.Info("Aqua Scanner: Applied ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
 		return reconcile.Result{Requeue: true}, nil
 	}
 
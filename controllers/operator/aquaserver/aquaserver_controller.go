@@ -26,11 +26,9 @@ import (
 	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s/secrets"
-	"github.com/banzaicloud/k8s-objectmatcher/patch"
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -62,11 +60,13 @@ type AquaServerReconciler struct {
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=route,resources=routes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -94,9 +94,28 @@ func (r *AquaServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return reconcile.Result{}, err
 	}
 
+	if common.ReconcilePaused(instance, instance.Spec.Paused) {
+		return reconcile.Result{}, nil
+	}
+
+	scrubbed, err := r.scrubPlaintextSecrets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if scrubbed {
+		reqLogger.Info("Moved plaintext credentials from the AquaServer spec into secrets")
+		err = r.Client.Update(context.Background(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	instance = r.updateServerObject(instance)
 	r.Client.Update(context.Background(), instance)
 
+	common.ResolveInfrastructureVersion(instance.Spec.Infrastructure, instance.Status.Version, instance.Spec.Common.AllowAnyVersion)
+
 	rbacHelper := common.NewAquaRbacHelper(
 		instance.Spec.Infrastructure,
 		instance.Name,
@@ -114,7 +133,7 @@ func (r *AquaServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, instance.Status.State) &&
 		!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentUpdateInProgress, instance.Status.State) {
 		instance.Status.State = operatorv1alpha1.AquaDeploymentStatePending
-		_ = r.Client.Status().Update(context.Background(), instance)
+		_ = common.UpdateStatus(r.Client, instance)
 	}
 
 	if instance.Spec.ServerService != nil {
@@ -174,6 +193,14 @@ func (r *AquaServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			instance.Spec.AuditDB = common.UpdateAquaAuditDB(instance.Spec.AuditDB, instance.Name)
 		}
 
+		if common.GetSecretStore(instance.Spec.Common) != nil {
+			reqLogger.Info("Start Syncing Secrets From Secret Store")
+			err = r.SyncSecretStore(instance)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+
 		reqLogger.Info("Start Creating Aqua server ConfigMap")
 		_, err = r.CreateServerConfigMap(instance)
 		if err != nil {
@@ -193,7 +220,11 @@ func (r *AquaServerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	}
 
-	return ctrl.Result{}, nil
+	if store := common.GetSecretStore(instance.Spec.Common); store != nil {
+		return ctrl.Result{RequeueAfter: common.NewAquaSecretStoreHelper(store, instance.Namespace, r.Client, r.Scheme, instance).RefreshInterval()}, nil
+	}
+
+	return common.MaintenanceResult(instance.Status.Maintenance), nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -222,6 +253,40 @@ func (r *AquaServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	----------------------------------------------------------------------------------------------------------------
 */
 
+// scrubPlaintextSecrets moves the plaintext credentials of the spec into owned secrets and sets the matching refs
+func (r *AquaServerReconciler) scrubPlaintextSecrets(cr *operatorv1alpha1.AquaServer) (bool, error) {
+	if cr.Spec.Common == nil {
+		cr.Spec.Common = &operatorv1alpha1.AquaCommon{}
+	}
+
+	adminMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.AdminPassword,
+		&cr.Spec.Common.AdminPassword,
+		fmt.Sprintf(consts.AdminPasswordSecretName, cr.Name),
+		consts.AdminPasswordSecretKey,
+		"Secret for aqua admin password")
+	if err != nil {
+		return false, err
+	}
+
+	licenseMoved, err := common.MoveToSecret(r.Client, r.Scheme, cr,
+		&cr.Spec.LicenseToken,
+		&cr.Spec.Common.AquaLicense,
+		fmt.Sprintf(consts.LicenseTokenSecretName, cr.Name),
+		consts.LicenseTokenSecretKey,
+		"Secret for aqua license token")
+	if err != nil {
+		return false, err
+	}
+
+	dbMoved, err := common.MoveDatabasePasswords(r.Client, r.Scheme, cr, cr.Spec.Common, cr.Spec.ExternalDb, cr.Spec.AuditDB)
+	if err != nil {
+		return false, err
+	}
+
+	return adminMoved || licenseMoved || dbMoved, nil
+}
+
 func (r *AquaServerReconciler) updateServerObject(cr *operatorv1alpha1.AquaServer) *operatorv1alpha1.AquaServer {
 	admin := false
 	license := false
@@ -234,6 +299,11 @@ func (r *AquaServerReconciler) updateServerObject(cr *operatorv1alpha1.AquaServe
 		license = true
 	}
 
+	if store := common.GetSecretStore(cr.Spec.Common); store != nil {
+		admin = admin || len(store.Keys.AdminPassword) != 0
+		license = license || len(store.Keys.LicenseToken) != 0
+	}
+
 	cr.Spec.Infrastructure = common.UpdateAquaInfrastructure(cr.Spec.Infrastructure, cr.Name, cr.Namespace)
 	cr.Spec.Common = common.UpdateAquaCommon(cr.Spec.Common, cr.Name, admin, license)
 
@@ -263,21 +333,34 @@ func (r *AquaServerReconciler) InstallServerDeployment(cr *operatorv1alpha1.Aqua
 	serverHelper := newAquaServerHelper(cr)
 	deployment := serverHelper.newDeployment(cr)
 
+	if cr.Status.Rollout == nil {
+		cr.Status.Rollout = &operatorv1alpha1.AquaRolloutStatus{}
+	}
+	rollout := common.NewAquaRolloutHelper(cr.Spec.ServerService.Rollout, cr.Status.Rollout, &cr.Status.Conditions, r.Client, r.Scheme, cr)
+	rollout.SetProgressDeadline(deployment)
+
+	if err := common.MapPodImages(&deployment.Spec.Template.Spec); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := common.PinImages(r.Client, cr, common.GetImageVerification(cr.Spec.Common), &cr.Status.Images, &deployment.Spec.Template.Spec); err != nil {
+		reqLogger.Error(err, "Aqua Server: Image verification failed, keeping the current workload")
+		return reconcile.Result{}, err
+	}
+
 	// Set AquaServer instance as the owner and controller
 	if err := controllerutil.SetControllerReference(cr, deployment, r.Scheme); err != nil {
 		return reconcile.Result{}, err
 	}
 
+	apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 	// Check if this deployment already exists
 	found := &appsv1.Deployment{}
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Creating a New Aqua Server Deployment", "Dervice.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
-		err = patch.DefaultAnnotator.SetLastAppliedAnnotation(deployment)
-		if err != nil {
-			reqLogger.Error(err, "Unable to set default for k8s-objectmatcher", err)
-		}
-		err = r.Client.Create(context.TODO(), deployment)
+		_, err = apply.Apply("AquaServer deployment", deployment)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -288,16 +371,27 @@ func (r *AquaServerReconciler) InstallServerDeployment(cr *operatorv1alpha1.Aqua
 	}
 
 	if found != nil {
-		update, err := k8s.CheckForK8sObjectUpdate("AquaServer deployment", found, deployment)
+		drift := common.NewAquaDriftHelper(common.GetDriftPolicy(cr.Spec.Common), &cr.Status.Drifts, r.Client, cr)
+		if _, err = drift.Reconcile(found, deployment); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		maintenance := common.NewAquaMaintenanceHelper(cr.Spec.MaintenanceWindow, &cr.Status.Maintenance, r.Client, cr)
+		wait, err := maintenance.Defer(fmt.Sprintf("Deployment %s image update", found.Name),
+			common.ImagesChanged(found.Spec.Template.Spec, deployment.Spec.Template.Spec))
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if wait > 0 {
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+
+		update, err := rollout.ReconcileDeployment("AquaServer deployment", found, deployment)
+		if err != nil {
+			reqLogger.Error(err, "Aqua Server: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
+			return reconcile.Result{}, err
+		}
 		if update {
-			err = r.Client.Update(context.Background(), deployment)
-			if err != nil {
-				reqLogger.Error(err, "Aqua Server: Failed to update Deployment.", "Deployment.Namespace", found.Namespace, "Deployment.Name", found.Name)
-				return reconcile.Result{}, err
-			}
 			// Spec updated - return and requeue
 			return reconcile.Result{Requeue: true}, nil
 		}
@@ -352,7 +446,7 @@ func (r *AquaServerReconciler) InstallServerDeployment(cr *operatorv1alpha1.Aqua
 		// Update status.Nodes if needed
 		if !reflect.DeepEqual(podNames, nodes) {
 			cr.Status.Nodes = podNames
-			r.Client.Status().Update(context.TODO(), cr)
+			common.UpdateStatus(r.Client, cr)
 		}
 
 		currentState := cr.Status.State
@@ -360,11 +454,18 @@ func (r *AquaServerReconciler) InstallServerDeployment(cr *operatorv1alpha1.Aqua
 			if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentUpdateInProgress, currentState) &&
 				!reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStatePending, currentState) {
 				cr.Status.State = operatorv1alpha1.AquaDeploymentUpdateInProgress
-				_ = r.Client.Status().Update(context.Background(), cr)
+				_ = common.UpdateStatus(r.Client, cr)
 			}
 		} else if !reflect.DeepEqual(operatorv1alpha1.AquaDeploymentStateRunning, currentState) {
 			cr.Status.State = operatorv1alpha1.AquaDeploymentStateRunning
-			_ = r.Client.Status().Update(context.Background(), cr)
+			_ = common.UpdateStatus(r.Client, cr)
+		}
+
+		// A rolled back deployment doesn't run the version of the spec
+		if k8s.IsDeploymentRolledOut(found) &&
+			(cr.Status.Rollout == nil || len(cr.Status.Rollout.RolledBackFrom) == 0) &&
+			common.UpdateVersionStatus(&cr.Status.Version, &cr.Status.AvailableUpgrades, cr.Spec.Infrastructure.Version) {
+			_ = common.UpdateStatus(r.Client, cr)
 		}
 	}
 
@@ -419,6 +520,57 @@ func (r *AquaServerReconciler) InstallServerService(cr *operatorv1alpha1.AquaSer
 	return reconcile.Result{Requeue: true}, nil
 }
 
+// SyncSecretStore materializes the admin password, license and external database passwords from the secret store
+func (r *AquaServerReconciler) SyncSecretStore(cr *operatorv1alpha1.AquaServer) error {
+	store := cr.Spec.Common.SecretStore
+	storeHelper := common.NewAquaSecretStoreHelper(store, cr.Namespace, r.Client, r.Scheme, cr)
+	app := fmt.Sprintf("%s-server", cr.Name)
+
+	if len(store.Keys.AdminPassword) > 0 && cr.Spec.Common.AdminPassword != nil {
+		_, err := storeHelper.SyncSecretKeys(cr.Spec.Common.AdminPassword.Name,
+			app,
+			"Secret for aqua admin password",
+			map[string]string{cr.Spec.Common.AdminPassword.Key: store.Keys.AdminPassword})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(store.Keys.LicenseToken) > 0 && cr.Spec.Common.AquaLicense != nil {
+		_, err := storeHelper.SyncSecretKeys(cr.Spec.Common.AquaLicense.Name,
+			app,
+			"Secret for aqua license token",
+			map[string]string{cr.Spec.Common.AquaLicense.Key: store.Keys.LicenseToken})
+		if err != nil {
+			return err
+		}
+	}
+
+	if cr.Spec.ExternalDb != nil {
+		if len(store.Keys.DbPassword) > 0 && cr.Spec.Common.DatabaseSecret != nil {
+			_, err := storeHelper.SyncSecretKeys(cr.Spec.Common.DatabaseSecret.Name,
+				app,
+				"Secret for aqua database password",
+				map[string]string{cr.Spec.Common.DatabaseSecret.Key: store.Keys.DbPassword})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(store.Keys.AuditDbPassword) > 0 && cr.Spec.AuditDB != nil && cr.Spec.AuditDB.AuditDBSecret != nil {
+			_, err := storeHelper.SyncSecretKeys(cr.Spec.AuditDB.AuditDBSecret.Name,
+				app,
+				"Secret for aqua audit database password",
+				map[string]string{cr.Spec.AuditDB.AuditDBSecret.Key: store.Keys.AuditDbPassword})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (r *AquaServerReconciler) CreateAdminPasswordSecret(cr *operatorv1alpha1.AquaServer) (reconcile.Result, error) {
 	reqLogger := log.WithValues("AquaServer Requirements Phase", "Create Server Secrets")
 	reqLogger.Info("Start creating aqua server admin password secret")
@@ -514,12 +666,14 @@ func (r *AquaServerReconciler) CreateServerConfigMap(cr *operatorv1alpha1.AquaSe
 		return reconcile.Result{}, err
 	}
 
+	apply := common.NewAquaApplyHelper(r.Client, r.Scheme, cr)
+
 	// Check if this ConfigMap already exists
 	foundConfigMap := &corev1.ConfigMap{}
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, foundConfigMap)
 	if err != nil && errors.IsNotFound(err) {
 		reqLogger.Info("Aqua Server: Creating a New ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
-		err = r.Client.Create(context.TODO(), configMap)
+		_, err = apply.Apply("AquaServer configmap", configMap)
 
 		if err != nil {
 			return reconcile.Result{Requeue: true}, nil
@@ -530,16 +684,20 @@ func (r *AquaServerReconciler) CreateServerConfigMap(cr *operatorv1alpha1.AquaSe
 		return reconcile.Result{}, err
 	}
 
-	// Check if the ConfigMap Data, matches the found Data
-	if !equality.Semantic.DeepDerivative(configMap.Data, foundConfigMap.Data) {
-		foundConfigMap = configMap
-		log.Info("Aqua Server: Updating ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-		err := r.Client.Update(context.TODO(), foundConfigMap)
-		if err != nil {
-			log.Error(err, "Aqua Server: Failed to update ConfigMap", "ConfigMap.Namespace", foundConfigMap.Namespace, "ConfigMap.Name", foundConfigMap.Name)
-			return reconcile.Result{}, err
-		}
+	drift := common.NewAquaDriftHelper(common.GetDriftPolicy(cr.Spec.Common), &cr.Status.Drifts, r.Client, cr)
+	_, err = drift.Reconcile(foundConfigMap, configMap)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
+	// Apply the ConfigMap Data, reverting the manual changes the drift policy enforces
+	update, err := apply.Apply("AquaServer configmap", configMap)
+	if err != nil {
+		log.Error(err, "Aqua Server: Failed to apply ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
+		return reconcile.Result{}, err
+	}
+	if update {
+		log.Info("Aqua Server: Applied ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name)
 		return reconcile.Result{Requeue: true}, nil
 	}
 
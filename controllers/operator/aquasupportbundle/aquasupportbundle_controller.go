@@ -0,0 +1,215 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aquasupportbundle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/supportbundle"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+)
+
+var log = logf.Log.WithName("controller_aquasupportbundle")
+
+// AquaSupportBundleReconciler reconciles a AquaSupportBundle object
+type AquaSupportBundleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Reader Reads the bundle content from the API server, so the events and pods aren't cached
+	Reader client.Reader
+	// Clientset Reads the pod logs
+	Clientset kubernetes.Interface
+}
+
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquasupportbundles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquasupportbundles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=operator.aquasec.com,resources=aquasupportbundles/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;
+//+kubebuilder:rbac:groups=core,resources=pods/log,verbs=get
+//+kubebuilder:rbac:groups=core,resources=events,verbs=get;list
+
+// Reconcile collects the bundle once, a completed or failed AquaSupportBundle is left as is. Create a new one
+// to collect again.
+func (r *AquaSupportBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling AquaSupportBundle")
+
+	// Fetch the AquaSupportBundle instance
+	instance := &operatorv1alpha1.AquaSupportBundle{}
+	err := r.Client.Get(context.TODO(), req.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, err
+	}
+
+	if len(instance.Status.State) > 0 {
+		return reconcile.Result{}, nil
+	}
+
+	logSince, err := time.ParseDuration(valueOr(instance.Spec.LogSince, consts.SupportBundleLogSince))
+	if err != nil {
+		return reconcile.Result{}, r.setFailed(instance, fmt.Sprintf("invalid logSince: %v", err))
+	}
+
+	tailLines := instance.Spec.TailLines
+	if tailLines == 0 {
+		tailLines = consts.SupportBundleTailLines
+	}
+
+	collector := supportbundle.NewCollector(r.Reader, r.Clientset, supportbundle.Options{
+		Namespace:         instance.Namespace,
+		LogSince:          logSince,
+		TailLines:         tailLines,
+		OperatorNamespace: extra.GetOperatorNamespace(),
+	})
+
+	bundle := &bytes.Buffer{}
+	err = collector.Write(bundle)
+	if err != nil {
+		return reconcile.Result{}, r.setFailed(instance, fmt.Sprintf("collecting the bundle: %v", err))
+	}
+
+	secretNames, err := r.storeBundle(instance, bundle.Bytes())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	now := metav1.Now()
+	instance.Status.State = operatorv1alpha1.AquaSupportBundleStateCompleted
+	instance.Status.Message = fmt.Sprintf("Bundle stored in %d secrets", len(secretNames))
+	instance.Status.Secrets = secretNames
+	instance.Status.Size = int64(bundle.Len())
+	instance.Status.CompletionTime = &now
+	instance.Status.Errors = collector.Errors
+	reqLogger.Info("Collected the support bundle", "Size", bundle.Len(), "Secrets", len(secretNames))
+
+	return reconcile.Result{}, r.Client.Status().Update(context.Background(), instance)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AquaSupportBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("aquasupportbundle-controller").
+		WithOptions(controller.Options{Reconciler: r}).
+		For(&operatorv1alpha1.AquaSupportBundle{}).
+		Complete(r)
+}
+
+/*	----------------------------------------------------------------------------------------------------------------
+							Aqua Support Bundle
+	----------------------------------------------------------------------------------------------------------------
+*/
+
+// storeBundle splits the bundle into secrets owned by the cr, a secret left by an interrupted run is overwritten
+func (r *AquaSupportBundleReconciler) storeBundle(cr *operatorv1alpha1.AquaSupportBundle, bundle []byte) ([]string, error) {
+	reqLogger := log.WithValues("Support Bundle Phase", "Store Bundle")
+	prefix := valueOr(cr.Spec.SecretName, fmt.Sprintf(consts.SupportBundleSecretName, cr.Name))
+
+	names := []string{}
+	for part := 0; part*consts.SupportBundleChunkSize < len(bundle); part++ {
+		end := (part + 1) * consts.SupportBundleChunkSize
+		if end > len(bundle) {
+			end = len(bundle)
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", prefix, part),
+				Namespace: cr.Namespace,
+				Labels: map[string]string{
+					"app":                cr.Name + "-support-bundle",
+					"aquasecoperator_cr": cr.Name,
+				},
+				Annotations: map[string]string{
+					"description": fmt.Sprintf("Part %d of the Aqua support bundle %s", part, cr.Name),
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				consts.SupportBundleSecretKey: bundle[part*consts.SupportBundleChunkSize : end],
+			},
+		}
+
+		err := controllerutil.SetControllerReference(cr, secret, r.Scheme)
+		if err != nil {
+			return nil, err
+		}
+
+		found := &corev1.Secret{}
+		err = r.Client.Get(context.TODO(), types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, found)
+		if err != nil && errors.IsNotFound(err) {
+			reqLogger.Info("Creating a New Support Bundle Secret", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+			err = r.Client.Create(context.TODO(), secret)
+		} else if err == nil {
+			if !metav1.IsControlledBy(found, cr) {
+				return nil, fmt.Errorf("secret %s already exists and isn't owned by AquaSupportBundle %s", secret.Name, cr.Name)
+			}
+			found.Data = secret.Data
+			err = r.Client.Update(context.TODO(), found)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		names = append(names, secret.Name)
+	}
+
+	return names, nil
+}
+
+func (r *AquaSupportBundleReconciler) setFailed(cr *operatorv1alpha1.AquaSupportBundle, message string) error {
+	now := metav1.Now()
+	cr.Status.State = operatorv1alpha1.AquaSupportBundleStateFailed
+	cr.Status.Message = message
+	cr.Status.CompletionTime = &now
+
+	return r.Client.Status().Update(context.Background(), cr)
+}
+
+func valueOr(value, fallback string) string {
+	if len(value) == 0 {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,74 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/banzaicloud/k8s-objectmatcher/patch"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Diff compares the rendered objects with the objects of the cluster, it prints the objects that would be
+// created and the patch of the objects that would be updated. Status fields are ignored.
+func Diff(live client.Reader, rendered []*unstructured.Unstructured, out io.Writer) error {
+	unchanged := 0
+
+	for _, obj := range rendered {
+		name := fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+		if len(obj.GetNamespace()) > 0 {
+			name = fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		}
+
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(obj.GroupVersionKind())
+		err := live.Get(context.TODO(), client.ObjectKeyFromObject(obj), found)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "# %s would be created\n%s---\n", name, data)
+			continue
+		}
+
+		// owner references are merged by uid, take the uids of the live owners
+		desired := obj.DeepCopy()
+		owners := []metav1.OwnerReference{}
+		for _, owner := range desired.GetOwnerReferences() {
+			for _, liveOwner := range found.GetOwnerReferences() {
+				if owner.Kind == liveOwner.Kind && owner.Name == liveOwner.Name {
+					owner.UID = liveOwner.UID
+					owners = append(owners, owner)
+				}
+			}
+		}
+		desired.SetOwnerReferences(owners)
+
+		result, err := patch.DefaultPatchMaker.Calculate(found, desired, patch.IgnoreStatusFields())
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if result.IsEmpty() {
+			unchanged++
+			continue
+		}
+
+		data, err := yaml.JSONToYAML(result.Patch)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "# %s would be updated with\n%s---\n", name, data)
+	}
+
+	fmt.Fprintf(out, "# %d objects unchanged\n", unchanged)
+	return nil
+}
@@ -0,0 +1,585 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	aquasecurityv1alpha1 "github.com/aquasecurity/aqua-operator/apis/aquasecurity/v1alpha1"
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/controllers/aquasecurity/aquastarboard"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquacsp"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquadatabase"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquaenforcer"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquagateway"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquakubeenforcer"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquascanner"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquaserver"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/operatorconfig"
+	routev1 "github.com/openshift/api/route/v1"
+	"go.uber.org/zap/zapcore"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sRuntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+// maxRounds Reconcile rounds run before giving up on the rendered objects to settle
+const maxRounds = 10
+
+// renderedTypes The objects the controllers generate, printed in this order. Secrets are never printed.
+var renderedTypes = []client.ObjectList{
+	&corev1.ServiceAccountList{},
+	&rbacv1.ClusterRoleList{},
+	&rbacv1.ClusterRoleBindingList{},
+	&rbacv1.RoleList{},
+	&rbacv1.RoleBindingList{},
+	&corev1.ConfigMapList{},
+	&corev1.PersistentVolumeClaimList{},
+	&corev1.ServiceList{},
+	&appsv1.DeploymentList{},
+	&appsv1.DaemonSetList{},
+	&batchv1.JobList{},
+	&admissionv1.ValidatingWebhookConfigurationList{},
+	&admissionv1.MutatingWebhookConfigurationList{},
+	&routev1.RouteList{},
+	&operatorv1alpha1.AquaDatabaseList{},
+	&operatorv1alpha1.AquaServerList{},
+	&operatorv1alpha1.AquaGatewayList{},
+	&operatorv1alpha1.AquaEnforcerList{},
+	&operatorv1alpha1.AquaKubeEnforcerList{},
+	&operatorv1alpha1.AquaScannerList{},
+	&aquasecurityv1alpha1.AquaStarboardList{},
+	&aquasecurityv1alpha1.AquaSecuritySummaryList{},
+}
+
+// Options The render subcommand flags
+type Options struct {
+	Files     []string
+	Namespace string
+	Diff      bool
+	Verbose   bool
+}
+
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// NewScheme returns the scheme of the objects the operator reads and generates, the openshift route API
+// is always registered since no cluster is checked for it
+func NewScheme() *k8sRuntime.Scheme {
+	scheme := k8sRuntime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(aquasecurityv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(routev1.AddToScheme(scheme))
+
+	return scheme
+}
+
+// Run runs the render subcommand, args are the arguments following "render"
+func Run(args []string, out io.Writer) error {
+	options := Options{}
+	files := fileList{}
+
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.Var(&files, "f", "File with the CRs to render, - reads stdin. Can be repeated.")
+	flags.StringVar(&options.Namespace, "namespace", "aqua", "Namespace of the objects that don't set one.")
+	flags.BoolVar(&options.Diff, "diff", false, "Compare the rendered objects with the objects of the current cluster.")
+	flags.BoolVar(&options.Verbose, "v", false, "Print the controllers logs to stderr.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	options.Files = files
+
+	if len(options.Files) == 0 {
+		return fmt.Errorf("no input, pass the CRs to render with -f")
+	}
+
+	// the operator runs in the namespace of the CRs, the kube-enforcer certificates are issued for it
+	if _, found := os.LookupEnv("WATCH_NAMESPACE"); !found {
+		if err := os.Setenv("WATCH_NAMESPACE", options.Namespace); err != nil {
+			return err
+		}
+	}
+
+	logs := io.Discard
+	if options.Verbose {
+		logs = os.Stderr
+	}
+	logf.SetLogger(zap.New(zap.WriteTo(logs), zap.Level(zapcore.InfoLevel)))
+
+	scheme := NewScheme()
+
+	inputs := []client.Object{}
+	for _, file := range options.Files {
+		objects, err := readObjects(scheme, file, options.Namespace)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, objects...)
+	}
+
+	rendered, err := Render(scheme, inputs)
+	if err != nil {
+		return err
+	}
+
+	if options.Diff {
+		cfg, err := ctrl.GetConfig()
+		if err != nil {
+			return err
+		}
+		live, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			return err
+		}
+		return Diff(live, rendered, out)
+	}
+
+	return Print(rendered, out)
+}
+
+// Render runs the controllers of the input CRs against an in-memory client, seeded with the inputs, until the
+// generated objects settle and returns them. Every generated deployment, daemonset and CR is reported healthy
+// between rounds so the objects created after a readiness check are rendered too. Image digests are not
+// resolved and secret stores are not synced since both require network access.
+func Render(scheme *k8sRuntime.Scheme, inputs []client.Object) ([]*unstructured.Unstructured, error) {
+	for _, input := range inputs {
+		disableNetworkAccess(input)
+	}
+
+	for _, input := range inputs {
+		setUID(scheme, input)
+	}
+
+	k8sclient := &renderClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(inputs...).Build()}
+	operatorconfig.SetReader(k8sclient)
+	defer operatorconfig.SetReader(nil)
+
+	reconcilers := []struct {
+		list       client.ObjectList
+		reconciler reconcile.Reconciler
+	}{
+		{&operatorv1alpha1.AquaCspList{}, &aquacsp.AquaCspReconciler{Client: k8sclient, Scheme: scheme}},
+		{&operatorv1alpha1.AquaDatabaseList{}, &aquadatabase.AquaDatabaseReconciler{Client: k8sclient, Scheme: scheme}},
+		{&operatorv1alpha1.AquaServerList{}, &aquaserver.AquaServerReconciler{Client: k8sclient, Scheme: scheme}},
+		{&operatorv1alpha1.AquaGatewayList{}, &aquagateway.AquaGatewayReconciler{Client: k8sclient, Scheme: scheme}},
+		{&operatorv1alpha1.AquaEnforcerList{}, &aquaenforcer.AquaEnforcerReconciler{Client: k8sclient, Scheme: scheme, Reader: k8sclient}},
+		{&operatorv1alpha1.AquaKubeEnforcerList{}, &aquakubeenforcer.AquaKubeEnforcerReconciler{Client: k8sclient, Scheme: scheme, Certs: aquakubeenforcer.GetKECerts()}},
+		{&operatorv1alpha1.AquaScannerList{}, &aquascanner.AquaScannerReconciler{Client: k8sclient, Scheme: scheme}},
+		{&aquasecurityv1alpha1.AquaStarboardList{}, &aquastarboard.AquaStarboardReconciler{Client: k8sclient, Scheme: scheme}},
+	}
+
+	skip := map[string]bool{}
+	for _, input := range inputs {
+		skip[objectKey(scheme, input)] = true
+	}
+
+	var previous []byte
+	for round := 0; round < maxRounds; round++ {
+		var roundErr error
+		for _, r := range reconcilers {
+			err := k8sclient.List(context.TODO(), r.list)
+			if err != nil {
+				return nil, err
+			}
+
+			items, err := meta.ExtractList(r.list)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range items {
+				obj := item.(client.Object)
+				_, err = r.reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+				if err != nil {
+					roundErr = fmt.Errorf("%s %s/%s: %v", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName(), err)
+				}
+			}
+		}
+
+		err := markHealthy(k8sclient)
+		if err != nil {
+			return nil, err
+		}
+
+		rendered, err := collect(k8sclient, scheme, skip)
+		if err != nil {
+			return nil, err
+		}
+
+		current := &bytes.Buffer{}
+		if err = Print(rendered, current); err != nil {
+			return nil, err
+		}
+
+		if roundErr == nil && bytes.Equal(previous, current.Bytes()) {
+			return rendered, nil
+		}
+		if round == maxRounds-1 && roundErr != nil {
+			return nil, roundErr
+		}
+		previous = current.Bytes()
+	}
+
+	return nil, fmt.Errorf("the rendered objects did not settle after %d rounds", maxRounds)
+}
+
+// Print writes the objects as a multi document YAML
+func Print(objects []*unstructured.Unstructured, out io.Writer) error {
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			if _, err = fmt.Fprintln(out, "---"); err != nil {
+				return err
+			}
+		}
+		if _, err = out.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readObjects(scheme *k8sRuntime.Scheme, file, namespace string) ([]client.Object, error) {
+	var input io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(input))
+
+	objects := []client.Object{}
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		decoded, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+
+		obj, ok := decoded.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported object %T", file, decoded)
+		}
+		if len(obj.GetNamespace()) == 0 {
+			obj.SetNamespace(namespace)
+		}
+		obj.SetResourceVersion("")
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// disableNetworkAccess clears the settings that make the controllers reach a registry or a secret store,
+// the child CRs copy them from the inputs
+func disableNetworkAccess(obj client.Object) {
+	var common *operatorv1alpha1.AquaCommon
+
+	switch cr := obj.(type) {
+	case *operatorv1alpha1.AquaCsp:
+		common = cr.Spec.Common
+	case *operatorv1alpha1.AquaDatabase:
+		common = cr.Spec.Common
+	case *operatorv1alpha1.AquaServer:
+		common = cr.Spec.Common
+	case *operatorv1alpha1.AquaGateway:
+		common = cr.Spec.Common
+	case *operatorv1alpha1.AquaEnforcer:
+		common = cr.Spec.Common
+	case *operatorv1alpha1.AquaScanner:
+		common = cr.Spec.Common
+	case *operatorv1alpha1.AquaKubeEnforcer:
+		cr.Spec.ImageVerification = nil
+	}
+
+	if common != nil {
+		common.ImageVerification = nil
+		common.SecretStore = nil
+	}
+}
+
+// markHealthy reports the generated workloads and CRs as rolled out and running
+func markHealthy(k8sclient client.Client) error {
+	deployments := &appsv1.DeploymentList{}
+	if err := k8sclient.List(context.TODO(), deployments); err != nil {
+		return err
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		deployment.Status = appsv1.DeploymentStatus{
+			ObservedGeneration: deployment.Generation,
+			Replicas:           replicas,
+			UpdatedReplicas:    replicas,
+			ReadyReplicas:      replicas,
+			AvailableReplicas:  replicas,
+		}
+		if err := k8sclient.Status().Update(context.TODO(), deployment); err != nil {
+			return err
+		}
+	}
+
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := k8sclient.List(context.TODO(), daemonSets); err != nil {
+		return err
+	}
+	for i := range daemonSets.Items {
+		daemonSet := &daemonSets.Items[i]
+		daemonSet.Status = appsv1.DaemonSetStatus{
+			ObservedGeneration:     daemonSet.Generation,
+			CurrentNumberScheduled: 1,
+			DesiredNumberScheduled: 1,
+			UpdatedNumberScheduled: 1,
+			NumberReady:            1,
+			NumberAvailable:        1,
+		}
+		if err := k8sclient.Status().Update(context.TODO(), daemonSet); err != nil {
+			return err
+		}
+	}
+
+	for _, list := range []client.ObjectList{
+		&operatorv1alpha1.AquaDatabaseList{},
+		&operatorv1alpha1.AquaServerList{},
+		&operatorv1alpha1.AquaGatewayList{},
+		&operatorv1alpha1.AquaEnforcerList{},
+		&operatorv1alpha1.AquaKubeEnforcerList{},
+		&operatorv1alpha1.AquaScannerList{},
+		&aquasecurityv1alpha1.AquaStarboardList{},
+	} {
+		gvk, err := apiutil.GVKForObject(list, k8sclient.Scheme())
+		if err != nil {
+			return err
+		}
+
+		crs := &unstructured.UnstructuredList{}
+		crs.SetGroupVersionKind(gvk)
+		if err = k8sclient.List(context.TODO(), crs); err != nil {
+			return err
+		}
+
+		for i := range crs.Items {
+			cr := &crs.Items[i]
+			state, _, _ := unstructured.NestedString(cr.Object, "status", "state")
+			if state == string(operatorv1alpha1.AquaDeploymentStateRunning) {
+				continue
+			}
+			if err = unstructured.SetNestedField(cr.Object, string(operatorv1alpha1.AquaDeploymentStateRunning), "status", "state"); err != nil {
+				return err
+			}
+			if err = k8sclient.Status().Update(context.TODO(), cr); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collect returns the generated objects without their status and server populated metadata
+func collect(k8sclient client.Client, scheme *k8sRuntime.Scheme, skip map[string]bool) ([]*unstructured.Unstructured, error) {
+	result := []*unstructured.Unstructured{}
+
+	for _, list := range renderedTypes {
+		list = list.DeepCopyObject().(client.ObjectList)
+		if err := k8sclient.List(context.TODO(), list); err != nil {
+			return nil, err
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+
+		objects := []*unstructured.Unstructured{}
+		for _, item := range items {
+			obj := item.(client.Object)
+			if skip[objectKey(scheme, obj)] {
+				continue
+			}
+
+			gvk, err := apiutil.GVKForObject(obj, scheme)
+			if err != nil {
+				return nil, err
+			}
+
+			content, err := k8sRuntime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				return nil, err
+			}
+
+			u := &unstructured.Unstructured{Object: content}
+			u.SetGroupVersionKind(gvk)
+			u.SetResourceVersion("")
+			u.SetManagedFields(nil)
+			unstructured.RemoveNestedField(u.Object, "metadata", "uid")
+			if owners, found, _ := unstructured.NestedSlice(u.Object, "metadata", "ownerReferences"); found {
+				for _, owner := range owners {
+					delete(owner.(map[string]interface{}), "uid")
+				}
+				_ = unstructured.SetNestedSlice(u.Object, owners, "metadata", "ownerReferences")
+			}
+			unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+			unstructured.RemoveNestedField(u.Object, "status")
+			objects = append(objects, u)
+		}
+
+		sort.Slice(objects, func(i, j int) bool {
+			if objects[i].GetNamespace() != objects[j].GetNamespace() {
+				return objects[i].GetNamespace() < objects[j].GetNamespace()
+			}
+			return objects[i].GetName() < objects[j].GetName()
+		})
+		result = append(result, objects...)
+	}
+
+	return result, nil
+}
+
+// renderClient sets the uid the API server would set on the created objects, the owner references the
+// controllers add are only valid with it
+type renderClient struct {
+	client.Client
+}
+
+func (c *renderClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	setUID(c.Scheme(), obj)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// Patch applies the server-side applies of the controllers, the fake client doesn't support them. The applied
+// fields are merged into the stored object, and an apply changing nothing doesn't write it, like the API server
+func (c *renderClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	options := &client.PatchOptions{}
+	options.ApplyOptions(opts)
+	dryRun := len(options.DryRun) > 0
+
+	found := obj.DeepCopyObject().(client.Object)
+	err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), found)
+	if apierrors.IsNotFound(err) {
+		if dryRun {
+			return nil
+		}
+		return c.Create(ctx, obj)
+	} else if err != nil {
+		return err
+	}
+
+	stored, err := k8sRuntime.DefaultUnstructuredConverter.ToUnstructured(found)
+	if err != nil {
+		return err
+	}
+	applied, err := k8sRuntime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	merged := mergeApplied(k8sRuntime.DeepCopyJSON(stored), applied)
+	changed := !equality.Semantic.DeepEqual(stored, merged)
+
+	reflect.ValueOf(obj).Elem().Set(reflect.Zero(reflect.TypeOf(obj).Elem()))
+	err = k8sRuntime.DefaultUnstructuredConverter.FromUnstructured(merged, obj)
+	if err != nil || !changed || dryRun {
+		return err
+	}
+
+	return c.Client.Update(ctx, obj)
+}
+
+// mergeApplied merges the applied fields into the stored ones, the lists are replaced as a whole
+func mergeApplied(stored, applied map[string]interface{}) map[string]interface{} {
+	for key, value := range applied {
+		switch value := value.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			if current, ok := stored[key].(map[string]interface{}); ok {
+				stored[key] = mergeApplied(current, value)
+				continue
+			}
+		}
+		stored[key] = value
+	}
+
+	return stored
+}
+
+func setUID(scheme *k8sRuntime.Scheme, obj client.Object) {
+	if len(obj.GetUID()) == 0 {
+		obj.SetUID(types.UID(fmt.Sprintf("%x", sha256.Sum256([]byte(objectKey(scheme, obj))))))
+	}
+}
+
+func objectKey(scheme *k8sRuntime.Scheme, obj client.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		gvk = schema.GroupVersionKind{}
+	}
+
+	return fmt.Sprintf("%s/%s/%s", gvk.GroupKind(), obj.GetNamespace(), obj.GetName())
+}
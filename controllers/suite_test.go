@@ -133,6 +133,7 @@ var _ = BeforeSuite(func() {
 	err = (&aquaenforcer.AquaEnforcerReconciler{
 		Client: mgr.GetClient(),
 		Scheme: scheme.Scheme,
+		Reader: mgr.GetAPIReader(),
 	}).SetupWithManager(mgr)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -19,8 +19,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	"github.com/aquasecurity/aqua-operator/controllers/aquasecurity/aquasecuritysummary"
 	"github.com/aquasecurity/aqua-operator/controllers/aquasecurity/aquastarboard"
+	"github.com/aquasecurity/aqua-operator/controllers/common"
 	"github.com/aquasecurity/aqua-operator/controllers/ocp"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquaconsole"
 	"github.com/aquasecurity/aqua-operator/controllers/operator/aquacsp"
 	"github.com/aquasecurity/aqua-operator/controllers/operator/aquadatabase"
 	"github.com/aquasecurity/aqua-operator/controllers/operator/aquaenforcer"
@@ -28,7 +31,10 @@ import (
 	"github.com/aquasecurity/aqua-operator/controllers/operator/aquakubeenforcer"
 	"github.com/aquasecurity/aqua-operator/controllers/operator/aquascanner"
 	"github.com/aquasecurity/aqua-operator/controllers/operator/aquaserver"
+	"github.com/aquasecurity/aqua-operator/controllers/operator/aquasupportbundle"
+	"github.com/aquasecurity/aqua-operator/controllers/render"
 	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/operatorconfig"
 	version2 "github.com/aquasecurity/aqua-operator/pkg/version"
 	routev1 "github.com/openshift/api/route/v1"
 	"os"
@@ -42,6 +48,7 @@ import (
 
 	k8sRuntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -69,15 +76,24 @@ func init() {
 
 	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(aquasecurityv1alpha1.AddToScheme(scheme))
+	//+kubebuilder:scaffold:scheme
+}
+
+func main() {
+	// The render subcommand prints the objects of the CRs without running the manager
+	if len(os.Args) > 1 && os.Args[1] == "render" {
+		if err := render.Run(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	isOpenshift, _ := ocp.VerifyRouteAPI()
 	if isOpenshift {
 		utilruntime.Must(routev1.AddToScheme(scheme))
 	}
-	//+kubebuilder:scaffold:scheme
-}
 
-func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
@@ -119,6 +135,9 @@ func main() {
 		os.Exit(1)
 	}
 
+	operatorconfig.SetReader(mgr.GetAPIReader())
+	common.SetEventRecorder(mgr.GetEventRecorderFor("aqua-operator"))
+
 	if err = (&aquacsp.AquaCspReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -136,6 +155,7 @@ func main() {
 	if err = (&aquaenforcer.AquaEnforcerReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		Reader: mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AquaEnforcer")
 		os.Exit(1)
@@ -176,6 +196,47 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "AquaStarboard")
 		os.Exit(1)
 	}
+	if err = (&aquasupportbundle.AquaSupportBundleReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Reader:    mgr.GetAPIReader(),
+		Clientset: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AquaSupportBundle")
+		os.Exit(1)
+	}
+	if err = (&aquasecuritysummary.AquaSecuritySummaryReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Reader: mgr.GetAPIReader(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AquaSecuritySummary")
+		os.Exit(1)
+	}
+	if err = (&aquaconsole.AquaConsoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Resource: aquaconsole.EnforcerGroups,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AquaEnforcerGroup")
+		os.Exit(1)
+	}
+	if err = (&aquaconsole.AquaConsoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Resource: aquaconsole.RegistryIntegrations,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AquaRegistryIntegration")
+		os.Exit(1)
+	}
+	if err = (&aquaconsole.AquaConsoleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Resource: aquaconsole.RuntimePolicies,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AquaRuntimePolicy")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
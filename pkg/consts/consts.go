@@ -1,5 +1,7 @@
 package consts
 
+import "time"
+
 const (
 	// ServiceAccount Service Account
 	ServiceAccount = "%s-sa"
@@ -12,6 +14,21 @@ const (
 	// StarboardRegistry URL
 	StarboardRegistry = "docker.io/aquasec"
 
+	// OperatorConfigMapName Operator level configuration, read from the operator namespace
+	OperatorConfigMapName = "aqua-operator-config"
+
+	// OperatorConfigImageRegistryMappingKey ConfigMap key of the image registry mapping
+	OperatorConfigImageRegistryMappingKey = "imageRegistryMapping"
+
+	// OperatorConfigAquaServerApiKey ConfigMap key of the address of the API of the AquaServers
+	OperatorConfigAquaServerApiKey = "aquaServerApi"
+
+	// AquaServerApiURL Default address of the API of an AquaServer, {service} and {namespace} are the ones of its service
+	AquaServerApiURL = "http://{service}.{namespace}.svc:8080"
+
+	// OperatorConfigRefreshInterval How long the operator configuration is cached
+	OperatorConfigRefreshInterval = 30 * time.Second
+
 	// PullPolicy Image Pull Policy
 	PullPolicy = "IfNotPresent"
 
@@ -36,6 +53,12 @@ const (
 	// EnforcerTokenSecretKey Enforcer Token Secret Key
 	EnforcerTokenSecretKey = "token"
 
+	// EnforcerGroupPasswordSecretName Password of the enforcerGroup login moved out of the CR spec
+	EnforcerGroupPasswordSecretName = "%s-enforcer-group-password"
+
+	// EnforcerGroupPasswordSecretKey Password of the enforcerGroup login Secret Key
+	EnforcerGroupPasswordSecretKey = "password"
+
 	// ScalockDbPasswordSecretName Scalock DB Password Secret Name
 	ScalockDbPasswordSecretName = "%s-aqua-db"
 
@@ -52,14 +75,37 @@ const (
 	AquaKubeEnforcerSAClusterReaderRoleBind = "aqua-kube-enforcer-sa-cluster-reader-crb"
 
 	AquaKubeEnforcerFinalizer                          = "aquakubeenforcers.operator.aquasec.com/finalizer"
+	AquaEnforcerFinalizer                              = "aquaenforcers.operator.aquasec.com/finalizer"
 	AquaKubeEnforcerMutantingWebhookConfigurationName  = "kube-enforcer-me-injection-hook-config"
 	AquaKubeEnforcerValidatingWebhookConfigurationName = "kube-enforcer-admission-hook-config"
 	AquaKubeEnforcerClusterRoleName                    = "aqua-kube-enforcer"
 	AquaKubeEnforcerClusterRoleBidingName              = "aqua-kube-enforcer"
+	AquaKubeEnforcerSSLSecretName                      = "kube-enforcer-ssl"
 
 	// AquaStarboardSAClusterReaderRoleBind is Openshift cluster role binding between aqua-starboard-sa and ClusterReaderRole
 	AquaStarboardSAClusterReaderRoleBind = "aqua-starboard-sa-cluster-reader-crb"
 
+	// RegistryPasswordSecretName Registry password moved out of the CR spec
+	RegistryPasswordSecretName = "%s-registry-password"
+
+	// RegistryPasswordSecretKey Registry password Secret Key
+	RegistryPasswordSecretKey = "password"
+
+	// KubeEnforcerTokenSecretName KubeEnforcer token moved out of the CR spec
+	KubeEnforcerTokenSecretName = "%s-ke-token"
+
+	// KubeEnforcerTokenSecretKey KubeEnforcer token Secret Key
+	KubeEnforcerTokenSecretKey = "token"
+
+	// ScannerLoginSecretName Scanner password and token moved out of the CR spec
+	ScannerLoginSecretName = "%s-scanner-login"
+
+	// ScannerLoginPasswordKey Scanner login password Secret Key
+	ScannerLoginPasswordKey = "password"
+
+	// ScannerLoginTokenKey Scanner login token Secret Key
+	ScannerLoginTokenKey = "token"
+
 	// ScalockDbPasswordSecretKey Scalock DB Password Secret Key
 	ScalockDbPasswordSecretKey = "password"
 
@@ -78,6 +124,63 @@ const (
 	// AuditDbPvcName DB PVC Name
 	AuditDbPvcName = "%s-audit-db-pvc"
 
+	// DbPvcMigrationName DB PVC created when the storage class changes, deployment name and storage class
+	DbPvcMigrationName = "%s-pvc-%s"
+
+	// DbStorageMigrationJobName Job copying the database files to the migrated PVC
+	DbStorageMigrationJobName = "%s-storage-migration"
+
+	// DbStorageMigrationRequeue Time to wait between checks of a running storage migration
+	DbStorageMigrationRequeue = 10 * time.Second
+
+	// DbBackupPvcName PVC the database is dumped to before its image is upgraded, deployment name
+	DbBackupPvcName = "%s-backup-pvc"
+
+	// DbBackupJobName Job dumping the database before its image is upgraded, deployment name and hash of the new images
+	DbBackupJobName = "%s-backup-%s"
+
+	// DefaultProgressDeadlineSeconds Seconds a rollout has to become ready before it is rolled back
+	DefaultProgressDeadlineSeconds = 600
+
+	// DefaultRevisionHistoryLimit Number of rendered deployment revisions kept for a CR
+	DefaultRevisionHistoryLimit = 10
+
+	// RevisionName ControllerRevision of a rendered deployment, deployment name and hash of the rendering
+	RevisionName = "%s-%s"
+
+	// RevisionOfLabel Name of the deployment a ControllerRevision was rendered for
+	RevisionOfLabel = "operator.aquasec.com/revision-of"
+
+	// RevisionHashLabel Hash of the rendering kept in a ControllerRevision
+	RevisionHashLabel = "operator.aquasec.com/revision-hash"
+
+	// RollbackAnnotation Set on a CR to roll its deployment back to a revision, removed once applied
+	RollbackAnnotation = "operator.aquasec.com/rollback-to"
+
+	// CertsRotatedAtAnnotation Pod template annotation restarting the kube-enforcer after its certificates are rotated
+	CertsRotatedAtAnnotation = "operator.aquasec.com/certs-rotated-at"
+
+	// PauseReconcileAnnotation Set to "true" on a CR to stop reconciling it, for break-glass changes to its objects
+	PauseReconcileAnnotation = "aquasec.com/pause-reconcile"
+
+	// MaxDriftRecords Number of drifts kept in the status of a CR
+	MaxDriftRecords = 10
+
+	// FieldManager Field manager of the server-side applies of the operator
+	FieldManager = "aqua-operator"
+
+	// LegacyFieldManager Field manager of the updates of the operator before server-side apply, the binary name
+	LegacyFieldManager = "manager"
+
+	// SecretStoreRefreshInterval Default interval for reading the secret store again
+	SecretStoreRefreshInterval = time.Hour
+
+	// SecretStoreHashAnnotation Hash of the secret store definition a secret was materialized from
+	SecretStoreHashAnnotation = "operator.aquasec.com/secret-store-hash"
+
+	// SecretStoreSyncedAnnotation Last time a secret was materialized from the secret store
+	SecretStoreSyncedAnnotation = "operator.aquasec.com/secret-store-synced-at"
+
 	// DbPvcSize Database PVC Size
 	DbPvcSize = 10
 
@@ -87,6 +190,18 @@ const (
 	// StarboardVersion Latest starboard version
 	StarboardVersion = "0.15.10"
 
+	// StarboardOperatorName Name of the starboard deployment, cluster role and cluster role binding
+	StarboardOperatorName = "starboard-operator"
+
+	// TrivyOperatorName Name of the trivy-operator deployment, cluster role and cluster role binding
+	TrivyOperatorName = "trivy-operator"
+
+	// TrivyOperatorVersion trivy-operator version deployed in place of starboard
+	TrivyOperatorVersion = "0.11.1"
+
+	// TrivyVersion Version of the trivy image the scan jobs of trivy-operator run
+	TrivyVersion = "0.36.1"
+
 	// CyberCenterAddress Aqua Cybercenter Address
 	CyberCenterAddress = "https://cybercenter5.aquasec.com"
 
@@ -106,6 +221,28 @@ const (
 
 	EnforcerDeamonsetName = "%s-agent"
 
+	// EnforcerGroupDaemonSetName Name of the enforcer DaemonSet of a node group, cr name and group name
+	EnforcerGroupDaemonSetName = "%s-%s-agent"
+
+	// EnforcerGroupConfigMapName Name of the enforcer ConfigMap of a node group, group name
+	EnforcerGroupConfigMapName = "aqua-csp-enforcer-%s"
+
+	// EnforcerGroupLabel Label of the objects of an enforcer node group, group name
+	EnforcerGroupLabel = "aqua.enforcer.group"
+
+	// EnforcerRuntimeDaemonSetName Name of the enforcer DaemonSet of the nodes running a container runtime, DaemonSet
+	// name of the group and runtime
+	EnforcerRuntimeDaemonSetName = "%s-%s"
+
+	// EnforcerRuntimeLabel Label of the enforcer DaemonSets of a container runtime, runtime name
+	EnforcerRuntimeLabel = "aqua.enforcer.runtime"
+
+	// ContainerRuntimeNodeLabel Label of the nodes with their container runtime, set by the operator
+	ContainerRuntimeNodeLabel = "aquasec.com/container-runtime"
+
+	// EnforcerSCCName Name of the SecurityContextConstraints of the enforcers of a CR, namespace and cr name
+	EnforcerSCCName = "aqua-enforcer-%s-%s"
+
 	ScannerDeployName = "%s-scanner"
 
 	ScannerSecretName = "aqua-scanner"
@@ -122,6 +259,12 @@ const (
 
 	DBInitContainerCommand = "[ -f $PGDATA/server.key ] && chmod 600 $PGDATA/server.key || echo 'OK'"
 
+	DBStorageMigrationCommand = "find /target -mindepth 1 -delete && cp -a /source/. /target/"
+
+	DBBackupCommand = "BACKUP_FILE=\"$BACKUP_NAME-$(date +%Y%m%d%H%M%S).sql\" && pg_dumpall -h \"$PGHOST\" -U \"$PGUSER\" -f \"/backup/$BACKUP_FILE\" && echo \"$BACKUP_FILE\""
+
+	DBRestoreCommand = "psql -h \"$PGHOST\" -U \"$PGUSER\" -d postgres -f \"/backup/$BACKUP_FILE\""
+
 	OpenShiftPlatform = "openshift"
 
 	// mtls
@@ -159,4 +302,62 @@ const (
 	ServerConfigMapName = "aqua-csp-server-config"
 
 	EnforcerConfigMapName = "aqua-csp-enforcer"
+
+	// SupportBundleSecretName Prefix of the secrets holding a support bundle, cr name
+	SupportBundleSecretName = "%s-support-bundle"
+
+	// SupportBundleSecretKey Key of the bundle part in each support bundle secret
+	SupportBundleSecretKey = "support-bundle.tar.gz"
+
+	// SupportBundleChunkSize Size of the bundle part stored per secret, under the 1MiB limit of a secret
+	SupportBundleChunkSize = 900 * 1024
+
+	SupportBundleLogSince = "24h"
+
+	SupportBundleTailLines = int64(2000)
+
+	// SecuritySummaryName Name of the AquaSecuritySummary the operator creates with starboard
+	SecuritySummaryName = "cluster"
+
+	// SecuritySummaryRefreshInterval Time between two aggregations of the reports
+	SecuritySummaryRefreshInterval = 5 * time.Minute
+
+	// SecuritySummaryTrendInterval Time between two points of the trend
+	SecuritySummaryTrendInterval = time.Hour
+
+	// SecuritySummaryTrendPoints Number of points of the trend kept, a week of hourly points
+	SecuritySummaryTrendPoints = 168
+
+	SecuritySummaryTopImages = 10
+
+	SecuritySummaryTopChecks = 20
+
+	// SecuritySummaryListLimit Number of reports read per request
+	SecuritySummaryListLimit = int64(500)
+
+	// ClusterVersionName Name of the OpenShift ClusterVersion
+	ClusterVersionName = "version"
+
+	// EnforcerNodesRefreshInterval Time between two reads of the enforcer nodes, to follow the cluster upgrades and the
+	// new nodes
+	EnforcerNodesRefreshInterval = 10 * time.Minute
+
+	// MachineConfigStateAnnotation State of the node set by the OpenShift machine config operator, Done once updated
+	MachineConfigStateAnnotation = "machineconfiguration.openshift.io/state"
+
+	MachineConfigCurrentAnnotation = "machineconfiguration.openshift.io/currentConfig"
+
+	MachineConfigDesiredAnnotation = "machineconfiguration.openshift.io/desiredConfig"
+
+	// ConsoleSyncInterval Default time between two comparisons of the console objects with the Aqua server
+	ConsoleSyncInterval = 10 * time.Minute
+
+	// AquaConsoleFinalizer Deletes the object from the console with the CR
+	AquaConsoleFinalizer = "console.operator.aquasec.com/finalizer"
+
+	// ConsoleDeletionPolicyDelete Deletes the object from the console with the CR, even an adopted one
+	ConsoleDeletionPolicyDelete = "Delete"
+
+	// ConsoleDeletionPolicyOrphan Keeps the object in the console when the CR is deleted
+	ConsoleDeletionPolicyOrphan = "Orphan"
 )
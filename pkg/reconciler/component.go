@@ -0,0 +1,109 @@
+package reconciler
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Mode How an object of a component is kept in its desired state
+type Mode int
+
+const (
+	// Apply server-side applies the rendering on every reconcile
+	Apply Mode = iota
+	// CreateOnly creates the object when it is missing and leaves it as is afterwards
+	CreateOnly
+)
+
+// Object A desired object of a component, a node of its graph
+type Object struct {
+	// Name Name of the node, used by DependsOn, the logs and the conditions
+	Name string
+	// DependsOn Nodes that have to be ready before the object is reconciled
+	DependsOn []string
+	// Mode How the object is kept in its desired state
+	Mode Mode
+	// Enabled Leaves the object out of the component when it returns false, nil always reconciles it
+	Enabled func() bool
+	// Build Renders the desired object
+	Build func() (client.Object, error)
+	// BeforeApply Runs with the stored object, nil when it is missing, before the rendering is applied. A positive
+	// duration defers the change, the object is reconciled again after it
+	BeforeApply func(found, desired client.Object) (time.Duration, error)
+	// Update Replaces the apply of an object that already exists, returns true when the object was changed
+	Update func(found, desired client.Object) (bool, error)
+	// Ready Checks the stored object is ready, with a message when it isn't. An object without a check is ready
+	// once it exists and is up to date
+	Ready func(found client.Object) (bool, string)
+}
+
+// Component A set of objects reconciled together for an owner, in the order of their dependencies
+type Component struct {
+	// Name Name of the component in the logs
+	Name string
+	// ConditionType Condition of the owner aggregating the readiness of the objects, not set when empty
+	ConditionType string
+	// Conditions Conditions of the status of the owner
+	Conditions *[]metav1.Condition
+	// Objects Desired objects of the component
+	Objects []Object
+	// Finalizer Finalizer added to the owner while Finalize has to run on its deletion
+	Finalizer string
+	// Finalize Cleans up the objects the garbage collector doesn't remove, cluster scoped ones
+	Finalize func() error
+}
+
+// sorted returns the objects ordered by their dependencies, keeping the declared order otherwise
+func (c *Component) sorted() ([]Object, error) {
+	index := map[string]int{}
+	for i, o := range c.Objects {
+		if _, ok := index[o.Name]; ok {
+			return nil, fmt.Errorf("component %s: object %s declared twice", c.Name, o.Name)
+		}
+		index[o.Name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(c.Objects))
+	sorted := make([]Object, 0, len(c.Objects))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("component %s: dependency cycle at object %s", c.Name, c.Objects[i].Name)
+		}
+
+		state[i] = visiting
+		for _, dep := range c.Objects[i].DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return fmt.Errorf("component %s: object %s depends on unknown object %s", c.Name, c.Objects[i].Name, dep)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		sorted = append(sorted, c.Objects[i])
+
+		return nil
+	}
+
+	for i := range c.Objects {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
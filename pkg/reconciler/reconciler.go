@@ -0,0 +1,250 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aquasecurity/aqua-operator/pkg/utils/k8s"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var log = logf.Log.WithName("reconciler")
+
+// Applier Applies the rendering of an object owned by the CR, returns true when the object was created or changed
+type Applier interface {
+	Apply(objectName string, obj client.Object) (bool, error)
+}
+
+// Reconciler Reconciles the components of an owner, the objects of a component are created or updated in the order
+// of their dependencies and their readiness is aggregated in a condition of the owner
+type Reconciler struct {
+	Client  client.Client
+	Scheme  *runtime.Scheme
+	Applier Applier
+}
+
+func New(k8sclient client.Client, scheme *runtime.Scheme, applier Applier) *Reconciler {
+	return &Reconciler{
+		Client:  k8sclient,
+		Scheme:  scheme,
+		Applier: applier,
+	}
+}
+
+// Finalize adds the finalizer of the component to the owner, or runs its Finalize and removes the finalizer once the
+// owner is deleted. Returns true when the owner is being deleted and mustn't be reconciled
+func (r *Reconciler) Finalize(ctx context.Context, owner client.Object, component *Component) (bool, error) {
+	if component.Finalizer == "" {
+		return owner.GetDeletionTimestamp() != nil, nil
+	}
+
+	if owner.GetDeletionTimestamp() != nil {
+		if !controllerutil.ContainsFinalizer(owner, component.Finalizer) {
+			return true, nil
+		}
+
+		// keep the finalizer when the finalization fails, so it is retried on the next reconcile
+		if component.Finalize != nil {
+			if err := component.Finalize(); err != nil {
+				return true, err
+			}
+		}
+
+		finalizers := []string{}
+		for _, finalizer := range owner.GetFinalizers() {
+			if finalizer != component.Finalizer {
+				finalizers = append(finalizers, finalizer)
+			}
+		}
+		return true, k8s.PatchFinalizers(ctx, r.Client, owner, finalizers)
+	}
+
+	// patch the finalizers only, the spec of the owner may hold values resolved for this reconcile. The lock keeps
+	// the finalizers another controller changed meanwhile
+	if !controllerutil.ContainsFinalizer(owner, component.Finalizer) {
+		finalizers := append(append([]string{}, owner.GetFinalizers()...), component.Finalizer)
+		return false, k8s.PatchFinalizers(ctx, r.Client, owner, finalizers)
+	}
+
+	return false, nil
+}
+
+// Reconcile creates or updates the objects of the component. An object whose dependencies aren't ready is skipped
+// and the owner is requeued, a deferred change requeues it after the deferral. Stops at the first failure.
+func (r *Reconciler) Reconcile(ctx context.Context, owner client.Object, component *Component) (reconcile.Result, error) {
+	reqLogger := log.WithValues("Component", component.Name, "Owner.Namespace", owner.GetNamespace(), "Owner.Name", owner.GetName())
+
+	objects, err := component.sorted()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	result := reconcile.Result{}
+	ready := map[string]bool{}
+	notReady := []string{}
+	var failure error
+	for _, o := range objects {
+		if o.Enabled != nil && !o.Enabled() {
+			// a disabled object doesn't hold back the objects depending on it
+			ready[o.Name] = true
+			continue
+		}
+
+		waiting := []string{}
+		for _, dep := range o.DependsOn {
+			if !ready[dep] {
+				waiting = append(waiting, dep)
+			}
+		}
+		if len(waiting) > 0 {
+			reqLogger.Info("Waiting for the dependencies of the object", "Object", o.Name, "DependsOn", waiting)
+			notReady = append(notReady, fmt.Sprintf("%s: waiting for %s", o.Name, strings.Join(waiting, ", ")))
+			result.Requeue = true
+			continue
+		}
+
+		ok, message, wait, err := r.reconcileObject(ctx, owner, component, o)
+		if err != nil {
+			reqLogger.Error(err, "Failed to reconcile the object", "Object", o.Name)
+			failure = err
+			notReady = append(notReady, fmt.Sprintf("%s: %v", o.Name, err))
+			break
+		}
+		if wait > 0 && (result.RequeueAfter == 0 || wait < result.RequeueAfter) {
+			result.RequeueAfter = wait
+		}
+
+		ready[o.Name] = ok
+		if !ok {
+			notReady = append(notReady, fmt.Sprintf("%s: %s", o.Name, message))
+		}
+	}
+
+	err = r.setCondition(ctx, owner, component, notReady, failure)
+	if failure != nil {
+		return reconcile.Result{}, failure
+	}
+
+	return result, err
+}
+
+// reconcileObject creates or updates the object, returns whether it is ready with a message when it isn't, and how
+// long its change is deferred
+func (r *Reconciler) reconcileObject(ctx context.Context, owner client.Object, component *Component, o Object) (bool, string, time.Duration, error) {
+	reqLogger := log.WithValues("Component", component.Name, "Object", o.Name)
+	objectName := fmt.Sprintf("%s %s", component.Name, o.Name)
+
+	desired, err := o.Build()
+	if err != nil {
+		return false, "", 0, err
+	}
+
+	// Set the owner as the owner and controller
+	if err := controllerutil.SetControllerReference(owner, desired, r.Scheme); err != nil {
+		return false, "", 0, err
+	}
+
+	// Check if this object already exists
+	found := desired.DeepCopyObject().(client.Object)
+	err = r.Client.Get(ctx, client.ObjectKeyFromObject(desired), found)
+	if errors.IsNotFound(err) {
+		found = nil
+	} else if err != nil {
+		return false, "", 0, err
+	}
+
+	if o.BeforeApply != nil {
+		wait, err := o.BeforeApply(found, desired)
+		if err != nil {
+			return false, "", 0, err
+		}
+		if wait > 0 {
+			return false, fmt.Sprintf("change deferred for %s", wait), wait, nil
+		}
+	}
+
+	if found == nil {
+		reqLogger.Info(fmt.Sprintf("Creating a New %s", objectName), "Namespace", desired.GetNamespace(), "Name", desired.GetName())
+		if o.Mode == CreateOnly {
+			err = r.Client.Create(ctx, desired)
+		} else {
+			_, err = r.Applier.Apply(objectName, desired)
+		}
+		if err != nil {
+			return false, "", 0, err
+		}
+
+		return o.Ready == nil, "created", 0, nil
+	}
+
+	changed := false
+	if o.Update != nil {
+		changed, err = o.Update(found, desired)
+	} else if o.Mode == Apply {
+		changed, err = r.Applier.Apply(objectName, desired)
+	}
+	if err != nil {
+		return false, "", 0, err
+	}
+	if changed {
+		return o.Ready == nil, "updated", 0, nil
+	}
+
+	if o.Ready != nil {
+		ok, message := o.Ready(found)
+		return ok, message, 0, nil
+	}
+
+	return true, "", 0, nil
+}
+
+// setCondition aggregates the readiness of the objects in the condition of the component, and updates the status of
+// the owner when it changed
+func (r *Reconciler) setCondition(ctx context.Context, owner client.Object, component *Component, notReady []string, failure error) error {
+	if component.ConditionType == "" || component.Conditions == nil {
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:               component.ConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Ready",
+		Message:            fmt.Sprintf("All %s objects are ready", component.Name),
+		ObservedGeneration: owner.GetGeneration(),
+	}
+	if failure != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Failed"
+		condition.Message = strings.Join(notReady, "; ")
+	} else if len(notReady) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotReady"
+		condition.Message = strings.Join(notReady, "; ")
+	}
+
+	original := append([]metav1.Condition{}, *component.Conditions...)
+	meta.SetStatusCondition(component.Conditions, condition)
+	if equality.Semantic.DeepEqual(original, *component.Conditions) {
+		return nil
+	}
+
+	// update a copy, so the spec of this reconcile isn't replaced with the stored one
+	cr := owner.DeepCopyObject().(client.Object)
+	err := r.Client.Status().Update(ctx, cr)
+	if err != nil {
+		return err
+	}
+	owner.SetResourceVersion(cr.GetResourceVersion())
+
+	return nil
+}
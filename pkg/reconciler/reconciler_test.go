@@ -0,0 +1,333 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// recordingApplier creates the objects it applies and records their names
+type recordingApplier struct {
+	client  client.Client
+	applied []string
+}
+
+func (a *recordingApplier) Apply(objectName string, obj client.Object) (bool, error) {
+	a.applied = append(a.applied, objectName)
+	err := a.client.Create(context.Background(), obj)
+	if errors.IsAlreadyExists(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func configMap(name string) func() (client.Object, error) {
+	return func() (client.Object, error) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "aqua"}}, nil
+	}
+}
+
+func names(objects []Object) []string {
+	result := []string{}
+	for _, o := range objects {
+		result = append(result, o.Name)
+	}
+
+	return result
+}
+
+var _ = Describe("Component", func() {
+	It("sorts the objects by their dependencies and keeps the declared order otherwise", func() {
+		component := &Component{Name: "KE", Objects: []Object{
+			{Name: "deployment", DependsOn: []string{"configmap", "secret"}},
+			{Name: "service"},
+			{Name: "secret", DependsOn: []string{"service account"}},
+			{Name: "configmap"},
+			{Name: "service account"},
+		}}
+		sorted, err := component.sorted()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(sorted)).To(Equal([]string{"configmap", "service account", "secret", "deployment", "service"}))
+	})
+
+	DescribeTable("fails on an invalid graph",
+		func(objects []Object, message string) {
+			_, err := (&Component{Name: "KE", Objects: objects}).sorted()
+			Expect(err).To(MatchError(message))
+		},
+		Entry("a cycle", []Object{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"c"}},
+			{Name: "c", DependsOn: []string{"a"}},
+		}, "component KE: dependency cycle at object a"),
+		Entry("a self dependency", []Object{{Name: "a", DependsOn: []string{"a"}}}, "component KE: dependency cycle at object a"),
+		Entry("an unknown dependency", []Object{{Name: "a", DependsOn: []string{"b"}}},
+			"component KE: object a depends on unknown object b"),
+		Entry("a name declared twice", []Object{{Name: "a"}, {Name: "a"}}, "component KE: object a declared twice"),
+	)
+})
+
+var _ = Describe("Reconciler", func() {
+	var (
+		ctx        context.Context
+		k8sclient  client.Client
+		applier    *recordingApplier
+		reconciler *Reconciler
+		owner      *operatorv1alpha1.AquaKubeEnforcer
+	)
+
+	readyCondition := func() *metav1.Condition {
+		stored := &operatorv1alpha1.AquaKubeEnforcer{}
+		Expect(k8sclient.Get(ctx, client.ObjectKeyFromObject(owner), stored)).To(Succeed())
+		return meta.FindStatusCondition(stored.Status.Conditions, "Ready")
+	}
+
+	component := func(objects ...Object) *Component {
+		return &Component{
+			Name:          "KE",
+			ConditionType: "Ready",
+			Conditions:    &owner.Status.Conditions,
+			Objects:       objects,
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(operatorv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		owner = &operatorv1alpha1.AquaKubeEnforcer{
+			ObjectMeta: metav1.ObjectMeta{Name: "aqua", Namespace: "aqua", UID: "uid", Generation: 2},
+		}
+		k8sclient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(owner).Build()
+		Expect(k8sclient.Get(ctx, client.ObjectKeyFromObject(owner), owner)).To(Succeed())
+		applier = &recordingApplier{client: k8sclient}
+		reconciler = New(k8sclient, scheme, applier)
+	})
+
+	Describe("Reconcile", func() {
+		It("applies the objects in the order of their dependencies and sets the condition", func() {
+			result, err := reconciler.Reconcile(ctx, owner, component(
+				Object{Name: "deployment", DependsOn: []string{"configmap"}, Build: configMap("deployment")},
+				Object{Name: "configmap", Build: configMap("configmap")},
+				Object{Name: "ssl secret", Mode: CreateOnly, Build: configMap("ssl")},
+			))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(applier.applied).To(Equal([]string{"KE configmap", "KE deployment"}))
+
+			created := &corev1.ConfigMap{}
+			Expect(k8sclient.Get(ctx, client.ObjectKey{Namespace: "aqua", Name: "ssl"}, created)).To(Succeed())
+			Expect(metav1.IsControlledBy(created, owner)).To(BeTrue())
+
+			condition := readyCondition()
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("Ready"))
+			Expect(condition.Message).To(Equal("All KE objects are ready"))
+			Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+		})
+
+		It("leaves a created only object as is", func() {
+			Expect(k8sclient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "ssl", Namespace: "aqua"},
+				Data:       map[string]string{"cert": "kept"},
+			})).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, owner, component(Object{Name: "ssl secret", Mode: CreateOnly, Build: configMap("ssl")}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applier.applied).To(BeEmpty())
+
+			found := &corev1.ConfigMap{}
+			Expect(k8sclient.Get(ctx, client.ObjectKey{Namespace: "aqua", Name: "ssl"}, found)).To(Succeed())
+			Expect(found.Data).To(Equal(map[string]string{"cert": "kept"}))
+		})
+
+		It("holds back the objects depending on one that isn't ready", func() {
+			Expect(k8sclient.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "configmap", Namespace: "aqua"}})).To(Succeed())
+
+			result, err := reconciler.Reconcile(ctx, owner, component(
+				Object{Name: "configmap", Build: configMap("configmap"), Ready: func(client.Object) (bool, string) {
+					return false, "waiting for the data"
+				}},
+				Object{Name: "deployment", DependsOn: []string{"configmap"}, Build: configMap("deployment")},
+			))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(applier.applied).To(Equal([]string{"KE configmap"}))
+
+			condition := readyCondition()
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NotReady"))
+			Expect(condition.Message).To(Equal("configmap: waiting for the data; deployment: waiting for configmap"))
+		})
+
+		It("doesn't hold back the objects depending on a disabled one", func() {
+			_, err := reconciler.Reconcile(ctx, owner, component(
+				Object{Name: "route", Enabled: func() bool { return false }, Build: configMap("route")},
+				Object{Name: "deployment", DependsOn: []string{"route"}, Build: configMap("deployment")},
+			))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applier.applied).To(Equal([]string{"KE deployment"}))
+			Expect(readyCondition().Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("requeues after the shortest deferral", func() {
+			deferFor := func(wait time.Duration) func(found, desired client.Object) (time.Duration, error) {
+				return func(found, desired client.Object) (time.Duration, error) {
+					return wait, nil
+				}
+			}
+
+			result, err := reconciler.Reconcile(ctx, owner, component(
+				Object{Name: "deployment", Build: configMap("deployment"), BeforeApply: deferFor(time.Hour)},
+				Object{Name: "certificates", Build: configMap("certificates"), BeforeApply: deferFor(time.Minute)},
+			))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+			Expect(applier.applied).To(BeEmpty())
+			Expect(readyCondition().Message).To(Equal("deployment: change deferred for 1h0m0s; certificates: change deferred for 1m0s"))
+		})
+
+		It("stops at the first failure", func() {
+			_, err := reconciler.Reconcile(ctx, owner, component(
+				Object{Name: "configmap", Build: configMap("configmap")},
+				Object{Name: "secret", Build: func() (client.Object, error) { return nil, fmt.Errorf("no certificate") }},
+				Object{Name: "deployment", Build: configMap("deployment")},
+			))
+
+			Expect(err).To(MatchError("no certificate"))
+			Expect(applier.applied).To(Equal([]string{"KE configmap"}))
+
+			condition := readyCondition()
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("Failed"))
+			Expect(condition.Message).To(Equal("secret: no certificate"))
+		})
+
+		It("uses the update of an existing object", func() {
+			Expect(k8sclient.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: "aqua"}})).To(Succeed())
+
+			updated := false
+			_, err := reconciler.Reconcile(ctx, owner, component(Object{
+				Name:  "deployment",
+				Build: configMap("deployment"),
+				Update: func(found, desired client.Object) (bool, error) {
+					updated = found.GetName() == "deployment"
+					return false, nil
+				},
+			}))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeTrue())
+			Expect(applier.applied).To(BeEmpty())
+		})
+
+		It("fails on an invalid graph without touching the objects", func() {
+			_, err := reconciler.Reconcile(ctx, owner, component(Object{Name: "a", DependsOn: []string{"b"}, Build: configMap("a")}))
+
+			Expect(err).To(HaveOccurred())
+			Expect(applier.applied).To(BeEmpty())
+			Expect(readyCondition()).To(BeNil())
+		})
+	})
+
+	Describe("Finalize", func() {
+		var finalized int
+
+		finalizing := func(err error) *Component {
+			return &Component{
+				Name:      "KE",
+				Finalizer: "aquasec.com/kube-enforcer",
+				Finalize: func() error {
+					finalized++
+					return err
+				},
+			}
+		}
+
+		deleteOwner := func() {
+			Expect(k8sclient.Delete(ctx, owner)).To(Succeed())
+			Expect(k8sclient.Get(ctx, client.ObjectKeyFromObject(owner), owner)).To(Succeed())
+			Expect(owner.DeletionTimestamp).NotTo(BeNil())
+		}
+
+		BeforeEach(func() {
+			finalized = 0
+		})
+
+		It("adds the finalizer without writing the spec", func() {
+			owner.Spec.Infrastructure = &operatorv1alpha1.AquaInfrastructure{Version: "2022.4"}
+			deleted, err := reconciler.Finalize(ctx, owner, finalizing(nil))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+			stored := &operatorv1alpha1.AquaKubeEnforcer{}
+			Expect(k8sclient.Get(ctx, client.ObjectKeyFromObject(owner), stored)).To(Succeed())
+			Expect(stored.Finalizers).To(Equal([]string{"aquasec.com/kube-enforcer"}))
+			Expect(stored.Spec.Infrastructure).To(BeNil())
+			Expect(owner.Spec.Infrastructure).To(Equal(&operatorv1alpha1.AquaInfrastructure{Version: "2022.4"}))
+			Expect(owner.Finalizers).To(Equal(stored.Finalizers))
+			Expect(finalized).To(BeZero())
+		})
+
+		It("runs the finalization and removes the finalizer on deletion", func() {
+			_, err := reconciler.Finalize(ctx, owner, finalizing(nil))
+			Expect(err).NotTo(HaveOccurred())
+			deleteOwner()
+
+			deleted, err := reconciler.Finalize(ctx, owner, finalizing(nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+			Expect(finalized).To(Equal(1))
+			err = k8sclient.Get(ctx, client.ObjectKeyFromObject(owner), &operatorv1alpha1.AquaKubeEnforcer{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("keeps the finalizer when the finalization fails", func() {
+			_, err := reconciler.Finalize(ctx, owner, finalizing(nil))
+			Expect(err).NotTo(HaveOccurred())
+			deleteOwner()
+
+			deleted, err := reconciler.Finalize(ctx, owner, finalizing(fmt.Errorf("cluster role in use")))
+			Expect(err).To(MatchError("cluster role in use"))
+			Expect(deleted).To(BeTrue())
+			stored := &operatorv1alpha1.AquaKubeEnforcer{}
+			Expect(k8sclient.Get(ctx, client.ObjectKeyFromObject(owner), stored)).To(Succeed())
+			Expect(stored.Finalizers).To(Equal([]string{"aquasec.com/kube-enforcer"}))
+		})
+
+		It("reports the deletion of an owner without a finalizer", func() {
+			deleted, err := reconciler.Finalize(ctx, owner, &Component{Name: "KE"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeFalse())
+
+			now := metav1.Now()
+			owner.DeletionTimestamp = &now
+			deleted, err = reconciler.Finalize(ctx, owner, &Component{Name: "KE"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeTrue())
+		})
+	})
+})
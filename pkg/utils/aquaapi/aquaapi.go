@@ -0,0 +1,211 @@
+package aquaapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+// EnforcerGroup An enforcer group of the Aqua console, the hostsbatch of the API
+type EnforcerGroup struct {
+	ID           string                     `json:"id"`
+	Description  string                     `json:"description,omitempty"`
+	Type         string                     `json:"type"`
+	Token        string                     `json:"token,omitempty"`
+	Orchestrator *EnforcerGroupOrchestrator `json:"orchestrator,omitempty"`
+}
+
+// EnforcerGroupOrchestrator The orchestrator the enforcers of the group are deployed by
+type EnforcerGroupOrchestrator struct {
+	Type           string `json:"type"`
+	Namespace      string `json:"namespace,omitempty"`
+	ServiceAccount string `json:"service_account,omitempty"`
+}
+
+// Client A client of the Aqua REST API, it logs in with the credentials on the first request
+type Client struct {
+	url      string
+	username string
+	password string
+	token    string
+	http     *http.Client
+}
+
+// NewClient returns a client of the Aqua API at url, e.g. http://aqua-server.aqua.svc:8080
+func NewClient(url, username, password string, insecure bool) *Client {
+	return &Client{
+		url:      strings.TrimRight(url, "/"),
+		username: username,
+		password: password,
+		http: &http.Client{
+			Timeout: requestTimeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			},
+		},
+	}
+}
+
+// Version returns the version of the server
+func (c *Client) Version() (string, error) {
+	body := struct {
+		Version string `json:"version"`
+	}{}
+	err := c.request(http.MethodGet, "/api/v1/version", nil, &body)
+	if err != nil {
+		return "", err
+	}
+
+	return body.Version, nil
+}
+
+// GetEnforcerGroup returns the enforcer group, nil when it doesn't exist
+func (c *Client) GetEnforcerGroup(id string) (*EnforcerGroup, error) {
+	group := &EnforcerGroup{}
+	err := c.request(http.MethodGet, "/api/v1/hostsbatch/"+url.PathEscape(id), nil, group)
+	if IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// CreateEnforcerGroup creates the enforcer group with its token
+func (c *Client) CreateEnforcerGroup(group *EnforcerGroup) error {
+	return c.request(http.MethodPost, "/api/v1/hostsbatch", group, nil)
+}
+
+// UpdateEnforcerGroup updates the enforcer group, a new token replaces the one the enforcers were installed with
+func (c *Client) UpdateEnforcerGroup(group *EnforcerGroup) error {
+	return c.request(http.MethodPut, "/api/v1/hostsbatch/"+url.PathEscape(group.ID), group, nil)
+}
+
+// Get returns the object at path, e.g. /api/v1/registries/<name>, nil when it doesn't exist
+func (c *Client) Get(path string) (map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	err := c.request(http.MethodGet, path, nil, &obj)
+	if IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Create creates the object in the collection at path, e.g. /api/v1/registries
+func (c *Client) Create(path string, obj interface{}) error {
+	return c.request(http.MethodPost, path, obj, nil)
+}
+
+// Update replaces the object at path
+func (c *Client) Update(path string, obj interface{}) error {
+	return c.request(http.MethodPut, path, obj, nil)
+}
+
+// Delete deletes the object at path, an object that doesn't exist is not an error
+func (c *Client) Delete(path string) error {
+	err := c.request(http.MethodDelete, path, nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// login exchanges the credentials for the bearer token of the next requests
+func (c *Client) login() error {
+	body := struct {
+		Token string `json:"token"`
+	}{}
+	err := c.do(http.MethodPost, "/api/v1/login", map[string]string{"id": c.username, "password": c.password}, &body)
+	if err != nil {
+		return err
+	}
+	if len(body.Token) == 0 {
+		return fmt.Errorf("login to %s as %s returned no token", c.url, c.username)
+	}
+	c.token = body.Token
+
+	return nil
+}
+
+func (c *Client) request(method, path string, in, out interface{}) error {
+	if len(c.token) == 0 {
+		err := c.login()
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.do(method, path, in, out)
+}
+
+func (c *Client) do(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	address := c.url + path
+	request, err := http.NewRequestWithContext(context.TODO(), method, address, body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if in != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if len(c.token) != 0 {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return &StatusError{Method: method, URL: address, Status: response.Status, StatusCode: response.StatusCode}
+	}
+	if out == nil || response.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(response.Body).Decode(out)
+}
+
+// StatusError An Aqua API request that didn't succeed
+type StatusError struct {
+	Method     string
+	URL        string
+	Status     string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s failed with status %s", e.Method, e.URL, e.Status)
+}
+
+// IsNotFound checks the error is a request of an object that doesn't exist
+func IsNotFound(err error) bool {
+	statusErr, ok := err.(*StatusError)
+	return ok && statusErr.StatusCode == http.StatusNotFound
+}
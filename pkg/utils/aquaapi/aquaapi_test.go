@@ -0,0 +1,143 @@
+package aquaapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server   *httptest.Server
+		client   *Client
+		logins   int
+		requests []string
+		groups   map[string]*EnforcerGroup
+		token    string
+	)
+
+	BeforeEach(func() {
+		logins = 0
+		requests = nil
+		groups = map[string]*EnforcerGroup{"default": {ID: "default", Type: "agent", Token: "token"}}
+		token = "bearer"
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			if r.URL.Path == "/api/v1/login" {
+				credentials := map[string]string{}
+				_ = json.NewDecoder(r.Body).Decode(&credentials)
+				if credentials["id"] != "administrator" || credentials["password"] != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				logins++
+				_, _ = fmt.Fprintf(w, `{"token":%q}`, token)
+				return
+			}
+
+			if r.Header.Get("Authorization") != "Bearer bearer" || r.Header.Get("Accept") != "application/json" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			switch r.Method + " " + r.URL.Path {
+			case "GET /api/v1/version":
+				_, _ = w.Write([]byte(`{"version":"2022.4.5"}`))
+			case "GET /api/v1/hostsbatch/default", "GET /api/v1/hostsbatch/a b":
+				group, ok := groups[r.URL.Path[len("/api/v1/hostsbatch/"):]]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(group)
+			case "POST /api/v1/hostsbatch":
+				if r.Header.Get("Content-Type") != "application/json" {
+					w.WriteHeader(http.StatusUnsupportedMediaType)
+					return
+				}
+				group := &EnforcerGroup{}
+				_ = json.NewDecoder(r.Body).Decode(group)
+				groups[group.ID] = group
+				w.WriteHeader(http.StatusNoContent)
+			case "DELETE /api/v1/registries/missing":
+				w.WriteHeader(http.StatusNotFound)
+			case "DELETE /api/v1/registries/locked":
+				w.WriteHeader(http.StatusForbidden)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(server.Close)
+
+		client = NewClient(server.URL+"/", "administrator", "secret", false)
+	})
+
+	It("logs in once and sends the bearer token", func() {
+		version, err := client.Version()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(version).To(Equal("2022.4.5"))
+
+		group, err := client.GetEnforcerGroup("default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(group).To(Equal(&EnforcerGroup{ID: "default", Type: "agent", Token: "token"}))
+
+		Expect(logins).To(Equal(1))
+		Expect(requests).To(Equal([]string{"POST /api/v1/login", "GET /api/v1/version", "GET /api/v1/hostsbatch/default"}))
+	})
+
+	It("fails when the login is rejected", func() {
+		client = NewClient(server.URL, "administrator", "wrong", false)
+		_, err := client.Version()
+		Expect(err).To(MatchError(fmt.Sprintf("POST %s/api/v1/login failed with status 401 Unauthorized", server.URL)))
+		Expect(IsNotFound(err)).To(BeFalse())
+	})
+
+	It("fails when the login returns no token", func() {
+		token = ""
+		_, err := client.Version()
+		Expect(err).To(MatchError(fmt.Sprintf("login to %s as administrator returned no token", server.URL)))
+	})
+
+	It("returns no group when it doesn't exist", func() {
+		group, err := client.GetEnforcerGroup("a b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(group).To(BeNil())
+		Expect(requests).To(ContainElement("GET /api/v1/hostsbatch/a b"))
+	})
+
+	It("creates a group", func() {
+		Expect(client.CreateEnforcerGroup(&EnforcerGroup{ID: "a b", Type: "agent", Token: "new"})).To(Succeed())
+		Expect(groups).To(HaveKeyWithValue("a b", &EnforcerGroup{ID: "a b", Type: "agent", Token: "new"}))
+	})
+
+	It("maps the status of the failed requests", func() {
+		Expect(client.Delete("/api/v1/registries/missing")).To(Succeed())
+
+		err := client.Delete("/api/v1/registries/locked")
+		statusErr := &StatusError{}
+		Expect(errors.As(err, &statusErr)).To(BeTrue())
+		Expect(statusErr.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(err).To(MatchError(fmt.Sprintf("DELETE %s/api/v1/registries/locked failed with status 403 Forbidden", server.URL)))
+		Expect(IsNotFound(err)).To(BeFalse())
+
+		obj, err := client.Get("/api/v1/registries/missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj).To(BeNil())
+	})
+
+	DescribeTable("IsNotFound",
+		func(err error, notFound bool) {
+			Expect(IsNotFound(err)).To(Equal(notFound))
+		},
+		Entry("a not found status", &StatusError{StatusCode: http.StatusNotFound}, true),
+		Entry("another status", &StatusError{StatusCode: http.StatusInternalServerError}, false),
+		Entry("another error", errors.New("connection refused"), false),
+		Entry("no error", nil, false),
+	)
+})
@@ -0,0 +1,194 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// the operator image has no zoneinfo, the time zones of the schedules are embedded
+	_ "time/tzdata"
+)
+
+// Schedule A parsed cron expression: minute hour day-of-month month day-of-week
+type Schedule struct {
+	minutes    map[int]bool
+	hours      map[int]bool
+	days       map[int]bool
+	months     map[int]bool
+	weekdays   map[int]bool
+	anyDay     bool
+	anyWeekday bool
+	location   *time.Location
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField  = field{name: "minute", min: 0, max: 59}
+	hourField    = field{name: "hour", min: 0, max: 23}
+	dayField     = field{name: "day of month", min: 1, max: 31}
+	monthField   = field{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	weekdayField = field{name: "day of week", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+// Parse parses a five fields cron expression evaluated in the time zone, UTC when empty. The fields take *, values,
+// ranges, lists and steps, the months and days of week also take their three letters names
+func Parse(expr, timeZone string) (*Schedule, error) {
+	location := time.UTC
+	if len(timeZone) > 0 {
+		var err error
+		location, err = time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+		}
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, found %d", expr, len(fields))
+	}
+
+	s := &Schedule{
+		anyDay:     fields[2] == "*" || fields[2] == "?",
+		anyWeekday: fields[4] == "*" || fields[4] == "?",
+		location:   location,
+	}
+
+	targets := []struct {
+		values *map[int]bool
+		field  field
+	}{
+		{&s.minutes, minuteField},
+		{&s.hours, hourField},
+		{&s.days, dayField},
+		{&s.months, monthField},
+		{&s.weekdays, weekdayField},
+	}
+	for i, target := range targets {
+		values, err := target.field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", expr, err)
+		}
+		*target.values = values
+	}
+
+	// 7 is sunday too
+	if s.weekdays[7] {
+		s.weekdays[0] = true
+	}
+
+	return s, nil
+}
+
+// Next returns the first start of the schedule after the time, the zero time when there is none in the next 5 years.
+// The starts are wall clock times of the time zone: a start in the hour skipped by a daylight saving change moves an
+// hour later, and a start in the repeated hour happens once
+func (s *Schedule) Next(after time.Time) time.Time {
+	local := after.In(s.location)
+	// the days are stepped in UTC, a day of the time zone can last 23 or 25 hours
+	first := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	for day := first; day.Before(first.AddDate(5, 0, 0)); day = day.AddDate(0, 0, 1) {
+		if !s.months[int(day.Month())] || !s.dayMatches(day) {
+			continue
+		}
+
+		// the starts of a day aren't in order around a daylight saving change, take the earliest one
+		next := time.Time{}
+		for hour := 0; hour < 24; hour++ {
+			if !s.hours[hour] {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if !s.minutes[minute] {
+					continue
+				}
+				t := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, s.location)
+				if t.After(after) && (next.IsZero() || t.Before(next)) {
+					next = t
+				}
+			}
+		}
+		if !next.IsZero() {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+// dayMatches follows cron, when both the day of month and the day of week are set either one matches
+func (s *Schedule) dayMatches(t time.Time) bool {
+	day := s.days[t.Day()]
+	weekday := s.weekdays[int(t.Weekday())]
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
+
+func (f field) parse(expr string) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, part := range strings.Split(expr, ",") {
+		step := 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[index+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid %s step %q", f.name, part)
+			}
+			part = part[:index]
+		}
+
+		first, last := f.min, f.max
+		if part != "*" && part != "?" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			first, err = f.value(bounds[0])
+			if err != nil {
+				return nil, err
+			}
+			last = first
+			if len(bounds) == 2 {
+				last, err = f.value(bounds[1])
+				if err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				last = f.max
+			}
+			if last < first {
+				return nil, fmt.Errorf("invalid %s range %q", f.name, part)
+			}
+		}
+
+		for value := first; value <= last; value += step {
+			values[value] = true
+		}
+	}
+
+	return values, nil
+}
+
+func (f field) value(expr string) (int, error) {
+	if value, ok := f.names[strings.ToLower(expr)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(expr)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, expr)
+	}
+	return value, nil
+}
@@ -0,0 +1,83 @@
+package cron
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func parseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	Expect(err).NotTo(HaveOccurred())
+	return t
+}
+
+var _ = Describe("Cron", func() {
+	DescribeTable("Parse fails",
+		func(expr, timeZone, message string) {
+			_, err := Parse(expr, timeZone)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("too few fields", "0 2 * *", "", "expected 5 fields, found 4"),
+		Entry("too many fields", "0 2 * * * 2022", "", "expected 5 fields, found 6"),
+		Entry("a minute out of range", "60 * * * *", "", `invalid minute "60"`),
+		Entry("an hour out of range", "0 24 * * *", "", `invalid hour "24"`),
+		Entry("day 0 of the month", "0 0 0 * *", "", `invalid day of month "0"`),
+		Entry("month 13", "0 0 * 13 *", "", `invalid month "13"`),
+		Entry("day 8 of the week", "0 0 * * 8", "", `invalid day of week "8"`),
+		Entry("an unknown name", "0 0 * * fun", "", `invalid day of week "fun"`),
+		Entry("a name in another field", "0 0 jan * *", "", `invalid day of month "jan"`),
+		Entry("a reversed range", "0 0 * * 5-1", "", `invalid day of week range "5-1"`),
+		Entry("a zero step", "*/0 * * * *", "", `invalid minute step "*/0"`),
+		Entry("a negative step", "*/-5 * * * *", "", `invalid minute step "*/-5"`),
+		Entry("an empty list item", "0, * * * *", "", `invalid minute ""`),
+		Entry("an unknown time zone", "0 2 * * *", "Mars/Olympus", `invalid time zone "Mars/Olympus"`),
+	)
+
+	DescribeTable("Next",
+		func(expr, timeZone, after, next string) {
+			schedule, err := Parse(expr, timeZone)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Next(parseTime(after))).To(BeTemporally("==", parseTime(next)))
+		},
+		Entry("every minute", "* * * * *", "", "2022-06-01T10:15:30Z", "2022-06-01T10:16:00Z"),
+		Entry("the time itself is excluded", "0 2 * * *", "", "2022-06-01T02:00:00Z", "2022-06-02T02:00:00Z"),
+		Entry("a step", "*/20 * * * *", "", "2022-06-01T10:41:00Z", "2022-06-01T11:00:00Z"),
+		Entry("a step from a value", "5/20 * * * *", "", "2022-06-01T10:26:00Z", "2022-06-01T10:45:00Z"),
+		Entry("a stepped range", "0 8-18/4 * * *", "", "2022-06-01T12:30:00Z", "2022-06-01T16:00:00Z"),
+		Entry("a list", "0 1,13 * * *", "", "2022-06-01T01:00:00Z", "2022-06-01T13:00:00Z"),
+		Entry("the weekend", "0 2 * * sat,sun", "", "2022-06-01T00:00:00Z", "2022-06-04T02:00:00Z"),
+		Entry("sunday as 7", "0 2 * * 7", "", "2022-06-01T00:00:00Z", "2022-06-05T02:00:00Z"),
+		Entry("upper case names", "0 0 1 JUL-AUG *", "", "2022-06-01T00:00:00Z", "2022-07-01T00:00:00Z"),
+		Entry("the day of month or the day of week", "0 0 13 * fri", "", "2022-05-01T00:00:00Z", "2022-05-06T00:00:00Z"),
+		Entry("the day of month when the day of week is ?", "0 0 13 * ?", "", "2022-05-01T00:00:00Z", "2022-05-13T00:00:00Z"),
+		Entry("the next year", "0 0 1 1 *", "", "2022-06-01T00:00:00Z", "2023-01-01T00:00:00Z"),
+		Entry("the next leap day", "0 0 29 2 *", "", "2022-03-01T00:00:00Z", "2024-02-29T00:00:00Z"),
+		Entry("the 31st skips the short months", "0 0 31 * *", "", "2022-03-31T00:00:00Z", "2022-05-31T00:00:00Z"),
+		Entry("a time zone", "0 2 * * *", "Europe/Berlin", "2022-05-31T23:00:00Z", "2022-06-01T00:00:00Z"),
+		Entry("a time zone behind UTC", "0 22 * * *", "America/New_York", "2022-06-01T00:00:00Z", "2022-06-01T02:00:00Z"),
+		Entry("the day of the time zone", "0 0 * * mon", "Asia/Tokyo", "2022-06-05T14:00:00Z", "2022-06-05T15:00:00Z"),
+		Entry("a start skipped by daylight saving moves an hour later", "30 2 * * *", "Europe/Berlin",
+			"2022-03-26T12:00:00Z", "2022-03-27T01:30:00Z"),
+		Entry("the day after daylight saving starts", "30 2 * * *", "Europe/Berlin", "2022-03-27T01:30:00Z", "2022-03-28T00:30:00Z"),
+		Entry("the hours around the skipped one", "0 * * * *", "Europe/Berlin", "2022-03-27T00:30:00Z", "2022-03-27T01:00:00Z"),
+		Entry("a start in the repeated hour", "30 2 * * *", "Europe/Berlin", "2022-10-29T12:00:00Z", "2022-10-30T01:30:00Z"),
+		Entry("a start in the repeated hour happens once", "30 2 * * *", "Europe/Berlin", "2022-10-30T01:30:00Z", "2022-10-31T01:30:00Z"),
+		Entry("a time in the first of the repeated hours", "45 2 * * *", "Europe/Berlin", "2022-10-30T00:50:00Z", "2022-10-30T01:45:00Z"),
+		Entry("a daylight saving change of the southern hemisphere", "0 2 * * *", "Australia/Sydney",
+			"2022-10-01T12:00:00Z", "2022-10-01T16:00:00Z"),
+	)
+
+	It("returns the zero time for a date that never comes", func() {
+		schedule, err := Parse("0 0 30 2 *", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schedule.Next(parseTime("2022-01-01T00:00:00Z")).IsZero()).To(BeTrue())
+	})
+
+	It("returns the time in the time zone of the schedule", func() {
+		schedule, err := Parse("0 2 * * *", "Europe/Berlin")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schedule.Next(parseTime("2022-06-01T12:00:00Z")).Location().String()).To(Equal("Europe/Berlin"))
+	})
+})
@@ -14,6 +14,7 @@ import (
 
 	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
 	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/versions"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/aokoli/goutils"
@@ -36,12 +37,6 @@ func Int32Ptr(i int32) *int32 {
 	return &i
 }
 
-// checkForUpgrade is making sure that .infra.version contain the latest version.
-// if not, there is a need to upgrade the images.
-func checkForUpgrade(existingTag string) bool {
-	return !strings.Contains(existingTag, consts.LatestVersion)
-}
-
 func GetImageData(repo string, version string, imageData *operatorv1alpha1.AquaImage, allowAnyVersion bool) (string, string, string, string) {
 	log := logf.Log.WithName("GetImageData")
 	log.Info(fmt.Sprintf("repo: %s", repo))
@@ -53,7 +48,7 @@ func GetImageData(repo string, version string, imageData *operatorv1alpha1.AquaI
 	tag := version
 	registry := consts.Registry
 
-	if repo == "starboard-operator" {
+	if repo == "starboard-operator" || repo == "trivy-operator" {
 		registry = consts.StarboardRegistry
 	}
 	if len(tag) == 0 {
@@ -86,8 +81,16 @@ func GetImageData(repo string, version string, imageData *operatorv1alpha1.AquaI
 		}
 	}
 
-	if checkForUpgrade(tag) && !allowAnyVersion {
-		tag = consts.LatestVersion
+	// the tag must belong to the release of the version, a stale or unsupported tag is replaced
+	if !allowAnyVersion && repo != "starboard-operator" {
+		release, ok := versions.Lookup(version)
+		if !ok {
+			release = versions.Latest()
+		}
+		if !release.MatchesTag(repo, tag) {
+			log.Info(fmt.Sprintf("Tag %s is not a release of version %s, setting tag %s", tag, release.Version, release.TagFor(repo)))
+			tag = release.TagFor(repo)
+		}
 	}
 
 	log.Info(fmt.Sprintf("pullPolicy: %s, registry: %s, repository: %s tag: %s", pullPolicy, registry, repository, tag))
@@ -146,6 +149,28 @@ func GetWatchNamespace() (string, error) {
 	return ns, nil
 }
 
+// GetOperatorNamespace returns the namespace the operator runs in, OPERATOR_NAMESPACE overrides the service account namespace
+func GetOperatorNamespace() string {
+	if ns, found := os.LookupEnv("OPERATOR_NAMESPACE"); found && len(ns) > 0 {
+		return ns
+	}
+
+	data, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err == nil {
+		return strings.TrimSpace(string(data))
+	}
+
+	ns, _ := os.LookupEnv("WATCH_NAMESPACE")
+	return ns
+}
+
+// GetSecretStoreRoot returns the directory the file secret stores of the CRs are read under, SECRET_STORE_ROOT,
+// empty when file secret stores are disabled
+func GetSecretStoreRoot() string {
+	root, _ := os.LookupEnv("SECRET_STORE_ROOT")
+	return root
+}
+
 func GenerateMD5ForSpec(spec interface{}) (string, error) {
 	b, err := json.Marshal(spec)
 	if err != nil {
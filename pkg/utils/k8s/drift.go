@@ -0,0 +1,409 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// PatchOperation A JSON patch (RFC 6902) operation
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// foreignField A field of the rendering another manager set to a different value
+type foreignField struct {
+	// path JSON pointer into the live object
+	path string
+	// rendered JSON pointer into the rendering
+	rendered    string
+	live        interface{}
+	desired     interface{}
+	subresource string
+}
+
+// DetectDrift returns the JSON patch from the rendering to the live object, made of the rendered fields a manager
+// other than the owners changed, and the JSON patch reverting them on the live object. The fields set through a
+// subresource, like the replicas an HPA scales, the status and the metadata other than the labels and annotations
+// are left out. A rendered field removed from the live object isn't a drift, the next apply sets it again
+func DetectDrift(found, desired runtime.Object, owners ...string) ([]PatchOperation, []PatchOperation, error) {
+	fields, err := foreignFields(found, desired, owners)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ops := []PatchOperation{}
+	reverts := []PatchOperation{}
+	for _, field := range fields {
+		if len(field.subresource) > 0 || !driftTracked(field.path) {
+			continue
+		}
+		ops = append(ops, PatchOperation{Op: "replace", Path: field.path, Value: field.live})
+		reverts = append(reverts, PatchOperation{Op: "replace", Path: field.path, Value: field.desired})
+	}
+
+	return ops, reverts, nil
+}
+
+// LeaveForeignFields removes from the rendering the fields a manager other than the owners set to a different
+// value, so applying the rendering leaves them to that manager. Returns the JSON pointers of the fields
+func LeaveForeignFields(found, desired runtime.Object, owners ...string) ([]string, error) {
+	fields, err := foreignFields(found, desired, owners)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	ops := []PatchOperation{}
+	for _, field := range fields {
+		paths = append(paths, field.path)
+		ops = append(ops, PatchOperation{Op: "remove", Path: field.rendered})
+	}
+
+	return paths, PreserveFields(desired, ops)
+}
+
+// PreserveFields applies the operations to the desired object, so applying it keeps the live values or leaves the
+// removed fields out
+func PreserveFields(desired runtime.Object, ops []PatchOperation) error {
+	if len(ops) == 0 {
+		return nil
+	}
+
+	content, err := toJSONMap(desired)
+	if err != nil {
+		return err
+	}
+	PreserveJSONFields(content, ops)
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	// decode into a zero object so the removed fields don't survive
+	target := reflect.New(reflect.TypeOf(desired).Elem())
+	if err = json.Unmarshal(data, target.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(desired).Elem().Set(target.Elem())
+
+	return nil
+}
+
+// DiffJSON returns the JSON patch from the applied JSON object to the live one, only the fields of applied are compared
+func DiffJSON(applied, live map[string]interface{}) []PatchOperation {
+	ops := []PatchOperation{}
+	for key, value := range applied {
+		ops = diffValues("/"+escapePointer(key), value, live[key], ops)
+	}
+
+	sortOperations(ops)
+	return ops
+}
+
+// PreserveJSONFields applies the operations to the desired JSON object, so writing it keeps these changes
+func PreserveJSONFields(content map[string]interface{}, ops []PatchOperation) {
+	for _, op := range ops {
+		tokens := splitPointer(op.Path)
+		switch op.Op {
+		case "remove":
+			removePath(content, tokens)
+		default:
+			setPath(content, tokens, op.Value)
+		}
+	}
+}
+
+// PathMatches checks the JSON pointer is the pointer prefix or a field under it
+func PathMatches(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return len(prefix) == 0 || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// foreignFields returns the fields of the rendering the managed fields of found give to a manager other than the
+// owners and set to a different value, the status subresource is left out
+func foreignFields(found, desired runtime.Object, owners []string) ([]foreignField, error) {
+	accessor, err := meta.Accessor(found)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := toJSONMap(found)
+	if err != nil {
+		return nil, err
+	}
+	rendering, err := toJSONMap(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []foreignField{}
+	seen := map[string]bool{}
+	for _, entry := range accessor.GetManagedFields() {
+		if entry.FieldsV1 == nil || entry.Subresource == "status" || isOwner(entry.Manager, owners) {
+			continue
+		}
+
+		set := &fieldpath.Set{}
+		if err = set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, err
+		}
+
+		set.Leaves().Iterate(func(path fieldpath.Path) {
+			// the content of a list item is tracked by its own fields
+			if len(path) == 0 || path[len(path)-1].Key != nil {
+				return
+			}
+
+			liveValue, pointer, ok := resolvePath(live, path)
+			if !ok || seen[pointer] {
+				return
+			}
+			desiredValue, rendered, ok := resolvePath(rendering, path)
+			if !ok || reflect.DeepEqual(liveValue, desiredValue) {
+				return
+			}
+
+			seen[pointer] = true
+			fields = append(fields, foreignField{
+				path:        pointer,
+				rendered:    rendered,
+				live:        liveValue,
+				desired:     desiredValue,
+				subresource: entry.Subresource,
+			})
+		})
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].path < fields[j].path
+	})
+	return fields, nil
+}
+
+func isOwner(manager string, owners []string) bool {
+	for _, owner := range owners {
+		if manager == owner {
+			return true
+		}
+	}
+
+	return false
+}
+
+// driftTracked checks a drift of the field is reported, the status and the metadata other than the labels and
+// annotations are left out
+func driftTracked(path string) bool {
+	if PathMatches(path, "/status") {
+		return false
+	}
+	if PathMatches(path, "/metadata") {
+		return PathMatches(path, "/metadata/labels") || PathMatches(path, "/metadata/annotations")
+	}
+
+	return true
+}
+
+// resolvePath returns the value of the managed field path in the JSON object, and its JSON pointer
+func resolvePath(content interface{}, path fieldpath.Path) (interface{}, string, bool) {
+	pointer := ""
+	for _, element := range path {
+		if element.FieldName != nil {
+			node, ok := content.(map[string]interface{})
+			if !ok {
+				return nil, "", false
+			}
+			content, ok = node[*element.FieldName]
+			if !ok {
+				return nil, "", false
+			}
+			pointer += "/" + escapePointer(*element.FieldName)
+			continue
+		}
+
+		items, ok := content.([]interface{})
+		if !ok {
+			return nil, "", false
+		}
+		index := listIndex(items, element)
+		if index < 0 {
+			return nil, "", false
+		}
+		content = items[index]
+		pointer += "/" + strconv.Itoa(index)
+	}
+
+	return content, pointer, true
+}
+
+// listIndex returns the index of the list item the path element selects, or -1
+func listIndex(items []interface{}, element fieldpath.PathElement) int {
+	if element.Index != nil {
+		if *element.Index < len(items) {
+			return *element.Index
+		}
+		return -1
+	}
+
+	for i, item := range items {
+		if element.Value != nil && value.Equals(value.NewValueInterface(item), *element.Value) {
+			return i
+		}
+		if element.Key != nil && keyMatches(item, *element.Key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func keyMatches(item interface{}, key value.FieldList) bool {
+	fields, ok := item.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, field := range key {
+		itemValue, ok := fields[field.Name]
+		if !ok || !value.Equals(value.NewValueInterface(itemValue), field.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func diffValues(path string, applied, live interface{}, ops []PatchOperation) []PatchOperation {
+	switch appliedValue := applied.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			if live == nil && len(appliedValue) == 0 {
+				return ops
+			}
+			return append(ops, operationTo(path, live))
+		}
+		for key, value := range appliedValue {
+			ops = diffValues(path+"/"+escapePointer(key), value, liveValue[key], ops)
+		}
+		return ops
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok || len(liveValue) != len(appliedValue) {
+			if live == nil && len(appliedValue) == 0 {
+				return ops
+			}
+			return append(ops, operationTo(path, live))
+		}
+		for i := range appliedValue {
+			ops = diffValues(path+"/"+strconv.Itoa(i), appliedValue[i], liveValue[i], ops)
+		}
+		return ops
+	}
+
+	if !reflect.DeepEqual(applied, live) {
+		ops = append(ops, operationTo(path, live))
+	}
+	return ops
+}
+
+func operationTo(path string, live interface{}) PatchOperation {
+	if live == nil {
+		return PatchOperation{Op: "remove", Path: path}
+	}
+
+	return PatchOperation{Op: "replace", Path: path, Value: live}
+}
+
+func sortOperations(ops []PatchOperation) {
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Path < ops[j].Path
+	})
+}
+
+func toJSONMap(obj runtime.Object) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	content := map[string]interface{}{}
+	return content, json.Unmarshal(data, &content)
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func splitPointer(path string) []string {
+	tokens := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tokens[i], "~1", "/"), "~0", "~")
+	}
+
+	return tokens
+}
+
+func setPath(content interface{}, tokens []string, value interface{}) {
+	for i, token := range tokens {
+		last := i == len(tokens)-1
+		switch node := content.(type) {
+		case map[string]interface{}:
+			if last {
+				node[token] = value
+				return
+			}
+			next, ok := node[token]
+			if !ok || next == nil {
+				next = map[string]interface{}{}
+				node[token] = next
+			}
+			content = next
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index >= len(node) {
+				return
+			}
+			if last {
+				node[index] = value
+				return
+			}
+			content = node[index]
+		default:
+			return
+		}
+	}
+}
+
+func removePath(content interface{}, tokens []string) {
+	for i, token := range tokens {
+		last := i == len(tokens)-1
+		switch node := content.(type) {
+		case map[string]interface{}:
+			if last {
+				delete(node, token)
+				return
+			}
+			content = node[token]
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index >= len(node) || last {
+				return
+			}
+			content = node[index]
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package k8s
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	operatorFields = `{"f:metadata":{"f:labels":{"f:app":{}}},"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"aqua-server\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`
+	editFields     = `{"f:metadata":{"f:labels":{"f:app":{}}},"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"aqua-server\"}":{"f:image":{}}}}}}}`
+	scaleFields    = `{"f:spec":{"f:replicas":{}}}`
+	meshFields     = `{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"istio-proxy\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`
+)
+
+func deployment(replicas int32, app, image string, managed ...metav1.ManagedFieldsEntry) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "aqua-server",
+			Namespace:     "aqua",
+			Labels:        map[string]string{"app": app},
+			ManagedFields: managed,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "aqua-server", Image: image}}},
+			},
+		},
+	}
+}
+
+func managedBy(manager, subresource, fields string) metav1.ManagedFieldsEntry {
+	operation := metav1.ManagedFieldsOperationUpdate
+	if manager == "aqua-operator" {
+		operation = metav1.ManagedFieldsOperationApply
+	}
+
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   operation,
+		APIVersion:  "apps/v1",
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+		Subresource: subresource,
+	}
+}
+
+var _ = Describe("Managed fields", func() {
+	Describe("DetectDrift", func() {
+		It("returns the rendered fields another manager changed and their reverts", func() {
+			found := deployment(3, "edited", "server:edited",
+				managedBy("aqua-operator", "", operatorFields), managedBy("kubectl-edit", "", editFields))
+			ops, reverts, err := DetectDrift(found, deployment(3, "aqua-server", "server:2022.4"), "aqua-operator")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ops).To(Equal([]PatchOperation{
+				{Op: "replace", Path: "/metadata/labels/app", Value: "edited"},
+				{Op: "replace", Path: "/spec/template/spec/containers/0/image", Value: "server:edited"},
+			}))
+			Expect(reverts).To(Equal([]PatchOperation{
+				{Op: "replace", Path: "/metadata/labels/app", Value: "aqua-server"},
+				{Op: "replace", Path: "/spec/template/spec/containers/0/image", Value: "server:2022.4"},
+			}))
+		})
+
+		It("leaves out the fields of the owners, of the scale subresource and the values equal to the rendering", func() {
+			found := deployment(5, "aqua-server", "server:6.5",
+				managedBy("aqua-operator", "", operatorFields),
+				managedBy("manager", "", editFields),
+				managedBy("kube-controller-manager", "scale", scaleFields),
+				managedBy("kubectl-edit", "", `{"f:metadata":{"f:labels":{"f:app":{}}}}`))
+			ops, reverts, err := DetectDrift(found, deployment(3, "aqua-server", "server:2022.4"), "aqua-operator", "manager")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ops).To(BeEmpty())
+			Expect(reverts).To(BeEmpty())
+		})
+
+		It("finds the list items by their keys", func() {
+			found := deployment(3, "aqua-server", "server:edited", managedBy("kubectl-edit", "", editFields), managedBy("istio", "", meshFields))
+			found.Spec.Template.Spec.Containers = append([]corev1.Container{{Name: "istio-proxy", Image: "proxy"}},
+				found.Spec.Template.Spec.Containers...)
+			ops, _, err := DetectDrift(found, deployment(3, "aqua-server", "server:2022.4"), "aqua-operator")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ops).To(Equal([]PatchOperation{
+				{Op: "replace", Path: "/spec/template/spec/containers/1/image", Value: "server:edited"},
+			}))
+		})
+
+		It("fails on malformed managed fields", func() {
+			found := deployment(3, "aqua-server", "server:edited", managedBy("kubectl-edit", "", `{"f:spec":`))
+			_, _, err := DetectDrift(found, deployment(3, "aqua-server", "server:2022.4"), "aqua-operator")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("LeaveForeignFields", func() {
+		It("removes the fields other managers set to another value from the rendering", func() {
+			found := deployment(5, "aqua-server", "server:edited",
+				managedBy("aqua-operator", "", operatorFields),
+				managedBy("kube-controller-manager", "scale", scaleFields),
+				managedBy("kubectl-edit", "", editFields))
+			desired := deployment(3, "aqua-server", "server:2022.4")
+			left, err := LeaveForeignFields(found, desired, "aqua-operator")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(left).To(Equal([]string{"/spec/replicas", "/spec/template/spec/containers/0/image"}))
+			Expect(desired.Spec.Replicas).To(BeNil())
+			Expect(desired.Spec.Template.Spec.Containers).To(Equal([]corev1.Container{{Name: "aqua-server"}}))
+			Expect(desired.Labels).To(Equal(map[string]string{"app": "aqua-server"}))
+		})
+
+		It("keeps the fields shared with the same value", func() {
+			found := deployment(3, "aqua-server", "server:2022.4",
+				managedBy("kube-controller-manager", "scale", scaleFields), managedBy("kubectl-edit", "", editFields))
+			desired := deployment(3, "aqua-server", "server:2022.4")
+			left, err := LeaveForeignFields(found, desired, "aqua-operator")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(left).To(BeEmpty())
+			Expect(desired).To(Equal(deployment(3, "aqua-server", "server:2022.4")))
+		})
+
+		It("keeps the status subresource out", func() {
+			found := deployment(3, "aqua-server", "server:2022.4", managedBy("kube-controller-manager", "status", scaleFields))
+			desired := deployment(1, "aqua-server", "server:2022.4")
+			left, err := LeaveForeignFields(found, desired, "aqua-operator")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(left).To(BeEmpty())
+			Expect(*desired.Spec.Replicas).To(Equal(int32(1)))
+		})
+	})
+})
+
+var _ = Describe("JSON patches", func() {
+	It("keeps the live values in the rendering", func() {
+		desired := deployment(3, "aqua-server", "server:2022.4")
+		desired.Spec.Template.Spec.Containers[0].Args = []string{"--debug"}
+		err := PreserveFields(desired, []PatchOperation{
+			{Op: "replace", Path: "/spec/replicas", Value: 5},
+			{Op: "replace", Path: "/metadata/labels/team", Value: "security"},
+			{Op: "remove", Path: "/spec/template/spec/containers/0/args"},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*desired.Spec.Replicas).To(Equal(int32(5)))
+		Expect(desired.Labels).To(Equal(map[string]string{"app": "aqua-server", "team": "security"}))
+		Expect(desired.Spec.Template.Spec.Containers[0].Args).To(BeNil())
+		Expect(desired.Spec.Template.Spec.Containers[0].Image).To(Equal("server:2022.4"))
+	})
+
+	It("leaves the rendering as is without operations", func() {
+		desired := deployment(3, "aqua-server", "server:2022.4")
+		Expect(PreserveFields(desired, nil)).To(Succeed())
+		Expect(desired).To(Equal(deployment(3, "aqua-server", "server:2022.4")))
+	})
+
+	It("skips the paths missing from the rendering", func() {
+		desired := deployment(3, "aqua-server", "server:2022.4")
+		Expect(PreserveFields(desired, []PatchOperation{
+			{Op: "replace", Path: "/spec/template/spec/containers/4/image", Value: "proxy"},
+			{Op: "remove", Path: "/spec/template/spec/volumes/0/name"},
+		})).To(Succeed())
+		Expect(desired).To(Equal(deployment(3, "aqua-server", "server:2022.4")))
+	})
+
+	DescribeTable("DiffJSON",
+		func(applied, live map[string]interface{}, ops []PatchOperation) {
+			Expect(DiffJSON(applied, live)).To(Equal(ops))
+		},
+		Entry("equal objects", map[string]interface{}{"name": "default", "hosts": []interface{}{"a"}},
+			map[string]interface{}{"name": "default", "hosts": []interface{}{"a"}, "id": 4.0}, []PatchOperation{}),
+		Entry("a changed value", map[string]interface{}{"name": "default", "enforce": true},
+			map[string]interface{}{"name": "default", "enforce": false},
+			[]PatchOperation{{Op: "replace", Path: "/enforce", Value: false}}),
+		Entry("a removed value", map[string]interface{}{"settings": map[string]interface{}{"audit": true}},
+			map[string]interface{}{"settings": map[string]interface{}{}},
+			[]PatchOperation{{Op: "remove", Path: "/settings/audit"}}),
+		Entry("a list of another length", map[string]interface{}{"hosts": []interface{}{"a"}},
+			map[string]interface{}{"hosts": []interface{}{"a", "b"}},
+			[]PatchOperation{{Op: "replace", Path: "/hosts", Value: []interface{}{"a", "b"}}}),
+		Entry("a changed list item", map[string]interface{}{"hosts": []interface{}{"a", "b"}},
+			map[string]interface{}{"hosts": []interface{}{"a", "c"}},
+			[]PatchOperation{{Op: "replace", Path: "/hosts/1", Value: "c"}}),
+		Entry("an empty map missing live", map[string]interface{}{"labels": map[string]interface{}{}},
+			map[string]interface{}{}, []PatchOperation{}),
+		Entry("an escaped key", map[string]interface{}{"a/b~c": "x"}, map[string]interface{}{"a/b~c": "y"},
+			[]PatchOperation{{Op: "replace", Path: "/a~1b~0c", Value: "y"}}),
+	)
+
+	It("applies the operations to a JSON object", func() {
+		content := map[string]interface{}{
+			"a/b":   "x",
+			"hosts": []interface{}{"a", "b"},
+			"settings": map[string]interface{}{
+				"audit": true,
+			},
+		}
+		PreserveJSONFields(content, []PatchOperation{
+			{Op: "replace", Path: "/a~1b", Value: "y"},
+			{Op: "replace", Path: "/hosts/1", Value: "c"},
+			{Op: "remove", Path: "/settings/audit"},
+			{Op: "add", Path: "/labels/team", Value: "security"},
+		})
+
+		Expect(content).To(Equal(map[string]interface{}{
+			"a/b":      "y",
+			"hosts":    []interface{}{"a", "c"},
+			"settings": map[string]interface{}{},
+			"labels":   map[string]interface{}{"team": "security"},
+		}))
+	})
+
+	DescribeTable("PathMatches",
+		func(path, prefix string, matches bool) {
+			Expect(PathMatches(path, prefix)).To(Equal(matches))
+		},
+		Entry("the same path", "/spec/replicas", "/spec/replicas", true),
+		Entry("a field under the prefix", "/spec/template/spec/containers/0/env", "/spec/template", true),
+		Entry("a trailing slash", "/spec/replicas", "/spec/", true),
+		Entry("an empty prefix", "/spec/replicas", "", true),
+		Entry("a sibling with the same start", "/spec/replicasCount", "/spec/replicas", false),
+		Entry("another field", "/metadata/labels", "/spec", false),
+	)
+})
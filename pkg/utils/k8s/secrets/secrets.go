@@ -3,6 +3,7 @@ package secrets
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -25,6 +26,35 @@ func CheckIfSecretExists(k8sclient client.Client, name, namespace string) bool {
 	return exist
 }
 
+// GetSecretValue Get the value of the secret key a secret ref points to
+func GetSecretValue(k8sclient client.Client, namespace string, ref *operatorv1alpha1.AquaSecret) (string, error) {
+	found := &corev1.Secret{}
+	err := k8sclient.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, found)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := found.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret %s", ref.Key, ref.Name)
+	}
+
+	return string(value), nil
+}
+
+// GetRegistryData Get the registry data with the password read from the password secret ref
+func GetRegistryData(k8sclient client.Client, namespace string, registry operatorv1alpha1.AquaDockerRegistry) (operatorv1alpha1.AquaDockerRegistry, error) {
+	if registry.PasswordSecretRef != nil {
+		password, err := GetSecretValue(k8sclient, namespace, registry.PasswordSecretRef)
+		if err != nil {
+			return registry, err
+		}
+		registry.Password = password
+	}
+
+	return registry, nil
+}
+
 // CreateSecret Create new secret
 func CreateSecret(cr, namespace, app, description, name, key, value string) *corev1.Secret {
 	labels := map[string]string{
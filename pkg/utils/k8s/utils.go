@@ -1,14 +1,13 @@
 package k8s
 
 import (
-	syserrors "errors"
+	"context"
 	"fmt"
 
 	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
 
-	"github.com/banzaicloud/k8s-objectmatcher/patch"
-
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	corev1 "k8s.io/api/core/v1"
@@ -17,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var log = logf.Log.WithName("k8s-utils")
@@ -96,33 +96,54 @@ func IsDeploymentReady(deployObj *appsv1.Deployment, expectedReplicas int) bool
 
 }
 
-func CheckForK8sObjectUpdate(objectName string, found, desired runtime.Object) (bool, error) {
-	reqLogger := log.WithValues("Checking For k8s object update", "Checking For k8s object update")
-
-	objectsMatcher, err := patch.DefaultPatchMaker.Calculate(found, desired, patch.IgnoreStatusFields())
-	if err != nil {
-		reqLogger.Error(err, "Unable to Calculate diff", err)
-		return false, err
-	}
-	if objectsMatcher == nil {
-		reqLogger.Error(err, "Unable to Calculate diff", err)
-		return false, syserrors.New("objectsMatcher == nil")
+// IsDeploymentRolledOut checks the current pod template of the deployment is rolled out and all its pods are ready
+func IsDeploymentRolledOut(deployObj *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployObj.Spec.Replicas != nil {
+		replicas = *deployObj.Spec.Replicas
 	}
-	upgrade := false
-	if !objectsMatcher.IsEmpty() {
-		upgrade = true
-		err = patch.DefaultAnnotator.SetLastAppliedAnnotation(desired)
-		if err != nil {
-			reqLogger.Error(err, "Unable to set default for k8s-objectmatcher", err)
-			return false, err
+
+	return deployObj.Status.ObservedGeneration >= deployObj.Generation &&
+		deployObj.Status.UpdatedReplicas == replicas &&
+		deployObj.Status.Replicas == replicas &&
+		deployObj.Status.ReadyReplicas == replicas
+}
+
+// IsDaemonSetRolledOut checks the current pod template of the daemonset is rolled out and all its pods are ready
+func IsDaemonSetRolledOut(dsObj *appsv1.DaemonSet) bool {
+	return dsObj.Status.ObservedGeneration >= dsObj.Generation &&
+		dsObj.Status.UpdatedNumberScheduled == dsObj.Status.DesiredNumberScheduled &&
+		dsObj.Status.NumberReady == dsObj.Status.DesiredNumberScheduled
+}
+
+// IsDeploymentProgressDeadlineExceeded checks the deployment controller gave up on the current rollout
+func IsDeploymentProgressDeadlineExceeded(deployObj *appsv1.Deployment) bool {
+	for _, condition := range deployObj.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing &&
+			condition.Status == corev1.ConditionFalse &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			return true
 		}
 	}
 
-	reqLogger.Info(fmt.Sprintf("Checking for %s Upgrade", objectName),
-		"PATCH", string(objectsMatcher.Patch),
-		"upgrade bool", upgrade)
+	return false
+}
+
+func IsJobFinished(job *batchv1.Job) (finished bool, failed bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, false
+		case batchv1.JobFailed:
+			return true, true
+		}
+	}
 
-	return upgrade, nil
+	return false, false
 }
 
 func CompareByHash(a, b interface{}) (bool, error) {
@@ -140,3 +161,21 @@ func CompareByHash(a, b interface{}) (bool, error) {
 	}
 	return false, nil
 }
+
+// PatchFinalizers patches the finalizers of the object to the given ones. A copy is patched, so the rest of obj keeps
+// the values set in memory, like a version resolved for the reconcile, and only its finalizers and resource version
+// are updated. The patch fails when the object changed since it was read.
+func PatchFinalizers(ctx context.Context, k8sclient client.Client, obj client.Object, finalizers []string) error {
+	base := obj.DeepCopyObject().(client.Object)
+	patched := obj.DeepCopyObject().(client.Object)
+	patched.SetFinalizers(finalizers)
+
+	err := k8sclient.Patch(ctx, patched, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	if err != nil {
+		return err
+	}
+
+	obj.SetFinalizers(patched.GetFinalizers())
+	obj.SetResourceVersion(patched.GetResourceVersion())
+	return nil
+}
@@ -0,0 +1,131 @@
+package openshift
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var clusterVersionKind = schema.GroupVersionKind{Group: "config.openshift.io", Version: "v1", Kind: "ClusterVersion"}
+
+// ClusterVersion The versions of an OpenShift cluster
+type ClusterVersion struct {
+	// Desired Version the cluster runs or is upgraded to
+	Desired string
+	// Completed Last version the cluster was completely upgraded to, Desired once the upgrade is done
+	Completed string
+	// Progressing True while the cluster is upgraded
+	Progressing bool
+}
+
+// GetClusterVersion reads the version of the cluster from its ClusterVersion, nil when the cluster isn't OpenShift
+func GetClusterVersion(ctx context.Context, reader client.Reader) (*ClusterVersion, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(clusterVersionKind)
+	err := reader.Get(ctx, types.NamespacedName{Name: consts.ClusterVersionName}, obj)
+	if meta.IsNoMatchError(err) || errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	version := &ClusterVersion{}
+	version.Desired, _, _ = unstructured.NestedString(obj.Object, "status", "desired", "version")
+
+	// the history is newest first, a partial entry is an upgrade not completed yet
+	history, _, _ := unstructured.NestedSlice(obj.Object, "status", "history")
+	for _, h := range history {
+		entry, ok := h.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if state, _, _ := unstructured.NestedString(entry, "state"); state == "Completed" {
+			version.Completed, _, _ = unstructured.NestedString(entry, "version")
+			break
+		}
+	}
+	if version.Completed == "" {
+		version.Completed = version.Desired
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		if conditionType == "Progressing" && status == "True" {
+			version.Progressing = true
+		}
+	}
+
+	if version.Desired == "" {
+		return nil, nil
+	}
+
+	return version, nil
+}
+
+// IsRhcos returns true when the node runs Red Hat Enterprise Linux CoreOS
+func IsRhcos(node *corev1.Node) bool {
+	return strings.Contains(node.Status.NodeInfo.OSImage, "CoreOS")
+}
+
+// IsNodeUpdating returns true while the machine config operator updates the node
+func IsNodeUpdating(node *corev1.Node) bool {
+	annotations := node.GetAnnotations()
+	if annotations[consts.MachineConfigStateAnnotation] == "" {
+		// a node the machine config operator doesn't manage
+		return false
+	}
+
+	return annotations[consts.MachineConfigStateAnnotation] != "Done" ||
+		annotations[consts.MachineConfigCurrentAnnotation] != annotations[consts.MachineConfigDesiredAnnotation]
+}
+
+// PoolVersion returns the OpenShift version of a pool of RHCOS nodes, their osImages and the number of nodes being
+// updated. The nodes run the desired version once the upgrade is over, they all run the same osImage and none is
+// updated. Until then the pool keeps the previous version, or the last completed version when it has none. Returns
+// an empty version for a pool without RHCOS nodes
+func PoolVersion(version *ClusterVersion, nodes []corev1.Node, previous string) (string, []string, int32) {
+	images := map[string]bool{}
+	updating := int32(0)
+	for i := range nodes {
+		if !IsRhcos(&nodes[i]) {
+			continue
+		}
+		images[nodes[i].Status.NodeInfo.OSImage] = true
+		if IsNodeUpdating(&nodes[i]) {
+			updating++
+		}
+	}
+	if len(images) == 0 {
+		return "", nil, 0
+	}
+
+	osImages := []string{}
+	for image := range images {
+		osImages = append(osImages, image)
+	}
+	sort.Strings(osImages)
+
+	if !version.Progressing && updating == 0 && len(osImages) == 1 {
+		return version.Desired, osImages, updating
+	}
+	if previous != "" {
+		return previous, osImages, updating
+	}
+
+	return version.Completed, osImages, updating
+}
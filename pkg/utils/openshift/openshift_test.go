@@ -0,0 +1,138 @@
+package openshift
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	rhcos410 = "Red Hat Enterprise Linux CoreOS 410.84.202205191234-0 (Ootpa)"
+	rhcos411 = "Red Hat Enterprise Linux CoreOS 411.86.202206101234-0 (Ootpa)"
+	rhel     = "Red Hat Enterprise Linux 8.6 (Ootpa)"
+)
+
+// clusterVersion returns the ClusterVersion with the status
+func clusterVersion(status string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	Expect(json.Unmarshal([]byte(`{"status":`+status+`}`), &obj.Object)).To(Succeed())
+	obj.SetGroupVersionKind(clusterVersionKind)
+	obj.SetName(consts.ClusterVersionName)
+
+	return obj
+}
+
+// node returns a node running the osImage, updated to the machine config current when state is Done
+func node(osImage, state, current, desired string) corev1.Node {
+	node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	node.Status.NodeInfo.OSImage = osImage
+	if len(state) != 0 {
+		node.Annotations = map[string]string{
+			consts.MachineConfigStateAnnotation:   state,
+			consts.MachineConfigCurrentAnnotation: current,
+			consts.MachineConfigDesiredAnnotation: desired,
+		}
+	}
+
+	return node
+}
+
+var _ = Describe("OpenShift", func() {
+	DescribeTable("GetClusterVersion",
+		func(objs []client.Object, expected *ClusterVersion) {
+			reader := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(objs...).Build()
+			version, err := GetClusterVersion(context.Background(), reader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(expected))
+		},
+		Entry("a cluster that isn't OpenShift", nil, nil),
+		Entry("a cluster on its version",
+			[]client.Object{clusterVersion(`{
+				"desired": {"version": "4.10.3"},
+				"history": [{"state": "Completed", "version": "4.10.3"}, {"state": "Completed", "version": "4.9.1"}],
+				"conditions": [{"type": "Available", "status": "True"}, {"type": "Progressing", "status": "False"}]
+			}`)},
+			&ClusterVersion{Desired: "4.10.3", Completed: "4.10.3"}),
+		Entry("a progressing upgrade",
+			[]client.Object{clusterVersion(`{
+				"desired": {"version": "4.11.0"},
+				"history": [{"state": "Partial", "version": "4.11.0"}, {"state": "Completed", "version": "4.10.3"}],
+				"conditions": [{"type": "Progressing", "status": "True"}]
+			}`)},
+			&ClusterVersion{Desired: "4.11.0", Completed: "4.10.3", Progressing: true}),
+		Entry("an installation in progress",
+			[]client.Object{clusterVersion(`{
+				"desired": {"version": "4.10.3"},
+				"history": [{"state": "Partial", "version": "4.10.3"}],
+				"conditions": [{"type": "Progressing", "status": "True"}]
+			}`)},
+			&ClusterVersion{Desired: "4.10.3", Completed: "4.10.3", Progressing: true}),
+		Entry("a ClusterVersion without a desired version", []client.Object{clusterVersion(`{}`)}, nil),
+	)
+
+	DescribeTable("IsNodeUpdating",
+		func(node corev1.Node, expected bool) {
+			Expect(IsNodeUpdating(&node)).To(Equal(expected))
+		},
+		Entry("a node the machine config operator doesn't manage", node(rhcos410, "", "", ""), false),
+		Entry("an updated node", node(rhcos410, "Done", "rendered-1", "rendered-1"), false),
+		Entry("a node being updated", node(rhcos410, "Working", "rendered-1", "rendered-2"), true),
+		Entry("a node with a new config to apply", node(rhcos410, "Done", "rendered-1", "rendered-2"), true),
+		Entry("a degraded node", node(rhcos410, "Degraded", "rendered-1", "rendered-1"), true),
+	)
+
+	upgraded := &ClusterVersion{Desired: "4.11.0", Completed: "4.11.0"}
+	upgrading := &ClusterVersion{Desired: "4.11.0", Completed: "4.10.3", Progressing: true}
+
+	DescribeTable("PoolVersion",
+		func(version *ClusterVersion, nodes []corev1.Node, previous, expected string, osImages []string, updating int32) {
+			result, images, updatingNodes := PoolVersion(version, nodes, previous)
+			Expect(result).To(Equal(expected))
+			Expect(images).To(Equal(osImages))
+			Expect(updatingNodes).To(Equal(updating))
+		},
+		Entry("the desired version once the upgrade is over",
+			upgraded,
+			[]corev1.Node{node(rhcos411, "Done", "rendered-2", "rendered-2"), node(rhcos411, "Done", "rendered-2", "rendered-2")},
+			"4.10.3", "4.11.0", []string{rhcos411}, int32(0)),
+		Entry("the previous version while the cluster is progressing",
+			upgrading,
+			[]corev1.Node{node(rhcos411, "Done", "rendered-2", "rendered-2")},
+			"4.10.3", "4.10.3", []string{rhcos411}, int32(0)),
+		Entry("the completed version while the cluster is progressing without a previous version",
+			upgrading,
+			[]corev1.Node{node(rhcos410, "Done", "rendered-1", "rendered-1")},
+			"", "4.10.3", []string{rhcos410}, int32(0)),
+		Entry("the previous version while nodes are updated",
+			upgraded,
+			[]corev1.Node{node(rhcos411, "Done", "rendered-2", "rendered-2"), node(rhcos410, "Working", "rendered-1", "rendered-2")},
+			"4.10.3", "4.10.3", []string{rhcos410, rhcos411}, int32(1)),
+		Entry("the previous version while the nodes run different osImages",
+			upgraded,
+			[]corev1.Node{node(rhcos411, "", "", ""), node(rhcos410, "", "", "")},
+			"4.10.3", "4.10.3", []string{rhcos410, rhcos411}, int32(0)),
+		Entry("the completed version while the nodes run different osImages without a previous version",
+			&ClusterVersion{Desired: "4.11.0", Completed: "4.10.3"},
+			[]corev1.Node{node(rhcos411, "", "", ""), node(rhcos410, "", "", "")},
+			"", "4.10.3", []string{rhcos410, rhcos411}, int32(0)),
+		Entry("the nodes that aren't RHCOS are ignored",
+			upgraded,
+			[]corev1.Node{node(rhcos411, "Done", "rendered-2", "rendered-2"), node(rhel, "Working", "rendered-1", "rendered-2")},
+			"4.10.3", "4.11.0", []string{rhcos411}, int32(0)),
+		Entry("no version for a pool without RHCOS nodes",
+			upgraded,
+			[]corev1.Node{node(rhel, "", "", "")},
+			"4.10.3", "", []string(nil), int32(0)),
+		Entry("no version for an empty pool", upgraded, nil, "4.10.3", "", []string(nil), int32(0)),
+	)
+})
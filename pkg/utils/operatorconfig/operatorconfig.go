@@ -0,0 +1,187 @@
+package operatorconfig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// ImageRegistryMapping rewrites the images of every workload the operator generates
+type ImageRegistryMapping struct {
+	// Registries maps a source registry, or registry/path prefix, to the registry it is rewritten to
+	Registries map[string]string `json:"registries,omitempty"`
+	// Repositories maps a source repository to the repository name used in the target registry
+	Repositories map[string]string `json:"repositories,omitempty"`
+	// PullSecret Image pull secret added to every workload, it must exist in the namespace of the workload
+	PullSecret string `json:"pullSecret,omitempty"`
+}
+
+// AquaServerApi How the operator reaches the API of the AquaServers, e.g. through a port-forward when it runs out of the
+// cluster
+type AquaServerApi struct {
+	// URL Address of the API, {service} and {namespace} are replaced with the ones of the service of the server
+	URL string `json:"url,omitempty"`
+	// TLSNoVerify Skips the verification of the certificate of an https address
+	TLSNoVerify bool `json:"tlsNoVerify,omitempty"`
+}
+
+// OperatorConfig Operator level configuration shared by all the CRs
+type OperatorConfig struct {
+	ImageRegistryMapping *ImageRegistryMapping
+	AquaServerApi        *AquaServerApi
+}
+
+var (
+	mutex    sync.Mutex
+	reader   client.Reader
+	cached   *OperatorConfig
+	loadedAt time.Time
+)
+
+// SetReader sets the reader the configuration is loaded with, an uncached reader lets the
+// operator namespace differ from the watched namespace
+func SetReader(r client.Reader) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	reader = r
+	cached = nil
+}
+
+// Get returns the operator configuration, it is loaded again after consts.OperatorConfigRefreshInterval
+func Get() (*OperatorConfig, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if reader == nil {
+		return &OperatorConfig{}, nil
+	}
+
+	if cached != nil && time.Since(loadedAt) < consts.OperatorConfigRefreshInterval {
+		return cached, nil
+	}
+
+	config, err := load(reader, extra.GetOperatorNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	cached = config
+	loadedAt = time.Now()
+	return cached, nil
+}
+
+func load(r client.Reader, namespace string) (*OperatorConfig, error) {
+	config := &OperatorConfig{}
+	if len(namespace) == 0 {
+		return config, nil
+	}
+
+	found := &corev1.ConfigMap{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: consts.OperatorConfigMapName, Namespace: namespace}, found)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+
+	if data, ok := found.Data[consts.OperatorConfigImageRegistryMappingKey]; ok {
+		mapping := &ImageRegistryMapping{}
+		err = yaml.UnmarshalStrict([]byte(data), mapping)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s in configmap %s: %v", consts.OperatorConfigImageRegistryMappingKey, consts.OperatorConfigMapName, err)
+		}
+		config.ImageRegistryMapping = mapping
+	}
+
+	if data, ok := found.Data[consts.OperatorConfigAquaServerApiKey]; ok {
+		api := &AquaServerApi{}
+		err = yaml.UnmarshalStrict([]byte(data), api)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s in configmap %s: %v", consts.OperatorConfigAquaServerApiKey, consts.OperatorConfigMapName, err)
+		}
+		config.AquaServerApi = api
+	}
+
+	return config, nil
+}
+
+// Address returns the address of the API of the server service, consts.AquaServerApiURL when the URL isn't set
+func (a *AquaServerApi) Address(service, namespace string) string {
+	address := consts.AquaServerApiURL
+	if a != nil && len(a.URL) != 0 {
+		address = a.URL
+	}
+
+	return strings.NewReplacer("{service}", service, "{namespace}", namespace).Replace(address)
+}
+
+// MapImage rewrites the registry and repository of the image, the tag or digest is kept
+func (m *ImageRegistryMapping) MapImage(image string) string {
+	if m == nil {
+		return image
+	}
+
+	name, suffix := image, ""
+	if i := strings.Index(name, "@"); i != -1 {
+		name, suffix = name[:i], name[i:]
+	}
+	if i := strings.LastIndex(name, ":"); i != -1 && !strings.Contains(name[i+1:], "/") {
+		name, suffix = name[:i], name[i:]+suffix
+	}
+
+	registry, repository := splitName(name)
+	mapped := false
+
+	if target, ok := m.Repositories[repository]; ok {
+		repository, mapped = target, true
+	} else if target, ok := m.Repositories[registry+"/"+repository]; ok {
+		repository, mapped = target, true
+	}
+	name = registry + "/" + repository
+
+	// the longest matching prefix wins, so a repository path can be mapped apart from its registry
+	prefixes := make([]string, 0, len(m.Registries))
+	for prefix := range m.Registries {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	for _, prefix := range prefixes {
+		trimmed := strings.TrimSuffix(prefix, "/")
+		if name == trimmed || strings.HasPrefix(name, trimmed+"/") {
+			name = strings.TrimSuffix(m.Registries[prefix], "/") + strings.TrimPrefix(name, trimmed)
+			mapped = true
+			break
+		}
+	}
+
+	if !mapped {
+		return image
+	}
+
+	return name + suffix
+}
+
+func splitName(name string) (string, string) {
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0], parts[1]
+	}
+
+	return "docker.io", name
+}
@@ -0,0 +1,57 @@
+package operatorconfig
+
+import (
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Operator config", func() {
+	DescribeTable("AquaServerApi Address",
+		func(api *AquaServerApi, address string) {
+			Expect(api.Address("aqua-server", "aqua")).To(Equal(address))
+		},
+		Entry("the service by default", nil, "http://aqua-server.aqua.svc:8080"),
+		Entry("an empty url", &AquaServerApi{}, "http://aqua-server.aqua.svc:8080"),
+		Entry("a template", &AquaServerApi{URL: "https://{service}.{namespace}.svc:8443"}, "https://aqua-server.aqua.svc:8443"),
+		Entry("a fixed address", &AquaServerApi{URL: "http://localhost:8080"}, "http://localhost:8080"),
+	)
+
+	Describe("load", func() {
+		configMap := func(data map[string]string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: consts.OperatorConfigMapName, Namespace: "aqua-operator"},
+				Data:       data,
+			}
+		}
+
+		It("is empty without the configmap", func() {
+			config, err := load(fake.NewClientBuilder().Build(), "aqua-operator")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).To(Equal(&OperatorConfig{}))
+		})
+
+		It("parses the server API", func() {
+			reader := fake.NewClientBuilder().WithObjects(configMap(map[string]string{
+				consts.OperatorConfigAquaServerApiKey: "url: http://localhost:8080\ntlsNoVerify: true\n",
+			})).Build()
+
+			config, err := load(reader, "aqua-operator")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.AquaServerApi).To(Equal(&AquaServerApi{URL: "http://localhost:8080", TLSNoVerify: true}))
+			Expect(config.ImageRegistryMapping).To(BeNil())
+		})
+
+		It("rejects unknown fields", func() {
+			reader := fake.NewClientBuilder().WithObjects(configMap(map[string]string{
+				consts.OperatorConfigAquaServerApiKey: "address: http://localhost:8080\n",
+			})).Build()
+
+			_, err := load(reader, "aqua-operator")
+			Expect(err).To(MatchError(ContainSubstring(consts.OperatorConfigAquaServerApiKey)))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// VerifyCosignSignature verifies the image digest has a cosign signature made with the private key of publicKey.
+// The signatures are read from the sha256-<hex>.sig tag of the image repository.
+func (c *Client) VerifyCosignSignature(digest string, publicKey []byte) error {
+	verify, err := newVerifier(publicKey)
+	if err != nil {
+		return err
+	}
+
+	signatureTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	body, err := c.getManifest(signatureTag)
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("no cosign signature found for %s@%s", c.ref.Repository, digest)
+		}
+		return err
+	}
+
+	manifest := struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}{}
+	err = json.Unmarshal(body, &manifest)
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range manifest.Layers {
+		signature, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil {
+			continue
+		}
+
+		payload, err := c.getBlob(layer.Digest)
+		if err != nil {
+			return err
+		}
+
+		if payloadDigest(payload) != digest {
+			continue
+		}
+
+		if verify(payload, sig) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no cosign signature of %s@%s matches the public key", c.ref.Repository, digest)
+}
+
+// payloadDigest returns the image digest a simple signing payload was made for
+func payloadDigest(payload []byte) string {
+	simpleSigning := struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}{}
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return ""
+	}
+
+	return simpleSigning.Critical.Image.DockerManifestDigest
+}
+
+// newVerifier parses a PEM public key, ECDSA keys are what cosign generates, RSA and ed25519 are accepted too
+func newVerifier(publicKey []byte) (func(payload, sig []byte) bool, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, fmt.Errorf("cosign public key is not PEM encoded")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		return func(payload, sig []byte) bool {
+			hash := sha256.Sum256(payload)
+			return ecdsa.VerifyASN1(key, hash[:], sig)
+		}, nil
+	case *rsa.PublicKey:
+		return func(payload, sig []byte) bool {
+			hash := sha256.Sum256(payload)
+			return rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig) == nil
+		}, nil
+	case ed25519.PublicKey:
+		return func(payload, sig []byte) bool {
+			return ed25519.Verify(key, payload, sig)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported cosign public key type %T", key)
+}
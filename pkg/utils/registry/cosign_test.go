@@ -0,0 +1,100 @@
+package registry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func cosignKey() (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	Expect(err).NotTo(HaveOccurred())
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// sign adds the signature manifest of digest to the registry, its payload is made for signed
+func sign(registry *fakeRegistry, key *ecdsa.PrivateKey, digest, signed string) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"aqua/enforcer"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, signed))
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	Expect(err).NotTo(HaveOccurred())
+
+	payloadDigest := fmt.Sprintf("sha256:%x", hash)
+	registry.blobs[payloadDigest] = payload
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"layers": []map[string]interface{}{{
+			"digest":      payloadDigest,
+			"annotations": map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+		}},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	registry.manifests[strings.Replace(digest, ":", "-", 1)+".sig"] = manifest
+}
+
+var _ = Describe("Cosign", func() {
+	var (
+		registry  *fakeRegistry
+		key       *ecdsa.PrivateKey
+		publicKey []byte
+		digest    string
+	)
+
+	BeforeEach(func() {
+		registry = newFakeRegistry()
+		key, publicKey = cosignKey()
+		digest = fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(`{"schemaVersion":2}`)))
+	})
+
+	AfterEach(func() {
+		registry.server.Close()
+	})
+
+	It("verifies a signature of the key", func() {
+		sign(registry, key, digest, digest)
+		c := registry.client("aqua/enforcer:2022.4", dockerConfigJson("pull", registry.host()))
+
+		Expect(c.VerifyCosignSignature(digest, publicKey)).To(Succeed())
+		Expect(registry.tokens).To(Equal(1))
+	})
+
+	It("refuses a signature of another key", func() {
+		other, _ := cosignKey()
+		sign(registry, other, digest, digest)
+		c := registry.client("aqua/enforcer:2022.4", dockerConfigJson("pull", registry.host()))
+
+		Expect(c.VerifyCosignSignature(digest, publicKey)).To(MatchError(fmt.Sprintf("no cosign signature of aqua/enforcer@%s matches the public key", digest)))
+	})
+
+	It("refuses a signature made for another image", func() {
+		sign(registry, key, digest, "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+		c := registry.client("aqua/enforcer:2022.4", dockerConfigJson("pull", registry.host()))
+
+		Expect(c.VerifyCosignSignature(digest, publicKey)).To(MatchError(fmt.Sprintf("no cosign signature of aqua/enforcer@%s matches the public key", digest)))
+	})
+
+	It("fails when the image has no signature", func() {
+		c := registry.client("aqua/enforcer:2022.4", dockerConfigJson("pull", registry.host()))
+
+		Expect(c.VerifyCosignSignature(digest, publicKey)).To(MatchError(fmt.Sprintf("no cosign signature found for aqua/enforcer@%s", digest)))
+	})
+
+	It("refuses a key that isn't PEM encoded", func() {
+		c := registry.client("aqua/enforcer:2022.4")
+
+		Expect(c.VerifyCosignSignature(digest, []byte("key"))).To(MatchError("cosign public key is not PEM encoded"))
+	})
+})
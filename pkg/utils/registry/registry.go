@@ -0,0 +1,393 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	requestTimeout = 30 * time.Second
+
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+)
+
+// manifestMediaTypes The manifest types accepted when resolving a tag, lists and indexes first so the digest is the multi arch one
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// Reference A parsed image reference
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses registry/repository[:tag][@digest], docker hub is the default registry
+func ParseReference(image string) (Reference, error) {
+	ref := Reference{}
+
+	name := image
+	if i := strings.Index(name, "@"); i != -1 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+	}
+
+	if i := strings.LastIndex(name, ":"); i != -1 && !strings.Contains(name[i+1:], "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry = parts[0]
+		ref.Repository = parts[1]
+	} else {
+		ref.Registry = dockerHubDomain
+		ref.Repository = name
+	}
+
+	if ref.Registry == dockerHubDomain && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+
+	if len(ref.Repository) == 0 {
+		return ref, fmt.Errorf("invalid image reference %q", image)
+	}
+
+	if len(ref.Tag) == 0 && len(ref.Digest) == 0 {
+		ref.Tag = "latest"
+	}
+
+	return ref, nil
+}
+
+func (ref Reference) apiHost() string {
+	if ref.Registry == dockerHubDomain {
+		return dockerHubRegistry
+	}
+
+	return ref.Registry
+}
+
+// Client Registry v2 API client for a single repository
+type Client struct {
+	ref      Reference
+	username string
+	password string
+	token    string
+	http     *http.Client
+}
+
+// NewClient returns a client for the repository of the image, the credentials are taken from the pull secrets
+func NewClient(k8sclient client.Client, namespace, image string, pullSecrets []string) (*Client, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		ref: ref,
+		http: &http.Client{
+			Timeout: requestTimeout,
+		},
+	}
+
+	for _, name := range pullSecrets {
+		username, password, found, err := credentialsFromSecret(k8sclient, namespace, name, ref.Registry)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			c.username = username
+			c.password = password
+			break
+		}
+	}
+
+	return c, nil
+}
+
+// Reference returns the parsed reference of the client image
+func (c *Client) Reference() Reference {
+	return c.ref
+}
+
+// ResolveDigest returns the digest the image tag points to, an image given by digest is returned as is
+func (c *Client) ResolveDigest() (string, error) {
+	if len(c.ref.Digest) != 0 {
+		return c.ref.Digest, nil
+	}
+
+	response, err := c.get(http.MethodHead, fmt.Sprintf("manifests/%s", c.ref.Tag), manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+
+	if digest := response.Header.Get("Docker-Content-Digest"); len(digest) != 0 {
+		return digest, nil
+	}
+
+	// Not all registries return the digest header, hash the manifest itself
+	response, err = c.get(http.MethodGet, fmt.Sprintf("manifests/%s", c.ref.Tag), manifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(body)), nil
+}
+
+func (c *Client) getManifest(reference string) ([]byte, error) {
+	response, err := c.get(http.MethodGet, fmt.Sprintf("manifests/%s", reference), manifestMediaTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return io.ReadAll(response.Body)
+}
+
+func (c *Client) getBlob(digest string) ([]byte, error) {
+	response, err := c.get(http.MethodGet, fmt.Sprintf("blobs/%s", digest), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if fmt.Sprintf("sha256:%x", sha256.Sum256(body)) != digest {
+		return nil, fmt.Errorf("blob %s of %s does not match its digest", digest, c.ref.Repository)
+	}
+
+	return body, nil
+}
+
+func (c *Client) get(method, path string, accept []string) (*http.Response, error) {
+	url := fmt.Sprintf("https://%s/v2/%s/%s", c.ref.apiHost(), c.ref.Repository, path)
+
+	response, err := c.do(method, url, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized && len(c.token) == 0 {
+		challenge := response.Header.Get("WWW-Authenticate")
+		response.Body.Close()
+
+		err = c.authorize(challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err = c.do(method, url, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, &StatusError{Method: method, URL: url, Status: response.Status, StatusCode: response.StatusCode}
+	}
+
+	return response, nil
+}
+
+func (c *Client) do(method, url string, accept []string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(context.TODO(), method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accept) != 0 {
+		request.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+
+	if len(c.token) != 0 {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	} else if len(c.username) != 0 {
+		request.SetBasicAuth(c.username, c.password)
+	}
+
+	return c.http.Do(request)
+}
+
+// authorize gets a bearer token for pulling the repository from the token service of the challenge
+func (c *Client) authorize(challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	if !strings.EqualFold(scheme, "bearer") {
+		return fmt.Errorf("registry %s refused the credentials", c.ref.Registry)
+	}
+
+	realm := params["realm"]
+	if len(realm) == 0 {
+		return fmt.Errorf("registry %s returned a bearer challenge without realm", c.ref.Registry)
+	}
+
+	request, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, realm, nil)
+	if err != nil {
+		return err
+	}
+
+	query := request.URL.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", c.ref.Repository))
+	request.URL.RawQuery = query.Encode()
+
+	if len(c.username) != 0 {
+		request.SetBasicAuth(c.username, c.password)
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("getting a token for %s from %s failed with status %s", c.ref.Repository, realm, response.Status)
+	}
+
+	body := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&body)
+	if err != nil {
+		return err
+	}
+
+	c.token = body.Token
+	if len(c.token) == 0 {
+		c.token = body.AccessToken
+	}
+	if len(c.token) == 0 {
+		return fmt.Errorf("token service %s returned no token", realm)
+	}
+
+	return nil
+}
+
+// StatusError A registry request that didn't return 200
+type StatusError struct {
+	Method     string
+	URL        string
+	Status     string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s failed with status %s", e.Method, e.URL, e.Status)
+}
+
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	if len(parts) != 2 {
+		return parts[0], params
+	}
+
+	for _, param := range strings.Split(parts[1], ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToLower(kv[0])] = strings.Trim(kv[1], "\"")
+		}
+	}
+
+	return parts[0], params
+}
+
+// credentialsFromSecret returns the credentials of the registry from a dockerconfigjson or dockercfg secret
+func credentialsFromSecret(k8sclient client.Client, namespace, name, registry string) (string, string, bool, error) {
+	secret := &corev1.Secret{}
+	err := k8sclient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	if err != nil {
+		return "", "", false, client.IgnoreNotFound(err)
+	}
+
+	type authEntry struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"`
+	}
+	auths := map[string]authEntry{}
+
+	if data, ok := secret.Data[corev1.DockerConfigJsonKey]; ok {
+		config := struct {
+			Auths map[string]authEntry `json:"auths"`
+		}{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return "", "", false, fmt.Errorf("parsing pull secret %s: %v", name, err)
+		}
+		auths = config.Auths
+	} else if data, ok := secret.Data[corev1.DockerConfigKey]; ok {
+		if err := json.Unmarshal(data, &auths); err != nil {
+			return "", "", false, fmt.Errorf("parsing pull secret %s: %v", name, err)
+		}
+	}
+
+	for server, entry := range auths {
+		if normalizeServer(server) != registry {
+			continue
+		}
+
+		if len(entry.Username) == 0 && len(entry.Auth) != 0 {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return "", "", false, fmt.Errorf("parsing pull secret %s: %v", name, err)
+			}
+			userPass := strings.SplitN(string(decoded), ":", 2)
+			if len(userPass) == 2 {
+				entry.Username = userPass[0]
+				entry.Password = userPass[1]
+			}
+		}
+
+		return entry.Username, entry.Password, true, nil
+	}
+
+	return "", "", false, nil
+}
+
+func normalizeServer(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	server = strings.SplitN(server, "/", 2)[0]
+
+	switch server {
+	case "index.docker.io", dockerHubRegistry:
+		return dockerHubDomain
+	}
+
+	return server
+}
@@ -0,0 +1,231 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeRegistry A registry v2 API of the aqua/enforcer repository, behind a bearer token service taking user:secret
+type fakeRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	// digestHeader Returns the Docker-Content-Digest header of the manifests
+	digestHeader bool
+	tokens       int
+}
+
+func newFakeRegistry() *fakeRegistry {
+	registry := &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}, digestHeader: true}
+	registry.server = httptest.NewTLSServer(http.HandlerFunc(registry.serve))
+	return registry
+}
+
+func (f *fakeRegistry) host() string {
+	return strings.TrimPrefix(f.server.URL, "https://")
+}
+
+func (f *fakeRegistry) client(image string, pullSecrets ...*corev1.Secret) *Client {
+	builder := fake.NewClientBuilder()
+	for _, secret := range pullSecrets {
+		builder = builder.WithObjects(secret)
+	}
+	names := []string{}
+	for _, secret := range pullSecrets {
+		names = append(names, secret.Name)
+	}
+
+	c, err := NewClient(builder.Build(), "aqua", f.host()+"/"+image, names)
+	Expect(err).NotTo(HaveOccurred())
+	c.http = f.server.Client()
+	return c
+}
+
+func (f *fakeRegistry) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "secret" ||
+			r.URL.Query().Get("service") != "fake" || r.URL.Query().Get("scope") != "repository:aqua/enforcer:pull" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.tokens++
+		_, _ = w.Write([]byte(`{"access_token":"bearer"}`))
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer bearer" {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, f.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if reference := strings.TrimPrefix(r.URL.Path, "/v2/aqua/enforcer/manifests/"); reference != r.URL.Path {
+		manifest, ok := f.manifests[reference]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if f.digestHeader {
+			w.Header().Set("Docker-Content-Digest", fmt.Sprintf("sha256:%x", sha256.Sum256(manifest)))
+		}
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(manifest)
+		}
+		return
+	}
+
+	if digest := strings.TrimPrefix(r.URL.Path, "/v2/aqua/enforcer/blobs/"); digest != r.URL.Path {
+		blob, ok := f.blobs[digest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(blob)
+		return
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func pullSecret(name string, secretType corev1.SecretType, key, data string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "aqua"},
+		Type:       secretType,
+		Data:       map[string][]byte{key: []byte(data)},
+	}
+}
+
+func dockerConfigJson(name, server string) *corev1.Secret {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	return pullSecret(name, corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey,
+		fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, server, auth))
+}
+
+var _ = Describe("Registry", func() {
+	DescribeTable("ParseReference",
+		func(image string, expected Reference) {
+			ref, err := ParseReference(image)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ref).To(Equal(expected))
+		},
+		Entry("a docker hub library image", "nginx",
+			Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}),
+		Entry("a docker hub image", "aquasec/enforcer:2022.4",
+			Reference{Registry: "docker.io", Repository: "aquasec/enforcer", Tag: "2022.4"}),
+		Entry("a registry with a port", "localhost:5000/aqua/enforcer",
+			Reference{Registry: "localhost:5000", Repository: "aqua/enforcer", Tag: "latest"}),
+		Entry("localhost", "localhost/enforcer:2022.4",
+			Reference{Registry: "localhost", Repository: "enforcer", Tag: "2022.4"}),
+		Entry("a tag and a digest", "registry.aquasec.com/enforcer:2022.4@sha256:abc",
+			Reference{Registry: "registry.aquasec.com", Repository: "enforcer", Tag: "2022.4", Digest: "sha256:abc"}),
+		Entry("a digest", "registry.aquasec.com/enforcer@sha256:abc",
+			Reference{Registry: "registry.aquasec.com", Repository: "enforcer", Digest: "sha256:abc"}),
+	)
+
+	It("refuses an image without repository", func() {
+		_, err := ParseReference("registry.aquasec.com/")
+		Expect(err).To(MatchError(`invalid image reference "registry.aquasec.com/"`))
+	})
+
+	Describe("ResolveDigest", func() {
+		var registry *fakeRegistry
+
+		BeforeEach(func() {
+			registry = newFakeRegistry()
+			registry.manifests["2022.4"] = []byte(`{"schemaVersion":2}`)
+		})
+
+		AfterEach(func() {
+			registry.server.Close()
+		})
+
+		It("gets a bearer token with the credentials of the pull secret", func() {
+			c := registry.client("aqua/enforcer:2022.4", dockerConfigJson("pull", registry.host()))
+
+			digest, err := c.ResolveDigest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(Equal(fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(`{"schemaVersion":2}`)))))
+			Expect(registry.tokens).To(Equal(1))
+		})
+
+		It("hashes the manifest when the registry returns no digest header", func() {
+			registry.digestHeader = false
+			c := registry.client("aqua/enforcer:2022.4", dockerConfigJson("pull", registry.host()))
+
+			digest, err := c.ResolveDigest()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(Equal(fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(`{"schemaVersion":2}`)))))
+		})
+
+		It("fails when the token service refuses the credentials", func() {
+			c := registry.client("aqua/enforcer:2022.4")
+
+			_, err := c.ResolveDigest()
+			Expect(err).To(MatchError(ContainSubstring("getting a token for aqua/enforcer from " + registry.server.URL + "/token failed with status 401")))
+		})
+
+		It("returns the status of a missing tag", func() {
+			c := registry.client("aqua/enforcer:2023.1", dockerConfigJson("pull", registry.host()))
+
+			_, err := c.ResolveDigest()
+			statusErr := &StatusError{}
+			Expect(err).To(BeAssignableToTypeOf(statusErr))
+			Expect(err.(*StatusError).StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("returns the digest of an image given by digest", func() {
+			c := registry.client("aqua/enforcer@sha256:abc")
+
+			Expect(c.ResolveDigest()).To(Equal("sha256:abc"))
+			Expect(registry.tokens).To(Equal(0))
+		})
+	})
+
+	DescribeTable("credentialsFromSecret",
+		func(secret *corev1.Secret, registry, username, password string, found bool) {
+			k8sclient := fake.NewClientBuilder().WithObjects(secret).Build()
+
+			u, p, ok, err := credentialsFromSecret(k8sclient, "aqua", secret.Name, registry)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(found))
+			Expect(u).To(Equal(username))
+			Expect(p).To(Equal(password))
+		},
+		Entry("a dockerconfigjson auth", dockerConfigJson("pull", "registry.aquasec.com"),
+			"registry.aquasec.com", "user", "secret", true),
+		Entry("a dockerconfigjson username and password",
+			pullSecret("pull", corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey,
+				`{"auths":{"registry.aquasec.com":{"username":"aqua","password":"pass"}}}`),
+			"registry.aquasec.com", "aqua", "pass", true),
+		Entry("a dockercfg of docker hub",
+			pullSecret("pull", corev1.SecretTypeDockercfg, corev1.DockerConfigKey,
+				`{"https://index.docker.io/v1/":{"username":"aqua","password":"pass"}}`),
+			"docker.io", "aqua", "pass", true),
+		Entry("another registry", dockerConfigJson("pull", "registry.aquasec.com"),
+			"localhost:5000", "", "", false),
+	)
+
+	It("skips a missing pull secret", func() {
+		_, _, found, err := credentialsFromSecret(fake.NewClientBuilder().Build(), "aqua", "pull", "registry.aquasec.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("fails on a malformed pull secret", func() {
+		secret := pullSecret("pull", corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey, `{"auths":`)
+		_, _, _, err := credentialsFromSecret(fake.NewClientBuilder().WithObjects(secret).Build(), "aqua", "pull", "registry.aquasec.com")
+		Expect(err).To(MatchError(ContainSubstring("parsing pull secret pull")))
+	})
+})
@@ -0,0 +1,12 @@
+package rego
+
+import (
+	"github.com/open-policy-agent/opa/ast"
+)
+
+// Check parses the Rego module with the OPA parser, the file name prefixes the line of each error. An empty module,
+// or one made of comments only, is an error
+func Check(filename, module string) error {
+	_, err := ast.ParseModule(filename, module)
+	return err
+}
@@ -0,0 +1,41 @@
+package rego
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rego", func() {
+	DescribeTable("Check accepts",
+		func(module string) {
+			Expect(Check("policy.rego", module)).To(Succeed())
+		},
+		Entry("a deny rule", `package appshield.kubernetes.KSV001
+
+import data.lib.kubernetes
+
+deny[msg] {
+	kubernetes.containers[container].securityContext.privileged == true
+	msg := sprintf("container %s is privileged", [container.name])
+}`),
+		Entry("comments before the package clause", "# the library\n\npackage lib.kubernetes\n\nname = input.metadata.name\n"),
+		Entry("a quoted package path", `package lib["kubernetes"]`),
+		Entry("strings with braces and escapes", "package p\n\nmsg := \"unbalanced { \\\" }\"\nraw := `a\n} b`\n"),
+		Entry("a function", "package p\n\nis_root(c) {\n\tc.securityContext.runAsUser == 0\n}\n"),
+		Entry("the future keywords", "package p\n\nimport future.keywords.in\n\ndeny[msg] {\n\t\"root\" in input.users\n\tmsg := \"root\"\n}\n"),
+	)
+
+	DescribeTable("Check rejects",
+		func(module, message string) {
+			Expect(Check("policy.rego", module)).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("an incomplete expression", "package p\n\ndeny[msg] { input.x == }\n", "policy.rego:3: rego_parse_error"),
+		Entry("a missing :=", "package p\n\ndeny[msg] {\n\tmsg \"denied\"\n}\n", "policy.rego:4: rego_parse_error"),
+		Entry("an unclosed brace", "package p\n\ndeny[msg] {\n\tmsg := \"denied\"\n", "rego_parse_error"),
+		Entry("an unterminated string", "package p\n\nmsg := \"denied\n", "policy.rego:3: rego_parse_error"),
+		Entry("no package clause", "deny[msg] {\n\tmsg := \"denied\"\n}\n", "policy.rego:1: rego_parse_error"),
+		Entry("an invalid package path", "package 1p\n", "policy.rego:1: rego_parse_error"),
+		Entry("an empty module", "", "policy.rego:0: rego_parse_error: empty module"),
+		Entry("comments only", "# nothing yet\n", "policy.rego:0: rego_parse_error: empty module"),
+	)
+})
@@ -0,0 +1,169 @@
+package secretstore
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	"github.com/aquasecurity/aqua-operator/pkg/utils/extra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const requestTimeout = 30 * time.Second
+
+// Read returns the values of the given keys from the secret store, missing keys are an error
+func Read(k8sclient client.Client, namespace string, store *operatorv1alpha1.AquaSecretStore, keys ...string) (map[string]string, error) {
+	var values map[string]string
+	var err error
+
+	switch {
+	case store.Vault != nil:
+		values, err = readVault(k8sclient, namespace, store.Vault)
+	case store.File != nil:
+		values, err = readFiles(extra.GetSecretStoreRoot(), store.File, keys)
+	default:
+		return nil, fmt.Errorf("secret store must define vault or file")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if _, ok := values[key]; !ok {
+			return nil, fmt.Errorf("key %q not found in secret store", key)
+		}
+	}
+
+	return values, nil
+}
+
+// readFiles reads a file per key from the path of the store under root. The path and the keys come from the CR, so
+// they must stay under root once their symlinks are resolved, the files of the operator pod are not readable
+func readFiles(root string, store *operatorv1alpha1.AquaFileSecretStore, keys []string) (map[string]string, error) {
+	if len(root) == 0 {
+		return nil, fmt.Errorf("file secret stores are disabled, SECRET_STORE_ROOT of the operator is not set")
+	}
+	if !isLocal(store.Path) {
+		return nil, fmt.Errorf("file secret store path %q must be relative to the secret store root", store.Path)
+	}
+
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for _, key := range keys {
+		if len(key) == 0 || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+			return nil, fmt.Errorf("file secret store key %q must be a file name", key)
+		}
+
+		path, err := filepath.EvalSymlinks(filepath.Join(root, store.Path, key))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if relative, err := filepath.Rel(root, path); err != nil || !isLocal(relative) {
+			return nil, fmt.Errorf("file secret store key %q resolves out of the secret store root", key)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = strings.TrimRight(string(data), "\r\n")
+	}
+
+	return values, nil
+}
+
+// isLocal checks the path is relative and doesn't climb out of the directory it is joined to
+func isLocal(path string) bool {
+	if filepath.IsAbs(path) {
+		return false
+	}
+
+	path = filepath.Clean(path)
+	return path != ".." && !strings.HasPrefix(path, ".."+string(filepath.Separator))
+}
+
+func readVault(k8sclient client.Client, namespace string, store *operatorv1alpha1.AquaVaultSecretStore) (map[string]string, error) {
+	if store.TokenSecret == nil {
+		return nil, fmt.Errorf("vault secret store must define tokenSecret")
+	}
+
+	secret := &corev1.Secret{}
+	err := k8sclient.Get(context.TODO(), types.NamespacedName{Name: store.TokenSecret.Name, Namespace: namespace}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := secret.Data[store.TokenSecret.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in secret %s", store.TokenSecret.Key, store.TokenSecret.Name)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimRight(store.Address, "/"), strings.TrimLeft(store.Path, "/"))
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Vault-Token", strings.TrimSpace(string(token)))
+	if len(store.Namespace) > 0 {
+		request.Header.Set("X-Vault-Namespace", store.Namespace)
+	}
+
+	httpClient := &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: store.Insecure},
+		},
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading %s from secret store failed with status %s", store.Path, response.Status)
+	}
+
+	// KV version 1 returns the values under data, version 2 under data.data
+	body := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	err = json.NewDecoder(response.Body).Decode(&body)
+	if err != nil {
+		return nil, err
+	}
+
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nested
+		}
+	}
+
+	values := map[string]string{}
+	for key, value := range data {
+		if str, ok := value.(string); ok {
+			values[key] = str
+		}
+	}
+
+	return values, nil
+}
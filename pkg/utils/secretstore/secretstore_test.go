@@ -0,0 +1,109 @@
+package secretstore
+
+import (
+	"os"
+	"path/filepath"
+
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("File secret store", func() {
+	var root, outside string
+
+	write := func(path, content string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir, err := os.MkdirTemp("", "secretstore")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(os.RemoveAll, dir)
+
+		root = filepath.Join(dir, "root")
+		outside = filepath.Join(dir, "outside")
+		write(filepath.Join(root, "aqua", "admin-password"), "secret\n")
+		write(filepath.Join(outside, "token"), "service account token")
+
+		// the layout of the secrets store CSI driver, the files link to a versioned directory
+		write(filepath.Join(root, "csi", "..2022_06_01", "license"), "license")
+		Expect(os.Symlink("..2022_06_01", filepath.Join(root, "csi", "..data"))).To(Succeed())
+		Expect(os.Symlink(filepath.Join("..data", "license"), filepath.Join(root, "csi", "license"))).To(Succeed())
+	})
+
+	It("reads a file per key under the root", func() {
+		values, err := readFiles(root, &operatorv1alpha1.AquaFileSecretStore{Path: "aqua"}, []string{"admin-password", "missing"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(Equal(map[string]string{"admin-password": "secret"}))
+	})
+
+	It("follows the symlinks that stay under the root", func() {
+		values, err := readFiles(root, &operatorv1alpha1.AquaFileSecretStore{Path: "csi"}, []string{"license"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(Equal(map[string]string{"license": "license"}))
+	})
+
+	It("is disabled without a root", func() {
+		_, err := readFiles("", &operatorv1alpha1.AquaFileSecretStore{Path: "aqua"}, []string{"admin-password"})
+		Expect(err).To(MatchError(ContainSubstring("file secret stores are disabled")))
+	})
+
+	DescribeTable("rejects the paths out of the root",
+		func(path string) {
+			_, err := readFiles(root, &operatorv1alpha1.AquaFileSecretStore{Path: path}, []string{"token"})
+			Expect(err).To(MatchError(ContainSubstring("must be relative to the secret store root")))
+		},
+		Entry("absolute path", "/var/run/secrets/kubernetes.io/serviceaccount"),
+		Entry("parent", ".."),
+		Entry("parent sibling", "../outside"),
+		Entry("parent after a directory", "aqua/../../outside"),
+	)
+
+	DescribeTable("rejects the keys that aren't file names",
+		func(key string) {
+			_, err := readFiles(root, &operatorv1alpha1.AquaFileSecretStore{Path: "aqua"}, []string{key})
+			Expect(err).To(MatchError(ContainSubstring("must be a file name")))
+		},
+		Entry("parent", ".."),
+		Entry("current", "."),
+		Entry("empty", ""),
+		Entry("path", "../../outside/token"),
+		Entry("absolute path", "/etc/passwd"),
+		Entry("windows separator", `..\outside`),
+	)
+
+	It("rejects the symlinks out of the root", func() {
+		Expect(os.Symlink(filepath.Join(outside, "token"), filepath.Join(root, "aqua", "token"))).To(Succeed())
+		_, err := readFiles(root, &operatorv1alpha1.AquaFileSecretStore{Path: "aqua"}, []string{"token"})
+		Expect(err).To(MatchError(ContainSubstring("resolves out of the secret store root")))
+	})
+
+	It("rejects a path symlinked out of the root", func() {
+		Expect(os.Symlink(outside, filepath.Join(root, "linked"))).To(Succeed())
+		_, err := readFiles(root, &operatorv1alpha1.AquaFileSecretStore{Path: "linked"}, []string{"token"})
+		Expect(err).To(MatchError(ContainSubstring("resolves out of the secret store root")))
+	})
+
+	It("reads the root of SECRET_STORE_ROOT and reports the missing keys", func() {
+		previous, found := os.LookupEnv("SECRET_STORE_ROOT")
+		Expect(os.Setenv("SECRET_STORE_ROOT", root)).To(Succeed())
+		DeferCleanup(func() {
+			if found {
+				_ = os.Setenv("SECRET_STORE_ROOT", previous)
+			} else {
+				_ = os.Unsetenv("SECRET_STORE_ROOT")
+			}
+		})
+
+		store := &operatorv1alpha1.AquaSecretStore{File: &operatorv1alpha1.AquaFileSecretStore{Path: "aqua"}}
+		values, err := Read(nil, "aqua", store, "admin-password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(Equal(map[string]string{"admin-password": "secret"}))
+
+		_, err = Read(nil, "aqua", store, "admin-password", "missing")
+		Expect(err).To(MatchError(`key "missing" not found in secret store`))
+	})
+})
@@ -0,0 +1,224 @@
+package securitysummary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/apis/aquasecurity/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Aggregator Sums up vulnerability and config audit reports, starboard and trivy-operator ones have the same layout
+type Aggregator struct {
+	status     v1alpha1.AquaSecuritySummaryStatus
+	namespaces map[string]*v1alpha1.AquaNamespaceSecuritySummary
+	images     map[string]*v1alpha1.AquaVulnerableImage
+	checks     map[string]*v1alpha1.AquaFailingCheck
+}
+
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		namespaces: map[string]*v1alpha1.AquaNamespaceSecuritySummary{},
+		images:     map[string]*v1alpha1.AquaVulnerableImage{},
+		checks:     map[string]*v1alpha1.AquaFailingCheck{},
+	}
+}
+
+// AddVulnerabilityReport adds the vulnerabilities of the report to its namespace, its image and the totals
+func (a *Aggregator) AddVulnerabilityReport(report *unstructured.Unstructured) {
+	counts := severityCounts(report, "report", "summary")
+
+	ns := a.namespace(report.GetNamespace())
+	ns.VulnerabilityReports++
+	add(&ns.Vulnerabilities, counts)
+	a.status.VulnerabilityReports++
+	add(&a.status.Vulnerabilities, counts)
+
+	// the reports of the same image hold the same vulnerabilities, they are counted once per image
+	name := image(report)
+	img, ok := a.images[name]
+	if !ok {
+		img = &v1alpha1.AquaVulnerableImage{Image: name, Vulnerabilities: counts}
+		a.images[name] = img
+	}
+	img.Workloads++
+}
+
+// AddConfigAuditReport adds the failing checks of the report to its namespace, the checks and the totals
+func (a *Aggregator) AddConfigAuditReport(report *unstructured.Unstructured) {
+	counts := severityCounts(report, "report", "summary")
+
+	ns := a.namespace(report.GetNamespace())
+	ns.ConfigAuditReports++
+	add(&ns.FailingChecks, counts)
+	a.status.ConfigAuditReports++
+	add(&a.status.FailingChecks, counts)
+
+	checks, _, _ := unstructured.NestedSlice(report.Object, "report", "checks")
+	for _, c := range checks {
+		check, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if success, _, _ := unstructured.NestedBool(check, "success"); success {
+			continue
+		}
+		id, _, _ := unstructured.NestedString(check, "checkID")
+		if id == "" {
+			continue
+		}
+		failing, ok := a.checks[id]
+		if !ok {
+			title, _, _ := unstructured.NestedString(check, "title")
+			severity, _, _ := unstructured.NestedString(check, "severity")
+			failing = &v1alpha1.AquaFailingCheck{ID: id, Title: title, Severity: strings.ToUpper(severity)}
+			a.checks[id] = failing
+		}
+		failing.Resources++
+	}
+}
+
+// Status returns the totals, the namespaces by name, the topImages most vulnerable images and the topChecks checks
+// failing the most
+func (a *Aggregator) Status(topImages, topChecks int) v1alpha1.AquaSecuritySummaryStatus {
+	status := a.status
+
+	status.Namespaces = []v1alpha1.AquaNamespaceSecuritySummary{}
+	for _, ns := range a.namespaces {
+		status.Namespaces = append(status.Namespaces, *ns)
+	}
+	sort.Slice(status.Namespaces, func(i, j int) bool {
+		return status.Namespaces[i].Namespace < status.Namespaces[j].Namespace
+	})
+
+	images := []v1alpha1.AquaVulnerableImage{}
+	for _, img := range a.images {
+		if total(img.Vulnerabilities) > 0 {
+			images = append(images, *img)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if c := compare(images[i].Vulnerabilities, images[j].Vulnerabilities); c != 0 {
+			return c > 0
+		}
+		return images[i].Image < images[j].Image
+	})
+	if len(images) > topImages {
+		images = images[:topImages]
+	}
+	status.TopVulnerableImages = images
+
+	checks := []v1alpha1.AquaFailingCheck{}
+	for _, check := range a.checks {
+		checks = append(checks, *check)
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		if si, sj := severityRank(checks[i].Severity), severityRank(checks[j].Severity); si != sj {
+			return si > sj
+		}
+		if checks[i].Resources != checks[j].Resources {
+			return checks[i].Resources > checks[j].Resources
+		}
+		return checks[i].ID < checks[j].ID
+	})
+	if len(checks) > topChecks {
+		checks = checks[:topChecks]
+	}
+	status.TopFailingChecks = checks
+
+	return status
+}
+
+func (a *Aggregator) namespace(name string) *v1alpha1.AquaNamespaceSecuritySummary {
+	ns, ok := a.namespaces[name]
+	if !ok {
+		ns = &v1alpha1.AquaNamespaceSecuritySummary{Namespace: name}
+		a.namespaces[name] = ns
+	}
+
+	return ns
+}
+
+// image returns registry/repository:tag of the artifact of a vulnerability report, the digest when it has no tag
+func image(report *unstructured.Unstructured) string {
+	server, _, _ := unstructured.NestedString(report.Object, "report", "registry", "server")
+	repository, _, _ := unstructured.NestedString(report.Object, "report", "artifact", "repository")
+	tag, _, _ := unstructured.NestedString(report.Object, "report", "artifact", "tag")
+	digest, _, _ := unstructured.NestedString(report.Object, "report", "artifact", "digest")
+
+	name := repository
+	if server != "" {
+		name = fmt.Sprintf("%s/%s", server, repository)
+	}
+	if tag != "" {
+		return fmt.Sprintf("%s:%s", name, tag)
+	}
+	if digest != "" {
+		return fmt.Sprintf("%s@%s", name, digest)
+	}
+
+	return name
+}
+
+func severityCounts(report *unstructured.Unstructured, fields ...string) v1alpha1.AquaSeverityCounts {
+	summary, _, _ := unstructured.NestedMap(report.Object, fields...)
+	// the API server decodes the counts as int64, a report read from JSON elsewhere holds float64
+	count := func(key string) int64 {
+		switch n := summary[key].(type) {
+		case int64:
+			return n
+		case float64:
+			return int64(n)
+		}
+		return 0
+	}
+
+	return v1alpha1.AquaSeverityCounts{
+		Critical: count("criticalCount"),
+		High:     count("highCount"),
+		Medium:   count("mediumCount"),
+		Low:      count("lowCount"),
+		Unknown:  count("unknownCount"),
+	}
+}
+
+func add(to *v1alpha1.AquaSeverityCounts, counts v1alpha1.AquaSeverityCounts) {
+	to.Critical += counts.Critical
+	to.High += counts.High
+	to.Medium += counts.Medium
+	to.Low += counts.Low
+	to.Unknown += counts.Unknown
+}
+
+func total(counts v1alpha1.AquaSeverityCounts) int64 {
+	return counts.Critical + counts.High + counts.Medium + counts.Low + counts.Unknown
+}
+
+// compare orders counts by critical, then high, medium, low and unknown findings
+func compare(a, b v1alpha1.AquaSeverityCounts) int {
+	for _, d := range []int64{a.Critical - b.Critical, a.High - b.High, a.Medium - b.Medium, a.Low - b.Low, a.Unknown - b.Unknown} {
+		if d > 0 {
+			return 1
+		} else if d < 0 {
+			return -1
+		}
+	}
+
+	return 0
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	}
+
+	return 0
+}
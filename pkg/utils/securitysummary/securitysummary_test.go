@@ -0,0 +1,126 @@
+package securitysummary
+
+import (
+	"encoding/json"
+
+	"github.com/aquasecurity/aqua-operator/apis/aquasecurity/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// report decodes a report from JSON, so its counts are float64
+func report(namespace, content string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	Expect(json.Unmarshal([]byte(content), &obj.Object)).To(Succeed())
+	obj.SetNamespace(namespace)
+
+	return obj
+}
+
+func vulnerabilityReport(namespace, registry, repository, tag, digest string, critical, high int64) *unstructured.Unstructured {
+	// the API server decodes the counts as int64
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"namespace": namespace},
+		"report": map[string]interface{}{
+			"registry": map[string]interface{}{"server": registry},
+			"artifact": map[string]interface{}{"repository": repository, "tag": tag, "digest": digest},
+			"summary":  map[string]interface{}{"criticalCount": critical, "highCount": high},
+		},
+	}}
+}
+
+var _ = Describe("Aggregator", func() {
+	var aggregator *Aggregator
+
+	BeforeEach(func() {
+		aggregator = NewAggregator()
+	})
+
+	It("sums up the vulnerabilities by namespace and counts each image once", func() {
+		aggregator.AddVulnerabilityReport(vulnerabilityReport("aqua", "index.docker.io", "library/nginx", "1.21", "", 2, 5))
+		aggregator.AddVulnerabilityReport(vulnerabilityReport("default", "index.docker.io", "library/nginx", "1.21", "", 2, 5))
+		aggregator.AddVulnerabilityReport(report("aqua", `{"report": {
+			"artifact": {"repository": "aquasec/server", "digest": "sha256:abc"},
+			"summary": {"criticalCount": 0, "highCount": 1, "mediumCount": 3, "lowCount": 7, "unknownCount": 1}}}`))
+		status := aggregator.Status(10, 10)
+
+		Expect(status.VulnerabilityReports).To(Equal(int64(3)))
+		Expect(status.Vulnerabilities).To(Equal(v1alpha1.AquaSeverityCounts{Critical: 4, High: 11, Medium: 3, Low: 7, Unknown: 1}))
+		Expect(status.Namespaces).To(Equal([]v1alpha1.AquaNamespaceSecuritySummary{
+			{Namespace: "aqua", VulnerabilityReports: 2, Vulnerabilities: v1alpha1.AquaSeverityCounts{Critical: 2, High: 6, Medium: 3, Low: 7, Unknown: 1}},
+			{Namespace: "default", VulnerabilityReports: 1, Vulnerabilities: v1alpha1.AquaSeverityCounts{Critical: 2, High: 5}},
+		}))
+		Expect(status.TopVulnerableImages).To(Equal([]v1alpha1.AquaVulnerableImage{
+			{Image: "index.docker.io/library/nginx:1.21", Workloads: 2, Vulnerabilities: v1alpha1.AquaSeverityCounts{Critical: 2, High: 5}},
+			{Image: "aquasec/server@sha256:abc", Workloads: 1, Vulnerabilities: v1alpha1.AquaSeverityCounts{High: 1, Medium: 3, Low: 7, Unknown: 1}},
+		}))
+	})
+
+	It("orders the images by severity, leaves out the clean ones and keeps the top ones", func() {
+		aggregator.AddVulnerabilityReport(vulnerabilityReport("aqua", "", "b", "1", "", 1, 0))
+		aggregator.AddVulnerabilityReport(vulnerabilityReport("aqua", "", "a", "1", "", 1, 0))
+		aggregator.AddVulnerabilityReport(vulnerabilityReport("aqua", "", "many-high", "1", "", 0, 50))
+		aggregator.AddVulnerabilityReport(vulnerabilityReport("aqua", "", "critical", "1", "", 2, 0))
+		aggregator.AddVulnerabilityReport(vulnerabilityReport("aqua", "", "clean", "1", "", 0, 0))
+
+		images := []string{}
+		for _, img := range aggregator.Status(4, 10).TopVulnerableImages {
+			images = append(images, img.Image)
+		}
+		Expect(images).To(Equal([]string{"critical:1", "a:1", "b:1", "many-high:1"}))
+		Expect(aggregator.Status(2, 10).TopVulnerableImages).To(HaveLen(2))
+	})
+
+	It("sums up the failing checks and orders them by severity and resources", func() {
+		checks := `"checks": [
+			{"checkID": "KSV001", "title": "Privileged", "severity": "high", "success": false},
+			{"checkID": "KSV012", "title": "Runs as root", "severity": "MEDIUM"},
+			{"checkID": "KSV014", "title": "Writable root file system", "severity": "LOW", "success": true},
+			{"title": "No ID", "severity": "CRITICAL"}
+		]`
+		aggregator.AddConfigAuditReport(report("aqua", `{"report": {"summary": {"highCount": 1, "mediumCount": 1}, `+checks+`}}`))
+		aggregator.AddConfigAuditReport(report("aqua", `{"report": {"summary": {"mediumCount": 1}, "checks": [
+			{"checkID": "KSV012", "title": "Runs as root", "severity": "MEDIUM"},
+			{"checkID": "KSV020", "title": "Low UID", "severity": "MEDIUM"}]}}`))
+		aggregator.AddConfigAuditReport(report("default", `{"report": {"summary": {"criticalCount": 1}, "checks": [
+			{"checkID": "KSV017", "title": "Host path", "severity": "CRITICAL"}]}}`))
+		status := aggregator.Status(10, 3)
+
+		Expect(status.ConfigAuditReports).To(Equal(int64(3)))
+		Expect(status.FailingChecks).To(Equal(v1alpha1.AquaSeverityCounts{Critical: 1, High: 1, Medium: 2}))
+		Expect(status.Namespaces).To(Equal([]v1alpha1.AquaNamespaceSecuritySummary{
+			{Namespace: "aqua", ConfigAuditReports: 2, FailingChecks: v1alpha1.AquaSeverityCounts{High: 1, Medium: 2}},
+			{Namespace: "default", ConfigAuditReports: 1, FailingChecks: v1alpha1.AquaSeverityCounts{Critical: 1}},
+		}))
+		Expect(status.TopFailingChecks).To(Equal([]v1alpha1.AquaFailingCheck{
+			{ID: "KSV017", Title: "Host path", Severity: "CRITICAL", Resources: 1},
+			{ID: "KSV001", Title: "Privileged", Severity: "HIGH", Resources: 1},
+			{ID: "KSV012", Title: "Runs as root", Severity: "MEDIUM", Resources: 2},
+		}))
+		Expect(status.VulnerabilityReports).To(BeZero())
+		Expect(status.TopVulnerableImages).To(BeEmpty())
+	})
+
+	It("counts a report without a summary as empty", func() {
+		aggregator.AddVulnerabilityReport(report("aqua", `{"report": {"artifact": {"repository": "nginx", "tag": "latest"}}}`))
+		aggregator.AddConfigAuditReport(report("aqua", `{"report": {"summary": "invalid"}}`))
+		status := aggregator.Status(10, 10)
+
+		Expect(status.VulnerabilityReports).To(Equal(int64(1)))
+		Expect(status.ConfigAuditReports).To(Equal(int64(1)))
+		Expect(status.Vulnerabilities).To(Equal(v1alpha1.AquaSeverityCounts{}))
+		Expect(status.TopVulnerableImages).To(BeEmpty())
+		Expect(status.TopFailingChecks).To(BeEmpty())
+	})
+
+	It("returns empty lists without reports", func() {
+		status := aggregator.Status(10, 10)
+
+		Expect(status.Namespaces).To(BeEmpty())
+		Expect(status.Namespaces).NotTo(BeNil())
+		Expect(status.TopVulnerableImages).NotTo(BeNil())
+		Expect(status.TopFailingChecks).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,62 @@
+package supportbundle
+
+import (
+	"regexp"
+)
+
+var (
+	// sensitiveKey Field, data key or env var names holding a credential
+	sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|token|license|apikey|api_key|privatekey|private_key|secretkey|secret_key)$`)
+
+	// sensitiveAssignment A credential assigned in free text: password=x, "token": "x", AQUA_TOKEN: x
+	sensitiveAssignment = regexp.MustCompile(`(?i)([\w.-]*(?:password|passwd|token|license|api[_-]?key|private[_-]?key|secret[_-]?key)[\w-]*["']?[ \t]*[:=][ \t]*["']?)([^\s"',;&{}\[\]]+)`)
+
+	// privateKeyBlock PEM encoded private keys
+	privateKeyBlock = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+
+	// jsonWebToken Bearer tokens, service account and Aqua tokens are JWTs
+	jsonWebToken = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+)
+
+// RedactText replaces what looks like a password, token, license or private key in logs and messages
+func RedactText(data []byte) []byte {
+	data = privateKeyBlock.ReplaceAll(data, []byte(RedactedValue))
+	data = jsonWebToken.ReplaceAll(data, []byte(RedactedValue))
+	return sensitiveAssignment.ReplaceAll(data, []byte("${1}"+RedactedValue))
+}
+
+// RedactObject redacts an unstructured object in place, the values of sensitive fields and env vars are
+// replaced and the other strings are redacted as text
+func RedactObject(obj map[string]interface{}) {
+	// env vars and the like: {name: AQUA_TOKEN, value: x}
+	if name, ok := obj["name"].(string); ok && sensitiveKey.MatchString(name) {
+		if _, ok := obj["value"].(string); ok {
+			obj["value"] = RedactedValue
+		}
+	}
+
+	for key, value := range obj {
+		if str, ok := value.(string); ok && sensitiveKey.MatchString(key) {
+			if len(str) > 0 {
+				obj[key] = RedactedValue
+			}
+			continue
+		}
+		obj[key] = redactValue(value)
+	}
+}
+
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		RedactObject(typed)
+	case []interface{}:
+		for i := range typed {
+			typed[i] = redactValue(typed[i])
+		}
+	case string:
+		return string(RedactText([]byte(typed)))
+	}
+
+	return value
+}
@@ -0,0 +1,286 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	aquasecurityv1alpha1 "github.com/aquasecurity/aqua-operator/apis/aquasecurity/v1alpha1"
+	operatorv1alpha1 "github.com/aquasecurity/aqua-operator/apis/operator/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// RedactedValue Replaces the values the bundle must not hold
+const RedactedValue = "<redacted>"
+
+// AquaKinds The Aqua CRs collected into the bundle
+var AquaKinds = []schema.GroupVersionKind{
+	operatorv1alpha1.GroupVersion.WithKind("AquaCsp"),
+	operatorv1alpha1.GroupVersion.WithKind("AquaDatabase"),
+	operatorv1alpha1.GroupVersion.WithKind("AquaServer"),
+	operatorv1alpha1.GroupVersion.WithKind("AquaGateway"),
+	operatorv1alpha1.GroupVersion.WithKind("AquaEnforcer"),
+	operatorv1alpha1.GroupVersion.WithKind("AquaKubeEnforcer"),
+	operatorv1alpha1.GroupVersion.WithKind("AquaScanner"),
+	aquasecurityv1alpha1.GroupVersion.WithKind("AquaStarboard"),
+}
+
+// ownedKinds The objects the operator creates for the CRs, found by the deployedby label
+var ownedKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "", Version: "v1", Kind: "ConfigMap"},
+	{Group: "", Version: "v1", Kind: "Service"},
+	{Group: "", Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Group: "", Version: "v1", Kind: "ServiceAccount"},
+}
+
+// webhookKinds The cluster scoped webhook configurations of the kube-enforcer, found by the deployedby label
+var webhookKinds = []schema.GroupVersionKind{
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+}
+
+// OperatorLabels The labels of the operator pods
+var OperatorLabels = map[string]string{"control-plane": "controller-manager"}
+
+// Options What the bundle is collected from
+type Options struct {
+	Namespace string
+	// LogSince Age of the oldest pod log lines collected
+	LogSince time.Duration
+	// TailLines Maximum number of log lines collected per container, no limit when 0
+	TailLines int64
+	// OperatorNamespace Namespace of the operator pods whose logs are collected, skipped when empty
+	OperatorNamespace string
+}
+
+// Collector Gathers the Aqua CRs of a namespace with the objects, events and pod logs around them
+type Collector struct {
+	Client    client.Reader
+	Clientset kubernetes.Interface
+	Options   Options
+	// Errors The parts of the last bundle that couldn't be collected
+	Errors []string
+}
+
+// NewCollector returns a collector reading the cluster with the client, the clientset reads the pod logs
+func NewCollector(k8sclient client.Reader, clientset kubernetes.Interface, options Options) *Collector {
+	return &Collector{
+		Client:    k8sclient,
+		Clientset: clientset,
+		Options:   options,
+	}
+}
+
+// Write writes the bundle as a gzipped tarball, a part that can't be collected is recorded in errors.txt
+// instead of failing the bundle. Secret values are dropped, and what looks like a credential in the other
+// objects, the events and the logs is redacted
+func (c *Collector) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	errs := []string{}
+	add := func(name string, data []byte) error {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	failed := func(part string, err error) {
+		errs = append(errs, fmt.Sprintf("%s: %v", part, err))
+	}
+
+	addObjects := func(gvk schema.GroupVersionKind, opts ...client.ListOption) error {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := c.Client.List(context.TODO(), list, opts...); err != nil {
+			failed(gvk.Kind, err)
+			return nil
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			obj.SetManagedFields(nil)
+			RedactObject(obj.Object)
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return err
+			}
+			if err = add(fmt.Sprintf("%s/%s.yaml", strings.ToLower(gvk.Kind), obj.GetName()), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, gvk := range AquaKinds {
+		if err := addObjects(gvk, client.InNamespace(c.Options.Namespace)); err != nil {
+			return err
+		}
+	}
+	for _, gvk := range ownedKinds {
+		if err := addObjects(gvk, client.InNamespace(c.Options.Namespace), client.MatchingLabels{"deployedby": "aqua-operator"}); err != nil {
+			return err
+		}
+	}
+	for _, gvk := range webhookKinds {
+		if err := addObjects(gvk, client.MatchingLabels{"deployedby": "aqua-operator"}); err != nil {
+			return err
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	err := c.Client.List(context.TODO(), secrets, client.InNamespace(c.Options.Namespace), client.MatchingLabels{"deployedby": "aqua-operator"})
+	if err != nil {
+		failed("Secret", err)
+	}
+	for i := range secrets.Items {
+		data, err := yaml.Marshal(redactSecret(&secrets.Items[i]))
+		if err != nil {
+			return err
+		}
+		if err = add(fmt.Sprintf("secret/%s.yaml", secrets.Items[i].Name), data); err != nil {
+			return err
+		}
+	}
+
+	events := &corev1.EventList{}
+	err = c.Client.List(context.TODO(), events, client.InNamespace(c.Options.Namespace))
+	if err != nil {
+		failed("Event", err)
+	} else {
+		data, err := marshalRedacted(events)
+		if err != nil {
+			return err
+		}
+		if err = add("events.yaml", data); err != nil {
+			return err
+		}
+	}
+
+	pods := &corev1.PodList{}
+	err = c.Client.List(context.TODO(), pods, client.InNamespace(c.Options.Namespace), client.MatchingLabels{"deployedby": "aqua-operator"})
+	if err != nil {
+		failed("Pod", err)
+	}
+	for i := range pods.Items {
+		if err = c.addPod(&pods.Items[i], "", add, failed); err != nil {
+			return err
+		}
+	}
+
+	if len(c.Options.OperatorNamespace) > 0 {
+		operatorPods := &corev1.PodList{}
+		err = c.Client.List(context.TODO(), operatorPods, client.InNamespace(c.Options.OperatorNamespace), client.MatchingLabels(OperatorLabels))
+		if err != nil {
+			failed("operator pods", err)
+		}
+		for i := range operatorPods.Items {
+			if err = c.addPod(&operatorPods.Items[i], "operator/", add, failed); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.Errors = errs
+	if len(errs) > 0 {
+		if err = add("errors.txt", []byte(strings.Join(errs, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// addPod adds the pod and the logs of its containers under the prefix
+func (c *Collector) addPod(pod *corev1.Pod, prefix string, add func(string, []byte) error, failed func(string, error)) error {
+	data, err := marshalRedacted(pod)
+	if err != nil {
+		return err
+	}
+	if err = add(fmt.Sprintf("%spod/%s.yaml", prefix, pod.Name), data); err != nil {
+		return err
+	}
+
+	for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		logs, err := c.podLogs(pod.Namespace, pod.Name, container.Name)
+		if err != nil {
+			failed(fmt.Sprintf("logs of %s/%s", pod.Name, container.Name), err)
+			continue
+		}
+		if err = add(fmt.Sprintf("%slogs/%s/%s.log", prefix, pod.Name, container.Name), RedactText(logs)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Collector) podLogs(namespace, pod, container string) ([]byte, error) {
+	options := &corev1.PodLogOptions{Container: container}
+	if c.Options.LogSince > 0 {
+		seconds := int64(c.Options.LogSince.Seconds())
+		options.SinceSeconds = &seconds
+	}
+	if c.Options.TailLines > 0 {
+		options.TailLines = &c.Options.TailLines
+	}
+
+	stream, err := c.Clientset.CoreV1().Pods(namespace).GetLogs(pod, options).Stream(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	logs := &bytes.Buffer{}
+	_, err = io.Copy(logs, stream)
+	return logs.Bytes(), err
+}
+
+// marshalRedacted marshals a typed object to yaml with the credentials it holds redacted
+func marshalRedacted(obj interface{}) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	RedactObject(content)
+
+	return yaml.Marshal(content)
+}
+
+// redactSecret keeps the keys of the secret and drops their values
+func redactSecret(secret *corev1.Secret) *corev1.Secret {
+	redacted := secret.DeepCopy()
+	redacted.ManagedFields = nil
+	redacted.StringData = nil
+	for key := range redacted.Data {
+		redacted.Data[key] = []byte(RedactedValue)
+	}
+	delete(redacted.Annotations, corev1.LastAppliedConfigAnnotation)
+	delete(redacted.Annotations, "banzaicloud.com/last-applied")
+
+	return redacted
+}
@@ -0,0 +1,194 @@
+package versions
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed matrix.yaml
+var matrixData []byte
+
+// Release An Aqua version supported by the operator, with the versions of the components released with it
+type Release struct {
+	Version      string `json:"version"`
+	KubeEnforcer string `json:"kubeEnforcer"`
+	Starboard    string `json:"starboard"`
+	// Upgrades The versions this version can be upgraded to directly
+	Upgrades []string `json:"upgrades,omitempty"`
+}
+
+// Matrix The compatibility matrix embedded in the operator
+type Matrix struct {
+	Releases []Release `json:"releases"`
+}
+
+var matrix = mustLoad(matrixData)
+
+func mustLoad(data []byte) *Matrix {
+	m := &Matrix{}
+	if err := yaml.UnmarshalStrict(data, m); err != nil {
+		panic(fmt.Sprintf("invalid version matrix: %v", err))
+	}
+
+	if _, ok := m.lookup(consts.LatestVersion); !ok {
+		panic(fmt.Sprintf("invalid version matrix: latest version %s is missing", consts.LatestVersion))
+	}
+
+	for _, release := range m.Releases {
+		for _, upgrade := range release.Upgrades {
+			if _, ok := m.lookup(upgrade); !ok {
+				panic(fmt.Sprintf("invalid version matrix: %s upgrades to unknown version %s", release.Version, upgrade))
+			}
+		}
+	}
+
+	return m
+}
+
+// UnsupportedUpgradeError The requested version can't be reached from the deployed version
+type UnsupportedUpgradeError struct {
+	From string
+	To   string
+}
+
+func (e *UnsupportedUpgradeError) Error() string {
+	return fmt.Sprintf("upgrade from %s to %s is not supported", e.From, e.To)
+}
+
+// UnsupportedVersionError The version is not in the compatibility matrix
+type UnsupportedVersionError struct {
+	Version string
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("version %s is not supported, supported versions are %s", e.Version, strings.Join(Supported(), ", "))
+}
+
+// Supported returns the supported Aqua versions
+func Supported() []string {
+	result := []string{}
+	for _, release := range matrix.Releases {
+		result = append(result, release.Version)
+	}
+
+	return result
+}
+
+// Latest returns the release of consts.LatestVersion
+func Latest() Release {
+	release, _ := matrix.lookup(consts.LatestVersion)
+	return release
+}
+
+// Lookup returns the release of a version or image tag, 2022.4.5 and 2022.4-ubi8 are releases of 2022.4
+func Lookup(version string) (Release, bool) {
+	return matrix.lookup(version)
+}
+
+func (m *Matrix) lookup(version string) (Release, bool) {
+	for _, release := range m.Releases {
+		if IsReleaseOf(version, release.Version) {
+			return release, true
+		}
+	}
+
+	return Release{}, false
+}
+
+// TagFor returns the image tag of the repository in this release
+func (r Release) TagFor(repository string) string {
+	if repository == "kube-enforcer" {
+		return r.KubeEnforcer
+	}
+
+	return r.Version
+}
+
+// MatchesTag checks the image tag of the repository belongs to this release
+func (r Release) MatchesTag(repository, tag string) bool {
+	return IsReleaseOf(tag, r.TagFor(repository))
+}
+
+// IsReleaseOf checks a tag or reported version is the version itself or one of its patch releases
+func IsReleaseOf(tag, version string) bool {
+	return tag == version ||
+		strings.HasPrefix(tag, version+".") ||
+		strings.HasPrefix(tag, version+"-")
+}
+
+// UpgradePath returns the versions to roll out one after the other to get from one version to another,
+// the last one is the release of to. An empty path means both are of the same release.
+func UpgradePath(from, to string) ([]string, error) {
+	fromRelease, ok := Lookup(from)
+	if !ok {
+		return nil, &UnsupportedVersionError{Version: from}
+	}
+
+	toRelease, ok := Lookup(to)
+	if !ok {
+		return nil, &UnsupportedVersionError{Version: to}
+	}
+
+	if fromRelease.Version == toRelease.Version {
+		return []string{}, nil
+	}
+
+	// breadth first, so the path takes the fewest steps
+	previous := map[string]string{fromRelease.Version: ""}
+	queue := []string{fromRelease.Version}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		release, _ := Lookup(current)
+		for _, next := range release.Upgrades {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = current
+
+			if next == toRelease.Version {
+				path := []string{}
+				for step := next; step != fromRelease.Version; step = previous[step] {
+					path = append([]string{step}, path...)
+				}
+				return path, nil
+			}
+
+			queue = append(queue, next)
+		}
+	}
+
+	return nil, &UnsupportedUpgradeError{From: fromRelease.Version, To: toRelease.Version}
+}
+
+// AvailableUpgrades returns the versions that can be reached from a version, nearest first
+func AvailableUpgrades(from string) []string {
+	release, ok := Lookup(from)
+	if !ok {
+		return nil
+	}
+
+	result := []string{}
+	seen := map[string]bool{release.Version: true}
+	queue := []string{release.Version}
+	for len(queue) > 0 {
+		current, _ := Lookup(queue[0])
+		queue = queue[1:]
+
+		for _, next := range current.Upgrades {
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			result = append(result, next)
+			queue = append(queue, next)
+		}
+	}
+
+	return result
+}
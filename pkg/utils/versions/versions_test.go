@@ -0,0 +1,73 @@
+package versions
+
+import (
+	"github.com/aquasecurity/aqua-operator/pkg/consts"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version matrix", func() {
+	It("has the latest version", func() {
+		Expect(Latest().Version).To(Equal(consts.LatestVersion))
+		Expect(Supported()).To(ContainElement(consts.LatestVersion))
+	})
+
+	DescribeTable("Lookup",
+		func(version, release string, found bool) {
+			result, ok := Lookup(version)
+			Expect(ok).To(Equal(found))
+			Expect(result.Version).To(Equal(release))
+		},
+		Entry("a release", "6.5", "6.5", true),
+		Entry("a patch release", "2022.4.5", "2022.4", true),
+		Entry("an image tag", "2022.4-ubi8", "2022.4", true),
+		Entry("a version sharing a prefix", "6.50", "", false),
+		Entry("an unknown version", "5.3", "", false),
+	)
+
+	DescribeTable("UpgradePath",
+		func(from, to string, path []string) {
+			result, err := UpgradePath(from, to)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(path))
+		},
+		Entry("the same release", "6.5", "6.5", []string{}),
+		Entry("patch releases of the same release", "2022.4.1", "2022.4.7", []string{}),
+		Entry("a direct upgrade", "6.5", "2022.4", []string{"2022.4"}),
+		Entry("a direct upgrade from a patch release", "6.2.3", "6.5", []string{"6.5"}),
+		Entry("an upgrade through an intermediate version", "6.2", "2022.4", []string{"6.5", "2022.4"}),
+		Entry("an upgrade to a patch release through an intermediate version", "6.2", "2022.4.5", []string{"6.5", "2022.4"}),
+	)
+
+	DescribeTable("UpgradePath errors",
+		func(from, to string, expected error) {
+			_, err := UpgradePath(from, to)
+			Expect(err).To(Equal(expected))
+		},
+		Entry("a downgrade", "2022.4", "6.5", &UnsupportedUpgradeError{From: "2022.4", To: "6.5"}),
+		Entry("an unknown version to upgrade from", "5.3", "6.5", &UnsupportedVersionError{Version: "5.3"}),
+		Entry("an unknown version to upgrade to", "6.5", "2023.1", &UnsupportedVersionError{Version: "2023.1"}),
+	)
+
+	DescribeTable("AvailableUpgrades",
+		func(from string, upgrades []string) {
+			Expect(AvailableUpgrades(from)).To(Equal(upgrades))
+		},
+		Entry("the nearest version first", "6.2", []string{"6.5", "2022.4"}),
+		Entry("the latest version", "2022.4", []string{}),
+		Entry("an unknown version", "5.3", nil),
+	)
+
+	It("rejects a matrix upgrading to an unknown version", func() {
+		data := []byte(`releases:
+  - version: "` + consts.LatestVersion + `"
+    upgrades:
+      - "9.9"
+`)
+		Expect(func() { mustLoad(data) }).To(PanicWith(ContainSubstring("unknown version 9.9")))
+	})
+
+	It("rejects a matrix without the latest version", func() {
+		Expect(func() { mustLoad([]byte("releases: []\n")) }).To(PanicWith(ContainSubstring("latest version")))
+	})
+})
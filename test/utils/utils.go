@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"os"
 	"os/exec"
@@ -502,3 +503,51 @@ func CreateStorageClassIfNotExist() {
 //	_, err = exec.Command("bash", "-c", "PATH=~:/usr/local/bin/:$PATH && kind delete cluster").Output()
 //	"kubectl get pods -n ${namespace} -l app.kubernetes.io/instance=aqua-${chart} -o jsonpath='{.items[*].status.containerStatuses[0].ready}'"
 //}
+
+// StatusClient writes only the status on a status update and reads the stored object back, like the status
+// subresource of the API server, the fake client writes the whole object
+type StatusClient struct {
+	client.Client
+}
+
+type statusWriter struct {
+	client.StatusWriter
+	reader client.Client
+}
+
+func (c StatusClient) Status() client.StatusWriter {
+	return statusWriter{StatusWriter: c.Client.Status(), reader: c.Client}
+}
+
+func (w statusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	updated, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	stored := obj.DeepCopyObject().(client.Object)
+	err = w.reader.Get(ctx, client.ObjectKeyFromObject(obj), stored)
+	if err != nil {
+		return err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(stored)
+	if err != nil {
+		return err
+	}
+	content["status"] = updated["status"]
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, stored)
+	if err != nil {
+		return err
+	}
+
+	err = w.StatusWriter.Update(ctx, stored, opts...)
+	if err != nil {
+		return err
+	}
+	content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(stored)
+	if err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(content, obj)
+}